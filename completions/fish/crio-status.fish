@@ -2,7 +2,7 @@
 
 function __fish_crio-status_no_subcommand --description 'Test if there has been any subcommand yet'
     for i in (commandline -opc)
-        if contains -- $i complete completion help h man markdown md config c containers container cs s info i help h
+        if contains -- $i complete completion help h man markdown md config c containers container cs s info i userns u selinux se network net hostports hp help h
             return 1
         end
     end
@@ -30,5 +30,14 @@ complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'containers
 complete -c crio-status -n '__fish_seen_subcommand_from containers container cs s' -f -l id -s i -r -d 'the container ID'
 complete -c crio-status -n '__fish_seen_subcommand_from info i' -f -l help -s h -d 'show help'
 complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'info i' -d 'Retrieve generic information about CRI-O, such as the cgroup and storage driver.'
+complete -c crio-status -n '__fish_seen_subcommand_from userns u' -f -l help -s h -d 'show help'
+complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'userns u' -d 'Display the user namespace ranges assigned to pods from the userns_range.'
+complete -c crio-status -n '__fish_seen_subcommand_from selinux se' -f -l help -s h -d 'show help'
+complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'selinux se' -d 'Display the SELinux MCS levels reserved for pods and the collisions between them.'
+complete -c crio-status -n '__fish_seen_subcommand_from network net' -f -l help -s h -d 'show help'
+complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'network net' -d 'Display the network state of the provided pod sandbox ID, including its CNI results and host ports.'
+complete -c crio-status -n '__fish_seen_subcommand_from network net' -f -l id -s i -r -d 'the pod sandbox ID'
+complete -c crio-status -n '__fish_seen_subcommand_from hostports hp' -f -l help -s h -d 'show help'
+complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'hostports hp' -d 'Display the host ports allocated on the node and the pods they belong to.'
 complete -c crio-status -n '__fish_seen_subcommand_from help h' -f -l help -s h -d 'show help'
 complete -r -c crio-status -n '__fish_crio-status_no_subcommand' -a 'help h' -d 'Shows a list of commands or help for one command'
@@ -2,7 +2,7 @@
 
 function __fish_crio_no_subcommand --description 'Test if there has been any subcommand yet'
     for i in (commandline -opc)
-        if contains -- $i complete completion help h man markdown md config version wipe status config c containers container cs s info i help h
+        if contains -- $i complete completion help h man markdown md config version wipe status config c containers container cs s info i userns u selinux se network net hostports hp help h
             return 1
         end
     end
@@ -12,16 +12,29 @@ end
 complete -c crio -n '__fish_crio_no_subcommand' -f -l absent-mount-sources-to-reject -r -d 'A list of paths that, when absent from the host, will cause a container creation to fail (as opposed to the current behavior of creating a directory).'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l add-inheritable-capabilities -d 'Add capabilities to the inheritable set, as well as the default group of permitted, bounding and effective.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l additional-devices -r -d 'Devices to add to the containers.'
+complete -c crio -n '__fish_crio_no_subcommand' -l admission-policy-file -r -d 'Path to the node-local admission policy file, which can deny or mutate RunPodSandbox and CreateContainer requests.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l allowed-devices -r -d 'Devices a user is allowed to specify with the "io.kubernetes.cri-o.Devices" allowed annotation.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l apparmor-profile -r -d 'Name of the apparmor profile to be used as the runtime\'s default. This only takes effect if the user does not specify a profile via the Kubernetes Pod\'s metadata annotation.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l audit-log-max-files -r -d 'Number of rotated audit log files to keep.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l audit-log-max-size -r -d 'Size in bytes at which the audit log file gets rotated. Zero disables the rotation.'
+complete -c crio -n '__fish_crio_no_subcommand' -l audit-log-path -r -d 'Path to the file the audit log of exec, attach, port forward and privileged container creation requests, as well as of syscalls audited by the seccomp notifier, gets appended to. An empty path disables the audit log file.'
+complete -c crio -n '__fish_crio_no_subcommand' -l audit-log-socket -r -d 'Path to a unix socket the audit log gets written to. An empty path disables the audit log socket.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l bandwidth-shaping -r -d 'How the bandwidth limits of the kubernetes.io/ingress-bandwidth and kubernetes.io/egress-bandwidth pod annotations are applied (\'cni\' or \'native\')
+    1. cni: The limits are passed to the CNI plugins, which requires the
+       bandwidth plugin to be chained in the CNI configuration.
+    2. native: The limits are applied by CRI-O using traffic control on the
+       host side veth peer of the pod interface.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l big-files-temporary-dir -r -d 'Path to the temporary directory to use for storing big files, used to store image blobs and data streams related to containers image management.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l bind-mount-prefix -r -d 'A prefix to use for the source of the bind mounts. This option would be useful if you were running CRI-O in a container. And had `/` mounted on `/host` in your container. Then if you ran CRI-O with the `--bind-mount-prefix=/host` option, CRI-O would add /host to any bind mounts it is handed over CRI. If Kubernetes asked to have `/var/lib/foobar` bind mounted into the container, then CRI-O would bind mount `/host/var/lib/foobar`. Since CRI-O itself is running in a container with `/` or the host mounted on `/host`, the container would end up with `/var/lib/foobar` from the host mounted in the container rather then `/var/lib/foobar` from the CRI-O container.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l blockio-config-file -r -d 'Path to the blockio class configuration file for configuring the cgroup blockio controller.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l cdi-spec-dirs -r -d 'Directories to scan for CDI Spec files.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l cgroup-manager -r -d 'cgroup manager (cgroupfs or systemd).'
 complete -c crio -n '__fish_crio_no_subcommand' -l clean-shutdown-file -r -d 'Location for CRI-O to lay down the clean shutdown file. It indicates whether we\'ve had time to sync changes to disk before shutting down. If not found, crio wipe will clear the storage directory.'
+complete -c crio -n '__fish_crio_no_subcommand' -l cni-attachments-dir -r -d 'Directory the pods whose CNI attachments have been created by CRI-O are recorded in. Only their attachments are released as stale.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l cni-check-period -r -d 'The number of seconds between running CNI CHECK on the networks of all running pods. If set to 0, the networks are not checked periodically.'
 complete -c crio -n '__fish_crio_no_subcommand' -l cni-config-dir -r -d 'CNI configuration files directory.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l cni-default-network -r -d 'Name of the default CNI network to select. If not set or "", then CRI-O will pick-up the first one found in --cni-config-dir.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l cni-gc-period -r -d 'The number of seconds between releasing the CNI attachments which do not belong to any known pod. If set to 0, the attachments are only released for pods which fail to be restored on startup.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l cni-plugin-dir -r -d 'CNI plugin binaries directory.'
 complete -c crio -n '__fish_crio_no_subcommand' -l config -s c -r -d 'Path to configuration file'
 complete -c crio -n '__fish_crio_no_subcommand' -l config-dir -s d -r -d 'Path to the configuration drop-in directory.
@@ -48,8 +61,15 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l default-runtime -r -d 'Def
 complete -c crio -n '__fish_crio_no_subcommand' -f -l default-sysctls -r -d 'Sysctls to add to the containers.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l default-transport -r -d 'A prefix to prepend to image names that cannot be pulled as-is.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l default-ulimits -r -d 'Ulimits to apply to containers by default (name=soft:hard).'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l denied-capabilities -r -d 'Capabilities non-privileged containers are not allowed to add.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l denied-sysctls -r -d 'Sysctls non-privileged pods are not allowed to set. Entries ending with "*" deny all sysctls with the same prefix.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l device-ownership-from-security-context -d 'Set devices\' uid/gid ownership from runAsUser/runAsGroup.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l disable-hostport-mapping -d 'If true, CRI-O would disable the hostport mapping.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l dns-local-cache -r -d 'IP address of a node-local DNS cache, which is added as first nameserver of all pods having nameservers.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l dns-max-nameservers -r -d 'Maximum number of nameservers of a pod. Additional nameservers are dropped with a warning. If set to 0, the number is not limited.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l dns-max-searches -r -d 'Maximum number of search domains of a pod. Additional search domains are dropped with a warning. If set to 0, the number is not limited.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l dns-namespace-options -r -d 'List of "<namespace>=<option>" entries, which replace the resolver options of the same name of all pods within a namespace.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l dns-options -r -d 'Resolver options added to the resolv.conf of all pods which do not set an option of the same name, for example "ndots:2".'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l drop-infra-ctr -d 'Determines whether pods are created without an infra container, when the pod is not using a pod level PID namespace.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-criu-support -d 'Enable CRIU integration, requires that the criu binary is available in $PATH.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-metrics -d 'Enable metrics endpoint for the server on localhost:9090.'
@@ -57,6 +77,8 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-nri -d 'Enable NRI
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-pod-events -d 'If true, CRI-O starts sending the container events to the kubelet'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-profile-unix-socket -d 'Enable pprof profiler on crio unix domain socket.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-tracing -d 'Enable OpenTelemetry trace data exporting.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l exec-sync-concurrency-limit -r -d 'Maximum number of exec sync requests, like exec probes, running in parallel on the node. A value of 0 disables the limit.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l generate-apparmor-profiles -d 'Generate and load an AppArmor profile derived from the spec of every container which would otherwise use the default profile.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l gid-mappings -r -d 'Specify the GID mappings to use for the user namespace.'
 complete -c crio -n '__fish_crio_no_subcommand' -l global-auth-file -r -d 'Path to a file like /var/lib/kubelet/config.json holding credentials necessary for pulling images from secure registries.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l grpc-max-recv-msg-size -r -d 'Maximum grpc receive message size in bytes.'
@@ -81,6 +103,8 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l hooks-dir -r -d 'Set the O
     Kubernetes configuration are considered. Bind mounts that CRI-O
     inserts by default (e.g. \'/dev/shm\') are not considered.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l hostnetwork-disable-selinux -d 'Determines whether SELinux should be disabled within a pod when it is running in the host network namespace.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l hostport-ranges-max-ports -r -d 'Maximum number of host ports a pod can map by the io.kubernetes.cri-o.HostPortRanges annotation. If set to 0, the number is not limited.'
+complete -c crio -n '__fish_crio_no_subcommand' -l hostport-reservations-file -r -d 'Path to the file the host ports reserved by pods are persisted in.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l image-volumes -r -d 'Image volume handling (\'mkdir\', \'bind\', or \'ignore\')
     1. mkdir: A directory is created inside the container root filesystem for
        the volumes.
@@ -106,6 +130,9 @@ complete -c crio -n '__fish_crio_no_subcommand' -l log -r -d 'Set the log file p
 complete -c crio -n '__fish_crio_no_subcommand' -l log-dir -r -d 'Default log directory where all logs will go unless directly specified by the kubelet.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l log-filter -r -d 'Filter the log messages by the provided regular expression. For example \'request.\*\' filters all gRPC requests.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l log-format -r -d 'Set the format used by logs: \'text\' or \'json\'.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l log-forward-buffer-size -r -d 'Maximum number of log lines buffered while the log forward socket is unavailable.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l log-forward-format -r -d 'Format of the lines forwarded to the log forward socket, either "json" or "syslog".'
+complete -c crio -n '__fish_crio_no_subcommand' -l log-forward-socket -r -d 'Path to a unix socket the container log lines get forwarded to in addition to the kubernetes log file. An empty path disables the log forwarding.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l log-journald -d 'Log to systemd journal (journald) in addition to kubernetes log file.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l log-level -s l -r -d 'Log messages above specified level: trace, debug, info, warn, error, fatal or panic.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l log-size-max -r -d 'Maximum log size in bytes for a container. If it is positive, it must be >= 8192 to match/exceed conmon read buffer. This option is deprecated. The Kubelet flag \'--container-log-max-size\' should be used instead.'
@@ -116,7 +143,9 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l metrics-port -r -d 'Port f
 complete -c crio -n '__fish_crio_no_subcommand' -f -l metrics-socket -r -d 'Socket for the metrics endpoint.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l minimum-mappable-gid -r -d 'Specify the lowest host GID which can be specified in mappings for a pod that will be run as a UID other than 0.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l minimum-mappable-uid -r -d 'Specify the lowest host UID which can be specified in mappings for a pod that will be run as a UID other than 0.'
+complete -c crio -n '__fish_crio_no_subcommand' -l namespaced-auth-dir -r -d 'Path to the root directory for namespaced credentials used for pulling seccomp profile images. Must be an absolute path.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l namespaces-dir -r -d 'The directory where the state of the managed namespaces gets tracked. Only used when manage-ns-lifecycle is true.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l namespaces-gc-period -r -d 'The number of seconds between removing the pinned namespaces which neither belong to any known pod nor are entered by any process. If set to 0, they are only removed on startup.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l no-pivot -d 'If true, the runtime will not use `pivot_root`, but instead use `MS_MOVE`.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l nri-disable-connections -r -d 'Disable connections from externally started NRI plugins. (default: false)'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l nri-listen -r -d 'Socket to listen on for externally started NRI plugins to connect to. (default: "/var/run/nri/nri.sock")'
@@ -141,8 +170,11 @@ complete -c crio -n '__fish_crio_no_subcommand' -l root -s r -r -d 'The CRI-O ro
 complete -c crio -n '__fish_crio_no_subcommand' -l runroot -r -d 'The CRI-O state directory.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l runtimes -r -d 'OCI runtimes, format is \'runtime_name:runtime_path:runtime_root:runtime_type:privileged_without_host_devices:runtime_config_path\'.'
 complete -c crio -n '__fish_crio_no_subcommand' -l seccomp-profile -r -d 'Path to the seccomp.json profile to be used as the runtime\'s default. If not specified, then the internal default seccomp profile will be used.'
+complete -c crio -n '__fish_crio_no_subcommand' -l seccomp-profile-record-dir -r -d 'Directory the seccomp profiles recorded for containers are written to.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l seccomp-use-default-when-empty -d 'Use the default seccomp profile when an empty one is specified. This option is currently deprecated, and will be replaced by the SeccompDefault FeatureGate in Kubernetes.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l selinux -d 'Enable selinux support.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l selinux-fixed-mcs -r -d 'List of "<namespace>=<level>" entries, which fix the SELinux MCS level of all pods within a namespace.'
+complete -c crio -n '__fish_crio_no_subcommand' -l selinux-mcs-reservations-file -r -d 'Path to the file the SELinux MCS levels reserved for pods are persisted in.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l separate-pull-cgroup -r -d '[EXPERIMENTAL] Pull in new cgroup.'
 complete -c crio -n '__fish_crio_no_subcommand' -l signature-policy -r -d 'Path to signature policy JSON file.'
 complete -c crio -n '__fish_crio_no_subcommand' -l signature-policy-dir -r -d 'Path to the root directory for namespaced signature policies. Must be an absolute path.'
@@ -159,8 +191,11 @@ complete -c crio -n '__fish_crio_no_subcommand' -l stream-tls-key -r -d 'Path to
 complete -c crio -n '__fish_crio_no_subcommand' -f -l tracing-endpoint -r -d 'Address on which the gRPC tracing collector will listen.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l tracing-sampling-rate-per-million -r -d 'Number of samples to collect per million OpenTelemetry spans. Set to 1000000 to always sample.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l uid-mappings -r -d 'Specify the UID mappings to use for the user namespace.'
+complete -c crio -n '__fish_crio_no_subcommand' -l userns-allocations-file -r -d 'Path to the file the user namespace ranges assigned to pods are persisted in.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l userns-range -r -d 'Range of host IDs assigned to the user namespaces of pods using the "auto" user namespace mode, in the format "<first ID>:<size>". If empty, the ranges get assigned by containers/storage instead.'
 complete -c crio -n '__fish_crio_no_subcommand' -l version-file -r -d 'Location for CRI-O to lay down the temporary version file. It is used to check if crio wipe should wipe containers, which should always happen on a node reboot.'
 complete -c crio -n '__fish_crio_no_subcommand' -l version-file-persist -r -d 'Location for CRI-O to lay down the persistent version file. It is used to check if crio wipe should wipe images, which should only happen when CRI-O has been upgraded.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l watch-pod-ips -d 'Watch the addresses of the network interface of the pods and update the pod IPs if they change after the network setup.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l help -s h -d 'show help'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l version -s v -d 'print the version'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l help -s h -d 'show help'
@@ -210,5 +245,14 @@ complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'containers conta
 complete -c crio -n '__fish_seen_subcommand_from containers container cs s' -f -l id -s i -r -d 'the container ID'
 complete -c crio -n '__fish_seen_subcommand_from info i' -f -l help -s h -d 'show help'
 complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'info i' -d 'Retrieve generic information about CRI-O, such as the cgroup and storage driver.'
+complete -c crio -n '__fish_seen_subcommand_from userns u' -f -l help -s h -d 'show help'
+complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'userns u' -d 'Display the user namespace ranges assigned to pods from the userns_range.'
+complete -c crio -n '__fish_seen_subcommand_from selinux se' -f -l help -s h -d 'show help'
+complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'selinux se' -d 'Display the SELinux MCS levels reserved for pods and the collisions between them.'
+complete -c crio -n '__fish_seen_subcommand_from network net' -f -l help -s h -d 'show help'
+complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'network net' -d 'Display the network state of the provided pod sandbox ID, including its CNI results and host ports.'
+complete -c crio -n '__fish_seen_subcommand_from network net' -f -l id -s i -r -d 'the pod sandbox ID'
+complete -c crio -n '__fish_seen_subcommand_from hostports hp' -f -l help -s h -d 'show help'
+complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'hostports hp' -d 'Display the host ports allocated on the node and the pods they belong to.'
 complete -c crio -n '__fish_seen_subcommand_from help h' -f -l help -s h -d 'show help'
 complete -r -c crio -n '__fish_crio_no_subcommand' -a 'help h' -d 'Shows a list of commands or help for one command'
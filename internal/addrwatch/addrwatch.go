@@ -0,0 +1,112 @@
+// Package addrwatch watches the addresses of the network interfaces of pods,
+// which may change after the CNI plugins set up the pod network, for example
+// by IPv6 stateless address autoconfiguration or by an external controller.
+package addrwatch
+
+import (
+	"net"
+	"sort"
+	"sync"
+)
+
+// Watcher watches the addresses of a network interface until stopped.
+type Watcher struct {
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// Stop stops watching the addresses. It can be called more than once.
+func (w *Watcher) Stop() {
+	if w == nil {
+		return
+	}
+	w.stopOnce.Do(func() {
+		close(w.done)
+	})
+}
+
+// stopped returns true if the watcher has been stopped.
+func (w *Watcher) stopped() bool {
+	select {
+	case <-w.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Change is a change of the addresses of the watched interface.
+type Change struct {
+	// Added are the addresses which have been added to the interface.
+	Added []string
+
+	// Removed are the addresses which have been removed from the interface.
+	Removed []string
+}
+
+// Empty returns true if no address has been added or removed.
+func (c *Change) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0
+}
+
+// Apply applies the change to the provided IPs. The order of the kept IPs
+// is preserved, so the primary IP of a pod does not change unless removed,
+// and the added IPs are appended.
+func (c *Change) Apply(ips []string) []string {
+	removed := make(map[string]bool, len(c.Removed))
+	for _, ip := range c.Removed {
+		removed[ip] = true
+	}
+	res := make([]string, 0, len(ips)+len(c.Added))
+	seen := make(map[string]bool, len(ips)+len(c.Added))
+	for _, ip := range ips {
+		if removed[ip] || seen[ip] {
+			continue
+		}
+		seen[ip] = true
+		res = append(res, ip)
+	}
+	for _, ip := range c.Added {
+		if seen[ip] {
+			continue
+		}
+		seen[ip] = true
+		res = append(res, ip)
+	}
+	return res
+}
+
+// diff returns the change from the previous to the current addresses.
+func diff(previous, current []string) *Change {
+	change := &Change{}
+	previousSet := make(map[string]bool, len(previous))
+	for _, ip := range previous {
+		previousSet[ip] = true
+	}
+	currentSet := make(map[string]bool, len(current))
+	for _, ip := range current {
+		currentSet[ip] = true
+		if !previousSet[ip] {
+			change.Added = append(change.Added, ip)
+		}
+	}
+	for _, ip := range previous {
+		if !currentSet[ip] {
+			change.Removed = append(change.Removed, ip)
+		}
+	}
+	return change
+}
+
+// sortIPs sorts the addresses with IPv4 addresses first and keeps the order
+// otherwise.
+func sortIPs(ips []string) {
+	sort.SliceStable(ips, func(i, j int) bool {
+		return isIPv4(ips[i]) && !isIPv4(ips[j])
+	})
+}
+
+func isIPv4(ip string) bool {
+	parsed := net.ParseIP(ip)
+	return parsed != nil && parsed.To4() != nil
+}
@@ -0,0 +1,99 @@
+package addrwatch
+
+import (
+	"fmt"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// Watch watches the addresses of the interface ifname within the network
+// namespace netnsPath until the returned watcher is stopped. notify is called
+// with the addresses present when starting to watch as added ones first and
+// with every change of them afterwards, one call at a time.
+func Watch(netnsPath, ifname string, notify func(*Change)) (*Watcher, error) {
+	w := &Watcher{done: make(chan struct{})}
+	updates := make(chan netlink.AddrUpdate)
+	// the socket stays within the namespace it has been created in
+	if err := ns.WithNetNSPath(netnsPath, func(ns.NetNS) error {
+		return netlink.AddrSubscribeWithOptions(updates, w.done, netlink.AddrSubscribeOptions{
+			ErrorCallback: func(err error) {
+				if !w.stopped() {
+					logrus.Warnf("Failed to receive address updates of %s in %s: %v", ifname, netnsPath, err)
+				}
+			},
+		})
+	}); err != nil {
+		return nil, fmt.Errorf("subscribe to address updates: %w", err)
+	}
+
+	previous, err := Addresses(netnsPath, ifname)
+	if err != nil {
+		w.Stop()
+		return nil, err
+	}
+
+	go func() {
+		if len(previous) > 0 {
+			notify(&Change{Added: previous})
+		}
+		for range updates {
+			if w.stopped() {
+				continue
+			}
+			current, err := Addresses(netnsPath, ifname)
+			if err != nil {
+				logrus.Debugf("Failed to get addresses of %s in %s: %v", ifname, netnsPath, err)
+				continue
+			}
+			change := diff(previous, current)
+			previous = current
+			if !change.Empty() {
+				notify(change)
+			}
+		}
+	}()
+	return w, nil
+}
+
+// Addresses returns the addresses of the interface ifname within the network
+// namespace netnsPath which are usable as pod IPs, IPv4 addresses first. Link
+// local, tentative, deprecated and temporary IPv6 addresses are skipped.
+func Addresses(netnsPath, ifname string) ([]string, error) {
+	ips := []string{}
+	err := ns.WithNetNSPath(netnsPath, func(ns.NetNS) error {
+		link, err := netlink.LinkByName(ifname)
+		if err != nil {
+			return fmt.Errorf("get interface %s: %w", ifname, err)
+		}
+		addrs, err := netlink.AddrList(link, netlink.FAMILY_ALL)
+		if err != nil {
+			return fmt.Errorf("list addresses of %s: %w", ifname, err)
+		}
+		for i := range addrs {
+			if usable(&addrs[i]) {
+				ips = append(ips, addrs[i].IP.String())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortIPs(ips)
+	return ips, nil
+}
+
+// usable returns true if the address can be reported as pod IP.
+func usable(addr *netlink.Addr) bool {
+	if addr.IPNet == nil || addr.Scope != unix.RT_SCOPE_UNIVERSE {
+		return false
+	}
+	if addr.Flags&(unix.IFA_F_TENTATIVE|unix.IFA_F_DADFAILED|unix.IFA_F_DEPRECATED) != 0 {
+		return false
+	}
+	// IFA_F_TEMPORARY is IFA_F_SECONDARY for IPv4, which is usable
+	return addr.IP.To4() != nil || addr.Flags&unix.IFA_F_TEMPORARY == 0
+}
@@ -0,0 +1,48 @@
+package addrwatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	change := diff([]string{"10.0.0.2", "fd00::2"}, []string{"10.0.0.2", "fd00::3", "fd00::4"})
+	assert.Equal(t, []string{"fd00::3", "fd00::4"}, change.Added)
+	assert.Equal(t, []string{"fd00::2"}, change.Removed)
+	assert.False(t, change.Empty())
+
+	assert.True(t, diff([]string{"10.0.0.2"}, []string{"10.0.0.2"}).Empty())
+}
+
+func TestChangeApply(t *testing.T) {
+	change := &Change{Added: []string{"fd00::3", "10.0.0.3"}, Removed: []string{"fd00::2"}}
+
+	// the IPs reported by the CNI plugins, which are not on the interface,
+	// are kept
+	ips := change.Apply([]string{"10.0.0.2", "fd00::2", "192.0.2.1"})
+	assert.Equal(t, []string{"10.0.0.2", "192.0.2.1", "fd00::3", "10.0.0.3"}, ips)
+
+	// added IPs are not duplicated
+	ips = change.Apply([]string{"fd00::3"})
+	assert.Equal(t, []string{"fd00::3", "10.0.0.3"}, ips)
+
+	assert.Empty(t, (&Change{Removed: []string{"10.0.0.2"}}).Apply([]string{"10.0.0.2"}))
+}
+
+func TestSortIPs(t *testing.T) {
+	ips := []string{"fd00::2", "10.0.0.2", "fd00::1", "10.0.0.1"}
+	sortIPs(ips)
+	assert.Equal(t, []string{"10.0.0.2", "10.0.0.1", "fd00::2", "fd00::1"}, ips)
+}
+
+func TestWatcherStop(t *testing.T) {
+	w := &Watcher{done: make(chan struct{})}
+	assert.False(t, w.stopped())
+	w.Stop()
+	w.Stop()
+	assert.True(t, w.stopped())
+
+	// stopping a nil watcher does nothing
+	(*Watcher)(nil).Stop()
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+// +build !linux
+
+package addrwatch
+
+import "errors"
+
+var errUnsupported = errors.New("watching addresses is not supported on this platform")
+
+// Watch is not supported on this platform.
+func Watch(string, string, func(*Change)) (*Watcher, error) {
+	return nil, errUnsupported
+}
+
+// Addresses is not supported on this platform.
+func Addresses(string, string) ([]string, error) {
+	return nil, errUnsupported
+}
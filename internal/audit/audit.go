@@ -0,0 +1,286 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// CRICallExec is the CRI call of exec sessions.
+	CRICallExec = "Exec"
+
+	// CRICallExecSync is the CRI call of synchronous execs.
+	CRICallExecSync = "ExecSync"
+
+	// CRICallAttach is the CRI call of attach sessions.
+	CRICallAttach = "Attach"
+
+	// CRICallPortForward is the CRI call of port forward sessions.
+	CRICallPortForward = "PortForward"
+
+	// CRICallCreateContainer is the CRI call of privileged container
+	// creations.
+	CRICallCreateContainer = "CreateContainer"
+
+	// CRICallSeccompNotifier is used for the syscalls allowed and audited by
+	// the seccomp notifier, which are no CRI calls.
+	CRICallSeccompNotifier = "SeccompNotifier"
+)
+
+const (
+	// StageRequested is the stage of events recorded when the streaming
+	// endpoint of an operation gets requested.
+	StageRequested = "requested"
+
+	// StageCompleted is the stage of events recorded when an operation
+	// finished, either successfully or not.
+	StageCompleted = "completed"
+
+	// StageAllowed is the stage of events recorded for syscalls allowed by
+	// the seccomp notifier.
+	StageAllowed = "allowed"
+)
+
+// Event is a single entry of the audit log.
+type Event struct {
+	// Time is the time the operation started.
+	Time time.Time `json:"time"`
+
+	// Hostname is the name of the node.
+	Hostname string `json:"hostname,omitempty"`
+
+	// CRICall is the CRI call of the operation.
+	CRICall string `json:"cri_call"`
+
+	// Stage is either StageRequested or StageCompleted.
+	Stage string `json:"stage"`
+
+	// Pod, Namespace and PodID identify the pod of the operation.
+	Pod       string `json:"pod,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	PodID     string `json:"pod_id,omitempty"`
+
+	// Container and ContainerID identify the container of the operation.
+	Container   string `json:"container,omitempty"`
+	ContainerID string `json:"container_id,omitempty"`
+
+	// Image is the image of created containers.
+	Image string `json:"image,omitempty"`
+
+	// Privileged is true for privileged containers.
+	Privileged bool `json:"privileged,omitempty"`
+
+	// Command is the argv of exec operations.
+	Command []string `json:"command,omitempty"`
+
+	// TTY is true if the operation uses a terminal.
+	TTY bool `json:"tty,omitempty"`
+
+	// Stdin is true if the operation reads from stdin.
+	Stdin bool `json:"stdin,omitempty"`
+
+	// Ports are the forwarded ports of port forward operations.
+	Ports []int32 `json:"ports,omitempty"`
+
+	// Syscall, Pid and SyscallArgs describe syscalls audited by the seccomp
+	// notifier, where Pid is the host process ID.
+	Syscall     string   `json:"syscall,omitempty"`
+	Pid         uint32   `json:"pid,omitempty"`
+	SyscallArgs []uint64 `json:"syscall_args,omitempty"`
+
+	// DurationSeconds is the duration of completed operations.
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
+
+	// ExitCode is the exit code of completed exec operations, if known.
+	ExitCode *int32 `json:"exit_code,omitempty"`
+
+	// Error is the error of failed operations.
+	Error string `json:"error,omitempty"`
+}
+
+// Complete marks the event as completed at the provided time, including the
+// duration of the operation and its error, if any.
+func (e *Event) Complete(end time.Time, err error) *Event {
+	e.Stage = StageCompleted
+	e.DurationSeconds = end.Sub(e.Time).Seconds()
+	if err != nil {
+		e.Error = err.Error()
+	}
+	return e
+}
+
+// sink is a destination of audit events.
+type sink interface {
+	write(line []byte) error
+	close() error
+}
+
+// Logger writes audit events as JSON lines to a file, a unix socket or both.
+type Logger struct {
+	lock     sync.Mutex
+	hostname string
+	sinks    []sink
+}
+
+// New creates a new audit logger. Events get appended to the file at path,
+// which gets rotated if it would exceed maxSize bytes, keeping maxFiles
+// rotated files. A maxSize of zero disables the rotation. Events are
+// additionally written asynchronously to the unix socket at socketPath. Empty
+// paths disable the respective sink.
+func New(path string, maxSize int64, maxFiles uint64, socketPath string) (*Logger, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		logrus.Debugf("Unable to get hostname for audit logging: %v", err)
+	}
+
+	l := &Logger{hostname: hostname}
+	if path != "" {
+		file, err := newFileSink(path, maxSize, maxFiles)
+		if err != nil {
+			return nil, err
+		}
+		l.sinks = append(l.sinks, file)
+	}
+	if socketPath != "" {
+		l.sinks = append(l.sinks, newAsyncSink(&socketSink{path: socketPath}))
+	}
+	return l, nil
+}
+
+// Log writes the provided event to all sinks. Failing sinks do not affect
+// the audited operation, which is why errors are only logged.
+func (l *Logger) Log(event *Event) {
+	if event.Hostname == "" {
+		event.Hostname = l.hostname
+	}
+	line, err := json.Marshal(event)
+	if err != nil {
+		logrus.Errorf("Unable to encode audit event of %s: %v", event.CRICall, err)
+		return
+	}
+	line = append(line, '\n')
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for _, s := range l.sinks {
+		if err := s.write(line); err != nil {
+			logrus.Errorf("Unable to write audit event of %s: %v", event.CRICall, err)
+		}
+	}
+}
+
+// Close closes all sinks of the logger.
+func (l *Logger) Close() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var errs []error
+	for _, s := range l.sinks {
+		if err := s.close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	l.sinks = nil
+	return errors.Join(errs...)
+}
+
+// fileSink appends events to a file, which gets rotated by size.
+type fileSink struct {
+	path     string
+	maxSize  int64
+	maxFiles uint64
+	file     *os.File
+	size     int64
+}
+
+func newFileSink(path string, maxSize int64, maxFiles uint64) (*fileSink, error) {
+	f := &fileSink{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open opens the audit log file for appending.
+func (f *fileSink) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("open audit log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("stat audit log file: %w", err)
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+func (f *fileSink) write(line []byte) error {
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return err
+		}
+	}
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(line)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := f.file.Write(line)
+	f.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("write audit log file: %w", err)
+	}
+	return nil
+}
+
+// rotate renames the audit log file to "<path>.1", shifts the previously
+// rotated files and removes the ones exceeding maxFiles.
+func (f *fileSink) rotate() error {
+	if err := f.close(); err != nil {
+		return fmt.Errorf("close audit log file: %w", err)
+	}
+
+	if f.maxFiles == 0 {
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove audit log file: %w", err)
+		}
+		return f.open()
+	}
+
+	if err := os.Remove(rotatedPath(f.path, f.maxFiles)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove rotated audit log file: %w", err)
+	}
+	for i := f.maxFiles - 1; i > 0; i-- {
+		if err := os.Rename(rotatedPath(f.path, i), rotatedPath(f.path, i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("rotate audit log file: %w", err)
+		}
+	}
+	if err := os.Rename(f.path, rotatedPath(f.path, 1)); err != nil {
+		return fmt.Errorf("rotate audit log file: %w", err)
+	}
+	return f.open()
+}
+
+func (f *fileSink) close() error {
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// rotatedPath returns the path of the rotated audit log file with the
+// provided index.
+func rotatedPath(path string, i uint64) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
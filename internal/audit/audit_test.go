@@ -0,0 +1,228 @@
+package audit_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/audit"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("Logger", func() {
+	var (
+		tempDir string
+		logPath string
+		sut     *audit.Logger
+	)
+
+	readEvents := func(path string) []*audit.Event {
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		events := []*audit.Event{}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			event := &audit.Event{}
+			Expect(json.Unmarshal([]byte(line), event)).To(Succeed())
+			events = append(events, event)
+		}
+		return events
+	}
+
+	newEvent := func(containerID string) *audit.Event {
+		return &audit.Event{
+			Time:        time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+			Hostname:    "node",
+			CRICall:     audit.CRICallExec,
+			Stage:       audit.StageRequested,
+			Pod:         "pod",
+			Namespace:   "namespace",
+			Container:   "container",
+			ContainerID: containerID,
+			Command:     []string{"sh", "-c", "id"},
+			TTY:         true,
+		}
+	}
+
+	BeforeEach(func() {
+		tempDir = t.MustTempDir("audit")
+		logPath = filepath.Join(tempDir, "audit.log")
+		sut = nil
+	})
+
+	AfterEach(func() {
+		if sut != nil {
+			Expect(sut.Close()).To(Succeed())
+		}
+	})
+
+	It("should append events as JSON lines", func() {
+		// Given
+		Expect(os.WriteFile(logPath, []byte(`{"cri_call":"ExecSync"}`+"\n"), 0o600)).To(Succeed())
+		var err error
+		sut, err = audit.New(logPath, 0, 0, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		sut.Log(newEvent("1"))
+		sut.Log(newEvent("2").Complete(time.Now(), errors.New("failed")))
+
+		// Then
+		events := readEvents(logPath)
+		Expect(events).To(HaveLen(3))
+		Expect(events[0].CRICall).To(Equal(audit.CRICallExecSync))
+		Expect(events[1].ContainerID).To(Equal("1"))
+		Expect(events[1].Stage).To(Equal(audit.StageRequested))
+		Expect(events[1].Command).To(Equal([]string{"sh", "-c", "id"}))
+		Expect(events[1].TTY).To(BeTrue())
+		Expect(events[1].Hostname).To(Equal("node"))
+		Expect(events[2].Stage).To(Equal(audit.StageCompleted))
+		Expect(events[2].Error).To(Equal("failed"))
+	})
+
+	It("should only record the exit code if set", func() {
+		// Given
+		var err error
+		sut, err = audit.New(logPath, 0, 0, "")
+		Expect(err).NotTo(HaveOccurred())
+		exitCode := int32(0)
+		withExitCode := newEvent("1")
+		withExitCode.ExitCode = &exitCode
+
+		// When
+		sut.Log(withExitCode)
+		sut.Log(newEvent("2"))
+
+		// Then
+		data, err := os.ReadFile(logPath)
+		Expect(err).NotTo(HaveOccurred())
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		Expect(lines).To(HaveLen(2))
+		Expect(lines[0]).To(ContainSubstring(`"exit_code":0`))
+		Expect(lines[1]).NotTo(ContainSubstring(`"exit_code"`))
+	})
+
+	It("should rotate the log file", func() {
+		// Given
+		line, err := json.Marshal(newEvent("0"))
+		Expect(err).NotTo(HaveOccurred())
+		// Every file fits two events.
+		size := len(line) + 1
+		sut, err = audit.New(logPath, int64(2*size+size/2), 2, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		for _, id := range []string{"1", "2", "3", "4", "5", "6", "7"} {
+			sut.Log(newEvent(id))
+		}
+
+		// Then
+		ids := func(path string) []string {
+			res := []string{}
+			for _, event := range readEvents(path) {
+				res = append(res, event.ContainerID)
+			}
+			return res
+		}
+		Expect(ids(logPath)).To(Equal([]string{"7"}))
+		Expect(ids(logPath + ".1")).To(Equal([]string{"5", "6"}))
+		Expect(ids(logPath + ".2")).To(Equal([]string{"3", "4"}))
+		Expect(logPath + ".3").NotTo(BeAnExistingFile())
+	})
+
+	It("should truncate the log file without rotated files", func() {
+		// Given
+		line, err := json.Marshal(newEvent("0"))
+		Expect(err).NotTo(HaveOccurred())
+		size := len(line) + 1
+		sut, err = audit.New(logPath, int64(size+size/2), 0, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		sut.Log(newEvent("1"))
+		sut.Log(newEvent("2"))
+
+		// Then
+		events := readEvents(logPath)
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].ContainerID).To(Equal("2"))
+		Expect(logPath + ".1").NotTo(BeAnExistingFile())
+	})
+
+	It("should fail if the log file cannot be opened", func() {
+		// Given
+		// When
+		res, err := audit.New(filepath.Join(tempDir, "missing", "audit.log"), 0, 0, "")
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(res).To(BeNil())
+	})
+
+	It("should write events to the socket once available", func() {
+		// Given
+		socketPath := filepath.Join(tempDir, "audit.sock")
+		var err error
+		sut, err = audit.New("", 0, 0, socketPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		// An unavailable socket does not block
+		sut.Log(newEvent("0"))
+
+		listener, err := net.Listen("unix", socketPath)
+		Expect(err).NotTo(HaveOccurred())
+		defer listener.Close()
+		receive := func() *audit.Event {
+			conn, err := listener.Accept()
+			Expect(err).NotTo(HaveOccurred())
+			defer conn.Close()
+			reader := bufio.NewReader(conn)
+			for {
+				// The event written while the socket was unavailable may
+				// still be delivered, because events are written
+				// asynchronously.
+				line, err := reader.ReadBytes('\n')
+				Expect(err).NotTo(HaveOccurred())
+				event := &audit.Event{}
+				Expect(json.Unmarshal(line, event)).To(Succeed())
+				if event.ContainerID != "0" {
+					return event
+				}
+			}
+		}
+
+		// When
+		sut.Log(newEvent("1"))
+		first := receive()
+
+		// Then
+		Expect(first.ContainerID).To(Equal("1"))
+		Expect(first.Namespace).To(Equal("namespace"))
+	})
+
+	It("should not block if the socket does not read events", func() {
+		// Given
+		socketPath := filepath.Join(tempDir, "audit.sock")
+		listener, err := net.Listen("unix", socketPath)
+		Expect(err).NotTo(HaveOccurred())
+		defer listener.Close()
+		sut, err = audit.New("", 0, 0, socketPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		start := time.Now()
+		for i := 0; i < 10000; i++ {
+			sut.Log(newEvent("1"))
+		}
+
+		// Then
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		Expect(listener.Close()).To(Succeed())
+	})
+})
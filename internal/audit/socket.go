@@ -0,0 +1,132 @@
+package audit
+
+import (
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// dialTimeout is the maximum time to wait for connecting to the audit
+	// socket.
+	dialTimeout = time.Second
+
+	// writeTimeout is the maximum time to wait for the audit socket to
+	// accept an event.
+	writeTimeout = 5 * time.Second
+
+	// socketQueueSize is the maximum number of events waiting to be written
+	// to the audit socket. Further events are dropped.
+	socketQueueSize = 1024
+)
+
+// asyncSink queues events for a background writer to not block the audited
+// operations on a slow or absent audit socket. Events are dropped and counted
+// if the queue is full.
+type asyncSink struct {
+	sink    sink
+	queue   chan []byte
+	done    chan struct{}
+	closing atomic.Bool
+	dropped atomic.Uint64
+}
+
+func newAsyncSink(s sink) *asyncSink {
+	a := &asyncSink{
+		sink:  s,
+		queue: make(chan []byte, socketQueueSize),
+		done:  make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// write queues the event without blocking. It must not be called after close.
+func (a *asyncSink) write(line []byte) error {
+	select {
+	case a.queue <- line:
+	default:
+		a.dropped.Add(1)
+	}
+	return nil
+}
+
+func (a *asyncSink) run() {
+	defer close(a.done)
+	var reported uint64
+	for line := range a.queue {
+		if err := a.sink.write(line); err != nil {
+			logrus.Errorf("Unable to write audit event: %v", err)
+			if a.closing.Load() {
+				// Do not delay the shutdown by retrying the remaining
+				// events.
+				a.dropped.Add(uint64(len(a.queue)))
+				return
+			}
+			continue
+		}
+		if dropped := a.dropped.Load(); dropped > reported {
+			logrus.Warnf("Dropped %d audit events because the audit socket did not keep up", dropped-reported)
+			reported = dropped
+		}
+	}
+}
+
+// close writes the queued events and closes the underlying sink.
+func (a *asyncSink) close() error {
+	a.closing.Store(true)
+	close(a.queue)
+	<-a.done
+	if dropped := a.dropped.Load(); dropped > 0 {
+		logrus.Warnf("Dropped %d audit events in total because the audit socket did not keep up", dropped)
+	}
+	return a.sink.close()
+}
+
+// socketSink writes events to a unix stream socket, reconnecting if the
+// connection got lost.
+type socketSink struct {
+	path string
+	conn net.Conn
+}
+
+func (s *socketSink) write(line []byte) error {
+	// A connection may have been closed by the peer since the last event,
+	// which is only noticed when writing. Retry once with a new connection
+	// to not lose the event in that case.
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			s.conn, err = net.DialTimeout("unix", s.path, dialTimeout)
+			if err != nil {
+				s.conn = nil
+				return fmt.Errorf("connect to audit socket: %w", err)
+			}
+		}
+		if err = s.send(line); err == nil {
+			return nil
+		}
+		s.close()
+	}
+	return fmt.Errorf("write to audit socket: %w", err)
+}
+
+func (s *socketSink) send(line []byte) error {
+	if err := s.conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return err
+	}
+	_, err := s.conn.Write(line)
+	return err
+}
+
+func (s *socketSink) close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
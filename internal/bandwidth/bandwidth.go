@@ -0,0 +1,82 @@
+// Package bandwidth limits the bandwidth of pod interfaces by the traffic
+// control of their host side veth peers, without relying on the CNI bandwidth
+// plugin.
+package bandwidth
+
+import (
+	"math"
+	"time"
+)
+
+// Limits are the bandwidth limits of a pod interface. The rates are in bits
+// per second and the bursts in bits, like the ones of the CNI bandwidth
+// capability. A zero rate does not limit the direction.
+type Limits struct {
+	// IngressRate and IngressBurst limit the traffic received by the pod.
+	IngressRate  uint64 `json:"ingressRate,omitempty"`
+	IngressBurst uint64 `json:"ingressBurst,omitempty"`
+
+	// EgressRate and EgressBurst limit the traffic sent by the pod.
+	EgressRate  uint64 `json:"egressRate,omitempty"`
+	EgressBurst uint64 `json:"egressBurst,omitempty"`
+}
+
+// Limited returns true if any direction is limited.
+func (l *Limits) Limited() bool {
+	return l != nil && (l.IngressRate > 0 || l.EgressRate > 0)
+}
+
+const (
+	// latency is the maximum time a packet may wait for tokens in the queue
+	// of a token bucket filter, matching the CNI bandwidth plugin.
+	latency = 25 * time.Millisecond
+
+	// timeUnitsPerSec is the number of traffic control time units, which
+	// are microseconds, per second.
+	timeUnitsPerSec = 1000000
+
+	// ifbPrefix is the prefix of the IFB devices shaping the egress
+	// traffic of the pods.
+	ifbPrefix = "ifb-"
+
+	// maxIfnameLen is the maximum length of an interface name.
+	maxIfnameLen = 15
+)
+
+// tbfParams returns the buffer in ticks and the queue limit in bytes of a
+// token bucket filter with the provided rate in bytes per second and burst in
+// bytes. The burst is capped to fit the buffer and limit into the kernel
+// parameters.
+func tbfParams(rate, burst uint64, tickInUsec float64) (buffer, limit uint32) {
+	queued := rate * uint64(latency/time.Microsecond) / timeUnitsPerSec
+	if queued >= math.MaxUint32 {
+		queued = math.MaxUint32 - 1
+	}
+	if maxBurst := uint64(math.MaxUint32) - queued; burst > maxBurst {
+		burst = maxBurst
+	}
+	if maxBurst := uint64(float64(math.MaxUint32) / tickInUsec * float64(rate) / timeUnitsPerSec); burst > maxBurst {
+		burst = maxBurst
+	}
+	ticks := float64(burst) * timeUnitsPerSec / float64(rate) * tickInUsec
+	if ticks > math.MaxUint32 {
+		ticks = math.MaxUint32
+	}
+	return uint32(ticks), uint32(queued + burst)
+}
+
+// tbfBurst returns the burst in bytes of a token bucket filter with the
+// provided rate in bytes per second and buffer in ticks.
+func tbfBurst(rate uint64, buffer uint32, tickInUsec float64) uint64 {
+	return uint64(float64(buffer) / tickInUsec * float64(rate) / timeUnitsPerSec)
+}
+
+// ifbName returns the name of the IFB device shaping the egress traffic of
+// the sandbox with the provided ID.
+func ifbName(id string) string {
+	name := ifbPrefix + id
+	if len(name) > maxIfnameLen {
+		name = name[:maxIfnameLen]
+	}
+	return name
+}
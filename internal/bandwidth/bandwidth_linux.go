@@ -0,0 +1,283 @@
+package bandwidth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// Apply limits the bandwidth of the veth interface ifname within the network
+// namespace netnsPath, replacing the limits applied before. The traffic is
+// shaped on the host side peer of the interface, because a pod granted
+// CAP_NET_ADMIN could remove the traffic control of its own interfaces. The
+// traffic received by the pod is shaped by a token bucket filter on the peer,
+// while the traffic sent by the pod is redirected to the IFB device of the
+// sandbox id and shaped by a token bucket filter on it. Directions which are
+// not limited anymore get their shaping removed.
+func Apply(id, netnsPath, ifname string, limits *Limits) error {
+	if limits == nil {
+		limits = &Limits{}
+	}
+	peer, err := hostPeer(netnsPath, ifname)
+	if err != nil {
+		return err
+	}
+
+	if limits.IngressRate > 0 {
+		if err := replaceTBF(peer, limits.IngressRate/8, limits.IngressBurst/8); err != nil {
+			return fmt.Errorf("limit ingress bandwidth of %s: %w", ifname, err)
+		}
+	} else if err := removeTBF(peer); err != nil {
+		return fmt.Errorf("remove ingress bandwidth limit of %s: %w", ifname, err)
+	}
+
+	if limits.EgressRate > 0 {
+		if err := applyIngress(peer, ifbName(id), limits.EgressRate/8, limits.EgressBurst/8); err != nil {
+			return fmt.Errorf("limit egress bandwidth of %s: %w", ifname, err)
+		}
+	} else if err := removeIngress(peer, ifbName(id)); err != nil {
+		return fmt.Errorf("remove egress bandwidth limit of %s: %w", ifname, err)
+	}
+	return nil
+}
+
+// Current returns the bandwidth limits applied to the veth interface ifname
+// within the network namespace netnsPath of the sandbox id.
+func Current(id, netnsPath, ifname string) (*Limits, error) {
+	peer, err := hostPeer(netnsPath, ifname)
+	if err != nil {
+		return nil, err
+	}
+	limits := &Limits{}
+	tbf, err := rootTBF(peer)
+	if err != nil {
+		return nil, err
+	}
+	if tbf != nil {
+		limits.IngressRate = tbf.Rate * 8
+		limits.IngressBurst = tbfBurst(tbf.Rate, tbf.Buffer, netlink.TickInUsec()) * 8
+	}
+
+	ifb, err := netlink.LinkByName(ifbName(id))
+	if isLinkNotFound(err) {
+		return limits, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get IFB device of %s: %w", ifname, err)
+	}
+	if tbf, err = rootTBF(ifb); err != nil {
+		return nil, err
+	}
+	if tbf != nil {
+		limits.EgressRate = tbf.Rate * 8
+		limits.EgressBurst = tbfBurst(tbf.Rate, tbf.Buffer, netlink.TickInUsec()) * 8
+	}
+	return limits, nil
+}
+
+// Remove removes the bandwidth limits of the veth interface ifname within the
+// network namespace netnsPath of the sandbox id. It succeeds if the network
+// namespace or the interface do not exist anymore.
+func Remove(id, netnsPath, ifname string) error {
+	peer, err := hostPeer(netnsPath, ifname)
+	var notExist ns.NSPathNotExistErr
+	if errors.As(err, &notExist) || isLinkNotFound(err) {
+		// The host side peer gets deleted together with the interface.
+		return deleteIFB(ifbName(id))
+	}
+	if err != nil {
+		return err
+	}
+	if err := removeTBF(peer); err != nil {
+		return fmt.Errorf("remove ingress bandwidth limit of %s: %w", ifname, err)
+	}
+	if err := removeIngress(peer, ifbName(id)); err != nil {
+		return fmt.Errorf("remove egress bandwidth limit of %s: %w", ifname, err)
+	}
+	return nil
+}
+
+// hostPeer returns the peer of the veth interface ifname within the network
+// namespace netnsPath, which has to reside in the current network namespace.
+func hostPeer(netnsPath, ifname string) (netlink.Link, error) {
+	var index, peerIndex int
+	err := ns.WithNetNSPath(netnsPath, func(ns.NetNS) error {
+		link, err := netlink.LinkByName(ifname)
+		if err != nil {
+			return fmt.Errorf("get interface %s: %w", ifname, err)
+		}
+		if _, ok := link.(*netlink.Veth); !ok {
+			return fmt.Errorf("interface %s is a %s device instead of a veth device", ifname, link.Type())
+		}
+		index, peerIndex = link.Attrs().Index, link.Attrs().ParentIndex
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	peer, err := netlink.LinkByIndex(peerIndex)
+	if err != nil {
+		return nil, fmt.Errorf("get host side peer of %s: %w", ifname, err)
+	}
+	if _, ok := peer.(*netlink.Veth); !ok || peer.Attrs().ParentIndex != index {
+		return nil, fmt.Errorf("host side peer of %s not found in the host network namespace", ifname)
+	}
+	return peer, nil
+}
+
+// applyIngress redirects the ingress traffic of the link to the IFB device
+// name and limits the bandwidth of the IFB device.
+func applyIngress(link netlink.Link, name string, rate, burst uint64) error {
+	ifb, err := netlink.LinkByName(name)
+	if isLinkNotFound(err) {
+		err = netlink.LinkAdd(&netlink.Ifb{
+			LinkAttrs: netlink.LinkAttrs{
+				Name:  name,
+				MTU:   link.Attrs().MTU,
+				Flags: unix.IFF_UP,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("create IFB device %s: %w", name, err)
+		}
+		ifb, err = netlink.LinkByName(name)
+	}
+	if err != nil {
+		return fmt.Errorf("get IFB device %s: %w", name, err)
+	}
+	if err := netlink.LinkSetUp(ifb); err != nil {
+		return fmt.Errorf("set IFB device %s up: %w", name, err)
+	}
+	if err := replaceTBF(ifb, rate, burst); err != nil {
+		return err
+	}
+
+	// Recreating the ingress qdisc drops the filters attached to it before,
+	// which would be duplicated otherwise.
+	if err := deleteIngressQdisc(link); err != nil {
+		return err
+	}
+	ingress := ingressQdisc(link)
+	if err := netlink.QdiscAdd(ingress); err != nil {
+		return fmt.Errorf("add ingress qdisc: %w", err)
+	}
+	filter := &netlink.U32{
+		FilterAttrs: netlink.FilterAttrs{
+			LinkIndex: link.Attrs().Index,
+			Parent:    ingress.Handle,
+			Priority:  1,
+			Protocol:  unix.ETH_P_ALL,
+		},
+		ClassId: netlink.MakeHandle(1, 1),
+		Actions: []netlink.Action{netlink.NewMirredAction(ifb.Attrs().Index)},
+	}
+	if err := netlink.FilterAdd(filter); err != nil {
+		return fmt.Errorf("redirect ingress traffic to %s: %w", name, err)
+	}
+	return nil
+}
+
+// removeIngress stops redirecting the ingress traffic of the link and
+// deletes the IFB device name.
+func removeIngress(link netlink.Link, name string) error {
+	if err := deleteIngressQdisc(link); err != nil {
+		return err
+	}
+	return deleteIFB(name)
+}
+
+func ingressQdisc(link netlink.Link) *netlink.Ingress {
+	return &netlink.Ingress{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(0xffff, 0),
+			Parent:    netlink.HANDLE_INGRESS,
+		},
+	}
+}
+
+func deleteIngressQdisc(link netlink.Link) error {
+	qdiscs, err := netlink.QdiscList(link)
+	if err != nil {
+		return fmt.Errorf("list qdiscs of %s: %w", link.Attrs().Name, err)
+	}
+	for _, qdisc := range qdiscs {
+		if _, ok := qdisc.(*netlink.Ingress); !ok {
+			continue
+		}
+		if err := netlink.QdiscDel(ingressQdisc(link)); err != nil {
+			return fmt.Errorf("delete ingress qdisc of %s: %w", link.Attrs().Name, err)
+		}
+	}
+	return nil
+}
+
+func deleteIFB(name string) error {
+	ifb, err := netlink.LinkByName(name)
+	if isLinkNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get IFB device %s: %w", name, err)
+	}
+	if err := netlink.LinkDel(ifb); err != nil {
+		return fmt.Errorf("delete IFB device %s: %w", name, err)
+	}
+	return nil
+}
+
+// replaceTBF replaces the root qdisc of the link by a token bucket filter
+// with the provided rate in bytes per second and burst in bytes.
+func replaceTBF(link netlink.Link, rate, burst uint64) error {
+	buffer, limit := tbfParams(rate, burst, netlink.TickInUsec())
+	tbf := &netlink.Tbf{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(1, 0),
+			Parent:    netlink.HANDLE_ROOT,
+		},
+		Rate:   rate,
+		Limit:  limit,
+		Buffer: buffer,
+	}
+	if err := netlink.QdiscReplace(tbf); err != nil {
+		return fmt.Errorf("replace root qdisc of %s: %w", link.Attrs().Name, err)
+	}
+	return nil
+}
+
+// removeTBF removes the token bucket filter of the link, which restores the
+// default root qdisc. Other root qdiscs are kept.
+func removeTBF(link netlink.Link) error {
+	tbf, err := rootTBF(link)
+	if err != nil || tbf == nil {
+		return err
+	}
+	if err := netlink.QdiscDel(tbf); err != nil {
+		return fmt.Errorf("delete root qdisc of %s: %w", link.Attrs().Name, err)
+	}
+	return nil
+}
+
+// rootTBF returns the token bucket filter which is the root qdisc of the
+// link, or nil if there is none.
+func rootTBF(link netlink.Link) (*netlink.Tbf, error) {
+	qdiscs, err := netlink.QdiscList(link)
+	if err != nil {
+		return nil, fmt.Errorf("list qdiscs of %s: %w", link.Attrs().Name, err)
+	}
+	for _, qdisc := range qdiscs {
+		if tbf, ok := qdisc.(*netlink.Tbf); ok && tbf.Parent == netlink.HANDLE_ROOT {
+			return tbf, nil
+		}
+	}
+	return nil, nil
+}
+
+func isLinkNotFound(err error) bool {
+	var notFound netlink.LinkNotFoundError
+	return errors.As(err, &notFound)
+}
@@ -0,0 +1,143 @@
+package bandwidth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// newNetNS creates a network namespace bind mounted into a temporary
+// directory, which gets removed when the test finishes.
+func newNetNS(t *testing.T) ns.NetNS {
+	path := filepath.Join(t.TempDir(), "netns")
+	require.NoError(t, os.WriteFile(path, nil, 0o644))
+
+	errCh := make(chan error)
+	go func() {
+		// The thread is not unlocked, which terminates it together with the
+		// goroutine instead of reusing it in the new network namespace.
+		runtime.LockOSThread()
+		if err := unix.Unshare(unix.CLONE_NEWNET); err != nil {
+			errCh <- err
+			return
+		}
+		errCh <- unix.Mount("/proc/thread-self/ns/net", path, "", unix.MS_BIND, "")
+	}()
+	if err := <-errCh; err != nil {
+		t.Skipf("unable to create network namespace: %v", err)
+	}
+	netns, err := ns.GetNS(path)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		netns.Close()
+		assert.NoError(t, unix.Unmount(path, unix.MNT_DETACH))
+	})
+	return netns
+}
+
+func TestApplyCurrentRemove(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("test requires root")
+	}
+	const id = "0123456789abcdef"
+	hostNS, podNS := newNetNS(t), newNetNS(t)
+
+	limits := &Limits{
+		IngressRate:  1000000,
+		IngressBurst: 262144,
+		EgressRate:   2000000,
+		EgressBurst:  524288,
+	}
+	var current, ingressOnly, removed *Limits
+	var podQdiscs []netlink.Qdisc
+	var ifbAfterUpdate, ifbAfterRemove error
+	err := hostNS.Do(func(ns.NetNS) error {
+		if err := netlink.LinkAdd(&netlink.Veth{
+			LinkAttrs:     netlink.LinkAttrs{Name: "veth-test"},
+			PeerName:      "eth0",
+			PeerNamespace: netlink.NsFd(int(podNS.Fd())),
+		}); err != nil {
+			return err
+		}
+
+		if err := Apply(id, podNS.Path(), "eth0", limits); err != nil {
+			return err
+		}
+		var err error
+		if current, err = Current(id, podNS.Path(), "eth0"); err != nil {
+			return err
+		}
+		err = podNS.Do(func(ns.NetNS) error {
+			link, err := netlink.LinkByName("eth0")
+			if err != nil {
+				return err
+			}
+			podQdiscs, err = netlink.QdiscList(link)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := Apply(id, podNS.Path(), "eth0", &Limits{
+			IngressRate:  limits.IngressRate,
+			IngressBurst: limits.IngressBurst,
+		}); err != nil {
+			return err
+		}
+		if ingressOnly, err = Current(id, podNS.Path(), "eth0"); err != nil {
+			return err
+		}
+		_, ifbAfterUpdate = netlink.LinkByName(ifbName(id))
+
+		if err := Remove(id, podNS.Path(), "eth0"); err != nil {
+			return err
+		}
+		if removed, err = Current(id, podNS.Path(), "eth0"); err != nil {
+			return err
+		}
+		_, ifbAfterRemove = netlink.LinkByName(ifbName(id))
+		return nil
+	})
+	if errors.Is(err, unix.EOPNOTSUPP) || errors.Is(err, unix.ENOENT) {
+		t.Skipf("traffic control is not supported: %v", err)
+	}
+	require.NoError(t, err)
+
+	assert.Equal(t, limits.IngressRate, current.IngressRate)
+	assert.InEpsilon(t, limits.IngressBurst, current.IngressBurst, 0.01)
+	assert.Equal(t, limits.EgressRate, current.EgressRate)
+	assert.InEpsilon(t, limits.EgressBurst, current.EgressBurst, 0.01)
+	for _, qdisc := range podQdiscs {
+		assert.NotEqual(t, "tbf", qdisc.Type(), "pod interface must not be shaped")
+		assert.NotEqual(t, "ingress", qdisc.Type(), "pod interface must not be shaped")
+	}
+
+	assert.Equal(t, limits.IngressRate, ingressOnly.IngressRate)
+	assert.Zero(t, ingressOnly.EgressRate)
+	assert.True(t, isLinkNotFound(ifbAfterUpdate))
+
+	assert.False(t, removed.Limited())
+	assert.True(t, isLinkNotFound(ifbAfterRemove))
+
+	// The limits of deleted network namespaces are removed as well.
+	assert.NoError(t, Remove(id, filepath.Join(t.TempDir(), "missing"), "eth0"))
+}
+
+func TestApplyRejectsNonVeth(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("test requires root")
+	}
+	podNS := newNetNS(t)
+
+	err := Apply("0123456789abcdef", podNS.Path(), "lo", &Limits{IngressRate: 1000000})
+	assert.ErrorContains(t, err, "instead of a veth device")
+}
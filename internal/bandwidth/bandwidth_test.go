@@ -0,0 +1,46 @@
+package bandwidth
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tickInUsec is the number of ticks per microsecond of common kernels.
+const tickInUsec = 15.625
+
+func TestTBFParams(t *testing.T) {
+	// 1 Mbit/s with a 32 KiB burst
+	buffer, limit := tbfParams(125000, 32768, tickInUsec)
+	assert.Equal(t, uint32(4096000), buffer)
+	assert.Equal(t, uint32(3125+32768), limit)
+	assert.Equal(t, uint64(32768), tbfBurst(125000, buffer, tickInUsec))
+}
+
+func TestTBFParamsCapsBurst(t *testing.T) {
+	// the 4 GiB burst of the annotations does not fit the buffer
+	buffer, limit := tbfParams(125000, math.MaxUint32-1, tickInUsec)
+	assert.InDelta(t, math.MaxUint32, buffer, 100)
+	burst := tbfBurst(125000, buffer, tickInUsec)
+	assert.Greater(t, burst, uint64(0))
+	assert.Less(t, burst, uint64(math.MaxUint32-1))
+	assert.Equal(t, uint32(3125), limit-uint32(burst))
+
+	// fast rates are capped by the limit
+	buffer, limit = tbfParams(125000000000, math.MaxUint32-1, tickInUsec)
+	assert.Equal(t, uint32(math.MaxUint32), limit)
+	assert.Less(t, tbfBurst(125000000000, buffer, tickInUsec), uint64(math.MaxUint32))
+}
+
+func TestIFBName(t *testing.T) {
+	assert.Equal(t, "ifb-1234", ifbName("1234"))
+	assert.Equal(t, "ifb-0123456789a", ifbName("0123456789abcdef"))
+}
+
+func TestLimited(t *testing.T) {
+	assert.False(t, (*Limits)(nil).Limited())
+	assert.False(t, (&Limits{IngressBurst: 1}).Limited())
+	assert.True(t, (&Limits{EgressRate: 1}).Limited())
+	assert.True(t, (&Limits{IngressRate: 1}).Limited())
+}
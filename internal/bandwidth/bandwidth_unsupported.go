@@ -0,0 +1,23 @@
+//go:build !linux
+// +build !linux
+
+package bandwidth
+
+import "errors"
+
+var errUnsupported = errors.New("bandwidth shaping is not supported on this platform")
+
+// Apply is not supported on this platform.
+func Apply(string, string, string, *Limits) error {
+	return errUnsupported
+}
+
+// Current is not supported on this platform.
+func Current(string, string, string) (*Limits, error) {
+	return nil, errUnsupported
+}
+
+// Remove does nothing on this platform.
+func Remove(string, string, string) error {
+	return nil
+}
@@ -2,10 +2,12 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"strings"
 	"syscall"
 	"time"
 
@@ -23,6 +25,10 @@ type CrioClient interface {
 	DaemonInfo() (types.CrioInfo, error)
 	ContainerInfo(string) (*types.ContainerInfo, error)
 	ConfigInfo() (string, error)
+	UsernsInfo() (*types.UsernsInfo, error)
+	SELinuxInfo() (*types.SELinuxInfo, error)
+	NetworkInfo(string) (*types.SandboxNetworkInfo, error)
+	HostportsInfo() (*types.HostportsInfo, error)
 }
 
 type crioClientImpl struct {
@@ -123,3 +129,86 @@ func (c *crioClientImpl) ConfigInfo() (string, error) {
 	}
 	return string(body), nil
 }
+
+// UsernsInfo returns the user namespace ranges assigned to pods by querying
+// the cri-o userns endpoint.
+func (c *crioClientImpl) UsernsInfo() (*types.UsernsInfo, error) {
+	req, err := c.getRequest(server.InspectUsernsEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	info := types.UsernsInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// SELinuxInfo returns the SELinux MCS levels reserved for pods by querying the
+// cri-o selinux endpoint.
+func (c *crioClientImpl) SELinuxInfo() (*types.SELinuxInfo, error) {
+	req, err := c.getRequest(server.InspectSELinuxEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	info := types.SELinuxInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// NetworkInfo returns the network state of a pod sandbox by querying the cri-o
+// network endpoint.
+func (c *crioClientImpl) NetworkInfo(id string) (*types.SandboxNetworkInfo, error) {
+	req, err := c.getRequest(server.InspectNetworkEndpoint + "/" + id)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, errors.New(strings.TrimSpace(string(body)))
+	}
+	info := types.SandboxNetworkInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// HostportsInfo returns the host ports allocated on the node by querying the
+// cri-o hostports endpoint.
+func (c *crioClientImpl) HostportsInfo() (*types.HostportsInfo, error) {
+	req, err := c.getRequest(server.InspectHostportsEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	info := types.HostportsInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
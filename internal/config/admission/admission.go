@@ -0,0 +1,322 @@
+package admission
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Config is the node-local admission policy configuration.
+type Config struct {
+	lock   sync.RWMutex
+	dryRun bool
+	rules  []*compiledRule
+}
+
+// New creates a new admission policy configuration, which admits every
+// request.
+func New() *Config {
+	return &Config{}
+}
+
+// Enabled returns true if an admission policy with at least one rule is
+// loaded.
+func (c *Config) Enabled() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return len(c.rules) > 0
+}
+
+// Load loads and validates the admission policy from the provided path. An
+// empty path disables the admission policy. The previous policy stays active
+// if loading fails.
+func (c *Config) Load(path string) error {
+	if path == "" {
+		logrus.Info("No admission policy file specified, admission policy not configured")
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		c.dryRun = false
+		c.rules = nil
+		return nil
+	}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("reading admission policy file failed: %w", err)
+	}
+
+	policy := &Policy{}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return fmt.Errorf("parsing admission policy failed: %w", err)
+	}
+
+	rules := make([]*compiledRule, 0, len(policy.Rules))
+	names := make(map[string]bool)
+	for i, rule := range policy.Rules {
+		if rule == nil {
+			return fmt.Errorf("admission policy rule %d is empty", i)
+		}
+		compiled, err := rule.compile()
+		if err != nil {
+			return fmt.Errorf("invalid admission policy: %w", err)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("invalid admission policy: duplicate rule name %q", rule.Name)
+		}
+		names[rule.Name] = true
+		rules = append(rules, compiled)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.dryRun = policy.DryRun
+	c.rules = rules
+	logrus.Infof("Admission policy successfully loaded from %q (%d rules, dry run: %v)", path, len(rules), policy.DryRun)
+	return nil
+}
+
+// Request contains the attributes of a request evaluated by the admission
+// policy.
+type Request struct {
+	// Operation is either OperationRunPodSandbox or OperationCreateContainer.
+	Operation string
+
+	// Namespace is the namespace of the pod.
+	Namespace string
+
+	// Name is the name of the pod or container.
+	Name string
+
+	// Images are the requested image of a container and its resolved names.
+	Images []string
+
+	// RuntimeHandler is the runtime handler of the pod.
+	RuntimeHandler string
+
+	// Privileged is true for privileged pods and containers.
+	Privileged bool
+
+	// HostNetwork, HostPID and HostIPC are true if the respective host
+	// namespace gets used.
+	HostNetwork, HostPID, HostIPC bool
+
+	// Capabilities are the capabilities added to a container.
+	Capabilities []string
+
+	// HostPaths are the host paths of the mounts of a container.
+	HostPaths []string
+
+	// Devices are the host paths of the devices of a container.
+	Devices []string
+}
+
+// HostNamespaces returns true if any host namespace gets used.
+func (r *Request) HostNamespaces() bool {
+	return r.HostNetwork || r.HostPID || r.HostIPC
+}
+
+// NewSandboxRequest creates the admission request for the provided pod
+// sandbox configuration.
+func NewSandboxRequest(config *types.PodSandboxConfig, runtimeHandler string) *Request {
+	req := &Request{
+		Operation:      OperationRunPodSandbox,
+		Namespace:      config.GetMetadata().GetNamespace(),
+		Name:           config.GetMetadata().GetName(),
+		RuntimeHandler: runtimeHandler,
+	}
+	securityContext := config.GetLinux().GetSecurityContext()
+	req.Privileged = securityContext.GetPrivileged()
+	setHostNamespaces(req, securityContext.GetNamespaceOptions())
+	return req
+}
+
+// NewContainerRequest creates the admission request for the provided
+// container configuration.
+func NewContainerRequest(config *types.ContainerConfig, namespace, runtimeHandler string, images []string) *Request {
+	req := &Request{
+		Operation:      OperationCreateContainer,
+		Namespace:      namespace,
+		Name:           config.GetMetadata().GetName(),
+		Images:         images,
+		RuntimeHandler: runtimeHandler,
+	}
+	securityContext := config.GetLinux().GetSecurityContext()
+	req.Privileged = securityContext.GetPrivileged()
+	setHostNamespaces(req, securityContext.GetNamespaceOptions())
+	req.Capabilities = securityContext.GetCapabilities().GetAddCapabilities()
+	for _, m := range config.GetMounts() {
+		req.HostPaths = append(req.HostPaths, filepath.Clean(m.HostPath))
+	}
+	for _, d := range config.GetDevices() {
+		req.Devices = append(req.Devices, filepath.Clean(d.HostPath))
+	}
+	return req
+}
+
+func setHostNamespaces(req *Request, options *types.NamespaceOption) {
+	req.HostNetwork = options.GetNetwork() == types.NamespaceMode_NODE
+	req.HostPID = options.GetPid() == types.NamespaceMode_NODE
+	req.HostIPC = options.GetIpc() == types.NamespaceMode_NODE
+}
+
+// Result is a rule matching a request.
+type Result struct {
+	// Rule is the name of the matching rule.
+	Rule string
+
+	// Action is the action of the matching rule.
+	Action string
+
+	// Message is the message of the matching rule.
+	Message string
+
+	// DryRun is true if the result does not get applied.
+	DryRun bool
+
+	mutation *Mutation
+}
+
+// Decision is the outcome of evaluating a request.
+type Decision struct {
+	// Results are the matching rules in their order, up to the first
+	// enforced denial.
+	Results []*Result
+}
+
+// Denial returns the result of the rule denying the request, or nil if the
+// request is admitted.
+func (d *Decision) Denial() *Result {
+	for _, r := range d.Results {
+		if r.Action == ActionDeny && !r.DryRun {
+			return r
+		}
+	}
+	return nil
+}
+
+// Reason returns the reason of the provided denial.
+func (r *Result) Reason() string {
+	msg := fmt.Sprintf("denied by admission policy rule %q", r.Rule)
+	if r.Message != "" {
+		msg += ": " + r.Message
+	}
+	return msg
+}
+
+// Evaluate evaluates the admission policy for the provided request.
+func (c *Config) Evaluate(req *Request) *Decision {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	decision := &Decision{}
+	for _, rule := range c.rules {
+		if !rule.matches(req) {
+			continue
+		}
+		result := &Result{
+			Rule:     rule.Name,
+			Action:   rule.Action,
+			Message:  rule.Message,
+			DryRun:   c.dryRun || rule.DryRun,
+			mutation: rule.Mutation,
+		}
+		decision.Results = append(decision.Results, result)
+		if result.Action == ActionDeny && !result.DryRun {
+			break
+		}
+	}
+	return decision
+}
+
+// MutateSandbox applies the enforced mutations of the decision to the
+// provided pod sandbox configuration.
+func (d *Decision) MutateSandbox(config *types.PodSandboxConfig) {
+	for _, m := range d.mutations() {
+		if len(m.Annotations) > 0 && config.Annotations == nil {
+			config.Annotations = make(map[string]string)
+		}
+		for k, v := range m.Annotations {
+			config.Annotations[k] = v
+		}
+	}
+}
+
+// MutateContainer applies the enforced mutations of the decision to the
+// provided container configuration.
+func (d *Decision) MutateContainer(config *types.ContainerConfig) {
+	for _, m := range d.mutations() {
+		if len(m.Annotations) > 0 && config.Annotations == nil {
+			config.Annotations = make(map[string]string)
+		}
+		for k, v := range m.Annotations {
+			config.Annotations[k] = v
+		}
+
+		if len(m.DropCapabilities) == 0 && !m.ReadOnlyRootFilesystem && !m.NoNewPrivileges {
+			continue
+		}
+		if config.Linux == nil {
+			config.Linux = &types.LinuxContainerConfig{}
+		}
+		if config.Linux.SecurityContext == nil {
+			config.Linux.SecurityContext = &types.LinuxContainerSecurityContext{}
+		}
+		securityContext := config.Linux.SecurityContext
+		if m.ReadOnlyRootFilesystem {
+			securityContext.ReadonlyRootfs = true
+		}
+		if m.NoNewPrivileges {
+			securityContext.NoNewPrivs = true
+		}
+		if len(m.DropCapabilities) > 0 {
+			if securityContext.Capabilities == nil {
+				securityContext.Capabilities = &types.Capability{}
+			}
+			dropCapabilities(securityContext.Capabilities, m.DropCapabilities)
+		}
+	}
+}
+
+// mutations returns the enforced mutations of the decision.
+func (d *Decision) mutations() []*Mutation {
+	res := []*Mutation{}
+	for _, r := range d.Results {
+		if r.Action == ActionMutate && !r.DryRun && r.mutation != nil {
+			res = append(res, r.mutation)
+		}
+	}
+	return res
+}
+
+// dropCapabilities removes the provided capabilities from the added ones and
+// adds them to the dropped ones.
+func dropCapabilities(capabilities *types.Capability, drop []string) {
+	toDrop := make(map[string]bool, len(drop))
+	for _, capability := range drop {
+		toDrop[normalizeCapability(capability)] = true
+	}
+
+	added := make([]string, 0, len(capabilities.AddCapabilities))
+	for _, capability := range capabilities.AddCapabilities {
+		if !toDrop[normalizeCapability(capability)] {
+			added = append(added, capability)
+		}
+	}
+	capabilities.AddCapabilities = added
+
+	dropped := make(map[string]bool, len(capabilities.DropCapabilities))
+	for _, capability := range capabilities.DropCapabilities {
+		dropped[normalizeCapability(capability)] = true
+	}
+	for _, capability := range drop {
+		if !dropped[normalizeCapability(capability)] {
+			capabilities.DropCapabilities = append(capabilities.DropCapabilities, capability)
+			dropped[normalizeCapability(capability)] = true
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package admission_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/cri-o/cri-o/internal/config/admission"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+var _ = t.Describe("Admission", func() {
+	var (
+		sut        *admission.Config
+		policyPath string
+	)
+
+	writePolicy := func(policy string) {
+		Expect(os.WriteFile(policyPath, []byte(policy), 0o644)).To(Succeed())
+	}
+
+	containerConfig := func() *types.ContainerConfig {
+		return &types.ContainerConfig{
+			Metadata: &types.ContainerMetadata{Name: "ctr"},
+			Image:    &types.ImageSpec{Image: "docker.io/library/nginx:latest"},
+			Linux: &types.LinuxContainerConfig{
+				SecurityContext: &types.LinuxContainerSecurityContext{
+					Capabilities: &types.Capability{
+						AddCapabilities: []string{"NET_RAW", "CAP_SYS_ADMIN"},
+					},
+				},
+			},
+			Mounts: []*types.Mount{{HostPath: "/var/run/docker.sock/"}},
+		}
+	}
+
+	BeforeEach(func() {
+		sut = admission.New()
+		policyPath = filepath.Join(t.MustTempDir("admission"), "policy.yaml")
+	})
+
+	t.Describe("Load", func() {
+		It("should be disabled by default", func() {
+			// Given
+			// When
+			err := sut.Load("")
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sut.Enabled()).To(BeFalse())
+		})
+
+		It("should succeed to load a valid policy", func() {
+			// Given
+			writePolicy(`
+rules:
+- name: deny-privileged
+  operations: [CreateContainer]
+  action: deny
+  match:
+    privileged: true
+`)
+
+			// When
+			err := sut.Load(policyPath)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sut.Enabled()).To(BeTrue())
+		})
+
+		It("should disable the policy for an empty path", func() {
+			// Given
+			writePolicy("rules: [{name: deny, action: deny}]")
+			Expect(sut.Load(policyPath)).To(Succeed())
+
+			// When
+			err := sut.Load("")
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sut.Enabled()).To(BeFalse())
+		})
+
+		It("should keep the previous policy if loading fails", func() {
+			// Given
+			writePolicy("rules: [{name: deny, action: deny}]")
+			Expect(sut.Load(policyPath)).To(Succeed())
+			writePolicy("rules: [{name: deny, action: invalid}]")
+
+			// When
+			err := sut.Load(policyPath)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(sut.Enabled()).To(BeTrue())
+		})
+
+		DescribeTable("should fail to load invalid policies",
+			func(policy, expected string) {
+				// Given
+				writePolicy(policy)
+
+				// When
+				err := sut.Load(policyPath)
+
+				// Then
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(expected))
+			},
+			Entry("unknown field", "rules: [{name: deny, action: deny, unknown: true}]", "unknown field"),
+			Entry("missing name", "rules: [{action: deny}]", "rule name is empty"),
+			Entry("duplicate name", "rules: [{name: deny, action: deny}, {name: deny, action: deny}]", "duplicate rule name"),
+			Entry("unknown action", "rules: [{name: deny, action: allow}]", "unknown action"),
+			Entry("unknown operation", "rules: [{name: deny, action: deny, operations: [ExecSync]}]", "unknown operation"),
+			Entry("deny with mutation", "rules: [{name: deny, action: deny, mutation: {noNewPrivileges: true}}]", "mutation is not supported"),
+			Entry("mutate without mutation", "rules: [{name: mutate, action: mutate}]", "mutation is required"),
+		)
+
+		It("should fail if the file does not exist", func() {
+			// Given
+			// When
+			err := sut.Load(policyPath)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(sut.Enabled()).To(BeFalse())
+		})
+	})
+
+	t.Describe("Evaluate", func() {
+		It("should deny matching requests", func() {
+			// Given
+			writePolicy(`
+rules:
+- name: deny-docker-socket
+  action: deny
+  message: no docker socket
+  match:
+    excludeNamespaces: ["kube-*"]
+    images: ["docker.io/*"]
+    hostPaths: ["/var/run/docker.sock"]
+`)
+			Expect(sut.Load(policyPath)).To(Succeed())
+			config := containerConfig()
+
+			// When
+			decision := sut.Evaluate(admission.NewContainerRequest(
+				config, "default", "", []string{config.Image.Image},
+			))
+
+			// Then
+			denial := decision.Denial()
+			Expect(denial).NotTo(BeNil())
+			Expect(denial.Rule).To(Equal("deny-docker-socket"))
+			Expect(denial.Reason()).To(ContainSubstring("no docker socket"))
+		})
+
+		It("should admit requests not matching all fields", func() {
+			// Given
+			writePolicy(`
+rules:
+- name: deny-docker-socket
+  action: deny
+  match:
+    excludeNamespaces: ["kube-*"]
+    hostPaths: ["/var/run/docker.sock"]
+`)
+			Expect(sut.Load(policyPath)).To(Succeed())
+
+			// When
+			decision := sut.Evaluate(admission.NewContainerRequest(
+				containerConfig(), "kube-system", "", nil,
+			))
+
+			// Then
+			Expect(decision.Denial()).To(BeNil())
+			Expect(decision.Results).To(BeEmpty())
+		})
+
+		It("should match capabilities with and without prefix", func() {
+			// Given
+			writePolicy(`
+rules:
+- name: deny-sys-admin
+  operations: [CreateContainer]
+  action: deny
+  match:
+    capabilities: ["sys_admin"]
+`)
+			Expect(sut.Load(policyPath)).To(Succeed())
+
+			// When
+			decision := sut.Evaluate(admission.NewContainerRequest(
+				containerConfig(), "default", "", nil,
+			))
+
+			// Then
+			Expect(decision.Denial()).NotTo(BeNil())
+		})
+
+		It("should match pod sandboxes using host namespaces", func() {
+			// Given
+			writePolicy(`
+rules:
+- name: deny-host-namespaces
+  operations: [RunPodSandbox]
+  action: deny
+  match:
+    runtimeHandlers: [""]
+    hostNamespaces: true
+`)
+			Expect(sut.Load(policyPath)).To(Succeed())
+			config := &types.PodSandboxConfig{
+				Metadata: &types.PodSandboxMetadata{Name: "pod", Namespace: "default"},
+				Linux: &types.LinuxPodSandboxConfig{
+					SecurityContext: &types.LinuxSandboxSecurityContext{
+						NamespaceOptions: &types.NamespaceOption{Pid: types.NamespaceMode_NODE},
+					},
+				},
+			}
+
+			// When
+			hostDecision := sut.Evaluate(admission.NewSandboxRequest(config, ""))
+			otherDecision := sut.Evaluate(admission.NewSandboxRequest(config, "kata"))
+
+			// Then
+			Expect(hostDecision.Denial()).NotTo(BeNil())
+			Expect(otherDecision.Denial()).To(BeNil())
+		})
+
+		It("should not enforce dry run rules", func() {
+			// Given
+			writePolicy(`
+rules:
+- name: deny-all
+  action: deny
+  dryRun: true
+- name: drop-net-raw
+  action: mutate
+  mutation:
+    dropCapabilities: [NET_RAW]
+`)
+			Expect(sut.Load(policyPath)).To(Succeed())
+
+			// When
+			decision := sut.Evaluate(admission.NewContainerRequest(
+				containerConfig(), "default", "", nil,
+			))
+
+			// Then
+			Expect(decision.Denial()).To(BeNil())
+			Expect(decision.Results).To(HaveLen(2))
+			Expect(decision.Results[0].DryRun).To(BeTrue())
+			Expect(decision.Results[1].DryRun).To(BeFalse())
+		})
+
+		It("should not enforce any rule of a dry run policy", func() {
+			// Given
+			writePolicy(`
+dryRun: true
+rules:
+- name: deny-all
+  action: deny
+`)
+			Expect(sut.Load(policyPath)).To(Succeed())
+
+			// When
+			decision := sut.Evaluate(admission.NewContainerRequest(
+				containerConfig(), "default", "", nil,
+			))
+
+			// Then
+			Expect(decision.Denial()).To(BeNil())
+			Expect(decision.Results).To(HaveLen(1))
+			Expect(decision.Results[0].DryRun).To(BeTrue())
+		})
+
+		It("should stop evaluating at the first denial", func() {
+			// Given
+			writePolicy(`
+rules:
+- name: first
+  action: deny
+- name: second
+  action: deny
+`)
+			Expect(sut.Load(policyPath)).To(Succeed())
+
+			// When
+			decision := sut.Evaluate(admission.NewContainerRequest(
+				containerConfig(), "default", "", nil,
+			))
+
+			// Then
+			Expect(decision.Results).To(HaveLen(1))
+			Expect(decision.Denial().Rule).To(Equal("first"))
+		})
+	})
+
+	t.Describe("Mutate", func() {
+		It("should mutate containers", func() {
+			// Given
+			writePolicy(`
+rules:
+- name: restrict
+  action: mutate
+  mutation:
+    dropCapabilities: [CAP_NET_RAW, SYS_ADMIN]
+    readOnlyRootFilesystem: true
+    noNewPrivileges: true
+    annotations:
+      example.com/restricted: "true"
+- name: dry-run
+  action: mutate
+  dryRun: true
+  mutation:
+    annotations:
+      example.com/dry-run: "true"
+`)
+			Expect(sut.Load(policyPath)).To(Succeed())
+			config := containerConfig()
+			decision := sut.Evaluate(admission.NewContainerRequest(config, "default", "", nil))
+
+			// When
+			decision.MutateContainer(config)
+
+			// Then
+			securityContext := config.Linux.SecurityContext
+			Expect(securityContext.ReadonlyRootfs).To(BeTrue())
+			Expect(securityContext.NoNewPrivs).To(BeTrue())
+			Expect(securityContext.Capabilities.AddCapabilities).To(BeEmpty())
+			Expect(securityContext.Capabilities.DropCapabilities).To(
+				Equal([]string{"CAP_NET_RAW", "SYS_ADMIN"}),
+			)
+			Expect(config.Annotations).To(Equal(map[string]string{
+				"example.com/restricted": "true",
+			}))
+		})
+
+		It("should mutate pod sandboxes", func() {
+			// Given
+			writePolicy(`
+rules:
+- name: annotate
+  operations: [RunPodSandbox]
+  action: mutate
+  mutation:
+    annotations:
+      example.com/annotated: "true"
+`)
+			Expect(sut.Load(policyPath)).To(Succeed())
+			config := &types.PodSandboxConfig{
+				Metadata: &types.PodSandboxMetadata{Name: "pod", Namespace: "default"},
+			}
+			decision := sut.Evaluate(admission.NewSandboxRequest(config, ""))
+
+			// When
+			decision.MutateSandbox(config)
+
+			// Then
+			Expect(config.Annotations).To(HaveKeyWithValue("example.com/annotated", "true"))
+		})
+	})
+})
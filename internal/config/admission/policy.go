@@ -0,0 +1,258 @@
+package admission
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// OperationRunPodSandbox is the operation of RunPodSandbox requests.
+	OperationRunPodSandbox = "RunPodSandbox"
+
+	// OperationCreateContainer is the operation of CreateContainer requests.
+	OperationCreateContainer = "CreateContainer"
+
+	// ActionDeny denies matching requests.
+	ActionDeny = "deny"
+
+	// ActionMutate mutates matching requests.
+	ActionMutate = "mutate"
+)
+
+// Policy is the node-local admission policy.
+type Policy struct {
+	// DryRun only logs and counts denials and mutations of all rules
+	// without applying them.
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Rules are evaluated in their order for every request.
+	Rules []*Rule `json:"rules,omitempty"`
+}
+
+// Rule denies or mutates the requests it matches.
+type Rule struct {
+	// Name identifies the rule in logs, errors and metrics.
+	Name string `json:"name"`
+
+	// Operations restricts the rule to the provided operations. The rule
+	// applies to all operations if empty.
+	Operations []string `json:"operations,omitempty"`
+
+	// Match selects the requests the rule applies to.
+	Match Match `json:"match,omitempty"`
+
+	// Action is either "deny" or "mutate".
+	Action string `json:"action"`
+
+	// Message is part of the error returned for denied requests.
+	Message string `json:"message,omitempty"`
+
+	// Mutation is applied to the requests matched by mutate rules.
+	Mutation *Mutation `json:"mutation,omitempty"`
+
+	// DryRun only logs and counts denials and mutations of the rule without
+	// applying them.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// Match selects requests. All specified fields have to match, while a list
+// matches if any of its entries matches. Patterns support "*" as wildcard for
+// any sequence of characters.
+type Match struct {
+	// Namespaces are patterns of the matching pod namespaces.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// ExcludeNamespaces are patterns of pod namespaces which never match.
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
+
+	// Images are patterns of the matching container images, compared to the
+	// requested image as well as its tags and digests.
+	Images []string `json:"images,omitempty"`
+
+	// RuntimeHandlers are patterns of the matching runtime handlers. The
+	// default runtime handler is the empty string.
+	RuntimeHandlers []string `json:"runtimeHandlers,omitempty"`
+
+	// Privileged matches privileged pods or containers if true and
+	// unprivileged ones if false.
+	Privileged *bool `json:"privileged,omitempty"`
+
+	// HostNamespaces matches if the request uses any of the host network,
+	// PID or IPC namespaces if true and none if false.
+	HostNamespaces *bool `json:"hostNamespaces,omitempty"`
+
+	// Capabilities are the matching added capabilities, for example
+	// "SYS_ADMIN". The "CAP_" prefix is optional.
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// HostPaths are patterns of the matching host paths of mounts.
+	HostPaths []string `json:"hostPaths,omitempty"`
+
+	// Devices are patterns of the matching host paths of devices.
+	Devices []string `json:"devices,omitempty"`
+}
+
+// Mutation describes the changes applied to a request.
+type Mutation struct {
+	// Annotations are added to the pod or container.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// DropCapabilities are removed from the added capabilities of containers
+	// and added to their dropped ones.
+	DropCapabilities []string `json:"dropCapabilities,omitempty"`
+
+	// ReadOnlyRootFilesystem enforces a read-only root filesystem for
+	// containers.
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+
+	// NoNewPrivileges enforces the no_new_privs flag for containers.
+	NoNewPrivileges bool `json:"noNewPrivileges,omitempty"`
+}
+
+// compiledRule is a validated rule including its compiled patterns.
+type compiledRule struct {
+	*Rule
+
+	operations        map[string]bool
+	namespaces        []*regexp.Regexp
+	excludeNamespaces []*regexp.Regexp
+	images            []*regexp.Regexp
+	runtimeHandlers   []*regexp.Regexp
+	capabilities      map[string]bool
+	hostPaths         []*regexp.Regexp
+	devices           []*regexp.Regexp
+}
+
+// compile validates the rule and compiles its patterns.
+func (r *Rule) compile() (*compiledRule, error) {
+	if r.Name == "" {
+		return nil, errors.New("rule name is empty")
+	}
+	switch r.Action {
+	case ActionDeny:
+		if r.Mutation != nil {
+			return nil, fmt.Errorf("rule %q: mutation is not supported for action %q", r.Name, r.Action)
+		}
+	case ActionMutate:
+		if r.Mutation == nil {
+			return nil, fmt.Errorf("rule %q: mutation is required for action %q", r.Name, r.Action)
+		}
+	default:
+		return nil, fmt.Errorf("rule %q: unknown action %q", r.Name, r.Action)
+	}
+
+	c := &compiledRule{
+		Rule:         r,
+		operations:   make(map[string]bool),
+		capabilities: make(map[string]bool),
+	}
+	for _, op := range r.Operations {
+		if op != OperationRunPodSandbox && op != OperationCreateContainer {
+			return nil, fmt.Errorf("rule %q: unknown operation %q", r.Name, op)
+		}
+		c.operations[op] = true
+	}
+	for _, capability := range r.Match.Capabilities {
+		c.capabilities[normalizeCapability(capability)] = true
+	}
+
+	var err error
+	for _, p := range []struct {
+		patterns []string
+		res      *[]*regexp.Regexp
+	}{
+		{r.Match.Namespaces, &c.namespaces},
+		{r.Match.ExcludeNamespaces, &c.excludeNamespaces},
+		{r.Match.Images, &c.images},
+		{r.Match.RuntimeHandlers, &c.runtimeHandlers},
+		{r.Match.HostPaths, &c.hostPaths},
+		{r.Match.Devices, &c.devices},
+	} {
+		if *p.res, err = compilePatterns(p.patterns); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+	}
+	return c, nil
+}
+
+// compilePatterns converts the provided wildcard patterns into anchored
+// regular expressions.
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		parts := strings.Split(pattern, "*")
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+		re, err := regexp.Compile("^" + strings.Join(parts, ".*") + "$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// normalizeCapability returns the capability name without "CAP_" prefix in
+// upper case.
+func normalizeCapability(capability string) string {
+	return strings.TrimPrefix(strings.ToUpper(capability), "CAP_")
+}
+
+// matchesAny returns true if any of the values matches any of the patterns.
+func matchesAny(patterns []*regexp.Regexp, values ...string) bool {
+	for _, re := range patterns {
+		for _, value := range values {
+			if re.MatchString(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matches returns true if the rule applies to the request.
+func (c *compiledRule) matches(req *Request) bool {
+	if len(c.operations) > 0 && !c.operations[req.Operation] {
+		return false
+	}
+	if len(c.namespaces) > 0 && !matchesAny(c.namespaces, req.Namespace) {
+		return false
+	}
+	if matchesAny(c.excludeNamespaces, req.Namespace) {
+		return false
+	}
+	if len(c.images) > 0 && !matchesAny(c.images, req.Images...) {
+		return false
+	}
+	if len(c.runtimeHandlers) > 0 && !matchesAny(c.runtimeHandlers, req.RuntimeHandler) {
+		return false
+	}
+	if c.Match.Privileged != nil && *c.Match.Privileged != req.Privileged {
+		return false
+	}
+	if c.Match.HostNamespaces != nil && *c.Match.HostNamespaces != req.HostNamespaces() {
+		return false
+	}
+	if len(c.capabilities) > 0 {
+		found := false
+		for _, capability := range req.Capabilities {
+			if c.capabilities[normalizeCapability(capability)] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(c.hostPaths) > 0 && !matchesAny(c.hostPaths, req.HostPaths...) {
+		return false
+	}
+	if len(c.devices) > 0 && !matchesAny(c.devices, req.Devices...) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,277 @@
+package apparmor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/containers/common/pkg/apparmor"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+// generatedProfilePrefix is the name prefix of the profiles generated for
+// containers.
+const generatedProfilePrefix = "crio-generated-"
+
+var (
+	// profileDirectory is the directory containing the AppArmor tunables and
+	// abstractions.
+	profileDirectory = "/etc/apparmor.d"
+
+	// removePath is the kernel interface to unload profiles.
+	removePath = "/sys/kernel/security/apparmor/.remove"
+)
+
+// generatedProfileTemplate is the template of the profiles generated for
+// containers. It is based on the default profile, but restricts the
+// capabilities and write access according to the container spec.
+const generatedProfileTemplate = `{{ range .Imports }}{{ . }}
+{{ end }}
+profile {{ .Name }} flags=(attach_disconnected,mediate_deleted) {
+{{ range .InnerImports }}  {{ . }}
+{{ end }}
+  network,
+{{ range .Capabilities }}  capability {{ . }},
+{{ end }}
+  # Allow reading the whole container filesystem and writing only to the
+  # writable root filesystem and mounts.
+  /** rlkmix,
+{{ range .Writable }}  {{ . }} w,
+{{ end }}{{ range .ReadOnly }}  deny {{ . }} w,
+{{ end }}{{ range .Masked }}  deny {{ . }} rwklx,
+{{ end }}
+  umount,
+  deny mount,
+
+  # Allow signals from privileged profiles and from within the same profile
+  signal (receive) peer=unconfined,
+  signal (send,receive) peer={{ .Name }},
+
+  deny @{PROC}/* w,   # deny write for all files directly in /proc (not in a subdir)
+  # deny write to files not in /proc/<number>/** or /proc/sys/**
+  deny @{PROC}/{[^1-9],[^1-9][^0-9],[^1-9s][^0-9y][^0-9s],[^1-9][^0-9][^0-9][^0-9]*}/** w,
+  deny @{PROC}/sys/[^k]** w,  # deny /proc/sys except /proc/sys/k* (effectively /proc/sys/kernel)
+  deny @{PROC}/sys/kernel/{?,??,[^s][^h][^m]**} w,  # deny everything except shm* in /proc/sys/kernel/
+  deny @{PROC}/sysrq-trigger rwklx,
+  deny @{PROC}/kcore rwklx,
+
+  deny /sys/[^f]*/** wklx,
+  deny /sys/f[^s]*/** wklx,
+  deny /sys/fs/[^c]*/** wklx,
+  deny /sys/fs/c[^g]*/** wklx,
+  deny /sys/fs/cg[^r]*/** wklx,
+  deny /sys/firmware/** rwklx,
+  deny /sys/kernel/security/** rwklx,
+
+  # suppress ptrace denials when using 'ps' inside a container
+  ptrace (trace,read) peer={{ .Name }},
+}
+`
+
+// generatedProfileData holds the values of a generated profile.
+type generatedProfileData struct {
+	Name         string
+	Imports      []string
+	InnerImports []string
+	Capabilities []string
+	Writable     []string
+	ReadOnly     []string
+	Masked       []string
+}
+
+// GeneratedProfileName returns the name of the profile generated for the
+// provided container.
+func GeneratedProfileName(containerID string) string {
+	return generatedProfilePrefix + containerID
+}
+
+// GenerateProfile returns a profile of the provided name derived from the
+// container spec. The profile allows only the capabilities of the bounding set
+// and write access only to the writable root filesystem and mounts of the
+// container, while denying any access to its masked paths.
+func GenerateProfile(name string, spec *rspec.Spec) ([]byte, error) {
+	if spec == nil {
+		return nil, errors.New("container spec is nil")
+	}
+	data := &generatedProfileData{Name: name}
+
+	if macroExists("tunables/global") {
+		data.Imports = append(data.Imports, "#include <tunables/global>")
+	} else {
+		data.Imports = append(data.Imports, "@{PROC}=/proc/")
+	}
+	if macroExists("abstractions/base") {
+		data.InnerImports = append(data.InnerImports, "#include <abstractions/base>")
+	}
+
+	if spec.Process != nil && spec.Process.Capabilities != nil {
+		for _, capability := range spec.Process.Capabilities.Bounding {
+			capability = strings.ToLower(strings.TrimPrefix(capability, "CAP_"))
+			if !isCapabilityName(capability) {
+				return nil, fmt.Errorf("invalid capability %q", capability)
+			}
+			data.Capabilities = append(data.Capabilities, capability)
+		}
+		sort.Strings(data.Capabilities)
+	}
+
+	if spec.Root == nil || !spec.Root.Readonly {
+		data.Writable = append(data.Writable, "/**")
+	}
+	for i := range spec.Mounts {
+		m := &spec.Mounts[i]
+		rule, err := pathRule(m.Destination)
+		if err != nil {
+			return nil, err
+		}
+		if isReadOnly(m.Options) {
+			data.ReadOnly = append(data.ReadOnly, rule)
+		} else {
+			data.Writable = append(data.Writable, rule)
+		}
+	}
+	if spec.Linux != nil {
+		for _, p := range spec.Linux.ReadonlyPaths {
+			rule, err := pathRule(p)
+			if err != nil {
+				return nil, err
+			}
+			data.ReadOnly = append(data.ReadOnly, rule)
+		}
+		for _, p := range spec.Linux.MaskedPaths {
+			rule, err := pathRule(p)
+			if err != nil {
+				return nil, err
+			}
+			data.Masked = append(data.Masked, rule)
+		}
+	}
+
+	compiled, err := template.New("apparmor_profile").Parse(generatedProfileTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("create AppArmor profile from template: %w", err)
+	}
+	buffer := &bytes.Buffer{}
+	if err := compiled.Execute(buffer, data); err != nil {
+		return nil, fmt.Errorf("execute compiled profile: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// InstallGenerated generates the profile of the provided container from its
+// spec, loads it and returns its name.
+func (c *Config) InstallGenerated(containerID string, spec *rspec.Spec) (string, error) {
+	if !c.IsEnabled() {
+		return "", errors.New("AppArmor is disabled by the system or at CRI-O build-time")
+	}
+	name := GeneratedProfileName(containerID)
+	content, err := GenerateProfile(name, spec)
+	if err != nil {
+		return "", fmt.Errorf("generate AppArmor profile %q: %w", name, err)
+	}
+	if err := loadProfile(content); err != nil {
+		return "", fmt.Errorf("load AppArmor profile %q: %w", name, err)
+	}
+	logrus.Debugf("Loaded generated AppArmor profile %s", name)
+	return name, nil
+}
+
+// RemoveGenerated unloads the generated profile of the provided container. It
+// does not fail if the profile is not loaded.
+func (c *Config) RemoveGenerated(containerID string) error {
+	if !c.IsEnabled() {
+		return nil
+	}
+	name := GeneratedProfileName(containerID)
+	isLoaded, err := apparmor.IsLoaded(name)
+	if err != nil {
+		return fmt.Errorf("checking if AppArmor profile %s is loaded: %w", name, err)
+	}
+	if !isLoaded {
+		return nil
+	}
+	if err := os.WriteFile(removePath, []byte(name), 0o644); err != nil {
+		return fmt.Errorf("unload AppArmor profile %s: %w", name, err)
+	}
+	logrus.Debugf("Unloaded generated AppArmor profile %s", name)
+	return nil
+}
+
+// loadProfile loads or replaces the provided profile using `apparmor_parser`.
+func loadProfile(content []byte) error {
+	apparmorParserPath, err := exec.LookPath("apparmor_parser")
+	if err != nil {
+		apparmorParserPath = "/sbin/apparmor_parser"
+	}
+	cmd := exec.Command(apparmorParserPath, "-Kr")
+	cmd.Stdin = bytes.NewReader(content)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("running %s failed with output: %s: %w", apparmorParserPath, output, err)
+	}
+	return nil
+}
+
+// macroExists checks if the provided tunable or abstraction exists.
+func macroExists(m string) bool {
+	_, err := os.Stat(filepath.Join(profileDirectory, m))
+	return err == nil
+}
+
+// isReadOnly returns true if the mount options contain "ro".
+func isReadOnly(options []string) bool {
+	for _, o := range options {
+		if o == "ro" {
+			return true
+		}
+	}
+	return false
+}
+
+// isCapabilityName returns true if the capability name is safe to be used in
+// a profile.
+func isCapabilityName(capability string) bool {
+	if capability == "" {
+		return false
+	}
+	for _, r := range capability {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// pathRule returns the quoted rule matching the provided path and everything
+// below it, escaping the AppArmor glob characters.
+func pathRule(p string) (string, error) {
+	for _, r := range p {
+		if unicode.IsControl(r) {
+			return "", fmt.Errorf("path %q contains control characters", p)
+		}
+	}
+	p = path.Clean("/" + p)
+	if p == "/" {
+		return "/**", nil
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range p {
+		switch r {
+		case '\\', '"', '*', '?', '[', ']', '{', '}', '^', '@':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteString(`{,/**}"`)
+	return b.String(), nil
+}
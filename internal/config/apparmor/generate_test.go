@@ -0,0 +1,133 @@
+package apparmor_test
+
+import (
+	"github.com/cri-o/cri-o/internal/config/apparmor"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// The actual test suite
+var _ = t.Describe("GenerateProfile", func() {
+	const name = "crio-generated-test"
+
+	var spec *rspec.Spec
+
+	BeforeEach(func() {
+		spec = &rspec.Spec{
+			Root: &rspec.Root{Path: "/rootfs"},
+			Process: &rspec.Process{
+				Capabilities: &rspec.LinuxCapabilities{
+					Bounding: []string{"CAP_NET_BIND_SERVICE", "CAP_CHOWN"},
+				},
+			},
+			Mounts: []rspec.Mount{
+				{Destination: "/data", Options: []string{"rw", "bind"}},
+				{Destination: "/config", Options: []string{"ro", "bind"}},
+			},
+			Linux: &rspec.Linux{
+				ReadonlyPaths: []string{"/proc/bus"},
+				MaskedPaths:   []string{"/proc/kcore"},
+			},
+		}
+	})
+
+	It("should name the generated profile after the container", func() {
+		// Given
+		// When
+		res := apparmor.GeneratedProfileName("id")
+
+		// Then
+		Expect(res).To(Equal("crio-generated-id"))
+	})
+
+	It("should derive the profile from the spec", func() {
+		// Given
+		// When
+		res, err := apparmor.GenerateProfile(name, spec)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(res)).To(And(
+			ContainSubstring("profile "+name+" flags=(attach_disconnected,mediate_deleted) {"),
+			ContainSubstring("  capability chown,\n  capability net_bind_service,\n"),
+			ContainSubstring("  /** w,\n"),
+			ContainSubstring(`  "/data{,/**}" w,`),
+			ContainSubstring(`  deny "/config{,/**}" w,`),
+			ContainSubstring(`  deny "/proc/bus{,/**}" w,`),
+			ContainSubstring(`  deny "/proc/kcore{,/**}" rwklx,`),
+			ContainSubstring("peer="+name+","),
+		))
+	})
+
+	It("should not allow any capability without bounding set", func() {
+		// Given
+		spec.Process.Capabilities = nil
+
+		// When
+		res, err := apparmor.GenerateProfile(name, spec)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(res)).NotTo(ContainSubstring("capability"))
+	})
+
+	It("should only allow writing to mounts with read-only root filesystem", func() {
+		// Given
+		spec.Root.Readonly = true
+
+		// When
+		res, err := apparmor.GenerateProfile(name, spec)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(res)).NotTo(ContainSubstring("  /** w,\n"))
+		Expect(string(res)).To(ContainSubstring(`  "/data{,/**}" w,`))
+	})
+
+	It("should escape glob characters of paths", func() {
+		// Given
+		spec.Mounts = []rspec.Mount{{Destination: `/data/*"x"{a,b}/`}}
+
+		// When
+		res, err := apparmor.GenerateProfile(name, spec)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(res)).To(ContainSubstring(`  "/data/\*\"x\"\{a,b\}{,/**}" w,`))
+	})
+
+	It("should fail with control characters in paths", func() {
+		// Given
+		spec.Mounts = []rspec.Mount{{Destination: "/data\n/** rwx,"}}
+
+		// When
+		res, err := apparmor.GenerateProfile(name, spec)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(res).To(BeNil())
+	})
+
+	It("should fail with invalid capabilities", func() {
+		// Given
+		spec.Process.Capabilities.Bounding = []string{"CAP_CHOWN, file"}
+
+		// When
+		res, err := apparmor.GenerateProfile(name, spec)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(res).To(BeNil())
+	})
+
+	It("should fail without spec", func() {
+		// Given
+		// When
+		res, err := apparmor.GenerateProfile(name, nil)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(res).To(BeNil())
+	})
+})
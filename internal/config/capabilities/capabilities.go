@@ -38,3 +38,48 @@ func (c Capabilities) Validate() error {
 	logrus.Infof("Using default capabilities: %s", strings.Join(caps, ", "))
 	return nil
 }
+
+// ValidateDenyList checks if the provided capabilities are available on the
+// system, allowing "ALL" to deny every capability.
+func (c Capabilities) ValidateDenyList() error {
+	caps := []string{}
+	for _, cap := range c {
+		if normalize(cap) == all {
+			continue
+		}
+		caps = append(caps, "CAP_"+normalize(cap))
+	}
+	if err := common.ValidateCapabilities(caps); err != nil {
+		return fmt.Errorf("validating capabilities: %w", err)
+	}
+	return nil
+}
+
+// Denied returns the capabilities of requested which are part of the deny
+// list c. If the deny list contains "ALL", every requested capability is
+// denied, while a requested "ALL" is denied by any non-empty deny list.
+func (c Capabilities) Denied(requested []string) []string {
+	if len(c) == 0 {
+		return nil
+	}
+	denied := make(map[string]bool, len(c))
+	for _, cap := range c {
+		denied[normalize(cap)] = true
+	}
+	res := []string{}
+	for _, cap := range requested {
+		cap = normalize(cap)
+		if denied[all] || denied[cap] || cap == all {
+			res = append(res, cap)
+		}
+	}
+	return res
+}
+
+// all is the capability name matching every capability.
+const all = "ALL"
+
+// normalize returns the upper case capability name without "CAP_" prefix.
+func normalize(cap string) string {
+	return strings.TrimPrefix(strings.ToUpper(cap), "CAP_")
+}
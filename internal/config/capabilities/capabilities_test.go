@@ -40,4 +40,70 @@ var _ = t.Describe("Capabilities", func() {
 		// Then
 		Expect(err).NotTo(BeNil())
 	})
+
+	It("should succeed to validate a deny list", func() {
+		// Given
+		sut := capabilities.Capabilities{"SYS_ADMIN", "cap_net_raw", "ALL"}
+
+		// When
+		err := sut.ValidateDenyList()
+
+		// Then
+		Expect(err).To(BeNil())
+	})
+
+	It("should fail to validate a wrong deny list", func() {
+		// Given
+		sut := capabilities.Capabilities{"SYS_ADMIN", "wrong"}
+
+		// When
+		err := sut.ValidateDenyList()
+
+		// Then
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should return the denied capabilities", func() {
+		// Given
+		sut := capabilities.Capabilities{"SYS_ADMIN", "net_raw"}
+
+		// When
+		res := sut.Denied([]string{"CAP_SYS_ADMIN", "CHOWN", "NET_RAW"})
+
+		// Then
+		Expect(res).To(Equal([]string{"SYS_ADMIN", "NET_RAW"}))
+	})
+
+	It("should deny requesting all capabilities", func() {
+		// Given
+		sut := capabilities.Capabilities{"SYS_ADMIN"}
+
+		// When
+		res := sut.Denied([]string{"ALL"})
+
+		// Then
+		Expect(res).To(Equal([]string{"ALL"}))
+	})
+
+	It("should deny every capability with ALL", func() {
+		// Given
+		sut := capabilities.Capabilities{"ALL"}
+
+		// When
+		res := sut.Denied([]string{"CHOWN", "KILL"})
+
+		// Then
+		Expect(res).To(Equal([]string{"CHOWN", "KILL"}))
+	})
+
+	It("should not deny anything with an empty deny list", func() {
+		// Given
+		sut := capabilities.Capabilities{}
+
+		// When
+		res := sut.Denied([]string{"ALL", "SYS_ADMIN"})
+
+		// Then
+		Expect(res).To(BeEmpty())
+	})
 })
@@ -63,6 +63,13 @@ type CgroupManager interface {
 	// returns the cgroup parent, cgroup path, and error. For systemd cgroups,
 	// it also checks there is enough memory in the given cgroup
 	SandboxCgroupPath(string, string) (string, string, error)
+	// PodCgroupPath takes the sandbox parent and returns the path of the pod
+	// cgroup relative to the root of the cgroup hierarchy.
+	PodCgroupPath(string) (string, error)
+	// CreatePodCgroup takes the sandbox parent and creates the pod cgroup
+	// if it does not exist yet, for example because the pod is not created
+	// by the kubelet.
+	CreatePodCgroup(string) error
 	// PopulateContainerCgroupStats takes arguments sandbox parent cgroup, and sandbox stats object.
 	// It fills the object with information from the cgroup found given that parent.
 	PopulateSandboxCgroupStats(sbParent string, stats *types.PodSandboxStats) error
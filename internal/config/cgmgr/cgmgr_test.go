@@ -155,6 +155,26 @@ var _ = t.Describe("Cgmgr", func() {
 				Expect(err).To(BeNil())
 			})
 		})
+		t.Describe("PodCgroupPath", func() {
+			It("should be the absolute sandbox parent", func() {
+				// Given
+				// When
+				cgPath, err := sut.PodCgroupPath("kubepods/pod-123")
+
+				// Then
+				Expect(err).To(BeNil())
+				Expect(cgPath).To(Equal("/kubepods/pod-123"))
+			})
+			It("should fail without sandbox parent", func() {
+				// Given
+				// When
+				cgPath, err := sut.PodCgroupPath("")
+
+				// Then
+				Expect(err).To(Not(BeNil()))
+				Expect(cgPath).To(BeEmpty())
+			})
+		})
 		t.Describe("MoveConmonToCgroup", func() {
 			It("should fail if invalid conmon cgroup", func() {
 				// Given
@@ -240,6 +260,26 @@ var _ = t.Describe("Cgmgr", func() {
 				Expect(err).To(Not(BeNil()))
 			})
 		})
+		t.Describe("PodCgroupPath", func() {
+			It("should expand the pod slice", func() {
+				// Given
+				// When
+				cgPath, err := sut.PodCgroupPath("kubepods-pod_123.slice")
+
+				// Then
+				Expect(err).To(BeNil())
+				Expect(cgPath).To(Equal("/kubepods.slice/kubepods-pod_123.slice"))
+			})
+			It("should fail without sandbox parent", func() {
+				// Given
+				// When
+				cgPath, err := sut.PodCgroupPath("")
+
+				// Then
+				Expect(err).To(Not(BeNil()))
+				Expect(cgPath).To(BeEmpty())
+			})
+		})
 		t.Describe("MoveConmonToCgroup", func() {
 			It("should fail if invalid conmon cgroup", func() {
 				// Given
@@ -4,6 +4,7 @@
 package cgmgr
 
 import (
+	"errors"
 	"fmt"
 	"path"
 	"path/filepath"
@@ -80,6 +81,33 @@ func (m *CgroupfsManager) SandboxCgroupPath(sbParent, sbID string) (cgParent, cg
 	return sbParent, filepath.Join(sbParent, containerCgroupPath(sbID)), nil
 }
 
+// PodCgroupPath returns the sandbox parent as an absolute path, which is the
+// path of the pod cgroup.
+func (m *CgroupfsManager) PodCgroupPath(sbParent string) (string, error) {
+	if sbParent == "" {
+		return "", errors.New("sandbox has no cgroup parent")
+	}
+	return filepath.Join("/", sbParent), nil
+}
+
+// CreatePodCgroup creates the cgroup of the sandbox parent.
+func (m *CgroupfsManager) CreatePodCgroup(sbParent string) error {
+	podCgroup, err := m.PodCgroupPath(sbParent)
+	if err != nil {
+		return err
+	}
+	mgr, err := libctrCgMgr.New(&cgcfgs.Cgroup{
+		Path: podCgroup,
+		Resources: &cgcfgs.Resources{
+			SkipDevices: true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return mgr.Apply(-1)
+}
+
 // PopulateSandboxCgroupStats takes arguments sandbox parent cgroup and sandbox stats object
 // It fills the object with information from the cgroup found given that cgroup
 func (m *CgroupfsManager) PopulateSandboxCgroupStats(sbParent string, stats *types.PodSandboxStats) error {
@@ -4,6 +4,7 @@
 package cgmgr
 
 import (
+	"errors"
 	"fmt"
 	"path"
 	"path/filepath"
@@ -15,7 +16,9 @@ import (
 	"github.com/cri-o/cri-o/internal/dbusmgr"
 	"github.com/cri-o/cri-o/utils"
 	"github.com/godbus/dbus/v5"
+	libctrCgMgr "github.com/opencontainers/runc/libcontainer/cgroups/manager"
 	"github.com/opencontainers/runc/libcontainer/cgroups/systemd"
+	cgcfgs "github.com/opencontainers/runc/libcontainer/configs"
 	rspec "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sys/unix"
@@ -166,6 +169,45 @@ func (m *SystemdManager) SandboxCgroupPath(sbParent, sbID string) (cgParent, cgP
 	return cgParent, cgPath, nil
 }
 
+// PodCgroupPath expands the pod slice to the path of the pod cgroup.
+func (m *SystemdManager) PodCgroupPath(sbParent string) (string, error) {
+	if sbParent == "" {
+		return "", errors.New("sandbox has no cgroup parent")
+	}
+	cgPath, err := systemd.ExpandSlice(sbParent)
+	if err != nil {
+		return "", fmt.Errorf("expanding systemd slice path for %q: %w", sbParent, err)
+	}
+	return cgPath, nil
+}
+
+// CreatePodCgroup starts the pod slice as transient unit, which is a no-op if
+// it already exists.
+func (m *SystemdManager) CreatePodCgroup(sbParent string) error {
+	slicePath, err := m.PodCgroupPath(sbParent)
+	if err != nil {
+		return err
+	}
+	// The hierarchy of slices is encoded in their names, the parent is only
+	// required for the dependency of the new slice.
+	parent := "-.slice"
+	if dir := path.Dir(slicePath); dir != "/" {
+		parent = path.Base(dir)
+	}
+	mgr, err := libctrCgMgr.New(&cgcfgs.Cgroup{
+		Name:    sbParent,
+		Parent:  parent,
+		Systemd: true,
+		Resources: &cgcfgs.Resources{
+			SkipDevices: true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return mgr.Apply(-1)
+}
+
 // PopulateSandboxCgroupStats takes arguments sandbox parent cgroup and sandbox stats object
 // It fills the object with information from the cgroup found given that cgroup
 func (m *SystemdManager) PopulateSandboxCgroupStats(sbParent string, stats *types.PodSandboxStats) error {
@@ -0,0 +1,152 @@
+package cnimgr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/containernetworking/cni/libcni"
+	"github.com/sirupsen/logrus"
+)
+
+// cniLoopbackNetwork is the network name ocicni uses for the loopback
+// interface of a pod, which does not hold any resources to be released.
+const cniLoopbackNetwork = "cni-loopback"
+
+// CachedAttachment is a pod network attachment recorded in the CNI result cache.
+type CachedAttachment struct {
+	// ContainerID is the ID of the sandbox the attachment belongs to.
+	ContainerID string
+
+	// Network is the name of the CNI network.
+	Network string
+
+	// Ifname is the name of the interface within the sandbox.
+	Ifname string
+
+	// PodName, PodNamespace and PodUID identify the Kubernetes pod, if the
+	// attachment has been created for one.
+	PodName      string
+	PodNamespace string
+	PodUID       string
+
+	// Modified is the last time the cache entry has been written.
+	Modified time.Time
+
+	// Result is the raw CNI result of the attachment.
+	Result json.RawMessage
+}
+
+// CachedAttachments returns the attachments recorded in the CNI result cache
+// below cacheDir, which defaults to the libcni cache directory. Entries which
+// have not been created for a Kubernetes pod are skipped.
+func CachedAttachments(cacheDir string) ([]CachedAttachment, error) {
+	return cachedAttachments(cacheDir, "")
+}
+
+// CachedPodAttachments returns the attachments of the provided pod sandbox
+// recorded in the CNI result cache below cacheDir, which defaults to the
+// libcni cache directory.
+func CachedPodAttachments(cacheDir, containerID string) ([]CachedAttachment, error) {
+	if containerID == "" {
+		return nil, errors.New("container ID must not be empty")
+	}
+	return cachedAttachments(cacheDir, containerID)
+}
+
+func cachedAttachments(cacheDir, containerID string) ([]CachedAttachment, error) {
+	if cacheDir == "" {
+		cacheDir = libcni.CacheDir
+	}
+	dir := filepath.Join(cacheDir, "results")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read CNI cache directory: %w", err)
+	}
+
+	attachments := []CachedAttachment{}
+	for _, entry := range entries {
+		// The cache files are named <network>-<container ID>-<interface>
+		if entry.IsDir() || (containerID != "" && !strings.Contains(entry.Name(), "-"+containerID+"-")) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		attachment, err := readCachedAttachment(path)
+		if err != nil {
+			logrus.Warnf("Unable to read CNI cache file %s: %v", path, err)
+			continue
+		}
+		if attachment != nil && (containerID == "" || attachment.ContainerID == containerID) {
+			attachments = append(attachments, *attachment)
+		}
+	}
+	sort.Slice(attachments, func(i, j int) bool {
+		if attachments[i].ContainerID != attachments[j].ContainerID {
+			return attachments[i].ContainerID < attachments[j].ContainerID
+		}
+		return attachments[i].Ifname < attachments[j].Ifname
+	})
+	return attachments, nil
+}
+
+func readCachedAttachment(path string) (*CachedAttachment, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cached := struct {
+		Kind        string          `json:"kind"`
+		ContainerID string          `json:"containerId"`
+		IfName      string          `json:"ifName"`
+		NetworkName string          `json:"networkName"`
+		CniArgs     [][2]string     `json:"cniArgs"`
+		Result      json.RawMessage `json:"result"`
+	}{}
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, err
+	}
+	if cached.Kind != libcni.CNICacheV1 {
+		return nil, fmt.Errorf("unknown kind %q", cached.Kind)
+	}
+	if cached.NetworkName == cniLoopbackNetwork {
+		return nil, nil
+	}
+
+	attachment := &CachedAttachment{
+		ContainerID: cached.ContainerID,
+		Network:     cached.NetworkName,
+		Ifname:      cached.IfName,
+		Modified:    info.ModTime(),
+		Result:      cached.Result,
+	}
+	infraContainerID := ""
+	for _, arg := range cached.CniArgs {
+		switch arg[0] {
+		case "K8S_POD_NAME":
+			attachment.PodName = arg[1]
+		case "K8S_POD_NAMESPACE":
+			attachment.PodNamespace = arg[1]
+		case "K8S_POD_UID":
+			attachment.PodUID = arg[1]
+		case "K8S_POD_INFRA_CONTAINER_ID":
+			infraContainerID = arg[1]
+		}
+	}
+	if attachment.ContainerID == "" || attachment.Network == "" || attachment.Ifname == "" ||
+		infraContainerID != attachment.ContainerID {
+		return nil, nil
+	}
+	return attachment, nil
+}
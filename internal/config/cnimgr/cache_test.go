@@ -0,0 +1,109 @@
+package cnimgr_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cri-o/cri-o/internal/config/cnimgr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("CachedAttachments", func() {
+	var cacheDir string
+
+	writeCacheFile := func(network, containerID, ifname, infraContainerID string) {
+		content := fmt.Sprintf(`{"kind":"cniCacheV1","containerId":%q,"ifName":%q,"networkName":%q,`+
+			`"cniArgs":[["IgnoreUnknown","1"],["K8S_POD_NAMESPACE","default"],["K8S_POD_NAME","pod"],`+
+			`["K8S_POD_INFRA_CONTAINER_ID",%q],["K8S_POD_UID","uid"]],"result":{"cniVersion":"1.0.0"}}`,
+			containerID, ifname, network, infraContainerID)
+		Expect(os.WriteFile(
+			filepath.Join(cacheDir, "results", network+"-"+containerID+"-"+ifname),
+			[]byte(content), 0o600,
+		)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		cacheDir = t.MustTempDir("cni-cache")
+		Expect(os.MkdirAll(filepath.Join(cacheDir, "results"), 0o700)).To(Succeed())
+	})
+
+	It("should return the attachments of pods", func() {
+		// Given
+		writeCacheFile("storage", "b", "net1", "b")
+		writeCacheFile("default", "b", "eth0", "b")
+		writeCacheFile("default", "a", "eth0", "a")
+
+		// When
+		attachments, err := cnimgr.CachedAttachments(cacheDir)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(attachments).To(HaveLen(3))
+		Expect(attachments[0].ContainerID).To(Equal("a"))
+		Expect(attachments[1].ContainerID).To(Equal("b"))
+		Expect(attachments[1].Network).To(Equal("default"))
+		Expect(attachments[1].Ifname).To(Equal("eth0"))
+		Expect(attachments[1].PodName).To(Equal("pod"))
+		Expect(attachments[1].PodNamespace).To(Equal("default"))
+		Expect(attachments[1].PodUID).To(Equal("uid"))
+		Expect(attachments[1].Modified).NotTo(BeZero())
+		Expect(string(attachments[1].Result)).To(Equal(`{"cniVersion":"1.0.0"}`))
+		Expect(attachments[2].Network).To(Equal("storage"))
+	})
+
+	It("should skip loopback and non pod entries", func() {
+		// Given
+		writeCacheFile("cni-loopback", "a", "lo", "a")
+		writeCacheFile("default", "b", "eth0", "")
+		Expect(os.WriteFile(filepath.Join(cacheDir, "results", "invalid"), []byte("{"), 0o600)).To(Succeed())
+
+		// When
+		attachments, err := cnimgr.CachedAttachments(cacheDir)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(attachments).To(BeEmpty())
+	})
+
+	It("should succeed without cache directory", func() {
+		// Given
+		Expect(os.RemoveAll(cacheDir)).To(Succeed())
+
+		// When
+		attachments, err := cnimgr.CachedAttachments(cacheDir)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(attachments).To(BeEmpty())
+	})
+
+	It("should return the attachments of a pod", func() {
+		// Given
+		writeCacheFile("default", "a", "eth0", "a")
+		writeCacheFile("default", "b", "eth0", "b")
+		writeCacheFile("storage", "b", "net1", "b")
+
+		// When
+		attachments, err := cnimgr.CachedPodAttachments(cacheDir, "b")
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(attachments).To(HaveLen(2))
+		Expect(attachments[0].ContainerID).To(Equal("b"))
+		Expect(attachments[0].Ifname).To(Equal("eth0"))
+		Expect(attachments[1].Ifname).To(Equal("net1"))
+	})
+
+	It("should fail to return the attachments without container ID", func() {
+		// Given
+		// When
+		attachments, err := cnimgr.CachedPodAttachments(cacheDir, "")
+
+		// Then
+		Expect(err).NotTo(BeNil())
+		Expect(attachments).To(BeNil())
+	})
+})
@@ -0,0 +1,74 @@
+package cnimgr
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// MarkPodAttachments records below dir that the CNI attachments of the
+// provided pod sandbox are created by CRI-O. The CNI cache is shared with
+// other CNI users on the node, which is why only the attachments of marked
+// pod sandboxes are released as stale.
+func MarkPodAttachments(dir, containerID string) error {
+	path, err := markerPath(dir, containerID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create CNI attachments directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("mark CNI attachments of %s: %w", containerID, err)
+	}
+	return f.Close()
+}
+
+// UnmarkPodAttachments removes the marker of the provided pod sandbox once
+// its CNI attachments have been released.
+func UnmarkPodAttachments(dir, containerID string) error {
+	path, err := markerPath(dir, containerID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unmark CNI attachments of %s: %w", containerID, err)
+	}
+	return nil
+}
+
+// MarkedPods returns the pod sandboxes marked below dir together with the
+// time they have been marked.
+func MarkedPods(dir string) (map[string]time.Time, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]time.Time{}, nil
+		}
+		return nil, fmt.Errorf("read CNI attachments directory: %w", err)
+	}
+	marked := make(map[string]time.Time, len(entries))
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		marked[entry.Name()] = info.ModTime()
+	}
+	return marked, nil
+}
+
+func markerPath(dir, containerID string) (string, error) {
+	if containerID == "" || containerID == "." || containerID == ".." ||
+		strings.ContainsRune(containerID, filepath.Separator) {
+		return "", fmt.Errorf("invalid container ID %q", containerID)
+	}
+	return filepath.Join(dir, containerID), nil
+}
@@ -0,0 +1,53 @@
+package cnimgr_test
+
+import (
+	"path/filepath"
+
+	"github.com/cri-o/cri-o/internal/config/cnimgr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("MarkPodAttachments", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = filepath.Join(t.MustTempDir("cni-attachments"), "markers")
+	})
+
+	It("should list the marked pods", func() {
+		// Given
+		Expect(cnimgr.MarkPodAttachments(dir, "a")).To(Succeed())
+		Expect(cnimgr.MarkPodAttachments(dir, "b")).To(Succeed())
+		Expect(cnimgr.MarkPodAttachments(dir, "b")).To(Succeed())
+
+		// When
+		marked, err := cnimgr.MarkedPods(dir)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(marked).To(HaveLen(2))
+		Expect(marked).To(HaveKey("a"))
+		Expect(marked).To(HaveKey("b"))
+	})
+
+	It("should not list unmarked pods", func() {
+		// Given
+		Expect(cnimgr.MarkPodAttachments(dir, "a")).To(Succeed())
+		Expect(cnimgr.UnmarkPodAttachments(dir, "a")).To(Succeed())
+		Expect(cnimgr.UnmarkPodAttachments(dir, "b")).To(Succeed())
+
+		// When
+		marked, err := cnimgr.MarkedPods(dir)
+
+		// Then
+		Expect(err).To(BeNil())
+		Expect(marked).To(BeEmpty())
+	})
+
+	It("should fail to mark invalid IDs", func() {
+		Expect(cnimgr.MarkPodAttachments(dir, "../a")).NotTo(Succeed())
+		Expect(cnimgr.MarkPodAttachments(dir, "..")).NotTo(Succeed())
+	})
+})
@@ -74,10 +74,12 @@ func (mgr *NamespaceManager) NewPodNamespaces(cfg *PodNamespacesConfig) ([]Names
 	}
 
 	typeToArg := map[NSType]string{
-		IPCNS:  "--ipc",
-		UTSNS:  "--uts",
-		USERNS: "--user",
-		NETNS:  "--net",
+		IPCNS:    "--ipc",
+		UTSNS:    "--uts",
+		USERNS:   "--user",
+		NETNS:    "--net",
+		TIMENS:   "--time",
+		CGROUPNS: "--cgroup",
 	}
 
 	pinnedNamespace := uuid.New().String()
@@ -102,6 +104,8 @@ func (mgr *NamespaceManager) NewPodNamespaces(cfg *PodNamespacesConfig) ([]Names
 		}
 		if ns.Host {
 			arg += "=host"
+		} else if !NamespaceSupported(ns.Type) {
+			return nil, fmt.Errorf("%s namespaces are not supported by the kernel", ns.Type)
 		}
 		pinnsArgs = append(pinnsArgs, arg)
 		ns.Path = filepath.Join(mgr.namespacesDir, string(ns.Type)+"ns", pinnedNamespace)
@@ -112,6 +116,14 @@ func (mgr *NamespaceManager) NewPodNamespaces(cfg *PodNamespacesConfig) ([]Names
 		}
 	}
 
+	for _, offset := range cfg.TimeOffsets {
+		pinnsArgs = append(pinnsArgs, "--time-offset="+offset.pinnsArg())
+	}
+
+	if cfg.CgroupPath != "" {
+		pinnsArgs = append(pinnsArgs, "--cgroup-path="+cfg.CgroupPath)
+	}
+
 	if cfg.IDMappings != nil {
 		pinnsArgs = append(pinnsArgs,
 			"--uid-mapping="+getMappingsForPinns(cfg.IDMappings.UIDs()),
@@ -219,6 +231,13 @@ func (mgr *NamespaceManager) dirForType(ns NSType) string {
 	return filepath.Join(mgr.namespacesDir, string(ns)+"ns")
 }
 
+// NamespaceSupported returns true if the kernel supports namespaces of the
+// provided type.
+func NamespaceSupported(nsType NSType) bool {
+	_, err := os.Stat(filepath.Join("/proc/self/ns", string(nsType)))
+	return err == nil
+}
+
 // NamespacePathFromProc returns the namespace path of type nsType for a given pid and type.
 func NamespacePathFromProc(nsType NSType, pid int) string {
 	// verify nsPath exists on the host. This will prevent us from fatally erroring
@@ -0,0 +1,182 @@
+package nsmgr
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// procDir is the proc file system used to find the namespaces entered by
+// processes.
+const procDir = "/proc"
+
+// PinnedNamespace is a namespace file within the namespaces directory.
+type PinnedNamespace struct {
+	// Path is the path of the namespace file.
+	Path string
+
+	// Type is the namespace type.
+	Type NSType
+
+	// Modified is the time the namespace file has been modified, which is
+	// the time the namespace has been created for pinned namespaces.
+	Modified time.Time
+}
+
+// nsKey identifies a namespace by the device and inode of its nsfs file.
+type nsKey struct {
+	dev uint64
+	ino uint64
+}
+
+// PinnedNamespaces returns the namespace files pinned by the namespace
+// manager within the namespaces directory. Other files, like the network
+// namespaces created by "ip netns" within the same directory, are skipped.
+func (mgr *NamespaceManager) PinnedNamespaces() ([]*PinnedNamespace, error) {
+	pinned := []*PinnedNamespace{}
+	for _, nsType := range supportedNamespacesForPinning() {
+		dir := mgr.dirForType(nsType)
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read namespaces sub-dir: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !pinnedName(nsType, entry.Name()) {
+				continue
+			}
+			info, err := entry.Info()
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("stat namespace file: %w", err)
+			}
+			pinned = append(pinned, &PinnedNamespace{
+				Path:     filepath.Join(dir, entry.Name()),
+				Type:     nsType,
+				Modified: info.ModTime(),
+			})
+		}
+	}
+	return pinned, nil
+}
+
+// RemoveOrphanedNamespaces unmounts and removes the pinned namespaces which
+// are not in use according to inUse, have not been modified after the
+// provided time and are not entered by any process. It returns the removed
+// namespaces. Namespaces failing to be removed are logged and skipped.
+func (mgr *NamespaceManager) RemoveOrphanedNamespaces(inUse func(path string) bool, modifiedBefore time.Time) ([]*PinnedNamespace, error) {
+	pinned, err := mgr.PinnedNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	candidates := []*PinnedNamespace{}
+	for _, ns := range pinned {
+		if ns.Modified.After(modifiedBefore) || inUse(ns.Path) {
+			continue
+		}
+		candidates = append(candidates, ns)
+	}
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	entered, err := enteredNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	removed := []*PinnedNamespace{}
+	for _, ns := range candidates {
+		isNSFS, key, err := namespaceKey(ns.Path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			logrus.Warnf("Unable to check orphaned namespace %s: %v", ns.Path, err)
+			continue
+		}
+		if isNSFS {
+			if entered[key] {
+				logrus.Debugf("Keeping orphaned namespace %s, which is entered by processes", ns.Path)
+				continue
+			}
+			// try to unmount, ignoring "not mounted" (EINVAL) error.
+			if err := unix.Unmount(ns.Path, unix.MNT_DETACH); err != nil && err != unix.EINVAL {
+				logrus.Warnf("Unable to unmount orphaned namespace %s: %v", ns.Path, err)
+				continue
+			}
+		}
+		if err := os.Remove(ns.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logrus.Warnf("Unable to remove orphaned namespace %s: %v", ns.Path, err)
+			continue
+		}
+		removed = append(removed, ns)
+	}
+	return removed, nil
+}
+
+// pinnedName returns true if the provided file name is one used by the
+// namespace manager for the namespace type. Namespaces pinned by pinns are
+// named by a UUID, while the PID namespaces pinned from proc entries are
+// temporary files named after their type.
+func pinnedName(nsType NSType, name string) bool {
+	if nsType == PIDNS {
+		_, err := strconv.ParseUint(strings.TrimPrefix(name, string(PIDNS)), 10, 64)
+		return strings.HasPrefix(name, string(PIDNS)) && err == nil
+	}
+	_, err := uuid.Parse(name)
+	return err == nil && len(name) == 36
+}
+
+// namespaceKey returns whether the file at the provided path is a namespace
+// and its key if so. Pinned namespaces are bind mounts of nsfs files, while
+// files which failed to be pinned are regular files.
+func namespaceKey(path string) (bool, nsKey, error) {
+	var fs unix.Statfs_t
+	if err := unix.Statfs(path, &fs); err != nil {
+		return false, nsKey{}, &os.PathError{Op: "statfs", Path: path, Err: err}
+	}
+	if fs.Type != unix.NSFS_MAGIC {
+		return false, nsKey{}, nil
+	}
+	var st unix.Stat_t
+	if err := unix.Stat(path, &st); err != nil {
+		return false, nsKey{}, &os.PathError{Op: "stat", Path: path, Err: err}
+	}
+	return true, nsKey{dev: uint64(st.Dev), ino: st.Ino}, nil //nolint:unconvert // the type of Dev differs between architectures
+}
+
+// enteredNamespaces returns the namespaces of all types which are entered by
+// any process.
+func enteredNamespaces() (map[nsKey]bool, error) {
+	entries, err := os.ReadDir(procDir)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", procDir, err)
+	}
+	entered := make(map[nsKey]bool)
+	for _, entry := range entries {
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue
+		}
+		for _, nsType := range supportedNamespacesForPinning() {
+			var st unix.Stat_t
+			// processes may exit in the meantime
+			if err := unix.Stat(filepath.Join(procDir, entry.Name(), "ns", string(nsType)), &st); err != nil {
+				continue
+			}
+			entered[nsKey{dev: uint64(st.Dev), ino: st.Ino}] = true //nolint:unconvert // the type of Dev differs between architectures
+		}
+	}
+	return entered, nil
+}
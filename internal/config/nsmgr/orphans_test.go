@@ -0,0 +1,75 @@
+package nsmgr_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/config/nsmgr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("RemoveOrphanedNamespaces", func() {
+	const (
+		orphaned = "0b4f4e07-4cd7-4d8e-8e36-8a1a0c7c1c42"
+		used     = "5b5c9d3e-0a4b-4a3c-9a40-0f6b6a6cbd1e"
+	)
+	var (
+		sut *nsmgr.NamespaceManager
+		dir string
+	)
+
+	BeforeEach(func() {
+		dir = t.MustTempDir("namespaces")
+		sut = nsmgr.New(dir, "")
+		Expect(sut.Initialize()).To(Succeed())
+	})
+
+	create := func(nsDir, name string) string {
+		path := filepath.Join(dir, nsDir, name)
+		Expect(os.WriteFile(path, nil, 0o644)).To(Succeed())
+		return path
+	}
+
+	It("should remove orphaned namespaces only", func() {
+		// Given
+		orphanedPath := create("netns", orphaned)
+		usedPath := create("ipcns", used)
+		pidPath := create("pidns", "pid1234")
+		ipNetnsPath := create("netns", "my-netns")
+
+		// When
+		removed, err := sut.RemoveOrphanedNamespaces(func(path string) bool {
+			return path == usedPath
+		}, time.Now())
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(HaveLen(2))
+		Expect(removed[0].Path).To(Equal(orphanedPath))
+		Expect(removed[0].Type).To(Equal(nsmgr.NETNS))
+		Expect(removed[1].Path).To(Equal(pidPath))
+		Expect(removed[1].Type).To(Equal(nsmgr.PIDNS))
+		Expect(orphanedPath).NotTo(BeAnExistingFile())
+		Expect(pidPath).NotTo(BeAnExistingFile())
+		Expect(usedPath).To(BeAnExistingFile())
+		Expect(ipNetnsPath).To(BeAnExistingFile())
+	})
+
+	It("should keep recently created namespaces", func() {
+		// Given
+		path := create("utsns", orphaned)
+
+		// When
+		removed, err := sut.RemoveOrphanedNamespaces(func(string) bool {
+			return false
+		}, time.Now().Add(-time.Minute))
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(BeEmpty())
+		Expect(path).To(BeAnExistingFile())
+	})
+})
@@ -6,7 +6,9 @@ package nsmgr
 import (
 	"fmt"
 	"os"
+	"strings"
 	"sync"
+	"time"
 
 	nspkg "github.com/containernetworking/plugins/pkg/ns"
 	"github.com/containers/storage/pkg/idtools"
@@ -22,20 +24,77 @@ const (
 	UTSNS                NSType = "uts"
 	USERNS               NSType = "user"
 	PIDNS                NSType = "pid"
-	ManagedNamespacesNum        = 5
+	TIMENS               NSType = "time"
+	CGROUPNS             NSType = "cgroup"
+	ManagedNamespacesNum        = 7
 )
 
 // supportedNamespacesForPinning returns a slice of
 // the names of namespaces that CRI-O supports
 // pinning.
 func supportedNamespacesForPinning() []NSType {
-	return []NSType{NETNS, IPCNS, UTSNS, USERNS, PIDNS}
+	return []NSType{NETNS, IPCNS, UTSNS, USERNS, PIDNS, TIMENS, CGROUPNS}
 }
 
 type PodNamespacesConfig struct {
-	Namespaces []*PodNamespaceConfig
-	IDMappings *idtools.IDMappings
-	Sysctls    map[string]string
+	Namespaces  []*PodNamespaceConfig
+	IDMappings  *idtools.IDMappings
+	Sysctls     map[string]string
+	TimeOffsets []TimeOffset
+	// CgroupPath is the cgroup on disk a new cgroup namespace is created in,
+	// which becomes the root of the namespace.
+	CgroupPath string
+}
+
+// TimeOffset is the offset of a clock within a time namespace relative to
+// the clock of the host.
+type TimeOffset struct {
+	// Clock is either "monotonic" or "boottime", the clocks which can be
+	// offset by the kernel.
+	Clock  string
+	Offset time.Duration
+}
+
+// ParseTimeOffsets parses the time offsets in the format
+// "<clock>=<duration>[,<clock>=<duration>]", for example
+// "monotonic=24h,boottime=-1h".
+func ParseTimeOffsets(value string) ([]TimeOffset, error) {
+	offsets := []TimeOffset{}
+	if strings.TrimSpace(value) == "" {
+		return offsets, nil
+	}
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(value, ",") {
+		clock, duration, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("time offset %q is not in the format <clock>=<duration>", entry)
+		}
+		if clock != "monotonic" && clock != "boottime" {
+			return nil, fmt.Errorf("invalid clock %q of time offset, must be monotonic or boottime", clock)
+		}
+		if seen[clock] {
+			return nil, fmt.Errorf("duplicate time offset for clock %s", clock)
+		}
+		seen[clock] = true
+		offset, err := time.ParseDuration(duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time offset for clock %s: %w", clock, err)
+		}
+		offsets = append(offsets, TimeOffset{Clock: clock, Offset: offset})
+	}
+	return offsets, nil
+}
+
+// pinnsArg returns the offset in the format expected by pinns, which is
+// "<clock>:<seconds>:<nanoseconds>" with non-negative nanoseconds.
+func (o TimeOffset) pinnsArg() string {
+	sec := int64(o.Offset / time.Second)
+	nsec := int64(o.Offset % time.Second)
+	if nsec < 0 {
+		sec--
+		nsec += int64(time.Second)
+	}
+	return fmt.Sprintf("%s:%d:%d", o.Clock, sec, nsec)
 }
 
 type PodNamespaceConfig struct {
@@ -49,7 +108,8 @@ type Namespace interface {
 	// Path returns the bind mount path of the namespace.
 	Path() string
 
-	// Type returns the namespace type (net, ipc, user, pid or uts).
+	// Type returns the namespace type (net, ipc, user, pid, uts, time or
+	// cgroup).
 	Type() NSType
 
 	// Remove ensures this namespace is closed and removed.
@@ -80,7 +140,7 @@ func (n *namespace) Path() string {
 	return n.nsPath
 }
 
-// Type returns the namespace type (net, ipc, user, pid or uts).
+// Type returns the namespace type (net, ipc, user, pid, uts, time or cgroup).
 func (n *namespace) Type() NSType {
 	return n.nsType
 }
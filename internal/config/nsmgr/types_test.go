@@ -0,0 +1,51 @@
+package nsmgr_test
+
+import (
+	"time"
+
+	"github.com/cri-o/cri-o/internal/config/nsmgr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("ParseTimeOffsets", func() {
+	It("should succeed with valid offsets", func() {
+		// Given
+		// When
+		res, err := nsmgr.ParseTimeOffsets("boottime=24h, monotonic=-1h30m")
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal([]nsmgr.TimeOffset{
+			{Clock: "boottime", Offset: 24 * time.Hour},
+			{Clock: "monotonic", Offset: -90 * time.Minute},
+		}))
+	})
+
+	It("should succeed without offsets", func() {
+		// Given
+		// When
+		res, err := nsmgr.ParseTimeOffsets("")
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(BeEmpty())
+	})
+
+	DescribeTable("should fail with invalid offsets",
+		func(value string) {
+			// Given
+			// When
+			res, err := nsmgr.ParseTimeOffsets(value)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(res).To(BeNil())
+		},
+		Entry("missing separator", "boottime"),
+		Entry("unsupported clock", "realtime=1h"),
+		Entry("invalid duration", "boottime=1d"),
+		Entry("duplicate clock", "boottime=1h,boottime=2h"),
+	)
+})
@@ -31,6 +31,10 @@ type Notifier struct {
 	timer          *time.Timer
 	timeLock       sync.Mutex
 	stopContainers bool
+	record         bool
+	recorder       *recorder
+	policy         *notifierPolicy
+	stopping       atomic.Bool
 }
 
 // StopContainers returns if the notifier should stop containers or not.
@@ -38,6 +42,12 @@ func (n *Notifier) StopContainers() bool {
 	return n.stopContainers
 }
 
+// ShouldStop returns true only for its first call, which ensures that the
+// container gets stopped once.
+func (n *Notifier) ShouldStop() bool {
+	return n.stopping.CompareAndSwap(false, true)
+}
+
 // Close can be used to close the notifier listener.
 func (n *Notifier) Close() error {
 	return n.listener.Close()
@@ -53,6 +63,21 @@ func (n *Notifier) AddSyscall(syscall string) {
 	}
 }
 
+// Recording returns if the notifier records the syscalls of the container
+// into a seccomp profile.
+func (n *Notifier) Recording() bool {
+	return n.record
+}
+
+// WriteProfile writes the seccomp profile recorded by the notifier to the
+// provided path.
+func (n *Notifier) WriteProfile(path string) error {
+	if !n.record {
+		return errors.New("notifier is not recording")
+	}
+	return n.recorder.writeProfile(path)
+}
+
 // UsedSyscalls returns a string representation of the used syscalls, sorted by
 // their name.
 func (n *Notifier) UsedSyscalls() string {
@@ -83,8 +108,10 @@ func (n *Notifier) OnExpired(callback func()) {
 
 // Notification is a seccomp notification which gets sent to the CRI-O server.
 type Notification struct {
-	ctx                  context.Context
-	containerID, syscall string
+	ctx                          context.Context
+	containerID, syscall, action string
+	pid                          uint32
+	args                         []uint64
 }
 
 // Ctx returns the context of the notification.
@@ -102,6 +129,99 @@ func (n *Notification) Syscall() string {
 	return n.syscall
 }
 
+// Action returns the notifier action which applies to the syscall of the
+// notification.
+func (n *Notification) Action() string {
+	return n.action
+}
+
+// Pid returns the host process ID which used the syscall.
+func (n *Notification) Pid() uint32 {
+	return n.pid
+}
+
+// Args returns the arguments of the syscall.
+func (n *Notification) Args() []uint64 {
+	return n.args
+}
+
+// notifierPolicy contains the notifier actions parsed from the
+// SeccompNotifierActionAnnotation. Its value is a comma separated list of an
+// optional default action, which applies to the syscalls blocked by the
+// profile, and "<syscall>=<action>" rules.
+type notifierPolicy struct {
+	defaultAction string
+	syscalls      map[string]string
+}
+
+// parseNotifierPolicy parses the value of the
+// SeccompNotifierActionAnnotation, for example "audit,ptrace=kill".
+func parseNotifierPolicy(value string) (*notifierPolicy, error) {
+	policy := &notifierPolicy{
+		defaultAction: annotations.SeccompNotifierActionLog,
+		syscalls:      make(map[string]string),
+	}
+	hasDefault := false
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		syscall, action, isRule := strings.Cut(item, "=")
+		if !isRule {
+			if hasDefault {
+				return nil, fmt.Errorf("multiple default seccomp notifier actions in %q", value)
+			}
+			if err := validateNotifierAction(item); err != nil {
+				return nil, err
+			}
+			policy.defaultAction = item
+			hasDefault = true
+			continue
+		}
+
+		syscall, action = strings.TrimSpace(syscall), strings.TrimSpace(action)
+		if syscall == "" {
+			return nil, fmt.Errorf("missing syscall in seccomp notifier rule %q", item)
+		}
+		if _, ok := policy.syscalls[syscall]; ok {
+			return nil, fmt.Errorf("multiple seccomp notifier rules for syscall %q", syscall)
+		}
+		if err := validateNotifierAction(action); err != nil {
+			return nil, err
+		}
+		policy.syscalls[syscall] = action
+	}
+	return policy, nil
+}
+
+func validateNotifierAction(action string) error {
+	switch action {
+	case annotations.SeccompNotifierActionLog,
+		annotations.SeccompNotifierActionStop,
+		annotations.SeccompNotifierActionKill,
+		annotations.SeccompNotifierActionAudit:
+		return nil
+	}
+	return fmt.Errorf("unknown seccomp notifier action %q", action)
+}
+
+// action returns the notifier action for the provided syscall.
+func (p *notifierPolicy) action(syscall string) string {
+	if action, ok := p.syscalls[syscall]; ok {
+		return action
+	}
+	return p.defaultAction
+}
+
+// continuous returns true if the notifier has to keep handling notifications
+// after the first blocked syscall, which is the case if syscalls get allowed
+// and audited or if later syscalls can have a different action.
+func (p *notifierPolicy) continuous() bool {
+	return p.defaultAction == annotations.SeccompNotifierActionAudit || len(p.syscalls) > 0
+}
+
 func (c *Config) injectNotifier(
 	ctx context.Context,
 	msgChan chan Notification,
@@ -114,12 +234,38 @@ func (c *Config) injectNotifier(
 	if containerID == "" || sandboxAnnotations == nil || msgChan == nil {
 		return nil, nil
 	}
-	if _, ok := sandboxAnnotations[annotations.SeccompNotifierActionAnnotation]; !ok {
+	_, record := sandboxAnnotations[annotations.SeccompProfileRecordAnnotation]
+	action, ok := sandboxAnnotations[annotations.SeccompNotifierActionAnnotation]
+	if !ok && !record {
 		return nil, nil
 	}
 
-	log.Infof(ctx, "Injecting seccomp notifier into seccomp profile of container %s", containerID)
+	if record {
+		log.Infof(ctx, "Recording the syscalls of container %s into a seccomp profile", containerID)
+		setupRecordingProfile(profile)
+	} else {
+		policy, err := parseNotifierPolicy(action)
+		if err != nil {
+			return nil, err
+		}
+		log.Infof(ctx, "Injecting seccomp notifier into seccomp profile of container %s", containerID)
+		overrideNotifierActions(ctx, profile)
+		addNotifierRules(profile, policy)
+	}
+
+	profile.ListenerPath = filepath.Join(c.NotifierPath(), containerID)
 
+	notifier, err := NewNotifier(ctx, msgChan, containerID, profile.ListenerPath, sandboxAnnotations)
+	if err != nil {
+		return nil, fmt.Errorf("unable to run notifier: %w", err)
+	}
+
+	return notifier, nil
+}
+
+// overrideNotifierActions replaces the actions of the profile which block
+// syscalls with the notify action.
+func overrideNotifierActions(ctx context.Context, profile *specs.LinuxSeccomp) {
 	isActionToOverride := func(action specs.LinuxSeccompAction) bool {
 		if action == specs.ActErrno ||
 			action == specs.ActKill ||
@@ -146,15 +292,39 @@ func (c *Config) injectNotifier(
 			profile.Syscalls[i].Action = specs.ActNotify
 		}
 	}
+}
 
-	profile.ListenerPath = filepath.Join(c.NotifierPath(), containerID)
-
-	notifier, err := NewNotifier(ctx, msgChan, containerID, profile.ListenerPath, sandboxAnnotations)
-	if err != nil {
-		return nil, fmt.Errorf("unable to run notifier: %w", err)
+// addNotifierRules notifies the syscalls of the policy rules independently of
+// the action the profile defines for them.
+func addNotifierRules(profile *specs.LinuxSeccomp, policy *notifierPolicy) {
+	if len(policy.syscalls) == 0 {
+		return
 	}
 
-	return notifier, nil
+	names := make([]string, 0, len(policy.syscalls))
+	for name := range policy.syscalls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	syscalls := make([]specs.LinuxSyscall, 0, len(profile.Syscalls)+1)
+	for _, syscall := range profile.Syscalls {
+		remaining := make([]string, 0, len(syscall.Names))
+		for _, name := range syscall.Names {
+			if _, ok := policy.syscalls[name]; !ok {
+				remaining = append(remaining, name)
+			}
+		}
+		if len(remaining) == 0 {
+			continue
+		}
+		syscall.Names = remaining
+		syscalls = append(syscalls, syscall)
+	}
+	profile.Syscalls = append(syscalls, specs.LinuxSyscall{
+		Names:  names,
+		Action: specs.ActNotify,
+	})
 }
 
 // NewNotifier starts the notifier for the provided arguments.
@@ -164,12 +334,39 @@ func NewNotifier(
 	containerID, listenerPath string,
 	annotationMap map[string]string,
 ) (*Notifier, error) {
+	_, record := annotationMap[annotations.SeccompProfileRecordAnnotation]
+	action, ok := annotationMap[annotations.SeccompNotifierActionAnnotation]
+	if !ok && !record {
+		return nil, fmt.Errorf("%s annotation not set on container", annotations.SeccompNotifierActionAnnotation)
+	}
+	var policy *notifierPolicy
+	if !record {
+		var err error
+		policy, err = parseNotifierPolicy(action)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	log.Infof(ctx, "Waiting for seccomp file descriptor on container %s", containerID)
 	listener, err := net.Listen("unix", listenerPath)
 	if err != nil {
 		return nil, fmt.Errorf("listen for seccomp socket: %w", err)
 	}
 
+	notifier := &Notifier{
+		listener:       listener,
+		syscalls:       sync.Map{},
+		timer:          nil,
+		timeLock:       sync.Mutex{},
+		stopContainers: policy != nil && policy.defaultAction == annotations.SeccompNotifierActionStop,
+		record:         record,
+		policy:         policy,
+	}
+	if record {
+		notifier.recorder = newRecorder()
+	}
+
 	go func() {
 		for {
 			conn, err := listener.Accept()
@@ -204,34 +401,32 @@ func NewNotifier(
 			}
 
 			log.Infof(ctx, "Received new seccomp fd: %v", newFd)
-			go handler(ctx, containerID, msgChan, libseccomp.ScmpFd(newFd))
+			if record {
+				go notifier.recorder.handle(ctx, containerID, libseccomp.ScmpFd(newFd))
+				continue
+			}
+			go handler(ctx, containerID, msgChan, policy, libseccomp.ScmpFd(newFd))
 		}
 	}()
 
-	action, ok := annotationMap[annotations.SeccompNotifierActionAnnotation]
-	if !ok {
-		return nil, fmt.Errorf("%s annotation not set on container", annotations.SeccompNotifierActionAnnotation)
-	}
-
-	return &Notifier{
-		listener:       listener,
-		syscalls:       sync.Map{},
-		timer:          nil,
-		timeLock:       sync.Mutex{},
-		stopContainers: action == annotations.SeccompNotifierActionStop,
-	}, nil
+	return notifier, nil
 }
 
 func handler(
 	ctx context.Context,
 	containerID string,
 	msgChan chan Notification,
+	policy *notifierPolicy,
 	fd libseccomp.ScmpFd,
 ) {
 	defer unix.Close(int(fd))
 	for {
 		req, err := libseccomp.NotifReceive(fd)
 		if err != nil {
+			if notifFdHungUp(fd) {
+				log.Debugf(ctx, "Stopping seccomp notifier handler for container %s", containerID)
+				return
+			}
 			log.Errorf(ctx, "Unable to receive notification: %v", err)
 			continue
 		}
@@ -247,7 +442,33 @@ func handler(
 			syscall, containerID, req.Pid,
 		)
 
-		msgChan <- Notification{ctx, containerID, syscall}
+		action := policy.action(syscall)
+		msg := Notification{ctx, containerID, syscall, action, req.Pid, req.Data.Args}
+
+		if action == annotations.SeccompNotifierActionAudit {
+			// The audited syscall must not wait for the server, which only
+			// writes the audit record.
+			select {
+			case msgChan <- msg:
+			default:
+				log.Warnf(ctx, "Dropping audit record of syscall %s for container %s because the notifier channel is full", syscall, containerID)
+			}
+			log.WithFields(ctx, map[string]interface{}{
+				"containerID": containerID,
+				"pid":         req.Pid,
+				"syscall":     syscall,
+				"args":        req.Data.Args,
+			}).Info("Allowed audited seccomp syscall")
+
+			resp := &libseccomp.ScmpNotifResp{
+				ID:    req.ID,
+				Flags: libseccomp.NotifRespFlagContinue,
+			}
+			if err := libseccomp.NotifRespond(fd, resp); err != nil {
+				log.Errorf(ctx, "Unable to send notification response: %v", err)
+			}
+			continue
+		}
 
 		resp := &libseccomp.ScmpNotifResp{
 			ID:    req.ID,
@@ -262,13 +483,30 @@ func handler(
 			continue
 		}
 
+		if action == annotations.SeccompNotifierActionKill {
+			// Kill the calling process before the syscall returns, the
+			// container gets stopped afterwards by the server.
+			if err := unix.Kill(int(req.Pid), unix.SIGKILL); err != nil {
+				log.Errorf(ctx, "Unable to kill process %d of container %s: %v", req.Pid, containerID, err)
+			}
+		}
+
 		if err = libseccomp.NotifRespond(fd, resp); err != nil {
-			log.Errorf(ctx, "Unable to send notification response: %v", err)
-			continue
+			// The notification is gone together with a killed process.
+			if action != annotations.SeccompNotifierActionKill {
+				log.Errorf(ctx, "Unable to send notification response: %v", err)
+				continue
+			}
+			log.Debugf(ctx, "Unable to send notification response for killed process %d: %v", req.Pid, err)
 		}
 
-		// We only catch the first syscall
-		break
+		msgChan <- msg
+
+		// We only catch the first blocked syscall, except if audited syscalls
+		// have to be allowed afterwards or per syscall rules apply.
+		if !policy.continuous() {
+			break
+		}
 	}
 }
 
@@ -0,0 +1,265 @@
+//go:build linux && cgo
+// +build linux,cgo
+
+package seccomp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/containers/common/pkg/seccomp"
+	"github.com/cri-o/cri-o/internal/log"
+	json "github.com/json-iterator/go"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	libseccomp "github.com/seccomp/libseccomp-golang"
+	"golang.org/x/sys/unix"
+)
+
+// maxSyscallNumber is the upper bound of the native syscall numbers which
+// get resolved for the recording profile.
+const maxSyscallNumber = 1024
+
+// recordingAllowedSyscalls are the syscalls which cannot be notified, because
+// the runtime requires them before the notifier has been connected. They are
+// always part of the recorded profile.
+var recordingAllowedSyscalls = []string{
+	"exit",
+	"exit_group",
+	"futex",
+	"rt_sigreturn",
+	"sendmsg",
+	"write",
+}
+
+// setupRecordingProfile converts the provided profile into a permissive
+// profile which notifies the listener about every syscall, except the ones
+// required by the runtime.
+func setupRecordingProfile(profile *specs.LinuxSeccomp) {
+	names := map[string]struct{}{}
+	for i := 0; i < maxSyscallNumber; i++ {
+		if name, err := libseccomp.ScmpSyscall(i).GetName(); err == nil {
+			names[name] = struct{}{}
+		}
+	}
+	// The default profile covers syscalls with architecture specific offsets
+	// as well.
+	for _, syscall := range DefaultProfile().Syscalls {
+		for _, name := range syscall.Names {
+			names[name] = struct{}{}
+		}
+	}
+	for _, name := range recordingAllowedSyscalls {
+		delete(names, name)
+	}
+
+	notify := make([]string, 0, len(names))
+	for name := range names {
+		notify = append(notify, name)
+	}
+	sort.Strings(notify)
+
+	profile.DefaultAction = specs.ActAllow
+	profile.DefaultErrnoRet = nil
+	profile.Syscalls = []specs.LinuxSyscall{{
+		Names:  notify,
+		Action: specs.ActNotify,
+	}}
+}
+
+// recorder collects the syscalls used by a container per architecture.
+type recorder struct {
+	lock     sync.Mutex
+	syscalls map[libseccomp.ScmpArch]map[string]struct{}
+}
+
+func newRecorder() *recorder {
+	return &recorder{syscalls: make(map[libseccomp.ScmpArch]map[string]struct{})}
+}
+
+// add records the provided syscall for the architecture.
+func (r *recorder) add(arch libseccomp.ScmpArch, syscall string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.syscalls[arch]; !ok {
+		r.syscalls[arch] = make(map[string]struct{})
+	}
+	r.syscalls[arch][syscall] = struct{}{}
+}
+
+// handle records every notified syscall of the provided seccomp fd and lets
+// the kernel continue executing it, until the container exits.
+func (r *recorder) handle(ctx context.Context, containerID string, fd libseccomp.ScmpFd) {
+	defer unix.Close(int(fd))
+	for {
+		req, err := libseccomp.NotifReceive(fd)
+		if err != nil {
+			if notifFdHungUp(fd) {
+				log.Debugf(ctx, "Stopping seccomp profile recording for container %s", containerID)
+				return
+			}
+			log.Errorf(ctx, "Unable to receive notification: %v", err)
+			continue
+		}
+
+		syscall, err := req.Data.Syscall.GetNameByArch(req.Data.Arch)
+		if err != nil {
+			log.Errorf(ctx, "Unable to decode syscall %v: %v", req.Data.Syscall, err)
+		} else {
+			r.add(req.Data.Arch, syscall)
+		}
+
+		resp := &libseccomp.ScmpNotifResp{
+			ID:    req.ID,
+			Flags: libseccomp.NotifRespFlagContinue,
+		}
+		if err := libseccomp.NotifRespond(fd, resp); err != nil {
+			log.Debugf(ctx, "Unable to send notification response: %v", err)
+		}
+	}
+}
+
+// notifFdHungUp returns true if all processes using the filter of the
+// provided seccomp fd have exited.
+func notifFdHungUp(fd libseccomp.ScmpFd) bool {
+	fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+	if _, err := unix.Poll(fds, 0); err != nil {
+		return false
+	}
+	return fds[0].Revents&(unix.POLLHUP|unix.POLLNVAL) != 0
+}
+
+// profile returns the recorded seccomp profile, which allows the recorded
+// syscalls and denies every other one.
+func (r *recorder) profile() (*seccomp.Seccomp, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	names := map[string]struct{}{}
+	for _, name := range recordingAllowedSyscalls {
+		names[name] = struct{}{}
+	}
+	recorded := map[seccomp.Arch]struct{}{}
+	for arch, syscalls := range r.syscalls {
+		a, err := profileArch(arch)
+		if err != nil {
+			return nil, err
+		}
+		recorded[a] = struct{}{}
+		for name := range syscalls {
+			names[name] = struct{}{}
+		}
+	}
+	archMap, err := recordedArchMap(recorded)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make([]string, 0, len(names))
+	for name := range names {
+		allowed = append(allowed, name)
+	}
+	sort.Strings(allowed)
+
+	return &seccomp.Seccomp{
+		DefaultAction: seccomp.ActErrno,
+		DefaultErrno:  "EPERM",
+		ArchMap:       archMap,
+		Syscalls: []*seccomp.Syscall{{
+			Names:  allowed,
+			Action: seccomp.ActAllow,
+		}},
+	}, nil
+}
+
+// recordedArchMap returns the architectures of the recorded profile. Like
+// for the default profile, the native architecture is listed together with
+// its compat architectures, which also covers the ones not used while
+// recording. Recorded architectures outside of them are added separately.
+func recordedArchMap(recorded map[seccomp.Arch]struct{}) ([]seccomp.Architecture, error) {
+	arch, err := libseccomp.GetNativeArch()
+	if err != nil {
+		return nil, fmt.Errorf("get native architecture: %w", err)
+	}
+	native, err := profileArch(arch)
+	if err != nil {
+		return nil, err
+	}
+
+	nativeArch := seccomp.Architecture{Arch: native, SubArches: []seccomp.Arch{}}
+	for _, a := range DefaultProfile().ArchMap {
+		if a.Arch == native {
+			nativeArch.SubArches = append(nativeArch.SubArches, a.SubArches...)
+			break
+		}
+	}
+	delete(recorded, native)
+	for _, sub := range nativeArch.SubArches {
+		delete(recorded, sub)
+	}
+
+	others := make([]seccomp.Architecture, 0, len(recorded))
+	for a := range recorded {
+		others = append(others, seccomp.Architecture{Arch: a, SubArches: []seccomp.Arch{}})
+	}
+	sort.Slice(others, func(i, j int) bool { return others[i].Arch < others[j].Arch })
+
+	return append([]seccomp.Architecture{nativeArch}, others...), nil
+}
+
+// writeProfile writes the recorded seccomp profile to the provided path.
+func (r *recorder) writeProfile(path string) error {
+	profile, err := r.profile()
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal seccomp profile: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create seccomp profile directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".seccomp-profile-")
+	if err != nil {
+		return fmt.Errorf("create seccomp profile: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write seccomp profile: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close seccomp profile: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("change seccomp profile permissions: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("rename seccomp profile: %w", err)
+	}
+	return nil
+}
+
+// profileArch converts the libseccomp architecture into the one used by
+// seccomp profiles.
+func profileArch(arch libseccomp.ScmpArch) (seccomp.Arch, error) {
+	switch arch {
+	case libseccomp.ArchAMD64:
+		return seccomp.ArchX86_64, nil
+	case libseccomp.ArchARM64:
+		return seccomp.ArchAARCH64, nil
+	case libseccomp.ArchInvalid, libseccomp.ArchNative:
+		return "", fmt.Errorf("unsupported architecture %v", arch)
+	}
+	name := arch.String()
+	if strings.Contains(name, " ") {
+		return "", fmt.Errorf("unsupported architecture: %s", name)
+	}
+	return seccomp.Arch("SCMP_ARCH_" + strings.ToUpper(name)), nil
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/containers/common/pkg/seccomp"
 	"github.com/cri-o/cri-o/internal/log"
+	crioann "github.com/cri-o/cri-o/pkg/annotations"
 	json "github.com/json-iterator/go"
 	"github.com/opencontainers/runtime-tools/generate"
 	"github.com/sirupsen/logrus"
@@ -174,6 +175,14 @@ func (c *Config) Profile() *seccomp.Seccomp {
 	return c.profile
 }
 
+// WithProfile returns a copy of the configuration which uses the provided
+// profile as default profile.
+func (c *Config) WithProfile(profile *seccomp.Seccomp) *Config {
+	copied := *c
+	copied.profile = profile
+	return &copied
+}
+
 // Setup can be used to setup the seccomp profile.
 func (c *Config) Setup(
 	ctx context.Context,
@@ -187,6 +196,21 @@ func (c *Config) Setup(
 	defer span.End()
 	log.Debugf(ctx, "Setup seccomp from profile field: %+v", profileField)
 
+	if _, ok := annotations[crioann.SeccompProfileRecordAnnotation]; ok && containerID != "" && msgChan != nil && !c.IsDisabled() {
+		// The recording profile replaces the requested one and uses the
+		// architectures of the default profile.
+		linuxSpecs, err := seccomp.LoadProfileFromConfig(DefaultProfile(), specGenerator.Config)
+		if err != nil {
+			return nil, "", fmt.Errorf("load default profile for recording: %w", err)
+		}
+		notifier, err := c.injectNotifier(ctx, msgChan, containerID, annotations, linuxSpecs)
+		if err != nil {
+			return nil, "", fmt.Errorf("inject notifier: %w", err)
+		}
+		specGenerator.Config.Linux.Seccomp = linuxSpecs
+		return notifier, types.SecurityProfile_Unconfined.String(), nil
+	}
+
 	if profileField == nil {
 		if !c.UseDefaultWhenEmpty() {
 			// running w/o seccomp, aka unconfined
@@ -243,7 +267,42 @@ func (c *Config) Setup(
 		)
 	}
 
-	linuxSpecs, err := seccomp.LoadProfileFromBytes(file, specGenerator.Config)
+	return c.setupFromContent(ctx, msgChan, containerID, annotations, specGenerator, file, localhostRef)
+}
+
+// SetupFromContent can be used to setup the provided seccomp profile content,
+// for example if it got pulled from a container registry. The provided ref is
+// returned as profile reference on success.
+func (c *Config) SetupFromContent(
+	ctx context.Context,
+	msgChan chan Notification,
+	containerID string,
+	annotations map[string]string,
+	specGenerator *generate.Generator,
+	content []byte,
+	ref string,
+) (*Notifier, string, error) {
+	ctx, span := log.StartSpan(ctx)
+	defer span.End()
+
+	if c.IsDisabled() {
+		return nil, "", errors.New(
+			"seccomp is not enabled, cannot run with custom profile",
+		)
+	}
+	return c.setupFromContent(ctx, msgChan, containerID, annotations, specGenerator, content, ref)
+}
+
+func (c *Config) setupFromContent(
+	ctx context.Context,
+	msgChan chan Notification,
+	containerID string,
+	annotations map[string]string,
+	specGenerator *generate.Generator,
+	content []byte,
+	ref string,
+) (*Notifier, string, error) {
+	linuxSpecs, err := seccomp.LoadProfileFromBytes(content, specGenerator.Config)
 	if err != nil {
 		return nil, "", fmt.Errorf("load local profile: %w", err)
 	}
@@ -252,5 +311,5 @@ func (c *Config) Setup(
 		return nil, "", fmt.Errorf("inject notifier: %w", err)
 	}
 	specGenerator.Config.Linux.Seccomp = linuxSpecs
-	return notifier, localhostRef, nil
+	return notifier, ref, nil
 }
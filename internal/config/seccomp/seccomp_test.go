@@ -3,10 +3,15 @@ package seccomp_test
 import (
 	"context"
 	"os"
+	"path/filepath"
 
+	containersseccomp "github.com/containers/common/pkg/seccomp"
 	"github.com/cri-o/cri-o/internal/config/seccomp"
+	"github.com/cri-o/cri-o/pkg/annotations"
+	json "github.com/json-iterator/go"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/opencontainers/runtime-tools/generate"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
@@ -160,5 +165,161 @@ var _ = t.Describe("Config", func() {
 			// Then
 			Expect(err).NotTo(BeNil())
 		})
+
+		It("should setup the recording profile if requested", func() {
+			if sut.IsDisabled() {
+				Skip("seccomp is not enabled")
+			}
+
+			// Given
+			generator, err := generate.New("linux")
+			Expect(err).To(BeNil())
+			sut.SetNotifierPath(t.MustTempDir("seccomp"))
+			field := &types.SecurityProfile{
+				ProfileType: types.SecurityProfile_Unconfined,
+			}
+
+			// When
+			notifier, ref, err := sut.Setup(
+				context.Background(),
+				make(chan seccomp.Notification),
+				"id",
+				map[string]string{annotations.SeccompProfileRecordAnnotation: "true"},
+				&generator,
+				field,
+			)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(notifier).NotTo(BeNil())
+			defer notifier.Close()
+			Expect(notifier.Recording()).To(BeTrue())
+			Expect(notifier.StopContainers()).To(BeFalse())
+			Expect(ref).To(Equal(types.SecurityProfile_Unconfined.String()))
+
+			profile := generator.Config.Linux.Seccomp
+			Expect(profile).NotTo(BeNil())
+			Expect(profile.DefaultAction).To(Equal(specs.ActAllow))
+			Expect(profile.ListenerPath).To(Equal(filepath.Join(sut.NotifierPath(), "id")))
+			Expect(profile.Syscalls).To(HaveLen(1))
+			Expect(profile.Syscalls[0].Action).To(Equal(specs.ActNotify))
+			Expect(profile.Syscalls[0].Names).To(ContainElement("read"))
+			Expect(profile.Syscalls[0].Names).NotTo(ContainElement("write"))
+		})
+
+		It("should write the recorded profile", func() {
+			if sut.IsDisabled() {
+				Skip("seccomp is not enabled")
+			}
+
+			// Given
+			generator, err := generate.New("linux")
+			Expect(err).To(BeNil())
+			sut.SetNotifierPath(t.MustTempDir("seccomp"))
+			notifier, _, err := sut.Setup(
+				context.Background(),
+				make(chan seccomp.Notification),
+				"id",
+				map[string]string{annotations.SeccompProfileRecordAnnotation: "true"},
+				&generator,
+				nil,
+			)
+			Expect(err).To(BeNil())
+			defer notifier.Close()
+			profilePath := filepath.Join(t.MustTempDir("profiles"), "namespace", "pod", "container.json")
+
+			// When
+			err = notifier.WriteProfile(profilePath)
+
+			// Then
+			Expect(err).To(BeNil())
+			content, err := os.ReadFile(profilePath)
+			Expect(err).To(BeNil())
+			profile := &containersseccomp.Seccomp{}
+			Expect(json.Unmarshal(content, profile)).To(Succeed())
+			Expect(profile.DefaultAction).To(Equal(containersseccomp.ActErrno))
+			Expect(profile.Architectures).To(HaveLen(1))
+			Expect(profile.Syscalls).To(HaveLen(1))
+			Expect(profile.Syscalls[0].Action).To(Equal(containersseccomp.ActAllow))
+			Expect(profile.Syscalls[0].Names).To(ContainElement("write"))
+		})
+
+		It("should notify syscalls with notifier rules", func() {
+			if sut.IsDisabled() {
+				Skip("seccomp is not enabled")
+			}
+
+			// Given
+			generator, err := generate.New("linux")
+			Expect(err).To(BeNil())
+			sut.SetNotifierPath(t.MustTempDir("seccomp"))
+
+			// When
+			notifier, _, err := sut.Setup(
+				context.Background(),
+				make(chan seccomp.Notification),
+				"id",
+				map[string]string{annotations.SeccompNotifierActionAnnotation: "audit, ptrace=kill,read=audit"},
+				&generator,
+				nil,
+			)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(notifier).NotTo(BeNil())
+			defer notifier.Close()
+			Expect(notifier.StopContainers()).To(BeFalse())
+
+			profile := generator.Config.Linux.Seccomp
+			Expect(profile).NotTo(BeNil())
+			last := profile.Syscalls[len(profile.Syscalls)-1]
+			Expect(last.Action).To(Equal(specs.ActNotify))
+			Expect(last.Names).To(Equal([]string{"ptrace", "read"}))
+			for _, syscall := range profile.Syscalls[:len(profile.Syscalls)-1] {
+				Expect(syscall.Names).NotTo(ContainElement("read"))
+			}
+		})
+
+		It("should fail with invalid notifier actions", func() {
+			if sut.IsDisabled() {
+				Skip("seccomp is not enabled")
+			}
+
+			for _, action := range []string{"invalid", "log,stop", "ptrace=invalid", "=audit", "ptrace=log,ptrace=kill"} {
+				// Given
+				generator, err := generate.New("linux")
+				Expect(err).To(BeNil())
+				sut.SetNotifierPath(t.MustTempDir("seccomp"))
+
+				// When
+				notifier, _, err := sut.Setup(
+					context.Background(),
+					make(chan seccomp.Notification),
+					"id",
+					map[string]string{annotations.SeccompNotifierActionAnnotation: action},
+					&generator,
+					nil,
+				)
+
+				// Then
+				Expect(err).NotTo(BeNil(), action)
+				Expect(notifier).To(BeNil())
+			}
+		})
+	})
+
+	t.Describe("Notifier", func() {
+		It("should stop the container only once", func() {
+			// Given
+			sut := &seccomp.Notifier{}
+
+			// When
+			first := sut.ShouldStop()
+			second := sut.ShouldStop()
+
+			// Then
+			Expect(first).To(BeTrue())
+			Expect(second).To(BeFalse())
+		})
 	})
 })
@@ -6,6 +6,7 @@ package seccomp
 import (
 	"context"
 
+	"github.com/containers/common/pkg/seccomp"
 	"github.com/opencontainers/runtime-tools/generate"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
@@ -30,6 +31,24 @@ func New() *Config {
 	}
 }
 
+// IsDisabled returns true if seccomp is disabled either via the missing
+// `seccomp` buildtag or globally by the system.
+func (c *Config) IsDisabled() bool {
+	return !c.enabled
+}
+
+// Profile returns the currently loaded seccomp profile.
+func (c *Config) Profile() *seccomp.Seccomp {
+	return nil
+}
+
+// WithProfile returns a copy of the configuration which uses the provided
+// profile as default profile.
+func (c *Config) WithProfile(profile *seccomp.Seccomp) *Config {
+	copied := *c
+	return &copied
+}
+
 // Setup can be used to setup the seccomp profile.
 func (c *Config) Setup(
 	ctx context.Context,
@@ -38,9 +57,21 @@ func (c *Config) Setup(
 	annotations map[string]string,
 	specGenerator *generate.Generator,
 	profileField *types.SecurityProfile,
-	profilePath string,
-) (notifier *Notifier, err error) {
-	return nil, nil
+) (*Notifier, string, error) {
+	return nil, "", nil
+}
+
+// SetupFromContent can be used to setup the provided seccomp profile content.
+func (c *Config) SetupFromContent(
+	ctx context.Context,
+	msgChan chan Notification,
+	containerID string,
+	annotations map[string]string,
+	specGenerator *generate.Generator,
+	content []byte,
+	ref string,
+) (*Notifier, string, error) {
+	return nil, "", nil
 }
 
 // SetUseDefaultWhenEmpty uses the default seccomp profile if true is passed as
@@ -100,9 +131,21 @@ func (*Notifier) StopContainers() bool {
 	return false
 }
 
+func (*Notifier) Recording() bool {
+	return false
+}
+
+func (*Notifier) WriteProfile(path string) error {
+	return nil
+}
+
 func (*Notifier) OnExpired(callback func()) {
 }
 
+func (*Notifier) ShouldStop() bool {
+	return false
+}
+
 func (*Notification) Ctx() context.Context {
 	return nil
 }
@@ -114,3 +157,15 @@ func (*Notification) ContainerID() string {
 func (*Notification) Syscall() string {
 	return ""
 }
+
+func (*Notification) Action() string {
+	return ""
+}
+
+func (*Notification) Pid() uint32 {
+	return 0
+}
+
+func (*Notification) Args() []uint64 {
+	return nil
+}
@@ -75,6 +75,9 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("global-auth-file") {
 		config.GlobalAuthFile = ctx.String("global-auth-file")
 	}
+	if ctx.IsSet("namespaced-auth-dir") {
+		config.NamespacedAuthDir = ctx.String("namespaced-auth-dir")
+	}
 	if ctx.IsSet("signature-policy") {
 		config.SignaturePolicyPath = ctx.String("signature-policy")
 	}
@@ -156,18 +159,33 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("selinux") {
 		config.SELinux = ctx.Bool("selinux")
 	}
+	if ctx.IsSet("selinux-fixed-mcs") {
+		config.SELinuxFixedMCS = StringSliceTrySplit(ctx, "selinux-fixed-mcs")
+	}
+	if ctx.IsSet("selinux-mcs-reservations-file") {
+		config.SELinuxMCSReservationsFile = ctx.String("selinux-mcs-reservations-file")
+	}
 	if ctx.IsSet("seccomp-profile") {
 		config.SeccompProfile = ctx.String("seccomp-profile")
 	}
 	if ctx.IsSet("seccomp-use-default-when-empty") {
 		config.SeccompUseDefaultWhenEmpty = ctx.Bool("seccomp-use-default-when-empty")
 	}
+	if ctx.IsSet("seccomp-profile-record-dir") {
+		config.SeccompProfileRecordDir = ctx.String("seccomp-profile-record-dir")
+	}
 	if ctx.IsSet("apparmor-profile") {
 		config.ApparmorProfile = ctx.String("apparmor-profile")
 	}
+	if ctx.IsSet("generate-apparmor-profiles") {
+		config.GenerateApparmorProfiles = ctx.Bool("generate-apparmor-profiles")
+	}
 	if ctx.IsSet("blockio-config-file") {
 		config.BlockIOConfigFile = ctx.String("blockio-config-file")
 	}
+	if ctx.IsSet("admission-policy-file") {
+		config.AdmissionPolicyFile = ctx.String("admission-policy-file")
+	}
 	if ctx.IsSet("irqbalance-config-file") {
 		config.IrqBalanceConfigFile = ctx.String("irqbalance-config-file")
 	}
@@ -195,6 +213,12 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("default-sysctls") {
 		config.DefaultSysctls = StringSliceTrySplit(ctx, "default-sysctls")
 	}
+	if ctx.IsSet("denied-capabilities") {
+		config.DeniedCapabilities = StringSliceTrySplit(ctx, "denied-capabilities")
+	}
+	if ctx.IsSet("denied-sysctls") {
+		config.DeniedSysctls = StringSliceTrySplit(ctx, "denied-sysctls")
+	}
 	if ctx.IsSet("default-ulimits") {
 		config.DefaultUlimits = StringSliceTrySplit(ctx, "default-ulimits")
 	}
@@ -207,6 +231,27 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("log-journald") {
 		config.LogToJournald = ctx.Bool("log-journald")
 	}
+	if ctx.IsSet("log-forward-socket") {
+		config.LogForwardSocket = ctx.String("log-forward-socket")
+	}
+	if ctx.IsSet("log-forward-format") {
+		config.LogForwardFormat = ctx.String("log-forward-format")
+	}
+	if ctx.IsSet("log-forward-buffer-size") {
+		config.LogForwardBufferSize = ctx.Uint64("log-forward-buffer-size")
+	}
+	if ctx.IsSet("audit-log-path") {
+		config.AuditLogPath = ctx.String("audit-log-path")
+	}
+	if ctx.IsSet("audit-log-max-size") {
+		config.AuditLogMaxSize = ctx.Int64("audit-log-max-size")
+	}
+	if ctx.IsSet("audit-log-max-files") {
+		config.AuditLogMaxFiles = ctx.Uint64("audit-log-max-files")
+	}
+	if ctx.IsSet("audit-log-socket") {
+		config.AuditLogSocket = ctx.String("audit-log-socket")
+	}
 	if ctx.IsSet("cni-default-network") {
 		config.CNIDefaultNetwork = ctx.String("cni-default-network")
 	}
@@ -216,6 +261,42 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("cni-plugin-dir") {
 		config.PluginDirs = StringSliceTrySplit(ctx, "cni-plugin-dir")
 	}
+	if ctx.IsSet("cni-check-period") {
+		config.CNICheckPeriod = ctx.Int("cni-check-period")
+	}
+	if ctx.IsSet("cni-gc-period") {
+		config.CNIGCPeriod = ctx.Int("cni-gc-period")
+	}
+	if ctx.IsSet("cni-attachments-dir") {
+		config.CNIAttachmentsDir = ctx.String("cni-attachments-dir")
+	}
+	if ctx.IsSet("hostport-reservations-file") {
+		config.HostportReservationsFile = ctx.String("hostport-reservations-file")
+	}
+	if ctx.IsSet("hostport-ranges-max-ports") {
+		config.HostportRangesMaxPorts = ctx.Int("hostport-ranges-max-ports")
+	}
+	if ctx.IsSet("bandwidth-shaping") {
+		config.BandwidthShaping = libconfig.BandwidthShapingType(ctx.String("bandwidth-shaping"))
+	}
+	if ctx.IsSet("dns-options") {
+		config.DNSOptions = StringSliceTrySplit(ctx, "dns-options")
+	}
+	if ctx.IsSet("dns-namespace-options") {
+		config.DNSNamespaceOptions = StringSliceTrySplit(ctx, "dns-namespace-options")
+	}
+	if ctx.IsSet("dns-local-cache") {
+		config.DNSLocalCache = ctx.String("dns-local-cache")
+	}
+	if ctx.IsSet("dns-max-nameservers") {
+		config.DNSMaxNameservers = ctx.Int("dns-max-nameservers")
+	}
+	if ctx.IsSet("dns-max-searches") {
+		config.DNSMaxSearches = ctx.Int("dns-max-searches")
+	}
+	if ctx.IsSet("watch-pod-ips") {
+		config.WatchPodIPs = ctx.Bool("watch-pod-ips")
+	}
 	if ctx.IsSet("image-volumes") {
 		config.ImageVolumes = libconfig.ImageVolumesType(ctx.String("image-volumes"))
 	}
@@ -237,6 +318,12 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("minimum-mappable-gid") {
 		config.MinimumMappableGID = ctx.Int64("minimum-mappable-gid")
 	}
+	if ctx.IsSet("userns-range") {
+		config.UsernsRange = ctx.String("userns-range")
+	}
+	if ctx.IsSet("userns-allocations-file") {
+		config.UsernsAllocationsFile = ctx.String("userns-allocations-file")
+	}
 	if ctx.IsSet("log-level") {
 		config.LogLevel = ctx.String("log-level")
 	}
@@ -291,6 +378,9 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("pinns-path") {
 		config.PinnsPath = ctx.String("pinns-path")
 	}
+	if ctx.IsSet("namespaces-gc-period") {
+		config.NamespacesGCPeriod = ctx.Int("namespaces-gc-period")
+	}
 	if ctx.IsSet("no-pivot") {
 		config.NoPivot = ctx.Bool("no-pivot")
 	}
@@ -390,6 +480,9 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("enable-pod-events") {
 		config.EnablePodEvents = ctx.Bool("enable-pod-events")
 	}
+	if ctx.IsSet("exec-sync-concurrency-limit") {
+		config.ExecSyncConcurrencyLimit = ctx.Uint64("exec-sync-concurrency-limit")
+	}
 	if ctx.IsSet("hostnetwork-disable-selinux") {
 		config.HostNetworkDisableSELinux = ctx.Bool("hostnetwork-disable-selinux")
 	}
@@ -535,6 +628,13 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			EnvVars:   []string{"CONTAINER_GLOBAL_AUTH_FILE"},
 			TakesFile: true,
 		},
+		&cli.StringFlag{
+			Name:      "namespaced-auth-dir",
+			Usage:     "Path to the root directory for namespaced credentials used for pulling seccomp profile images. Must be an absolute path.",
+			Value:     defConf.NamespacedAuthDir,
+			EnvVars:   []string{"CONTAINER_NAMESPACED_AUTH_DIR"},
+			TakesFile: true,
+		},
 		&cli.StringFlag{
 			Name:      "signature-policy",
 			Usage:     "Path to signature policy JSON file.",
@@ -630,17 +730,37 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			EnvVars: []string{"CONTAINER_SECCOMP_USE_DEFAULT_WHEN_EMPTY"},
 			Value:   defConf.Seccomp().UseDefaultWhenEmpty(),
 		},
+		&cli.StringFlag{
+			Name:      "seccomp-profile-record-dir",
+			Usage:     "Directory the seccomp profiles recorded for containers are written to.",
+			EnvVars:   []string{"CONTAINER_SECCOMP_PROFILE_RECORD_DIR"},
+			Value:     defConf.SeccompProfileRecordDir,
+			TakesFile: true,
+		},
 		&cli.StringFlag{
 			Name:    "apparmor-profile",
 			Usage:   "Name of the apparmor profile to be used as the runtime's default. This only takes effect if the user does not specify a profile via the Kubernetes Pod's metadata annotation.",
 			Value:   defConf.ApparmorProfile,
 			EnvVars: []string{"CONTAINER_APPARMOR_PROFILE"},
 		},
+		&cli.BoolFlag{
+			Name:    "generate-apparmor-profiles",
+			Usage:   "Generate and load an AppArmor profile derived from the spec of every container which would otherwise use the default profile.",
+			Value:   defConf.GenerateApparmorProfiles,
+			EnvVars: []string{"CONTAINER_GENERATE_APPARMOR_PROFILES"},
+		},
 		&cli.StringFlag{
 			Name:  "blockio-config-file",
 			Usage: "Path to the blockio class configuration file for configuring the cgroup blockio controller.",
 			Value: defConf.BlockIOConfigFile,
 		},
+		&cli.StringFlag{
+			Name:      "admission-policy-file",
+			Usage:     "Path to the node-local admission policy file, which can deny or mutate RunPodSandbox and CreateContainer requests.",
+			EnvVars:   []string{"CONTAINER_ADMISSION_POLICY_FILE"},
+			Value:     defConf.AdmissionPolicyFile,
+			TakesFile: true,
+		},
 		&cli.StringFlag{
 			Name:  "irqbalance-config-file",
 			Usage: "The irqbalance service config file which is used by CRI-O.",
@@ -657,6 +777,19 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			EnvVars: []string{"CONTAINER_SELINUX"},
 			Value:   defConf.SELinux,
 		},
+		&cli.StringSliceFlag{
+			Name:    "selinux-fixed-mcs",
+			Usage:   "List of \"<namespace>=<level>\" entries, which fix the SELinux MCS level of all pods within a namespace.",
+			EnvVars: []string{"CONTAINER_SELINUX_FIXED_MCS"},
+			Value:   cli.NewStringSlice(defConf.SELinuxFixedMCS...),
+		},
+		&cli.StringFlag{
+			Name:      "selinux-mcs-reservations-file",
+			Usage:     "Path to the file the SELinux MCS levels reserved for pods are persisted in.",
+			EnvVars:   []string{"CONTAINER_SELINUX_MCS_RESERVATIONS_FILE"},
+			Value:     defConf.SELinuxMCSReservationsFile,
+			TakesFile: true,
+		},
 		&cli.StringFlag{
 			Name:    "cgroup-manager",
 			Usage:   "cgroup manager (cgroupfs or systemd).",
@@ -681,6 +814,51 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			EnvVars: []string{"CONTAINER_LOG_JOURNALD"},
 			Value:   defConf.LogToJournald,
 		},
+		&cli.StringFlag{
+			Name:      "log-forward-socket",
+			Usage:     "Path to a unix socket the container log lines get forwarded to in addition to the kubernetes log file. An empty path disables the log forwarding.",
+			EnvVars:   []string{"CONTAINER_LOG_FORWARD_SOCKET"},
+			Value:     defConf.LogForwardSocket,
+			TakesFile: true,
+		},
+		&cli.StringFlag{
+			Name:    "log-forward-format",
+			Usage:   `Format of the lines forwarded to the log forward socket, either "json" or "syslog".`,
+			EnvVars: []string{"CONTAINER_LOG_FORWARD_FORMAT"},
+			Value:   defConf.LogForwardFormat,
+		},
+		&cli.Uint64Flag{
+			Name:    "log-forward-buffer-size",
+			Usage:   "Maximum number of log lines buffered while the log forward socket is unavailable.",
+			EnvVars: []string{"CONTAINER_LOG_FORWARD_BUFFER_SIZE"},
+			Value:   defConf.LogForwardBufferSize,
+		},
+		&cli.StringFlag{
+			Name:      "audit-log-path",
+			Usage:     "Path to the file the audit log of exec, attach, port forward and privileged container creation requests, as well as of syscalls audited by the seccomp notifier, gets appended to. An empty path disables the audit log file.",
+			EnvVars:   []string{"CONTAINER_AUDIT_LOG_PATH"},
+			Value:     defConf.AuditLogPath,
+			TakesFile: true,
+		},
+		&cli.Int64Flag{
+			Name:    "audit-log-max-size",
+			Usage:   "Size in bytes at which the audit log file gets rotated. Zero disables the rotation.",
+			EnvVars: []string{"CONTAINER_AUDIT_LOG_MAX_SIZE"},
+			Value:   defConf.AuditLogMaxSize,
+		},
+		&cli.Uint64Flag{
+			Name:    "audit-log-max-files",
+			Usage:   "Number of rotated audit log files to keep.",
+			EnvVars: []string{"CONTAINER_AUDIT_LOG_MAX_FILES"},
+			Value:   defConf.AuditLogMaxFiles,
+		},
+		&cli.StringFlag{
+			Name:      "audit-log-socket",
+			Usage:     "Path to a unix socket the audit log gets written to. An empty path disables the audit log socket.",
+			EnvVars:   []string{"CONTAINER_AUDIT_LOG_SOCKET"},
+			Value:     defConf.AuditLogSocket,
+			TakesFile: true,
+		},
 		&cli.StringFlag{
 			Name:    "cni-default-network",
 			Usage:   `Name of the default CNI network to select. If not set or "", then CRI-O will pick-up the first one found in --cni-config-dir.`,
@@ -700,6 +878,84 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			Usage:   "CNI plugin binaries directory.",
 			EnvVars: []string{"CONTAINER_CNI_PLUGIN_DIR"},
 		},
+		&cli.IntFlag{
+			Name:    "cni-check-period",
+			Usage:   "The number of seconds between running CNI CHECK on the networks of all running pods. If set to 0, the networks are not checked periodically.",
+			Value:   defConf.CNICheckPeriod,
+			EnvVars: []string{"CONTAINER_CNI_CHECK_PERIOD"},
+		},
+		&cli.IntFlag{
+			Name:    "cni-gc-period",
+			Usage:   "The number of seconds between releasing the CNI attachments which do not belong to any known pod. If set to 0, the attachments are only released for pods which fail to be restored on startup.",
+			Value:   defConf.CNIGCPeriod,
+			EnvVars: []string{"CONTAINER_CNI_GC_PERIOD"},
+		},
+		&cli.StringFlag{
+			Name:      "cni-attachments-dir",
+			Usage:     "Directory the pods whose CNI attachments have been created by CRI-O are recorded in. Only their attachments are released as stale.",
+			EnvVars:   []string{"CONTAINER_CNI_ATTACHMENTS_DIR"},
+			Value:     defConf.CNIAttachmentsDir,
+			TakesFile: true,
+		},
+		&cli.StringFlag{
+			Name:      "hostport-reservations-file",
+			Usage:     "Path to the file the host ports reserved by pods are persisted in.",
+			EnvVars:   []string{"CONTAINER_HOSTPORT_RESERVATIONS_FILE"},
+			Value:     defConf.HostportReservationsFile,
+			TakesFile: true,
+		},
+		&cli.IntFlag{
+			Name:    "hostport-ranges-max-ports",
+			Usage:   "Maximum number of host ports a pod can map by the io.kubernetes.cri-o.HostPortRanges annotation. If set to 0, the number is not limited.",
+			EnvVars: []string{"CONTAINER_HOSTPORT_RANGES_MAX_PORTS"},
+			Value:   defConf.HostportRangesMaxPorts,
+		},
+		&cli.StringFlag{
+			Name:  "bandwidth-shaping",
+			Value: string(defConf.BandwidthShaping),
+			Usage: "How the bandwidth limits of the kubernetes.io/ingress-bandwidth and kubernetes.io/egress-bandwidth pod annotations are applied ('cni' or 'native')" + `
+    1. cni: The limits are passed to the CNI plugins, which requires the
+       bandwidth plugin to be chained in the CNI configuration.
+    2. native: The limits are applied by CRI-O using traffic control on the
+       host side veth peer of the pod interface.`,
+			EnvVars: []string{"CONTAINER_BANDWIDTH_SHAPING"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "dns-options",
+			Usage:   "Resolver options added to the resolv.conf of all pods which do not set an option of the same name, for example \"ndots:2\".",
+			EnvVars: []string{"CONTAINER_DNS_OPTIONS"},
+			Value:   cli.NewStringSlice(defConf.DNSOptions...),
+		},
+		&cli.StringSliceFlag{
+			Name:    "dns-namespace-options",
+			Usage:   "List of \"<namespace>=<option>\" entries, which replace the resolver options of the same name of all pods within a namespace.",
+			EnvVars: []string{"CONTAINER_DNS_NAMESPACE_OPTIONS"},
+			Value:   cli.NewStringSlice(defConf.DNSNamespaceOptions...),
+		},
+		&cli.StringFlag{
+			Name:    "dns-local-cache",
+			Usage:   "IP address of a node-local DNS cache, which is added as first nameserver of all pods having nameservers.",
+			EnvVars: []string{"CONTAINER_DNS_LOCAL_CACHE"},
+			Value:   defConf.DNSLocalCache,
+		},
+		&cli.IntFlag{
+			Name:    "dns-max-nameservers",
+			Usage:   "Maximum number of nameservers of a pod. Additional nameservers are dropped with a warning. If set to 0, the number is not limited.",
+			EnvVars: []string{"CONTAINER_DNS_MAX_NAMESERVERS"},
+			Value:   defConf.DNSMaxNameservers,
+		},
+		&cli.IntFlag{
+			Name:    "dns-max-searches",
+			Usage:   "Maximum number of search domains of a pod. Additional search domains are dropped with a warning. If set to 0, the number is not limited.",
+			EnvVars: []string{"CONTAINER_DNS_MAX_SEARCHES"},
+			Value:   defConf.DNSMaxSearches,
+		},
+		&cli.BoolFlag{
+			Name:    "watch-pod-ips",
+			Usage:   "Watch the addresses of the network interface of the pods and update the pod IPs if they change after the network setup.",
+			EnvVars: []string{"CONTAINER_WATCH_POD_IPS"},
+			Value:   defConf.WatchPodIPs,
+		},
 		&cli.StringFlag{
 			Name:  "image-volumes",
 			Value: string(libconfig.ImageVolumesMkdir),
@@ -759,6 +1015,18 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			EnvVars: []string{"CONTAINER_DEFAULT_SYSCTLS"},
 			Value:   cli.NewStringSlice(defConf.DefaultSysctls...),
 		},
+		&cli.StringSliceFlag{
+			Name:    "denied-capabilities",
+			Usage:   "Capabilities non-privileged containers are not allowed to add.",
+			EnvVars: []string{"CONTAINER_DENIED_CAPABILITIES"},
+			Value:   cli.NewStringSlice(defConf.DeniedCapabilities...),
+		},
+		&cli.StringSliceFlag{
+			Name:    "denied-sysctls",
+			Usage:   "Sysctls non-privileged pods are not allowed to set. Entries ending with \"*\" deny all sysctls with the same prefix.",
+			EnvVars: []string{"CONTAINER_DENIED_SYSCTLS"},
+			Value:   cli.NewStringSlice(defConf.DeniedSysctls...),
+		},
 		&cli.StringSliceFlag{
 			Name:    "default-ulimits",
 			Usage:   "Ulimits to apply to containers by default (name=soft:hard).",
@@ -915,6 +1183,19 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			Value:   defConf.MinimumMappableGID,
 			EnvVars: []string{"CONTAINER_MINIMUM_MAPPABLE_GID"},
 		},
+		&cli.StringFlag{
+			Name:    "userns-range",
+			Usage:   `Range of host IDs assigned to the user namespaces of pods using the "auto" user namespace mode, in the format "<first ID>:<size>". If empty, the ranges get assigned by containers/storage instead.`,
+			Value:   defConf.UsernsRange,
+			EnvVars: []string{"CONTAINER_USERNS_RANGE"},
+		},
+		&cli.StringFlag{
+			Name:      "userns-allocations-file",
+			Usage:     "Path to the file the user namespace ranges assigned to pods are persisted in.",
+			Value:     defConf.UsernsAllocationsFile,
+			EnvVars:   []string{"CONTAINER_USERNS_ALLOCATIONS_FILE"},
+			TakesFile: true,
+		},
 		&cli.StringSliceFlag{
 			Name:    "allowed-devices",
 			Usage:   "Devices a user is allowed to specify with the \"io.kubernetes.cri-o.Devices\" allowed annotation.",
@@ -1001,6 +1282,12 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			Value:   defConf.NamespacesDir,
 			EnvVars: []string{"CONTAINER_NAMESPACES_DIR"},
 		},
+		&cli.IntFlag{
+			Name:    "namespaces-gc-period",
+			Usage:   "The number of seconds between removing the pinned namespaces which neither belong to any known pod nor are entered by any process. If set to 0, they are only removed on startup.",
+			Value:   defConf.NamespacesGCPeriod,
+			EnvVars: []string{"CONTAINER_NAMESPACES_GC_PERIOD"},
+		},
 		&cli.BoolFlag{
 			Name:    "no-pivot",
 			Usage:   "If true, the runtime will not use `pivot_root`, but instead use `MS_MOVE`.",
@@ -1114,6 +1401,12 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			Usage:   "If true, CRI-O starts sending the container events to the kubelet",
 			EnvVars: []string{"ENABLE_POD_EVENTS"},
 		},
+		&cli.Uint64Flag{
+			Name:    "exec-sync-concurrency-limit",
+			Value:   defConf.ExecSyncConcurrencyLimit,
+			Usage:   "Maximum number of exec sync requests, like exec probes, running in parallel on the node. A value of 0 disables the limit.",
+			EnvVars: []string{"CONTAINER_EXEC_SYNC_CONCURRENCY_LIMIT"},
+		},
 		&cli.StringFlag{
 			Name:  "irqbalance-config-restore-file",
 			Value: defConf.IrqBalanceConfigRestoreFile,
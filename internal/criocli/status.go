@@ -3,6 +3,7 @@ package criocli
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/cri-o/cri-o/internal/client"
 
@@ -53,6 +54,31 @@ var StatusCommand = &cli.Command{
 		Aliases: []string{"i"},
 		Name:    "info",
 		Usage:   "Retrieve generic information about CRI-O, such as the cgroup and storage driver.",
+	}, {
+		Action:  usernsSubCommand,
+		Aliases: []string{"u"},
+		Name:    "userns",
+		Usage:   "Display the user namespace ranges assigned to pods from the userns_range.",
+	}, {
+		Action:  selinuxSubCommand,
+		Aliases: []string{"se"},
+		Name:    "selinux",
+		Usage:   "Display the SELinux MCS levels reserved for pods and the collisions between them.",
+	}, {
+		Action:  networkSubCommand,
+		Aliases: []string{"net"},
+		Flags: []cli.Flag{&cli.StringFlag{
+			Name:    idArg,
+			Aliases: []string{"i"},
+			Usage:   "the pod sandbox ID",
+		}},
+		Name:  "network",
+		Usage: "Display the network state of the provided pod sandbox ID, including its CNI results and host ports.",
+	}, {
+		Action:  hostportsSubCommand,
+		Aliases: []string{"hp"},
+		Name:    "hostports",
+		Usage:   "Display the host ports allocated on the node and the pods they belong to.",
 	}},
 }
 
@@ -139,6 +165,133 @@ func info(c *cli.Context) error {
 	return nil
 }
 
+func usernsSubCommand(c *cli.Context) error {
+	crioClient, err := crioClient(c)
+	if err != nil {
+		return err
+	}
+
+	info, err := crioClient.UsernsInfo()
+	if err != nil {
+		return err
+	}
+
+	if info.Size == 0 {
+		fmt.Printf("userns range: not configured\n")
+		return nil
+	}
+	fmt.Printf("userns range: %d-%d\n", info.FirstID, uint64(info.FirstID)+uint64(info.Size)-1)
+	fmt.Printf("assigned ranges (format <host>:<size> <namespace>/<pod> <pod ID>):\n")
+	for _, a := range info.Allocations {
+		fmt.Printf("  %d:%d %s/%s %s\n", a.HostID, a.Size, a.Namespace, a.Pod, a.PodID)
+	}
+
+	return nil
+}
+
+func selinuxSubCommand(c *cli.Context) error {
+	crioClient, err := crioClient(c)
+	if err != nil {
+		return err
+	}
+
+	info, err := crioClient.SELinuxInfo()
+	if err != nil {
+		return err
+	}
+
+	if !info.Enabled {
+		fmt.Printf("selinux: disabled\n")
+		return nil
+	}
+	fmt.Printf("reserved levels (format <level> <source> <namespace>/<pod> <pod ID>):\n")
+	for _, r := range info.Reservations {
+		fmt.Printf("  %s %s %s/%s %s\n", r.Level, r.Source, r.Namespace, r.Pod, r.PodID)
+	}
+	if len(info.Collisions) > 0 {
+		fmt.Printf("collisions (format <level> <pod IDs>):\n")
+		for _, col := range info.Collisions {
+			fmt.Printf("  %s %s\n", col.Level, strings.Join(col.PodIDs, ","))
+		}
+	}
+
+	return nil
+}
+
+func networkSubCommand(c *cli.Context) error {
+	crioClient, err := crioClient(c)
+	if err != nil {
+		return err
+	}
+
+	id := c.String(idArg)
+	if id == "" {
+		return fmt.Errorf("the argument --%s cannot be empty", idArg)
+	}
+
+	info, err := crioClient.NetworkInfo(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("pod: %s/%s\n", info.Namespace, info.Pod)
+	fmt.Printf("host network: %v\n", info.HostNetwork)
+	fmt.Printf("netns path: %s\n", info.NetNSPath)
+	fmt.Printf("ips: %s\n", strings.Join(info.IPs, ", "))
+	if info.LastCheckTime != 0 {
+		result := "ok"
+		if info.LastCheckError != "" {
+			result = info.LastCheckError
+		}
+		fmt.Printf("last check: %v %s\n", time.Unix(0, info.LastCheckTime), result)
+	}
+	if info.Bandwidth != nil {
+		fmt.Printf("bandwidth (bits/s): ingress %d, egress %d\n", info.Bandwidth.IngressRate, info.Bandwidth.EgressRate)
+	}
+	fmt.Printf("networks (format <interface> <network> <cached CNI result>):\n")
+	for _, n := range info.Networks {
+		fmt.Printf("  %s %s %s\n", n.Interface, n.Network, n.Result)
+	}
+	fmt.Printf("hostports (format <host IP>:<host port>/<protocol> -> <container port> IPv<family> <open> <chains>):\n")
+	for _, hp := range info.Hostports {
+		chains := []string{}
+		for _, chain := range hp.Chains {
+			state := "missing"
+			if chain.Exists {
+				state = "exists"
+			}
+			chains = append(chains, chain.Name+"="+state)
+		}
+		fmt.Printf("  %s:%d/%s -> %d IPv%s open=%v %s\n",
+			hp.HostIP, hp.HostPort, hp.Protocol, hp.ContainerPort, hp.IPFamily, hp.Open, strings.Join(chains, ","))
+	}
+
+	return nil
+}
+
+func hostportsSubCommand(c *cli.Context) error {
+	crioClient, err := crioClient(c)
+	if err != nil {
+		return err
+	}
+
+	info, err := crioClient.HostportsInfo()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("hostports (format <host IP>:<host port>/<protocol> IPv<family> <open> <namespace>/<pod> <pod ID>):\n")
+	for _, hp := range info.Hostports {
+		owner := "<none>"
+		if hp.PodID != "" {
+			owner = fmt.Sprintf("%s/%s %s", hp.Namespace, hp.Pod, hp.PodID)
+		}
+		fmt.Printf("  %s:%d/%s IPv%s open=%v %s\n", hp.HostIP, hp.HostPort, hp.Protocol, hp.IPFamily, hp.Open, owner)
+	}
+
+	return nil
+}
+
 func crioClient(c *cli.Context) (client.CrioClient, error) {
 	return client.New(c.String(socketArg))
 }
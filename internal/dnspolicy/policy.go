@@ -0,0 +1,180 @@
+// Package dnspolicy applies the node-level DNS policy to the DNS
+// configuration requested for pods and validates the result before it gets
+// written to their resolv.conf.
+package dnspolicy
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// Policy is the DNS policy applied to the DNS configuration of all pods.
+type Policy struct {
+	// DefaultOptions are added to the resolver options of the pods which
+	// do not set an option of the same name.
+	DefaultOptions []string
+
+	// NamespaceOptions are the resolver options per namespace, which
+	// replace the options of the same name of the pods within the
+	// namespace.
+	NamespaceOptions map[string][]string
+
+	// LocalCache is the nameserver of a node-local DNS cache, which is
+	// queried before the nameservers of the pods.
+	LocalCache string
+
+	// MaxNameservers and MaxSearches limit the number of nameservers and
+	// search domains of the pods. Zero does not limit them.
+	MaxNameservers int
+	MaxSearches    int
+}
+
+// Result is the DNS configuration of a pod after applying the policy.
+type Result struct {
+	Servers  []string `json:"servers,omitempty"`
+	Searches []string `json:"searches,omitempty"`
+	Options  []string `json:"options,omitempty"`
+
+	// Warnings are the changes of the requested configuration, which the
+	// resolver of the pod would have applied silently otherwise.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ParseNamespaceOptions parses a list of "<namespace>=<option>" entries into
+// a map from namespace to options. A namespace may occur more than once to
+// set multiple options.
+func ParseNamespaceOptions(entries []string) (map[string][]string, error) {
+	options := make(map[string][]string)
+	for _, entry := range entries {
+		namespace, option, ok := strings.Cut(entry, "=")
+		if !ok || namespace == "" {
+			return nil, fmt.Errorf("%q is not in the format <namespace>=<option>", entry)
+		}
+		if err := ValidateOption(option); err != nil {
+			return nil, fmt.Errorf("namespace %q: %w", namespace, err)
+		}
+		options[namespace] = append(options[namespace], option)
+	}
+	return options, nil
+}
+
+// ValidateNameserver returns an error if the provided nameserver is not an IP
+// address, which may have a zone for IPv6 link-local addresses.
+func ValidateNameserver(server string) error {
+	ip, _, _ := strings.Cut(server, "%")
+	if net.ParseIP(ip) == nil {
+		return fmt.Errorf("invalid DNS nameserver %q", server)
+	}
+	return nil
+}
+
+// ValidateOption returns an error if the provided resolver option can not be
+// written to resolv.conf.
+func ValidateOption(option string) error {
+	if option == "" || strings.ContainsAny(option, " \t\r\n#;") {
+		return fmt.Errorf("invalid DNS option %q", option)
+	}
+	return nil
+}
+
+// Apply applies the policy to the DNS configuration requested for a pod
+// within the provided namespace. It returns nil if the pod does not request
+// any DNS configuration, in which case the one of the host is used, and an
+// error if the configuration can not be written to resolv.conf.
+func (p *Policy) Apply(namespace string, config *types.DNSConfig) (*Result, error) {
+	if config == nil || (len(config.Servers) == 0 && len(config.Searches) == 0 && len(config.Options) == 0) {
+		return nil, nil
+	}
+	if p == nil {
+		p = &Policy{}
+	}
+
+	for _, server := range config.Servers {
+		if err := ValidateNameserver(server); err != nil {
+			return nil, err
+		}
+	}
+	for _, search := range config.Searches {
+		if search == "" || strings.ContainsAny(search, " \t\r\n#;") {
+			return nil, fmt.Errorf("invalid DNS search domain %q", search)
+		}
+	}
+	for _, option := range config.Options {
+		if err := ValidateOption(option); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &Result{}
+	servers := config.Servers
+	if p.LocalCache != "" && len(servers) > 0 {
+		servers = append([]string{p.LocalCache}, servers...)
+	}
+	res.Servers = res.unique("nameserver", servers)
+	res.Searches = res.unique("search domain", config.Searches)
+	res.Servers = res.limit("nameservers", res.Servers, p.MaxNameservers)
+	res.Searches = res.limit("search domains", res.Searches, p.MaxSearches)
+	res.Options = mergeOptions(config.Options, p.DefaultOptions, p.NamespaceOptions[namespace])
+	return res, nil
+}
+
+// unique returns the provided values without duplicates and records a
+// warning for every dropped one.
+func (r *Result) unique(kind string, values []string) []string {
+	seen := make(map[string]bool, len(values))
+	res := make([]string, 0, len(values))
+	for _, value := range values {
+		if seen[value] {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("dropping duplicate %s %s", kind, value))
+			continue
+		}
+		seen[value] = true
+		res = append(res, value)
+	}
+	return res
+}
+
+// limit returns the first maxCount provided values and records a warning if
+// any got dropped.
+func (r *Result) limit(kind string, values []string, maxCount int) []string {
+	if maxCount <= 0 || len(values) <= maxCount {
+		return values
+	}
+	r.Warnings = append(r.Warnings, fmt.Sprintf(
+		"dropping %s %s, only %d are allowed", kind, strings.Join(values[maxCount:], " "), maxCount,
+	))
+	return values[:maxCount]
+}
+
+// mergeOptions merges the requested options with the default options not
+// requested and replaces the options of the same name by the overrides. The
+// name of an option is the part before the colon, like "ndots" for
+// "ndots:5".
+func mergeOptions(requested, defaults, overrides []string) []string {
+	res := []string{}
+	index := map[string]int{}
+	set := func(option string, replace bool) {
+		name, _, _ := strings.Cut(option, ":")
+		if i, ok := index[name]; ok {
+			if replace {
+				res[i] = option
+			}
+			return
+		}
+		index[name] = len(res)
+		res = append(res, option)
+	}
+	for _, option := range requested {
+		set(option, true)
+	}
+	for _, option := range defaults {
+		set(option, false)
+	}
+	for _, option := range overrides {
+		set(option, true)
+	}
+	return res
+}
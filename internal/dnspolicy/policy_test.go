@@ -0,0 +1,171 @@
+package dnspolicy_test
+
+import (
+	"github.com/cri-o/cri-o/internal/dnspolicy"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// The actual test suite
+var _ = t.Describe("Policy", func() {
+	It("should keep the configuration without policy", func() {
+		// Given
+		var sut *dnspolicy.Policy
+
+		// When
+		res, err := sut.Apply("default", &types.DNSConfig{
+			Servers:  []string{"10.96.0.10"},
+			Searches: []string{"default.svc.cluster.local", "svc.cluster.local"},
+			Options:  []string{"ndots:5"},
+		})
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(&dnspolicy.Result{
+			Servers:  []string{"10.96.0.10"},
+			Searches: []string{"default.svc.cluster.local", "svc.cluster.local"},
+			Options:  []string{"ndots:5"},
+		}))
+	})
+
+	It("should return nil without configuration", func() {
+		// Given
+		sut := &dnspolicy.Policy{DefaultOptions: []string{"ndots:2"}}
+
+		// When
+		res, err := sut.Apply("default", &types.DNSConfig{})
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(BeNil())
+	})
+
+	It("should merge the options", func() {
+		// Given
+		sut := &dnspolicy.Policy{
+			DefaultOptions: []string{"ndots:2", "single-request-reopen"},
+			NamespaceOptions: map[string][]string{
+				"team-a": {"ndots:1", "timeout:1"},
+			},
+		}
+		config := &types.DNSConfig{Options: []string{"ndots:5", "attempts:3"}}
+
+		// When
+		res, err := sut.Apply("default", config)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Options).To(Equal([]string{"ndots:5", "attempts:3", "single-request-reopen"}))
+
+		// When
+		res, err = sut.Apply("team-a", config)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Options).To(Equal([]string{"ndots:1", "attempts:3", "single-request-reopen", "timeout:1"}))
+	})
+
+	It("should add the local cache and enforce the limits", func() {
+		// Given
+		sut := &dnspolicy.Policy{
+			LocalCache:     "169.254.20.10",
+			MaxNameservers: 3,
+			MaxSearches:    2,
+		}
+
+		// When
+		res, err := sut.Apply("default", &types.DNSConfig{
+			Servers:  []string{"10.96.0.10", "10.96.0.11", "10.96.0.10", "10.96.0.12"},
+			Searches: []string{"a.local", "b.local", "a.local", "c.local"},
+		})
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Servers).To(Equal([]string{"169.254.20.10", "10.96.0.10", "10.96.0.11"}))
+		Expect(res.Searches).To(Equal([]string{"a.local", "b.local"}))
+		Expect(res.Warnings).To(Equal([]string{
+			"dropping duplicate nameserver 10.96.0.10",
+			"dropping duplicate search domain a.local",
+			"dropping nameservers 10.96.0.12, only 3 are allowed",
+			"dropping search domains c.local, only 2 are allowed",
+		}))
+	})
+
+	It("should not add the local cache without nameservers", func() {
+		// Given
+		sut := &dnspolicy.Policy{LocalCache: "169.254.20.10"}
+
+		// When
+		res, err := sut.Apply("default", &types.DNSConfig{Searches: []string{"a.local"}})
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Servers).To(BeEmpty())
+	})
+
+	DescribeTable("should fail with invalid configuration",
+		func(config *types.DNSConfig) {
+			// Given
+			sut := &dnspolicy.Policy{}
+
+			// When
+			res, err := sut.Apply("default", config)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(res).To(BeNil())
+		},
+		Entry("hostname nameserver", &types.DNSConfig{Servers: []string{"dns.local"}}),
+		Entry("empty search domain", &types.DNSConfig{Searches: []string{""}}),
+		Entry("search domain with space", &types.DNSConfig{Searches: []string{"a.local b.local"}}),
+		Entry("option with newline", &types.DNSConfig{Options: []string{"ndots:5\nnameserver 1.1.1.1"}}),
+	)
+
+	It("should accept IPv6 link-local nameservers", func() {
+		// Given
+		sut := &dnspolicy.Policy{}
+
+		// When
+		res, err := sut.Apply("default", &types.DNSConfig{Servers: []string{"fe80::1%eth0"}})
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Servers).To(Equal([]string{"fe80::1%eth0"}))
+	})
+})
+
+var _ = t.Describe("ParseNamespaceOptions", func() {
+	It("should succeed with valid entries", func() {
+		// Given
+		// When
+		res, err := dnspolicy.ParseNamespaceOptions([]string{
+			"team-a=ndots:2",
+			"team-b=ndots:1",
+			"team-a=single-request-reopen",
+		})
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(map[string][]string{
+			"team-a": {"ndots:2", "single-request-reopen"},
+			"team-b": {"ndots:1"},
+		}))
+	})
+
+	DescribeTable("should fail with invalid entries",
+		func(entries ...string) {
+			// Given
+			// When
+			res, err := dnspolicy.ParseNamespaceOptions(entries)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(res).To(BeNil())
+		},
+		Entry("missing separator", "team-a"),
+		Entry("missing namespace", "=ndots:2"),
+		Entry("missing option", "team-a="),
+		Entry("option with space", "team-a=ndots:2 timeout:1"),
+	)
+})
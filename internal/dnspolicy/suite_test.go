@@ -0,0 +1,26 @@
+package dnspolicy_test
+
+import (
+	"testing"
+
+	. "github.com/cri-o/cri-o/test/framework"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// TestDNSPolicy runs the created specs
+func TestDNSPolicy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunFrameworkSpecs(t, "DNSPolicy")
+}
+
+var t *TestFramework
+
+var _ = BeforeSuite(func() {
+	t = NewTestFramework(NilFunc, NilFunc)
+	t.Setup()
+})
+
+var _ = AfterSuite(func() {
+	t.Teardown()
+})
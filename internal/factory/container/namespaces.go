@@ -69,10 +69,12 @@ func (c *container) SpecAddNamespaces(sb *sandbox.Sandbox, targetCtr *oci.Contai
 // to add or replace the defaults to these paths
 func ConfigureGeneratorGivenNamespacePaths(managedNamespaces []*sandbox.ManagedNamespace, g *generate.Generator) error {
 	typeToSpec := map[nsmgr.NSType]rspec.LinuxNamespaceType{
-		nsmgr.IPCNS:  rspec.IPCNamespace,
-		nsmgr.NETNS:  rspec.NetworkNamespace,
-		nsmgr.UTSNS:  rspec.UTSNamespace,
-		nsmgr.USERNS: rspec.UserNamespace,
+		nsmgr.IPCNS:    rspec.IPCNamespace,
+		nsmgr.NETNS:    rspec.NetworkNamespace,
+		nsmgr.UTSNS:    rspec.UTSNamespace,
+		nsmgr.USERNS:   rspec.UserNamespace,
+		nsmgr.TIMENS:   rspec.TimeNamespace,
+		nsmgr.CGROUPNS: rspec.CgroupNamespace,
 	}
 
 	for _, ns := range managedNamespaces {
@@ -84,13 +86,34 @@ func ConfigureGeneratorGivenNamespacePaths(managedNamespaces []*sandbox.ManagedN
 		if nsForSpec == "" {
 			return fmt.Errorf("invalid namespace type %s", ns.Type())
 		}
-		if err := g.AddOrReplaceLinuxNamespace(string(nsForSpec), ns.Path()); err != nil {
+		if err := addOrReplaceLinuxNamespace(g, nsForSpec, ns.Path()); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// addOrReplaceLinuxNamespace adds or replaces the namespace of the provided
+// type in the generator. The generator does not support time namespaces, so
+// they are set on its config directly.
+func addOrReplaceLinuxNamespace(g *generate.Generator, nsType rspec.LinuxNamespaceType, path string) error {
+	if nsType != rspec.TimeNamespace {
+		return g.AddOrReplaceLinuxNamespace(string(nsType), path)
+	}
+	if g.Config.Linux == nil {
+		g.Config.Linux = &rspec.Linux{}
+	}
+	namespace := rspec.LinuxNamespace{Type: nsType, Path: path}
+	for i, ns := range g.Config.Linux.Namespaces {
+		if ns.Type == nsType {
+			g.Config.Linux.Namespaces[i] = namespace
+			return nil
+		}
+	}
+	g.Config.Linux.Namespaces = append(g.Config.Linux.Namespaces, namespace)
+	return nil
+}
+
 func (c *container) PidNamespace() nsmgr.Namespace {
 	return c.pidns
 }
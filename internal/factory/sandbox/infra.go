@@ -7,6 +7,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/cri-o/cri-o/internal/dnspolicy"
 	"github.com/cri-o/cri-o/internal/factory/container"
 	"github.com/cri-o/cri-o/internal/storage"
 	libconfig "github.com/cri-o/cri-o/pkg/config"
@@ -18,7 +19,7 @@ import (
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
-func (s *sandbox) InitInfraContainer(serverConfig *libconfig.Config, podContainer *storage.ContainerInfo) error {
+func (s *sandbox) InitInfraContainer(serverConfig *libconfig.Config, podContainer *storage.ContainerInfo, runtimeHandler string) error {
 	var err error
 	s.infra, err = container.New()
 	if err != nil {
@@ -40,17 +41,17 @@ func (s *sandbox) InitInfraContainer(serverConfig *libconfig.Config, podContaine
 	g.SetRootReadonly(true)
 
 	// configure default ulimits
-	for _, u := range serverConfig.Ulimits() {
+	for _, u := range serverConfig.UlimitsForRuntime(runtimeHandler) {
 		g.AddProcessRlimits(u.Name, u.Hard, u.Soft)
 	}
 	g.SetProcessArgs(pauseCommand)
 
-	if err := s.createResolvConf(podContainer); err != nil {
+	if err := s.createResolvConf(serverConfig.DNSPolicy(), podContainer); err != nil {
 		return fmt.Errorf("create resolv conf: %w", err)
 	}
 
 	// Add capabilities from crio.conf if default_capabilities is defined
-	if err := s.infra.SpecSetupCapabilities(&types.Capability{}, serverConfig.DefaultCapabilities, serverConfig.AddInheritableCapabilities); err != nil {
+	if err := s.infra.SpecSetupCapabilities(&types.Capability{}, serverConfig.DefaultCapabilitiesForRuntime(runtimeHandler), serverConfig.AddInheritableCapabilities); err != nil {
 		return err
 	}
 
@@ -86,17 +87,26 @@ func PauseCommand(cfg *libconfig.Config, image *v1.Image) ([]string, error) {
 	return cmd, nil
 }
 
-func (s *sandbox) createResolvConf(podContainer *storage.ContainerInfo) (retErr error) {
+func (s *sandbox) createResolvConf(policy *dnspolicy.Policy, podContainer *storage.ContainerInfo) (retErr error) {
 	// set DNS options
 	if s.config.DnsConfig == nil {
 		return nil
 	}
 
-	dnsServers := s.config.DnsConfig.Servers
-	dnsSearches := s.config.DnsConfig.Searches
-	dnsOptions := s.config.DnsConfig.Options
+	dns, err := policy.Apply(s.config.Metadata.Namespace, s.config.DnsConfig)
+	if err != nil {
+		return err
+	}
+	s.dns = dns
+
+	var dnsServers, dnsSearches, dnsOptions []string
+	if dns != nil {
+		dnsServers = dns.Servers
+		dnsSearches = dns.Searches
+		dnsOptions = dns.Options
+	}
 	s.resolvPath = fmt.Sprintf("%s/resolv.conf", podContainer.RunDir)
-	err := ParseDNSOptions(dnsServers, dnsSearches, dnsOptions, s.resolvPath)
+	err = ParseDNSOptions(dnsServers, dnsSearches, dnsOptions, s.resolvPath)
 	defer func() {
 		if retErr != nil {
 			if err := os.Remove(s.resolvPath); err != nil {
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/containers/storage/pkg/stringid"
+	"github.com/cri-o/cri-o/internal/dnspolicy"
 	"github.com/cri-o/cri-o/internal/factory/container"
 	"github.com/cri-o/cri-o/internal/storage"
 	libconfig "github.com/cri-o/cri-o/pkg/config"
@@ -30,8 +31,9 @@ type Sandbox interface {
 	// Name returns the id of the pod sandbox
 	Name() string
 
-	// InitInfraContainer initializes the sandbox's infra container
-	InitInfraContainer(*libconfig.Config, *storage.ContainerInfo) error
+	// InitInfraContainer initializes the sandbox's infra container, applying the
+	// default security settings of the provided runtime handler
+	InitInfraContainer(*libconfig.Config, *storage.ContainerInfo, string) error
 
 	// Spec returns the infra container's generator
 	// Must be called after InitInfraContainer
@@ -39,6 +41,11 @@ type Sandbox interface {
 
 	// ResolvPath returns the sandbox's resolvPath
 	ResolvPath() string
+
+	// DNS returns the DNS configuration of the sandbox after applying the
+	// DNS policy, or nil if the sandbox uses the one of the host
+	// Must be called after InitInfraContainer
+	DNS() *dnspolicy.Result
 }
 
 // sandbox is the hidden default type behind the Sandbox interface
@@ -48,6 +55,7 @@ type sandbox struct {
 	name       string
 	infra      container.Container
 	resolvPath string
+	dns        *dnspolicy.Result
 }
 
 // New creates a new, empty Sandbox instance
@@ -138,3 +146,9 @@ func (s *sandbox) Name() string {
 func (s *sandbox) ResolvPath() string {
 	return s.resolvPath
 }
+
+// DNS returns the DNS configuration of the sandbox after applying the DNS
+// policy
+func (s *sandbox) DNS() *dnspolicy.Result {
+	return s.dns
+}
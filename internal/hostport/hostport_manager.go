@@ -46,6 +46,11 @@ type HostPortManager interface {
 	// Remove cleans up matching port mappings
 	// Remove must be able to clean up port mappings without pod IP
 	Remove(id string, podPortMapping *PodPortMapping) error
+	// Status returns the state of the host ports of the given pods indexed by
+	// their ID, including the iptables chains owned by them.
+	Status(pods map[string]*PodPortMapping) map[string][]HostportStatus
+	// OpenHostports returns all host ports held open by the manager.
+	OpenHostports() []OpenHostport
 }
 
 type hostportManager struct {
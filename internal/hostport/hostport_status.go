@@ -0,0 +1,127 @@
+package hostport
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	utiliptables "k8s.io/kubernetes/pkg/util/iptables"
+)
+
+// HostportStatus is the state of a host port mapping of a pod for one IP family.
+type HostportStatus struct {
+	// IPFamily is the IP family of the mapping, either "4" or "6".
+	IPFamily string
+
+	// Mapping is the port mapping of the pod.
+	Mapping PortMapping
+
+	// Open is true if the host port is held open by the manager.
+	Open bool
+
+	// Chains are the iptables chains of the nat table owned by the mapping.
+	Chains []ChainStatus
+}
+
+// ChainStatus is the state of an iptables chain owned by a HostPortManager.
+type ChainStatus struct {
+	// Name is the name of the chain.
+	Name string
+
+	// Exists is true if the chain exists in the nat table.
+	Exists bool
+}
+
+// OpenHostport is a host port held open by a HostPortManager.
+type OpenHostport struct {
+	// IPFamily is the IP family of the port, either "4" or "6".
+	IPFamily string
+
+	// IP is the host IP the port is bound to, if any.
+	IP string
+
+	// Port is the host port.
+	Port int32
+
+	// Protocol is the lower case protocol of the port.
+	Protocol string
+}
+
+func (hm *hostportManager) Status(pods map[string]*PodPortMapping) map[string][]HostportStatus {
+	mappings := make(map[string][]*PortMapping, len(pods))
+	for id, pod := range pods {
+		if pod == nil || pod.HostNetwork {
+			continue
+		}
+		if pms := gatherHostportMappings(pod, hm.iptables.IsIPv6()); len(pms) > 0 {
+			mappings[id] = pms
+		}
+	}
+	if len(mappings) == 0 {
+		return nil
+	}
+
+	// The chains are read once for all pods and without holding the lock,
+	// because iptables-save would block adding and removing host ports.
+	existingChains, _, err := getExistingHostportIPTablesRules(hm.iptables)
+	if err != nil {
+		logrus.Warnf("Unable to get the hostport chains: %v", err)
+	}
+
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	family := hm.getIPFamily()
+	status := make(map[string][]HostportStatus, len(mappings))
+	for id, pms := range mappings {
+		for _, pm := range pms {
+			_, open := hm.hostPortMap[portMappingToHostport(pm, family)]
+			s := HostportStatus{
+				IPFamily: string(family),
+				Mapping:  *pm,
+				Open:     open,
+			}
+			for _, chain := range []utiliptables.Chain{
+				getHostportChain(kubeHostportChainPrefix, id, pm),
+				getHostportChain(crioMasqueradeChainPrefix, id, pm),
+			} {
+				_, exists := existingChains[chain]
+				s.Chains = append(s.Chains, ChainStatus{Name: string(chain), Exists: exists})
+			}
+			status[id] = append(status[id], s)
+		}
+	}
+	return status
+}
+
+func (hm *hostportManager) OpenHostports() []OpenHostport {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	ports := make([]OpenHostport, 0, len(hm.hostPortMap))
+	for hp := range hm.hostPortMap {
+		ports = append(ports, OpenHostport{
+			IPFamily: string(hp.ipFamily),
+			IP:       hp.ip,
+			Port:     hp.port,
+			Protocol: hp.protocol,
+		})
+	}
+	sortOpenHostports(ports)
+	return ports
+}
+
+func sortOpenHostports(ports []OpenHostport) {
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Port != ports[j].Port {
+			return ports[i].Port < ports[j].Port
+		}
+		if ports[i].Protocol != ports[j].Protocol {
+			return ports[i].Protocol < ports[j].Protocol
+		}
+		if ports[i].IPFamily != ports[j].IPFamily {
+			return ports[i].IPFamily < ports[j].IPFamily
+		}
+		return strings.Compare(ports[i].IP, ports[j].IP) < 0
+	})
+}
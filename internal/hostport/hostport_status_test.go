@@ -0,0 +1,98 @@
+package hostport
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	utiliptables "k8s.io/kubernetes/pkg/util/iptables"
+)
+
+func TestHostportManagerStatus(t *testing.T) {
+	iptables := newFakeIPTables()
+	iptables.protocol = utiliptables.ProtocolIPv4
+	portOpener := newFakeSocketManager()
+	manager := &hostportManager{
+		hostPortMap: make(map[hostport]closeable),
+		iptables:    iptables,
+		portOpener:  portOpener.openFakeSocket,
+	}
+
+	mapping := &PodPortMapping{
+		Name:      "pod1",
+		Namespace: "ns1",
+		IP:        net.ParseIP("10.1.1.2"),
+		PortMappings: []*PortMapping{
+			{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP},
+			{HostPort: 8443, ContainerPort: 443, Protocol: v1.ProtocolTCP, HostIP: "127.0.0.1"},
+			{HostPort: 0, ContainerPort: 9090, Protocol: v1.ProtocolTCP},
+			{HostPort: 8081, ContainerPort: 81, Protocol: v1.ProtocolTCP, HostIP: "::1"},
+		},
+	}
+
+	// Not added yet
+	pods := map[string]*PodPortMapping{"id1": mapping}
+	status := manager.Status(pods)["id1"]
+	assert.Len(t, status, 2)
+	for _, s := range status {
+		assert.Equal(t, "4", s.IPFamily)
+		assert.False(t, s.Open)
+		assert.Len(t, s.Chains, 2)
+		for _, c := range s.Chains {
+			assert.False(t, c.Exists)
+		}
+	}
+	assert.Empty(t, manager.OpenHostports())
+
+	// Added
+	assert.NoError(t, manager.Add("id1", mapping, ""))
+	status = manager.Status(pods)["id1"]
+	assert.Len(t, status, 2)
+	assert.Equal(t, int32(8080), status[0].Mapping.HostPort)
+	assert.Equal(t, "127.0.0.1", status[1].Mapping.HostIP)
+	for _, s := range status {
+		assert.True(t, s.Open)
+		assert.Equal(t, string(getHostportChain(kubeHostportChainPrefix, "id1", &s.Mapping)), s.Chains[0].Name)
+		assert.Equal(t, string(getHostportChain(crioMasqueradeChainPrefix, "id1", &s.Mapping)), s.Chains[1].Name)
+		for _, c := range s.Chains {
+			assert.True(t, c.Exists)
+		}
+	}
+	assert.Equal(t, []OpenHostport{
+		{IPFamily: "4", Port: 8080, Protocol: "tcp"},
+		{IPFamily: "4", IP: "127.0.0.1", Port: 8443, Protocol: "tcp"},
+	}, manager.OpenHostports())
+
+	// Removed
+	assert.NoError(t, manager.Remove("id1", mapping))
+	for _, s := range manager.Status(pods)["id1"] {
+		assert.False(t, s.Open)
+		for _, c := range s.Chains {
+			assert.False(t, c.Exists)
+		}
+	}
+	assert.Empty(t, manager.OpenHostports())
+
+	// Multiple pods
+	assert.NoError(t, manager.Add("id1", mapping, ""))
+	other := &PodPortMapping{
+		Name:         "pod2",
+		Namespace:    "ns1",
+		IP:           net.ParseIP("10.1.1.3"),
+		PortMappings: []*PortMapping{{HostPort: 9000, ContainerPort: 90, Protocol: v1.ProtocolUDP}},
+	}
+	status = manager.Status(map[string]*PodPortMapping{"id1": mapping, "id2": other})["id2"]
+	assert.Len(t, status, 1)
+	assert.False(t, status[0].Open)
+	for _, c := range status[0].Chains {
+		assert.False(t, c.Exists)
+	}
+	for _, s := range manager.Status(map[string]*PodPortMapping{"id1": mapping, "id2": other})["id1"] {
+		assert.True(t, s.Open)
+	}
+	assert.NoError(t, manager.Remove("id1", mapping))
+
+	// Host network
+	assert.Nil(t, manager.Status(map[string]*PodPortMapping{"id1": {HostNetwork: true}}))
+}
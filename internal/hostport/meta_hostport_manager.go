@@ -39,6 +39,23 @@ func (mh *metaHostportManager) Add(id string, podPortMapping *PodPortMapping, na
 	return mh.ipv4HostportManager.Add(id, podPortMapping, natInterfaceName)
 }
 
+func (mh *metaHostportManager) Status(pods map[string]*PodPortMapping) map[string][]HostportStatus {
+	status := mh.ipv4HostportManager.Status(pods)
+	for id, s := range mh.ipv6HostportManager.Status(pods) {
+		if status == nil {
+			status = make(map[string][]HostportStatus)
+		}
+		status[id] = append(status[id], s...)
+	}
+	return status
+}
+
+func (mh *metaHostportManager) OpenHostports() []OpenHostport {
+	ports := append(mh.ipv4HostportManager.OpenHostports(), mh.ipv6HostportManager.OpenHostports()...)
+	sortOpenHostports(ports)
+	return ports
+}
+
 func (mh *metaHostportManager) Remove(id string, podPortMapping *PodPortMapping) error {
 	var errstrings []string
 	// Remove may not have the IP information, so we try to clean us much as possible
@@ -20,3 +20,11 @@ func (mh *noopHostportManager) Remove(id string, podPortMapping *PodPortMapping)
 	logrus.Debug("HostPort Mapping is Disabled in CRI-O")
 	return nil
 }
+
+func (mh *noopHostportManager) Status(pods map[string]*PodPortMapping) map[string][]HostportStatus {
+	return nil
+}
+
+func (mh *noopHostportManager) OpenHostports() []OpenHostport {
+	return nil
+}
@@ -16,4 +16,7 @@ func TestNoopHostportManager(t *testing.T) {
 
 	err = manager.Remove("id", nil)
 	assert.NoError(t, err)
+
+	assert.Nil(t, manager.Status(map[string]*PodPortMapping{"id": nil}))
+	assert.Nil(t, manager.OpenHostports())
 }
@@ -0,0 +1,120 @@
+package hostport
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// ParsePortRanges parses a comma separated list of host port ranges in the
+// format "<host port>[-<last host port>][:<container port>][/<protocol>][@<host IP>]"
+// into port mappings, one for each port of the ranges. The container ports
+// default to the host ports and the protocol defaults to TCP, for example
+// "10000-10099/udp" maps the UDP host ports 10000 to 10099 to the same
+// container ports, while "8000-8009:9000@192.0.2.1" maps the TCP ports 8000
+// to 8009 of the host IP 192.0.2.1 to the container ports 9000 to 9009. At
+// most maxPorts ports can be mapped in total, where 0 means no limit. Each
+// mapped port holds an open socket and its own iptables rules, so mapping
+// large ranges is expensive.
+func ParsePortRanges(value string, maxPorts int) ([]*PortMapping, error) {
+	mappings := []*PortMapping{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		remaining := -1
+		if maxPorts > 0 {
+			remaining = maxPorts - len(mappings)
+		}
+		parsed, err := parsePortRange(entry, remaining)
+		if errors.Is(err, errTooManyPorts) {
+			return nil, fmt.Errorf("port ranges map more than %d ports", maxPorts)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid port range %q: %w", entry, err)
+		}
+		mappings = append(mappings, parsed...)
+	}
+	return mappings, nil
+}
+
+// errTooManyPorts is returned by parsePortRange if the range exceeds the
+// number of ports which can still be mapped.
+var errTooManyPorts = errors.New("too many ports")
+
+// parsePortRange parses a single port range, which may map at most maxPorts
+// ports. A negative maxPorts does not limit the range.
+func parsePortRange(entry string, maxPorts int) ([]*PortMapping, error) {
+	hostIP := ""
+	if i := strings.LastIndex(entry, "@"); i >= 0 {
+		hostIP = strings.Trim(entry[i+1:], "[]")
+		if net.ParseIP(hostIP) == nil {
+			return nil, fmt.Errorf("invalid host IP %q", hostIP)
+		}
+		entry = entry[:i]
+	}
+
+	protocol := v1.ProtocolTCP
+	if i := strings.LastIndex(entry, "/"); i >= 0 {
+		switch p := v1.Protocol(strings.ToUpper(entry[i+1:])); p {
+		case v1.ProtocolTCP, v1.ProtocolUDP, v1.ProtocolSCTP:
+			protocol = p
+		default:
+			return nil, fmt.Errorf("unsupported protocol %q", entry[i+1:])
+		}
+		entry = entry[:i]
+	}
+
+	hostPorts, containerPort, hasContainerPort := strings.Cut(entry, ":")
+	first, last, isRange := strings.Cut(hostPorts, "-")
+	start, err := parsePort(first)
+	if err != nil {
+		return nil, err
+	}
+	end := start
+	if isRange {
+		if end, err = parsePort(last); err != nil {
+			return nil, err
+		}
+		if end < start {
+			return nil, fmt.Errorf("last port %d is lower than first port %d", end, start)
+		}
+	}
+	containerStart := start
+	if hasContainerPort {
+		if containerStart, err = parsePort(containerPort); err != nil {
+			return nil, err
+		}
+		if containerStart+(end-start) > 65535 {
+			return nil, fmt.Errorf("container ports starting at %d exceed 65535", containerStart)
+		}
+	}
+
+	if maxPorts >= 0 && int(end-start)+1 > maxPorts {
+		return nil, errTooManyPorts
+	}
+
+	mappings := make([]*PortMapping, 0, end-start+1)
+	for port := start; port <= end; port++ {
+		mappings = append(mappings, &PortMapping{
+			HostPort:      port,
+			ContainerPort: containerStart + (port - start),
+			Protocol:      protocol,
+			HostIP:        hostIP,
+		})
+	}
+	return mappings, nil
+}
+
+func parsePort(value string) (int32, error) {
+	port, err := strconv.ParseUint(value, 10, 16)
+	if err != nil || port == 0 {
+		return 0, fmt.Errorf("invalid port %q", value)
+	}
+	return int32(port), nil
+}
@@ -0,0 +1,346 @@
+package hostport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containers/storage/pkg/ioutils"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+)
+
+// PortRange is a contiguous range of host ports of one protocol bound to a
+// host IP.
+type PortRange struct {
+	// Protocol is the lower case protocol of the ports.
+	Protocol string `json:"protocol"`
+
+	// HostIP is the host IP the ports are bound to. An empty IP binds the
+	// ports to all addresses of both IP families.
+	HostIP string `json:"host_ip,omitempty"`
+
+	// Start and End are the first and last port of the range.
+	Start int32 `json:"start"`
+	End   int32 `json:"end"`
+}
+
+func (r PortRange) String() string {
+	s := strconv.Itoa(int(r.Start))
+	if r.End != r.Start {
+		s += "-" + strconv.Itoa(int(r.End))
+	}
+	s += "/" + r.Protocol
+	if r.HostIP != "" {
+		s += " on " + r.HostIP
+	}
+	return s
+}
+
+// Overlaps returns true if both ranges share a port of the same protocol on
+// an overlapping host IP.
+func (r PortRange) Overlaps(o PortRange) bool {
+	if r.Protocol != o.Protocol || r.End < o.Start || o.End < r.Start {
+		return false
+	}
+	return hostIPsOverlap(r.HostIP, o.HostIP)
+}
+
+// hostIPsOverlap returns true if ports bound to both host IPs conflict. The
+// unspecified address of an IP family overlaps with all addresses of it.
+func hostIPsOverlap(a, b string) bool {
+	if a == "" || b == "" {
+		return true
+	}
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return a == b
+	}
+	if (ipA.To4() == nil) != (ipB.To4() == nil) {
+		return false
+	}
+	return ipA.IsUnspecified() || ipB.IsUnspecified() || ipA.Equal(ipB)
+}
+
+// PortRanges returns the host ports of the provided mappings as ranges of
+// contiguous ports. Mappings without host port are skipped.
+func PortRanges(mappings []*PortMapping) []PortRange {
+	ports := make([]PortRange, 0, len(mappings))
+	for _, pm := range mappings {
+		if pm.HostPort <= 0 {
+			continue
+		}
+		protocol := strings.ToLower(string(pm.Protocol))
+		if protocol == "" {
+			protocol = strings.ToLower(string(v1.ProtocolTCP))
+		}
+		ports = append(ports, PortRange{
+			Protocol: protocol,
+			HostIP:   pm.HostIP,
+			Start:    pm.HostPort,
+			End:      pm.HostPort,
+		})
+	}
+	sortPortRanges(ports)
+
+	res := []PortRange{}
+	for i := range ports {
+		if n := len(res); n > 0 {
+			last := &res[n-1]
+			if last.Protocol == ports[i].Protocol && last.HostIP == ports[i].HostIP && last.End+1 == ports[i].Start {
+				last.End = ports[i].End
+				continue
+			}
+		}
+		res = append(res, ports[i])
+	}
+	return res
+}
+
+func sortPortRanges(ports []PortRange) {
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Protocol != ports[j].Protocol {
+			return ports[i].Protocol < ports[j].Protocol
+		}
+		if ports[i].HostIP != ports[j].HostIP {
+			return ports[i].HostIP < ports[j].HostIP
+		}
+		return ports[i].Start < ports[j].Start
+	})
+}
+
+// Reservation are the host ports reserved for a pod.
+type Reservation struct {
+	// PodID is the ID of the pod sandbox.
+	PodID string `json:"pod_id"`
+
+	// Pod and Namespace are the name and namespace of the pod.
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+
+	// Ports are the reserved host port ranges.
+	Ports []PortRange `json:"ports"`
+
+	// Created is the time of the reservation.
+	Created time.Time `json:"created"`
+}
+
+// ConflictError is returned if host ports requested by a pod are already
+// reserved.
+type ConflictError struct {
+	// Ports are the requested host ports.
+	Ports PortRange
+
+	// PodID, Pod and Namespace identify the pod owning the ports. The
+	// PodID is empty if the ports are requested more than once by the pod
+	// itself.
+	PodID     string
+	Pod       string
+	Namespace string
+}
+
+func (e *ConflictError) Error() string {
+	if e.PodID == "" {
+		return fmt.Sprintf("host port %s is requested more than once", e.Ports.String())
+	}
+	return fmt.Sprintf("host port %s is already allocated by pod %s/%s (%s)", e.Ports.String(), e.Namespace, e.Pod, e.PodID)
+}
+
+// registryState is the persisted state of the registry.
+type registryState struct {
+	Reservations []*Reservation `json:"reservations"`
+}
+
+// Registry keeps track of the host ports reserved by all pods of the node
+// and persists them across restarts.
+type Registry struct {
+	lock         sync.Mutex
+	stateFile    string
+	reservations map[string]*Reservation
+}
+
+// NewRegistry creates a new registry, which persists its reservations in
+// stateFile, and loads the reservations persisted before.
+func NewRegistry(stateFile string) (*Registry, error) {
+	r := &Registry{
+		stateFile:    stateFile,
+		reservations: make(map[string]*Reservation),
+	}
+
+	data, err := os.ReadFile(stateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read host port reservations: %w", err)
+	}
+	s := &registryState{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parse host port reservations: %w", err)
+	}
+	for _, res := range s.Reservations {
+		if res.PodID == "" {
+			logrus.Warn("Dropping host port reservation without pod ID")
+			continue
+		}
+		r.reservations[res.PodID] = res
+	}
+	return r, nil
+}
+
+// Reserve reserves the provided host ports for the pod, replacing its
+// previous reservation. A ConflictError is returned if any of the ports is
+// already reserved by another pod or requested more than once.
+func (r *Registry) Reserve(podID, pod, namespace string, ports []PortRange) (*Reservation, error) {
+	for i := range ports {
+		for j := i + 1; j < len(ports); j++ {
+			if ports[i].Overlaps(ports[j]) {
+				return nil, &ConflictError{Ports: ports[j]}
+			}
+		}
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, other := range r.sorted() {
+		if other.PodID == podID {
+			continue
+		}
+		for i := range ports {
+			for j := range other.Ports {
+				if ports[i].Overlaps(other.Ports[j]) {
+					return nil, &ConflictError{
+						Ports:     ports[i],
+						PodID:     other.PodID,
+						Pod:       other.Pod,
+						Namespace: other.Namespace,
+					}
+				}
+			}
+		}
+	}
+
+	res := &Reservation{
+		PodID:     podID,
+		Pod:       pod,
+		Namespace: namespace,
+		Ports:     append([]PortRange{}, ports...),
+		Created:   time.Now(),
+	}
+	previous, existed := r.reservations[podID]
+	r.reservations[podID] = res
+	if err := r.save(); err != nil {
+		if existed {
+			r.reservations[podID] = previous
+		} else {
+			delete(r.reservations, podID)
+		}
+		return nil, err
+	}
+	copied := *res
+	return &copied, nil
+}
+
+// Release releases the host ports of the provided pod, if it has any.
+func (r *Registry) Release(podID string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	res, ok := r.reservations[podID]
+	if !ok {
+		return nil
+	}
+	delete(r.reservations, podID)
+	if err := r.save(); err != nil {
+		r.reservations[podID] = res
+		return err
+	}
+	return nil
+}
+
+// Prune releases the host ports of all pods for which keep returns false and
+// returns their reservations.
+func (r *Registry) Prune(keep func(podID string) bool) ([]*Reservation, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	pruned := []*Reservation{}
+	for podID, res := range r.reservations {
+		if !keep(podID) {
+			pruned = append(pruned, res)
+			delete(r.reservations, podID)
+		}
+	}
+	if len(pruned) == 0 {
+		return pruned, nil
+	}
+	if err := r.save(); err != nil {
+		for _, res := range pruned {
+			r.reservations[res.PodID] = res
+		}
+		return nil, err
+	}
+	return pruned, nil
+}
+
+// Get returns the reservation of the provided pod, or nil if it has none.
+func (r *Registry) Get(podID string) *Reservation {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	res, ok := r.reservations[podID]
+	if !ok {
+		return nil
+	}
+	copied := *res
+	return &copied
+}
+
+// List returns all reservations ordered by their pod IDs.
+func (r *Registry) List() []*Reservation {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	list := []*Reservation{}
+	for _, res := range r.sorted() {
+		copied := *res
+		list = append(list, &copied)
+	}
+	return list
+}
+
+// sorted returns the reservations ordered by their pod IDs.
+func (r *Registry) sorted() []*Reservation {
+	list := make([]*Reservation, 0, len(r.reservations))
+	for _, res := range r.reservations {
+		list = append(list, res)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].PodID < list[j].PodID
+	})
+	return list
+}
+
+// save persists the reservations atomically.
+func (r *Registry) save() error {
+	data, err := json.Marshal(&registryState{Reservations: r.sorted()})
+	if err != nil {
+		return fmt.Errorf("encode host port reservations: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.stateFile), 0o700); err != nil {
+		return fmt.Errorf("create host port reservations directory: %w", err)
+	}
+	if err := ioutils.AtomicWriteFile(r.stateFile, data, 0o600); err != nil {
+		return fmt.Errorf("write host port reservations: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package hostport
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestPortRanges(t *testing.T) {
+	ranges := PortRanges([]*PortMapping{
+		{HostPort: 8081, ContainerPort: 81, Protocol: v1.ProtocolTCP},
+		{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP},
+		{HostPort: 8082, ContainerPort: 82},
+		{HostPort: 8084, ContainerPort: 84, Protocol: v1.ProtocolTCP},
+		{HostPort: 8083, ContainerPort: 83, Protocol: v1.ProtocolUDP},
+		{HostPort: 8085, ContainerPort: 85, Protocol: v1.ProtocolTCP, HostIP: "127.0.0.1"},
+		{HostPort: 0, ContainerPort: 9090, Protocol: v1.ProtocolTCP},
+	})
+
+	assert.Equal(t, []PortRange{
+		{Protocol: "tcp", Start: 8080, End: 8082},
+		{Protocol: "tcp", Start: 8084, End: 8084},
+		{Protocol: "tcp", HostIP: "127.0.0.1", Start: 8085, End: 8085},
+		{Protocol: "udp", Start: 8083, End: 8083},
+	}, ranges)
+	assert.Equal(t, "8080-8082/tcp", ranges[0].String())
+	assert.Equal(t, "8085/tcp on 127.0.0.1", ranges[2].String())
+}
+
+func TestPortRangeOverlaps(t *testing.T) {
+	for _, tc := range []struct {
+		a, b     PortRange
+		overlaps bool
+	}{
+		{PortRange{Protocol: "tcp", Start: 80, End: 90}, PortRange{Protocol: "tcp", Start: 90, End: 95}, true},
+		{PortRange{Protocol: "tcp", Start: 80, End: 90}, PortRange{Protocol: "tcp", Start: 91, End: 95}, false},
+		{PortRange{Protocol: "tcp", Start: 80, End: 90}, PortRange{Protocol: "udp", Start: 80, End: 90}, false},
+		{PortRange{Protocol: "tcp", Start: 80, End: 80}, PortRange{Protocol: "tcp", HostIP: "10.0.0.1", Start: 80, End: 80}, true},
+		{PortRange{Protocol: "tcp", HostIP: "0.0.0.0", Start: 80, End: 80}, PortRange{Protocol: "tcp", HostIP: "10.0.0.1", Start: 80, End: 80}, true},
+		{PortRange{Protocol: "tcp", HostIP: "0.0.0.0", Start: 80, End: 80}, PortRange{Protocol: "tcp", HostIP: "fd00::1", Start: 80, End: 80}, false},
+		{PortRange{Protocol: "tcp", HostIP: "::", Start: 80, End: 80}, PortRange{Protocol: "tcp", HostIP: "fd00::1", Start: 80, End: 80}, true},
+		{PortRange{Protocol: "tcp", HostIP: "10.0.0.1", Start: 80, End: 80}, PortRange{Protocol: "tcp", HostIP: "10.0.0.2", Start: 80, End: 80}, false},
+	} {
+		assert.Equal(t, tc.overlaps, tc.a.Overlaps(tc.b), "%s and %s", tc.a, tc.b)
+		assert.Equal(t, tc.overlaps, tc.b.Overlaps(tc.a), "%s and %s", tc.b, tc.a)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state", "hostport-reservations.json")
+	registry, err := NewRegistry(stateFile)
+	require.NoError(t, err)
+	assert.Empty(t, registry.List())
+
+	// Reserve
+	_, err = registry.Reserve("id1", "pod1", "ns1", []PortRange{{Protocol: "udp", Start: 10000, End: 10999}})
+	require.NoError(t, err)
+	_, err = registry.Reserve("id2", "pod2", "ns2", []PortRange{{Protocol: "tcp", HostIP: "10.0.0.1", Start: 8080, End: 8080}})
+	require.NoError(t, err)
+
+	// Conflict with another pod
+	_, err = registry.Reserve("id3", "pod3", "ns3", []PortRange{{Protocol: "udp", HostIP: "10.0.0.1", Start: 10500, End: 10500}})
+	var conflict *ConflictError
+	require.True(t, errors.As(err, &conflict))
+	assert.Equal(t, "id1", conflict.PodID)
+	assert.Equal(t, "host port 10500/udp on 10.0.0.1 is already allocated by pod ns1/pod1 (id1)", err.Error())
+	assert.Nil(t, registry.Get("id3"))
+
+	// Conflict within the pod
+	_, err = registry.Reserve("id3", "pod3", "ns3", []PortRange{
+		{Protocol: "tcp", Start: 9000, End: 9010},
+		{Protocol: "tcp", HostIP: "127.0.0.1", Start: 9005, End: 9005},
+	})
+	require.True(t, errors.As(err, &conflict))
+	assert.Empty(t, conflict.PodID)
+
+	// Replacing the own reservation
+	_, err = registry.Reserve("id1", "pod1", "ns1", []PortRange{{Protocol: "udp", Start: 10000, End: 10099}})
+	require.NoError(t, err)
+	_, err = registry.Reserve("id3", "pod3", "ns3", []PortRange{{Protocol: "udp", Start: 10500, End: 10500}})
+	require.NoError(t, err)
+
+	// Persisted
+	restored, err := NewRegistry(stateFile)
+	require.NoError(t, err)
+	list := restored.List()
+	require.Len(t, list, 3)
+	assert.Equal(t, "id1", list[0].PodID)
+	assert.Equal(t, []PortRange{{Protocol: "udp", Start: 10000, End: 10099}}, list[0].Ports)
+	assert.Equal(t, "pod2", list[1].Pod)
+
+	// Release and prune
+	require.NoError(t, restored.Release("id1"))
+	require.NoError(t, restored.Release("unknown"))
+	pruned, err := restored.Prune(func(podID string) bool { return podID == "id3" })
+	require.NoError(t, err)
+	require.Len(t, pruned, 1)
+	assert.Equal(t, "id2", pruned[0].PodID)
+
+	restored, err = NewRegistry(stateFile)
+	require.NoError(t, err)
+	require.Len(t, restored.List(), 1)
+	assert.NotNil(t, restored.Get("id3"))
+}
+
+func TestNewRegistryInvalidState(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "hostport-reservations.json")
+	require.NoError(t, os.WriteFile(stateFile, []byte("{"), 0o600))
+
+	_, err := NewRegistry(stateFile)
+	assert.Error(t, err)
+}
+
+func TestParsePortRanges(t *testing.T) {
+	mappings, err := ParsePortRanges("10000-10002/udp, 8000-8001:9000@192.0.2.1,7000/sctp@[fd00::1]", 128)
+	require.NoError(t, err)
+	assert.Equal(t, []*PortMapping{
+		{HostPort: 10000, ContainerPort: 10000, Protocol: v1.ProtocolUDP},
+		{HostPort: 10001, ContainerPort: 10001, Protocol: v1.ProtocolUDP},
+		{HostPort: 10002, ContainerPort: 10002, Protocol: v1.ProtocolUDP},
+		{HostPort: 8000, ContainerPort: 9000, Protocol: v1.ProtocolTCP, HostIP: "192.0.2.1"},
+		{HostPort: 8001, ContainerPort: 9001, Protocol: v1.ProtocolTCP, HostIP: "192.0.2.1"},
+		{HostPort: 7000, ContainerPort: 7000, Protocol: v1.ProtocolSCTP, HostIP: "fd00::1"},
+	}, mappings)
+
+	for _, value := range []string{
+		"0",
+		"70000",
+		"9000-8000",
+		"8000/icmp",
+		"8000@invalid",
+		"8000-8010:65530",
+		"a-b",
+		"10000-10128",
+		"10000-10099,20000-20099",
+	} {
+		_, err := ParsePortRanges(value, 128)
+		assert.Error(t, err, value)
+	}
+
+	mappings, err = ParsePortRanges("1-65535", 0)
+	require.NoError(t, err)
+	assert.Len(t, mappings, 65535)
+
+	_, err = ParsePortRanges("10000-10001,20000", 2)
+	assert.EqualError(t, err, "port ranges map more than 2 ports")
+}
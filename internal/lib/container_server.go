@@ -13,6 +13,7 @@ import (
 	cstorage "github.com/containers/storage"
 	"github.com/containers/storage/pkg/ioutils"
 	"github.com/containers/storage/pkg/truncindex"
+	"github.com/cri-o/cri-o/internal/dnspolicy"
 	"github.com/cri-o/cri-o/internal/hostport"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	statsserver "github.com/cri-o/cri-o/internal/lib/stats"
@@ -219,6 +220,25 @@ func (c *ContainerServer) LoadSandbox(ctx context.Context, id string) (sb *sandb
 		return nil, err
 	}
 	sb.AddHostnamePath(m.Annotations[annotations.HostnamePath])
+	if v, found := m.Annotations[crioann.ContainerPorts]; found {
+		containerPorts := []*hostport.PortMapping{}
+		if err := json.Unmarshal([]byte(v), &containerPorts); err != nil {
+			return nil, fmt.Errorf("error unmarshalling %s annotation: %w", crioann.ContainerPorts, err)
+		}
+		sb.SetContainerPorts(containerPorts)
+	}
+	if v, found := m.Annotations[crioann.DNSConfig]; found {
+		dns := dnspolicy.Result{}
+		if err := json.Unmarshal([]byte(v), &dns); err != nil {
+			return nil, fmt.Errorf("error unmarshalling %s annotation: %w", crioann.DNSConfig, err)
+		}
+		sb.SetDNSConfig(&types.DNSConfig{
+			Servers:  dns.Servers,
+			Searches: dns.Searches,
+			Options:  dns.Options,
+		})
+		sb.SetDNSWarnings(dns.Warnings)
+	}
 	sb.SetSeccompProfilePath(spp)
 	sb.SetNamespaceOptions(&nsOpts)
 
@@ -308,6 +328,8 @@ func (c *ContainerServer) LoadSandbox(ctx context.Context, id string) (sb *sandb
 		{rspecNS: rspec.IPCNamespace, joinFunc: sb.IpcNsJoin},
 		{rspecNS: rspec.UTSNamespace, joinFunc: sb.UtsNsJoin},
 		{rspecNS: rspec.UserNamespace, joinFunc: sb.UserNsJoin},
+		{rspecNS: rspec.TimeNamespace, joinFunc: sb.TimeNsJoin},
+		{rspecNS: rspec.CgroupNamespace, joinFunc: sb.CgroupNsJoin},
 	}
 	for _, namespaceToJoin := range namespacesToJoin {
 		path, err := configNsPath(&m, namespaceToJoin.rspecNS)
@@ -157,6 +157,47 @@ var _ = t.Describe("ContainerServer", func() {
 			Expect(err).To(BeNil())
 		})
 
+		It("should succeed with DNS configuration", func() {
+			// Given
+			createDummyState()
+			manifest := bytes.Replace(testManifest,
+				[]byte(`"io.kubernetes.cri-o.PortMappings": "[]",`),
+				[]byte(`"io.kubernetes.cri-o.PortMappings": "[]",
+				"io.kubernetes.cri-o.DNSConfig": "{\"servers\":[\"10.0.0.10\"],\"searches\":[\"svc\"],\"warnings\":[\"dropped search domain\"]}",`), 1,
+			)
+			mockDirs(manifest)
+
+			// When
+			sb, err := sut.LoadSandbox(context.Background(), "id")
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sb.DNSConfig().Servers).To(Equal([]string{"10.0.0.10"}))
+			Expect(sb.DNSConfig().Searches).To(Equal([]string{"svc"}))
+			Expect(sb.DNSWarnings()).To(Equal([]string{"dropped search domain"}))
+		})
+
+		It("should fail with invalid DNS configuration", func() {
+			// Given
+			manifest := bytes.Replace(testManifest,
+				[]byte(`"io.kubernetes.cri-o.PortMappings": "[]",`),
+				[]byte(`"io.kubernetes.cri-o.PortMappings": "[]",
+				"io.kubernetes.cri-o.DNSConfig": "[]",`), 1,
+			)
+			gomock.InOrder(
+				storeMock.EXPECT().
+					FromContainerDirectory(gomock.Any(), gomock.Any()).
+					Return(manifest, nil),
+			)
+
+			// When
+			sb, err := sut.LoadSandbox(context.Background(), "id")
+
+			// Then
+			Expect(sb).To(BeNil())
+			Expect(err).NotTo(BeNil())
+		})
+
 		It("should succeed with invalid network namespace", func() {
 			// Given
 			createDummyState()
@@ -47,6 +47,10 @@ func (s *Sandbox) AddManagedNamespaces(namespaces []nsmgr.Namespace) {
 			s.netns = ns
 		case nsmgr.USERNS:
 			s.userns = ns
+		case nsmgr.TIMENS:
+			s.timens = ns
+		case nsmgr.CGROUPNS:
+			s.cgroupns = ns
 		default:
 			// this should never happen, as we control the NSTypes
 			panic(fmt.Errorf("unknown namespace type %s", ns))
@@ -55,7 +59,8 @@ func (s *Sandbox) AddManagedNamespaces(namespaces []nsmgr.Namespace) {
 }
 
 // NamespacePaths returns all the paths of the namespaces of the sandbox. If a namespace is not
-// managed by the sandbox, the namespace of the infra container will be returned.
+// managed by the sandbox, the namespace of the infra container will be returned, except for the
+// time and cgroup namespaces, which are only shared within the pod if managed.
 // It returns a slice of ManagedNamespaces
 func (s *Sandbox) NamespacePaths() []*ManagedNamespace {
 	pid := infraPid(s.InfraContainer())
@@ -86,6 +91,18 @@ func (s *Sandbox) NamespacePaths() []*ManagedNamespace {
 			nsPath: user,
 		})
 	}
+	if time := s.TimeNsPath(); time != "" {
+		typesAndPaths = append(typesAndPaths, &ManagedNamespace{
+			nsType: nsmgr.TIMENS,
+			nsPath: time,
+		})
+	}
+	if cgroup := s.CgroupNsPath(); cgroup != "" {
+		typesAndPaths = append(typesAndPaths, &ManagedNamespace{
+			nsType: nsmgr.CGROUPNS,
+			nsPath: cgroup,
+		})
+	}
 	return typesAndPaths
 }
 
@@ -100,7 +117,7 @@ func (s *Sandbox) RemoveManagedNamespaces() error {
 func (s *Sandbox) runFunctionOnNamespaces(toRun func(nsmgr.Namespace) error) error {
 	errs := make([]error, 0)
 
-	allNamespaces := []nsmgr.Namespace{s.utsns, s.ipcns, s.netns, s.userns}
+	allNamespaces := []nsmgr.Namespace{s.utsns, s.ipcns, s.netns, s.userns, s.timens, s.cgroupns}
 	for _, ns := range allNamespaces {
 		if ns == nil {
 			continue
@@ -207,6 +224,54 @@ func (s *Sandbox) UserNsJoin(nspath string) error {
 	return nil
 }
 
+// TimeNs specific functions
+
+// TimeNsPath returns the path to the time namespace of the sandbox.
+// If the sandbox does not manage a time namespace, the empty string is returned.
+func (s *Sandbox) TimeNsPath() string {
+	if s.timens == nil {
+		return ""
+	}
+	return s.timens.Path()
+}
+
+// TimeNsJoin attempts to join the sandbox to an existing time namespace
+// This will fail if the sandbox is already part of a time namespace
+func (s *Sandbox) TimeNsJoin(nspath string) error {
+	ns, err := nsJoin(nspath, nsmgr.TIMENS, s.timens)
+	// Regardless of error, set the namespace
+	s.timens = ns
+	// Only error if the sandbox is not stopped
+	if err != nil && !s.stopped {
+		return err
+	}
+	return nil
+}
+
+// CgroupNs specific functions
+
+// CgroupNsPath returns the path to the cgroup namespace of the sandbox.
+// If the sandbox does not manage a cgroup namespace, the empty string is returned.
+func (s *Sandbox) CgroupNsPath() string {
+	if s.cgroupns == nil {
+		return ""
+	}
+	return s.cgroupns.Path()
+}
+
+// CgroupNsJoin attempts to join the sandbox to an existing cgroup namespace
+// This will fail if the sandbox is already part of a cgroup namespace
+func (s *Sandbox) CgroupNsJoin(nspath string) error {
+	ns, err := nsJoin(nspath, nsmgr.CGROUPNS, s.cgroupns)
+	// Regardless of error, set the namespace
+	s.cgroupns = ns
+	// Only error if the sandbox is not stopped
+	if err != nil && !s.stopped {
+		return err
+	}
+	return nil
+}
+
 // PidNs specific functions
 
 // PidNsPath returns the path to the pid namespace of the sandbox.
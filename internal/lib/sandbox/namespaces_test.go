@@ -280,6 +280,33 @@ var _ = t.Describe("SandboxManagedNamespaces", func() {
 			// Then
 			Expect(path).ToNot(Equal(""))
 		})
+		It("should get nothing when time and cgroup not set", func() {
+			// Given
+			infra, err := nsmgrtest.ContainerWithPid(os.Getpid())
+			Expect(err).To(BeNil())
+			Expect(testSandbox.SetInfraContainer(infra)).To(BeNil())
+			// When
+			timePath := testSandbox.TimeNsPath()
+			cgroupPath := testSandbox.CgroupNsPath()
+			// Then
+			Expect(timePath).To(Equal(""))
+			Expect(cgroupPath).To(Equal(""))
+		})
+		It("should get something when time and cgroup are set", func() {
+			// Given
+			testSandbox.AddManagedNamespaces([]nsmgr.Namespace{
+				&nsmgrtest.SpoofedNamespace{NsType: nsmgr.TIMENS},
+				&nsmgrtest.SpoofedNamespace{NsType: nsmgr.CGROUPNS},
+			})
+			// When
+			nsPaths := testSandbox.NamespacePaths()
+			// Then
+			Expect(testSandbox.TimeNsPath()).ToNot(Equal(""))
+			Expect(testSandbox.CgroupNsPath()).ToNot(Equal(""))
+			Expect(nsPaths).To(HaveLen(2))
+			Expect(nsPaths[0].Type()).To(Equal(nsmgr.TIMENS))
+			Expect(nsPaths[1].Type()).To(Equal(nsmgr.CGROUPNS))
+		})
 	})
 	t.Describe("NamespacePaths with infra", func() {
 		It("should get nothing when infra set but pid 0", func() {
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cri-o/cri-o/internal/bandwidth"
 	"github.com/cri-o/cri-o/internal/config/nsmgr"
 	"github.com/cri-o/cri-o/internal/hostport"
 	"github.com/cri-o/cri-o/internal/log"
@@ -44,6 +45,8 @@ type Sandbox struct {
 	ipcns          nsmgr.Namespace
 	utsns          nsmgr.Namespace
 	userns         nsmgr.Namespace
+	timens         nsmgr.Namespace
+	cgroupns       nsmgr.Namespace
 	shmPath        string
 	cgroupParent   string
 	runtimeHandler string
@@ -52,10 +55,17 @@ type Sandbox struct {
 	hostname       string
 	// ipv4 or ipv6 cache
 	ips                []string
+	ipsMutex           sync.RWMutex
+	networkAttachments []NetworkAttachment
+	networkCheck       *NetworkCheck
+	networkCheckMutex  sync.RWMutex
+	bandwidthLimits    *bandwidth.Limits
+	bandwidthMutex     sync.RWMutex
 	seccompProfilePath string
 	infraContainer     *oci.Container
 	nsOpts             *types.NamespaceOption
 	dnsConfig          *types.DNSConfig
+	dnsWarnings        []string
 	stopMutex          sync.RWMutex
 	created            bool
 	stopped            bool
@@ -66,6 +76,27 @@ type Sandbox struct {
 	containerEnvPath   string
 	podLinuxOverhead   *types.LinuxContainerResources
 	podLinuxResources  *types.LinuxContainerResources
+	containerPorts     []*hostport.PortMapping
+}
+
+// NetworkAttachment is a network interface attached to the sandbox by CNI
+type NetworkAttachment struct {
+	// Network is the name of the CNI network.
+	Network string `json:"network"`
+	// Interface is the name of the interface within the sandbox.
+	Interface string `json:"interface"`
+	// IPs are the addresses assigned to the interface.
+	IPs []string `json:"ips"`
+	// MAC is the hardware address of the interface, if reported.
+	MAC string `json:"mac,omitempty"`
+}
+
+// NetworkCheck is the result of the last CNI CHECK of the sandbox network
+type NetworkCheck struct {
+	// Time is when the check was done.
+	Time time.Time `json:"time"`
+	// Error is the reason of the failed check, if any.
+	Error string `json:"error,omitempty"`
 }
 
 // DefaultShmSize is the default shm size
@@ -138,6 +169,8 @@ func (s *Sandbox) SeccompProfilePath() string {
 
 // AddIPs stores the ip in the sandbox
 func (s *Sandbox) AddIPs(ips []string) {
+	s.ipsMutex.Lock()
+	defer s.ipsMutex.Unlock()
 	s.ips = ips
 }
 
@@ -161,6 +194,18 @@ func (s *Sandbox) DNSConfig() *types.DNSConfig {
 	return s.dnsConfig
 }
 
+// SetDNSWarnings sets the changes of the requested DNS configuration done by
+// the DNS policy
+func (s *Sandbox) SetDNSWarnings(warnings []string) {
+	s.dnsWarnings = warnings
+}
+
+// DNSWarnings returns the changes of the requested DNS configuration done by
+// the DNS policy
+func (s *Sandbox) DNSWarnings() []string {
+	return s.dnsWarnings
+}
+
 // StopMutex returns the mutex to use when stopping the sandbox
 func (s *Sandbox) StopMutex() *sync.RWMutex {
 	return &s.stopMutex
@@ -168,9 +213,57 @@ func (s *Sandbox) StopMutex() *sync.RWMutex {
 
 // IPs returns the ip of the sandbox
 func (s *Sandbox) IPs() []string {
+	s.ipsMutex.RLock()
+	defer s.ipsMutex.RUnlock()
 	return s.ips
 }
 
+// SetNetworkAttachments sets the network interfaces attached to the sandbox
+func (s *Sandbox) SetNetworkAttachments(attachments []NetworkAttachment) {
+	s.networkAttachments = attachments
+}
+
+// NetworkAttachments returns the network interfaces attached to the sandbox,
+// starting with the one of the default network
+func (s *Sandbox) NetworkAttachments() []NetworkAttachment {
+	return s.networkAttachments
+}
+
+// SetNetworkCheck records the result of a CNI CHECK of the sandbox network
+func (s *Sandbox) SetNetworkCheck(checkErr error) {
+	check := &NetworkCheck{Time: time.Now()}
+	if checkErr != nil {
+		check.Error = checkErr.Error()
+	}
+	s.networkCheckMutex.Lock()
+	defer s.networkCheckMutex.Unlock()
+	s.networkCheck = check
+}
+
+// NetworkCheck returns the result of the last CNI CHECK of the sandbox
+// network, or nil if it has not been checked yet
+func (s *Sandbox) NetworkCheck() *NetworkCheck {
+	s.networkCheckMutex.RLock()
+	defer s.networkCheckMutex.RUnlock()
+	return s.networkCheck
+}
+
+// SetBandwidthLimits records the bandwidth limits applied to the sandbox
+// network by traffic control
+func (s *Sandbox) SetBandwidthLimits(limits *bandwidth.Limits) {
+	s.bandwidthMutex.Lock()
+	defer s.bandwidthMutex.Unlock()
+	s.bandwidthLimits = limits
+}
+
+// BandwidthLimits returns the bandwidth limits applied to the sandbox network
+// by traffic control, or nil if none are applied
+func (s *Sandbox) BandwidthLimits() *bandwidth.Limits {
+	s.bandwidthMutex.RLock()
+	defer s.bandwidthMutex.RUnlock()
+	return s.bandwidthLimits
+}
+
 // ID returns the id of the sandbox
 func (s *Sandbox) ID() string {
 	return s.criSandbox.Id
@@ -290,6 +383,17 @@ func (s *Sandbox) PortMappings() []*hostport.PortMapping {
 	return s.portMappings
 }
 
+// SetContainerPorts sets the ports declared by the containers of the sandbox
+func (s *Sandbox) SetContainerPorts(ports []*hostport.PortMapping) {
+	s.containerPorts = ports
+}
+
+// ContainerPorts returns the ports declared by the containers of the sandbox,
+// including those without a host port
+func (s *Sandbox) ContainerPorts() []*hostport.PortMapping {
+	return s.containerPorts
+}
+
 // PodLinuxOverhead returns the overheads associated with this sandbox
 func (s *Sandbox) PodLinuxOverhead() *types.LinuxContainerResources {
 	return s.podLinuxOverhead
@@ -2,13 +2,16 @@ package sandbox_test
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/cri-o/cri-o/internal/bandwidth"
 	"github.com/cri-o/cri-o/internal/hostport"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/oci"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
@@ -87,6 +90,23 @@ var _ = t.Describe("Sandbox", func() {
 		})
 	})
 
+	t.Describe("SetContainerPorts", func() {
+		It("should succeed", func() {
+			// Given
+			ports := []*hostport.PortMapping{
+				{ContainerPort: 53, Protocol: v1.ProtocolUDP},
+				{ContainerPort: 8080, HostPort: 80, Protocol: v1.ProtocolTCP},
+			}
+			Expect(testSandbox.ContainerPorts()).To(BeEmpty())
+
+			// When
+			testSandbox.SetContainerPorts(ports)
+
+			// Then
+			Expect(testSandbox.ContainerPorts()).To(Equal(ports))
+		})
+	})
+
 	t.Describe("AddIPs", func() {
 		It("should succeed", func() {
 			// Given
@@ -129,6 +149,41 @@ var _ = t.Describe("Sandbox", func() {
 		})
 	})
 
+	t.Describe("NetworkCheck", func() {
+		It("should succeed", func() {
+			// Given
+			Expect(testSandbox.NetworkCheck()).To(BeNil())
+
+			// When
+			testSandbox.SetNetworkCheck(errors.New("no route"))
+
+			// Then
+			Expect(testSandbox.NetworkCheck()).NotTo(BeNil())
+			Expect(testSandbox.NetworkCheck().Error).To(Equal("no route"))
+			Expect(testSandbox.NetworkCheck().Time).NotTo(BeZero())
+
+			// When
+			testSandbox.SetNetworkCheck(nil)
+
+			// Then
+			Expect(testSandbox.NetworkCheck().Error).To(BeEmpty())
+		})
+	})
+
+	t.Describe("BandwidthLimits", func() {
+		It("should succeed", func() {
+			// Given
+			Expect(testSandbox.BandwidthLimits()).To(BeNil())
+			limits := &bandwidth.Limits{EgressRate: 1000000}
+
+			// When
+			testSandbox.SetBandwidthLimits(limits)
+
+			// Then
+			Expect(testSandbox.BandwidthLimits()).To(Equal(limits))
+		})
+	})
+
 	t.Describe("DNSConfig", func() {
 		It("should succeed", func() {
 			// Given
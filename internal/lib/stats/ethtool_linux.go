@@ -0,0 +1,96 @@
+package statsserver
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// ethtoolStatsStringSet is ETH_SS_STATS, the string set of the driver statistics.
+	ethtoolStatsStringSet = 1
+
+	// ethtoolStringLen is ETH_GSTRING_LEN, the length of a single ethtool string.
+	ethtoolStringLen = 32
+)
+
+// ethtoolIfreq is the struct ifreq passed to the SIOCETHTOOL ioctl, which
+// carries a pointer to the ethtool command.
+type ethtoolIfreq struct {
+	name [unix.IFNAMSIZ]byte
+	data uintptr
+	_    [24 - unsafe.Sizeof(uintptr(0))]byte
+}
+
+// ethtoolDriverStats returns the names and values of the driver statistics of
+// the provided interface within the network namespace of the calling thread.
+// Interfaces without driver statistics return empty slices.
+func ethtoolDriverStats(ifname string) ([]string, []uint64, error) {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create ethtool socket: %w", err)
+	}
+	defer unix.Close(fd)
+
+	// struct ethtool_sset_info with a single data entry
+	ssetInfo := make([]uint64, 3)
+	*(*uint32)(unsafe.Pointer(&ssetInfo[0])) = unix.ETHTOOL_GSSET_INFO
+	ssetInfo[1] = 1 << ethtoolStatsStringSet
+	if err := ethtoolIoctl(fd, ifname, unsafe.Pointer(&ssetInfo[0])); err != nil {
+		if err == unix.EOPNOTSUPP {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("get ethtool string set info of %s: %w", ifname, err)
+	}
+	if ssetInfo[1] == 0 {
+		return nil, nil, nil
+	}
+	count := *(*uint32)(unsafe.Pointer(&ssetInfo[2]))
+	if count == 0 {
+		return nil, nil, nil
+	}
+
+	// struct ethtool_gstrings
+	stringsBuf := make([]uint32, 3+int(count)*ethtoolStringLen/4)
+	stringsBuf[0] = unix.ETHTOOL_GSTRINGS
+	stringsBuf[1] = ethtoolStatsStringSet
+	stringsBuf[2] = count
+	if err := ethtoolIoctl(fd, ifname, unsafe.Pointer(&stringsBuf[0])); err != nil {
+		return nil, nil, fmt.Errorf("get ethtool statistics names of %s: %w", ifname, err)
+	}
+
+	// struct ethtool_stats
+	statsBuf := make([]uint64, 1+int(count))
+	*(*uint32)(unsafe.Pointer(&statsBuf[0])) = unix.ETHTOOL_GSTATS
+	*(*uint32)(unsafe.Add(unsafe.Pointer(&statsBuf[0]), 4)) = count
+	if err := ethtoolIoctl(fd, ifname, unsafe.Pointer(&statsBuf[0])); err != nil {
+		return nil, nil, fmt.Errorf("get ethtool statistics of %s: %w", ifname, err)
+	}
+
+	data := unsafe.Slice((*byte)(unsafe.Pointer(&stringsBuf[3])), int(count)*ethtoolStringLen)
+	names := make([]string, 0, count)
+	for i := 0; i < int(count); i++ {
+		name := data[i*ethtoolStringLen : (i+1)*ethtoolStringLen]
+		for j, b := range name {
+			if b == 0 {
+				name = name[:j]
+				break
+			}
+		}
+		names = append(names, string(name))
+	}
+	return names, statsBuf[1:], nil
+}
+
+func ethtoolIoctl(fd int, ifname string, data unsafe.Pointer) error {
+	if len(ifname) >= unix.IFNAMSIZ {
+		return fmt.Errorf("interface name %s too long", ifname)
+	}
+	ifr := ethtoolIfreq{data: uintptr(data)}
+	copy(ifr.name[:], ifname)
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), unix.SIOCETHTOOL, uintptr(unsafe.Pointer(&ifr))); errno != 0 {
+		return errno
+	}
+	return nil
+}
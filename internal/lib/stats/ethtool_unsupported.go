@@ -0,0 +1,8 @@
+//go:build !linux
+// +build !linux
+
+package statsserver
+
+func ethtoolDriverStats(string) ([]string, []uint64, error) {
+	return nil, nil, nil
+}
@@ -0,0 +1,307 @@
+package statsserver
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/server/metrics"
+	"github.com/vishvananda/netlink"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+const (
+	// procNetPath is the path to the network information of the network
+	// namespace the calling thread is in.
+	procNetPath = "/proc/thread-self/net"
+
+	// conntrackCountPath is the path to the number of connection tracking
+	// entries of the network namespace the file gets opened in.
+	conntrackCountPath = "/proc/sys/net/netfilter/nf_conntrack_count"
+)
+
+// queueStatRegexp matches the per-queue statistics exposed by the network
+// drivers via ethtool, like "rx_queue_0_packets", "tx0_bytes" or "rx-1.drops".
+var queueStatRegexp = regexp.MustCompile(`^(rx|tx)[_-]?(?:queue_)?(\d+)[_.](.+)$`)
+
+// NetworkStats are the extended statistics of a pod sandbox network namespace,
+// which go beyond what the CRI NetworkUsage is able to represent.
+type NetworkStats struct {
+	// Timestamp is the time the statistics have been collected in nanoseconds.
+	Timestamp int64
+
+	// Interfaces are the statistics of the network interfaces within the sandbox.
+	Interfaces []InterfaceStats
+
+	// Sockets are the socket counts of the network namespace.
+	Sockets SocketStats
+
+	// ConntrackEntries is the number of connection tracking entries of the
+	// network namespace.
+	ConntrackEntries uint64
+}
+
+// InterfaceStats are the statistics of a network interface.
+type InterfaceStats struct {
+	Name      string
+	RxBytes   uint64
+	RxPackets uint64
+	RxErrors  uint64
+	RxDropped uint64
+	TxBytes   uint64
+	TxPackets uint64
+	TxErrors  uint64
+	TxDropped uint64
+	Multicast uint64
+
+	// Queues are the per-queue statistics exposed by the driver, if any.
+	Queues []QueueStat
+}
+
+// QueueStat is a single per-queue statistic of a network interface.
+type QueueStat struct {
+	// Queue is the direction and index of the queue, for example "rx-0".
+	Queue string
+
+	// Name is the name of the statistic as reported by the driver, for example "packets".
+	Name string
+
+	Value uint64
+}
+
+// SocketStats are the socket counts of a network namespace.
+type SocketStats struct {
+	TCP uint64
+	UDP uint64
+}
+
+// linkToInterfaceStats translates information found from the netlink
+// package into the InterfaceStats structure.
+func linkToInterfaceStats(link netlink.Link) (*InterfaceStats, error) {
+	attrs := link.Attrs()
+	if attrs == nil {
+		return nil, errors.New("get stats for iface")
+	}
+	if attrs.Statistics == nil {
+		return nil, fmt.Errorf("get stats for iface %s", attrs.Name)
+	}
+	return &InterfaceStats{
+		Name:      attrs.Name,
+		RxBytes:   attrs.Statistics.RxBytes,
+		RxPackets: attrs.Statistics.RxPackets,
+		RxErrors:  attrs.Statistics.RxErrors,
+		RxDropped: attrs.Statistics.RxDropped,
+		TxBytes:   attrs.Statistics.TxBytes,
+		TxPackets: attrs.Statistics.TxPackets,
+		TxErrors:  attrs.Statistics.TxErrors,
+		TxDropped: attrs.Statistics.TxDropped,
+		Multicast: attrs.Statistics.Multicast,
+	}, nil
+}
+
+// statistics returns the interface statistics by their metric label value.
+func (i *InterfaceStats) statistics() map[string]uint64 {
+	return map[string]uint64{
+		"rx_bytes":   i.RxBytes,
+		"rx_packets": i.RxPackets,
+		"rx_errors":  i.RxErrors,
+		"rx_dropped": i.RxDropped,
+		"tx_bytes":   i.TxBytes,
+		"tx_packets": i.TxPackets,
+		"tx_errors":  i.TxErrors,
+		"tx_dropped": i.TxDropped,
+		"multicast":  i.Multicast,
+	}
+}
+
+// queueStats selects the per-queue statistics from the driver statistics
+// names and values reported by ethtool.
+func queueStats(names []string, values []uint64) []QueueStat {
+	stats := []QueueStat{}
+	for i, name := range names {
+		if i >= len(values) {
+			break
+		}
+		match := queueStatRegexp.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+		stats = append(stats, QueueStat{
+			Queue: match[1] + "-" + match[2],
+			Name:  match[3],
+			Value: values[i],
+		})
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].Queue < stats[j].Queue
+	})
+	return stats
+}
+
+// readSocketStats counts the TCP and UDP sockets listed below the provided
+// procfs net directory. Missing files, for example if IPv6 is disabled, are
+// skipped.
+func readSocketStats(dir string) (SocketStats, error) {
+	stats := SocketStats{}
+	for file, count := range map[string]*uint64{
+		"tcp":  &stats.TCP,
+		"tcp6": &stats.TCP,
+		"udp":  &stats.UDP,
+		"udp6": &stats.UDP,
+	} {
+		n, err := countSockets(filepath.Join(dir, file))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return stats, err
+		}
+		*count += n
+	}
+	return stats, nil
+}
+
+// countSockets counts the entries of a procfs socket table, which starts
+// with a header line.
+func countSockets(path string) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var lines uint64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) != "" {
+			lines++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("read %s: %w", path, err)
+	}
+	if lines == 0 {
+		return 0, nil
+	}
+	return lines - 1, nil
+}
+
+// readConntrackEntries returns the number of connection tracking entries
+// from the provided path, or zero if connection tracking is not available.
+func readConntrackEntries(path string) (uint64, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	entries, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// updateNetworkMetrics exposes the extended network statistics of the
+// provided sandbox via the prometheus metrics.
+func updateNetworkMetrics(sb *sandbox.Sandbox, stats *NetworkStats) {
+	m := metrics.Instance()
+	pod, namespace, id := sb.KubeName(), sb.Namespace(), sb.ID()
+	for i := range stats.Interfaces {
+		iface := &stats.Interfaces[i]
+		for statistic, value := range iface.statistics() {
+			m.MetricPodNetworkInterfaceStatisticsSet(value, pod, namespace, id, iface.Name, statistic)
+		}
+		for _, q := range iface.Queues {
+			m.MetricPodNetworkQueueStatisticsSet(q.Value, pod, namespace, id, iface.Name, q.Queue, q.Name)
+		}
+	}
+	m.MetricPodNetworkSocketsSet(stats.Sockets.TCP, pod, namespace, id, "tcp")
+	m.MetricPodNetworkSocketsSet(stats.Sockets.UDP, pod, namespace, id, "udp")
+	m.MetricPodNetworkConntrackEntriesSet(stats.ConntrackEntries, pod, namespace, id)
+}
+
+// Names of the metrics returned by ListPodSandboxMetrics. The interface
+// metrics follow the naming of cAdvisor.
+const (
+	metricNetworkReceiveBytes            = "container_network_receive_bytes_total"
+	metricNetworkReceivePackets          = "container_network_receive_packets_total"
+	metricNetworkReceiveErrors           = "container_network_receive_errors_total"
+	metricNetworkReceivePacketsDropped   = "container_network_receive_packets_dropped_total"
+	metricNetworkTransmitBytes           = "container_network_transmit_bytes_total"
+	metricNetworkTransmitPackets         = "container_network_transmit_packets_total"
+	metricNetworkTransmitErrors          = "container_network_transmit_errors_total"
+	metricNetworkTransmitPacketsDropped  = "container_network_transmit_packets_dropped_total"
+	metricNetworkReceiveMulticastPackets = "container_network_receive_multicast_packets_total"
+	metricNetworkQueueStatistics         = "container_network_queue_statistics"
+	metricNetworkSockets                 = "container_network_sockets"
+	metricNetworkConntrackEntries        = "container_network_conntrack_entries"
+)
+
+// MetricDescriptors returns the descriptors of the metrics returned for
+// every pod sandbox by MetricsForSandbox.
+func MetricDescriptors() []*types.MetricDescriptor {
+	iface := []string{"interface"}
+	return []*types.MetricDescriptor{
+		{Name: metricNetworkReceiveBytes, Help: "Cumulative count of bytes received", LabelKeys: iface},
+		{Name: metricNetworkReceivePackets, Help: "Cumulative count of packets received", LabelKeys: iface},
+		{Name: metricNetworkReceiveErrors, Help: "Cumulative count of errors encountered while receiving", LabelKeys: iface},
+		{Name: metricNetworkReceivePacketsDropped, Help: "Cumulative count of packets dropped while receiving", LabelKeys: iface},
+		{Name: metricNetworkTransmitBytes, Help: "Cumulative count of bytes transmitted", LabelKeys: iface},
+		{Name: metricNetworkTransmitPackets, Help: "Cumulative count of packets transmitted", LabelKeys: iface},
+		{Name: metricNetworkTransmitErrors, Help: "Cumulative count of errors encountered while transmitting", LabelKeys: iface},
+		{Name: metricNetworkTransmitPacketsDropped, Help: "Cumulative count of packets dropped while transmitting", LabelKeys: iface},
+		{Name: metricNetworkReceiveMulticastPackets, Help: "Cumulative count of multicast packets received", LabelKeys: iface},
+		{Name: metricNetworkQueueStatistics, Help: "Per-queue statistics reported by the network driver", LabelKeys: []string{"interface", "queue", "statistic"}},
+		{Name: metricNetworkSockets, Help: "Number of sockets in the network namespace", LabelKeys: []string{"protocol"}},
+		{Name: metricNetworkConntrackEntries, Help: "Number of connection tracking entries of the network namespace"},
+	}
+}
+
+// podSandboxMetrics translates the extended network statistics of a sandbox
+// into the CRI PodSandboxMetrics structure.
+func podSandboxMetrics(id string, stats *NetworkStats) *types.PodSandboxMetrics {
+	podMetrics := &types.PodSandboxMetrics{
+		PodSandboxId: id,
+		Metrics:      []*types.Metric{},
+	}
+	if stats == nil {
+		return podMetrics
+	}
+	metric := func(name string, metricType types.MetricType, value uint64, labels ...string) {
+		podMetrics.Metrics = append(podMetrics.Metrics, &types.Metric{
+			Name:        name,
+			Timestamp:   stats.Timestamp,
+			MetricType:  metricType,
+			LabelValues: labels,
+			Value:       &types.UInt64Value{Value: value},
+		})
+	}
+	for i := range stats.Interfaces {
+		iface := &stats.Interfaces[i]
+		metric(metricNetworkReceiveBytes, types.MetricType_COUNTER, iface.RxBytes, iface.Name)
+		metric(metricNetworkReceivePackets, types.MetricType_COUNTER, iface.RxPackets, iface.Name)
+		metric(metricNetworkReceiveErrors, types.MetricType_COUNTER, iface.RxErrors, iface.Name)
+		metric(metricNetworkReceivePacketsDropped, types.MetricType_COUNTER, iface.RxDropped, iface.Name)
+		metric(metricNetworkTransmitBytes, types.MetricType_COUNTER, iface.TxBytes, iface.Name)
+		metric(metricNetworkTransmitPackets, types.MetricType_COUNTER, iface.TxPackets, iface.Name)
+		metric(metricNetworkTransmitErrors, types.MetricType_COUNTER, iface.TxErrors, iface.Name)
+		metric(metricNetworkTransmitPacketsDropped, types.MetricType_COUNTER, iface.TxDropped, iface.Name)
+		metric(metricNetworkReceiveMulticastPackets, types.MetricType_COUNTER, iface.Multicast, iface.Name)
+		for _, q := range iface.Queues {
+			metric(metricNetworkQueueStatistics, types.MetricType_COUNTER, q.Value, iface.Name, q.Queue, q.Name)
+		}
+	}
+	metric(metricNetworkSockets, types.MetricType_GAUGE, stats.Sockets.TCP, "tcp")
+	metric(metricNetworkSockets, types.MetricType_GAUGE, stats.Sockets.UDP, "udp")
+	metric(metricNetworkConntrackEntries, types.MetricType_GAUGE, stats.ConntrackEntries)
+	return podMetrics
+}
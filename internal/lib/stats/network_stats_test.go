@@ -0,0 +1,128 @@
+package statsserver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+func TestQueueStats(t *testing.T) {
+	stats := queueStats(
+		[]string{"peer_ifindex", "tx_queue_0_xdp_xmit", "rx_queue_0_packets", "rx1_bytes", "rx-2.drops", "rx_bytes", "missing_value_0"},
+		[]uint64{5, 1, 2, 3, 4, 6},
+	)
+
+	expected := []QueueStat{
+		{Queue: "rx-0", Name: "packets", Value: 2},
+		{Queue: "rx-1", Name: "bytes", Value: 3},
+		{Queue: "rx-2", Name: "drops", Value: 4},
+		{Queue: "tx-0", Name: "xdp_xmit", Value: 1},
+	}
+	if !reflect.DeepEqual(stats, expected) {
+		t.Fatalf("expected queue stats %+v, got %+v", expected, stats)
+	}
+}
+
+func TestReadSocketStats(t *testing.T) {
+	dir := t.TempDir()
+	header := "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+	entry := "   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1 1 0 100 0 0 10 0\n"
+	for file, content := range map[string]string{
+		"tcp":  header + entry + entry,
+		"tcp6": header + entry,
+		"udp":  header,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := readSocketStats(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (SocketStats{TCP: 3, UDP: 0}); stats != expected {
+		t.Fatalf("expected socket stats %+v, got %+v", expected, stats)
+	}
+}
+
+func TestReadConntrackEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nf_conntrack_count")
+
+	entries, err := readConntrackEntries(path)
+	if err != nil || entries != 0 {
+		t.Fatalf("expected no entries without conntrack, got %d: %v", entries, err)
+	}
+
+	if err := os.WriteFile(path, []byte("42\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err = readConntrackEntries(path)
+	if err != nil || entries != 42 {
+		t.Fatalf("expected 42 entries, got %d: %v", entries, err)
+	}
+
+	if err := os.WriteFile(path, []byte("invalid"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readConntrackEntries(path); err == nil {
+		t.Fatal("expected error for invalid conntrack count")
+	}
+}
+
+func TestPodSandboxMetrics(t *testing.T) {
+	podMetrics := podSandboxMetrics("id", &NetworkStats{
+		Timestamp: 1,
+		Interfaces: []InterfaceStats{{
+			Name:      "eth0",
+			RxPackets: 10,
+			Queues:    []QueueStat{{Queue: "rx-0", Name: "packets", Value: 10}},
+		}},
+		Sockets:          SocketStats{TCP: 2, UDP: 1},
+		ConntrackEntries: 3,
+	})
+
+	if podMetrics.PodSandboxId != "id" {
+		t.Fatalf("expected pod sandbox ID id, got %s", podMetrics.PodSandboxId)
+	}
+	descriptors := map[string]*types.MetricDescriptor{}
+	for _, d := range MetricDescriptors() {
+		descriptors[d.Name] = d
+	}
+	values := map[string]uint64{}
+	for _, m := range podMetrics.Metrics {
+		d, ok := descriptors[m.Name]
+		if !ok {
+			t.Fatalf("metric %s has no descriptor", m.Name)
+		}
+		if len(d.LabelKeys) != len(m.LabelValues) {
+			t.Fatalf("metric %s has labels %v, expected keys %v", m.Name, m.LabelValues, d.LabelKeys)
+		}
+		if m.Timestamp != 1 {
+			t.Fatalf("expected timestamp 1 for metric %s, got %d", m.Name, m.Timestamp)
+		}
+		values[m.Name+"/"+fmt.Sprint(m.LabelValues)] = m.Value.Value
+	}
+	for key, expected := range map[string]uint64{
+		metricNetworkReceivePackets + "/[eth0]":               10,
+		metricNetworkQueueStatistics + "/[eth0 rx-0 packets]": 10,
+		metricNetworkSockets + "/[tcp]":                       2,
+		metricNetworkSockets + "/[udp]":                       1,
+		metricNetworkConntrackEntries + "/[]":                 3,
+	} {
+		if values[key] != expected {
+			t.Fatalf("expected %s to be %d, got %d", key, expected, values[key])
+		}
+	}
+}
+
+func TestPodSandboxMetricsWithoutStats(t *testing.T) {
+	podMetrics := podSandboxMetrics("id", nil)
+	if podMetrics.PodSandboxId != "id" || len(podMetrics.Metrics) != 0 {
+		t.Fatalf("expected empty metrics for pod sandbox id, got %+v", podMetrics)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/oci"
 	"github.com/cri-o/cri-o/pkg/config"
+	"github.com/cri-o/cri-o/server/metrics"
 	"github.com/sirupsen/logrus"
 	"github.com/vishvananda/netlink"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
@@ -26,6 +27,9 @@ type StatsServer struct {
 	collectionPeriod time.Duration
 	sboxStats        map[string]*types.PodSandboxStats
 	ctrStats         map[string]*types.ContainerStats
+	// netStats contains nil entries for sandboxes whose network statistics
+	// could not be collected, until they get collected again.
+	netStats map[string]*NetworkStats
 	parentServerIface
 	mutex sync.Mutex
 }
@@ -49,6 +53,7 @@ func New(cs parentServerIface) *StatsServer {
 		collectionPeriod:  time.Duration(cs.Config().StatsCollectionPeriod) * time.Second,
 		sboxStats:         make(map[string]*types.PodSandboxStats),
 		ctrStats:          make(map[string]*types.ContainerStats),
+		netStats:          make(map[string]*NetworkStats),
 		parentServerIface: cs,
 	}
 	go ss.updateLoop()
@@ -87,6 +92,7 @@ func (ss *StatsServer) update() {
 // updateSandbox updates the StatsServer's entry for this sandbox, as well as each child container.
 // It first populates the stats from the CgroupParent, then calculates network usage, updates
 // each of its children container stats by calling into the runtime, and finally calculates the CPUNanoCores.
+// The extended network statistics of the sandbox are exposed via the prometheus metrics as well.
 func (ss *StatsServer) updateSandbox(sb *sandbox.Sandbox) *types.PodSandboxStats {
 	if sb == nil {
 		return nil
@@ -103,8 +109,13 @@ func (ss *StatsServer) updateSandbox(sb *sandbox.Sandbox) *types.PodSandboxStats
 	if err := ss.Config().CgroupManager().PopulateSandboxCgroupStats(sb.CgroupParent(), sandboxStats); err != nil {
 		logrus.Errorf("Error getting sandbox stats %s: %v", sb.ID(), err)
 	}
-	if err := ss.populateNetworkUsage(sandboxStats, sb); err != nil {
+	netStats := &NetworkStats{Timestamp: time.Now().UnixNano()}
+	if err := ss.populateNetworkUsage(sandboxStats, netStats, sb); err != nil {
 		logrus.Errorf("Error adding network stats for sandbox %s: %v", sb.ID(), err)
+		ss.netStats[sb.ID()] = nil
+	} else {
+		ss.netStats[sb.ID()] = netStats
+		updateNetworkMetrics(sb, netStats)
 	}
 	containerStats := make([]*types.ContainerStats, 0, len(sb.Containers().List()))
 	for _, c := range sb.Containers().List() {
@@ -205,7 +216,9 @@ func (ss *StatsServer) writableLayerForContainer(container *oci.Container) (*typ
 }
 
 // populateNetworkUsage gathers information about the network from within the sandbox's network namespace.
-func (ss *StatsServer) populateNetworkUsage(stats *types.PodSandboxStats, sb *sandbox.Sandbox) error {
+// Besides the CRI network usage, it collects the extended interface statistics, per-queue statistics,
+// socket counts and connection tracking entries into netStats.
+func (ss *StatsServer) populateNetworkUsage(stats *types.PodSandboxStats, netStats *NetworkStats, sb *sandbox.Sandbox) error {
 	return ns.WithNetNSPath(sb.NetNsPath(), func(_ ns.NetNS) error {
 		links, err := netlink.LinkList()
 		if err != nil {
@@ -227,6 +240,24 @@ func (ss *StatsServer) populateNetworkUsage(stats *types.PodSandboxStats, sb *sa
 			} else {
 				stats.Linux.Network.Interfaces = append(stats.Linux.Network.Interfaces, iface)
 			}
+
+			ifaceStats, err := linkToInterfaceStats(links[i])
+			if err != nil {
+				continue
+			}
+			names, values, err := ethtoolDriverStats(ifaceStats.Name)
+			if err != nil {
+				logrus.Debugf("Unable to get queue stats of interface %s for pod %s: %v", ifaceStats.Name, sb.ID(), err)
+			}
+			ifaceStats.Queues = queueStats(names, values)
+			netStats.Interfaces = append(netStats.Interfaces, *ifaceStats)
+		}
+
+		if netStats.Sockets, err = readSocketStats(procNetPath); err != nil {
+			logrus.Errorf("Unable to get socket stats for pod %s: %v", sb.ID(), err)
+		}
+		if netStats.ConntrackEntries, err = readConntrackEntries(conntrackCountPath); err != nil {
+			logrus.Errorf("Unable to get conntrack entries for pod %s: %v", sb.ID(), err)
 		}
 		return nil
 	})
@@ -285,12 +316,31 @@ func (ss *StatsServer) statsForSandbox(sb *sandbox.Sandbox) *types.PodSandboxSta
 	return ss.updateSandbox(sb)
 }
 
+// MetricsForSandboxes returns the extended network metrics for the given list of sandboxes.
+func (ss *StatsServer) MetricsForSandboxes(sboxes []*sandbox.Sandbox) []*types.PodSandboxMetrics {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+	podMetrics := make([]*types.PodSandboxMetrics, 0, len(sboxes))
+	for _, sb := range sboxes {
+		if ss.collectionPeriod == 0 {
+			ss.updateSandbox(sb)
+		} else if _, ok := ss.netStats[sb.ID()]; !ok {
+			// Cache miss, try again
+			ss.updateSandbox(sb)
+		}
+		podMetrics = append(podMetrics, podSandboxMetrics(sb.ID(), ss.netStats[sb.ID()]))
+	}
+	return podMetrics
+}
+
 // RemoveStatsForSandbox removes the saved entry for the specified sandbox
 // to prevent the map from always growing.
 func (ss *StatsServer) RemoveStatsForSandbox(sb *sandbox.Sandbox) {
 	ss.mutex.Lock()
 	defer ss.mutex.Unlock()
 	delete(ss.sboxStats, sb.ID())
+	delete(ss.netStats, sb.ID())
+	metrics.Instance().MetricPodNetworkDelete(sb.ID())
 }
 
 // StatsForContainer returns the stats for the given container
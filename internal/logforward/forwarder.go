@@ -0,0 +1,510 @@
+package logforward
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultInterval is the interval in which the log files get read.
+	defaultInterval = 250 * time.Millisecond
+
+	// retryInterval is the interval in which connecting to the log forward
+	// socket gets retried.
+	retryInterval = time.Second
+
+	// writeTimeout is the maximum time to wait for the log agent to accept
+	// a line, before reconnecting.
+	writeTimeout = 5 * time.Second
+
+	// readBufferSize is the size of the chunks the log files get read in.
+	readBufferSize = 32 * 1024
+
+	// maxMessageSize is the maximum size of a message joined from partial
+	// lines. Larger messages get forwarded in multiple records.
+	maxMessageSize = 1024 * 1024
+)
+
+// Forwarder reads the log files of the registered containers and forwards
+// every line to a unix socket. Lines are buffered while the socket is
+// unavailable.
+type Forwarder struct {
+	socketPath string
+	encode     encoder
+	interval   time.Duration
+
+	// lock protects the watches, but is not held while reading the log
+	// files, which is serialized per watch instead.
+	lock    sync.Mutex
+	watches map[string]*watch
+
+	buffer *buffer
+	notify chan struct{}
+}
+
+// New creates a new log forwarder for the provided unix socket path, format
+// and maximum number of buffered lines.
+func New(socketPath, format string, bufferSize uint64) (*Forwarder, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		logrus.Debugf("Unable to get hostname for log forwarding: %v", err)
+	}
+	encode, err := newEncoder(format, hostname)
+	if err != nil {
+		return nil, err
+	}
+	return &Forwarder{
+		socketPath: socketPath,
+		encode:     encode,
+		interval:   defaultInterval,
+		watches:    make(map[string]*watch),
+		buffer:     &buffer{size: bufferSize},
+		notify:     make(chan struct{}, 1),
+	}, nil
+}
+
+// Add starts forwarding the log lines of the container with the provided ID.
+// If fromStart is false, only lines written after adding the container get
+// forwarded.
+func (f *Forwarder) Add(id, logPath string, metadata Metadata, fromStart bool) {
+	w := &watch{
+		id:       id,
+		logPath:  logPath,
+		metadata: metadata,
+		partial:  make(map[string][]byte),
+	}
+	if file, err := os.Open(logPath); err == nil {
+		if !fromStart {
+			if _, err := file.Seek(0, io.SeekEnd); err != nil {
+				logrus.Debugf("Unable to seek to the end of log file %s: %v", logPath, err)
+			}
+		}
+		w.file = file
+	}
+
+	f.lock.Lock()
+	old, ok := f.watches[id]
+	f.watches[id] = w
+	f.lock.Unlock()
+
+	if ok {
+		old.lock.Lock()
+		defer old.lock.Unlock()
+		old.close()
+	}
+}
+
+// Remove forwards the remaining log lines of the container with the provided
+// ID and stops forwarding its logs afterwards.
+func (f *Forwarder) Remove(id string) {
+	f.lock.Lock()
+	w, ok := f.watches[id]
+	delete(f.watches, id)
+	f.lock.Unlock()
+	if !ok {
+		return
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	f.read(w)
+	w.close()
+	f.wakeSender()
+}
+
+// Start forwards the log lines in the background until done is closed.
+func (f *Forwarder) Start(done <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(f.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				f.Forward()
+			case <-done:
+				logrus.Debug("Closing log forwarder")
+				for _, w := range f.list() {
+					w.lock.Lock()
+					w.close()
+					w.lock.Unlock()
+				}
+				return
+			}
+		}
+	}()
+	go f.send(done)
+}
+
+// Forward reads the new lines of all registered containers once and queues
+// them for sending.
+func (f *Forwarder) Forward() {
+	for _, w := range f.list() {
+		w.lock.Lock()
+		f.read(w)
+		w.lock.Unlock()
+	}
+	f.wakeSender()
+}
+
+// list returns the currently registered watches.
+func (f *Forwarder) list() []*watch {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	watches := make([]*watch, 0, len(f.watches))
+	for _, w := range f.watches {
+		watches = append(watches, w)
+	}
+	return watches
+}
+
+// read queues the new lines of the provided watch, which has to be locked.
+func (f *Forwarder) read(w *watch) {
+	if err := w.read(f.buffer.push); err != nil {
+		logrus.Debugf("Unable to read log file of container %s for forwarding: %v", w.id, err)
+	}
+}
+
+// wakeSender notifies the sender about queued lines.
+func (f *Forwarder) wakeSender() {
+	select {
+	case f.notify <- struct{}{}:
+	default:
+	}
+}
+
+// send writes the queued lines to the log forward socket until done is
+// closed, reconnecting if required.
+func (f *Forwarder) send(done <-chan struct{}) {
+	var conn net.Conn
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+
+	wait := func(d time.Duration) bool {
+		select {
+		case <-done:
+			return false
+		case <-time.After(d):
+			return true
+		}
+	}
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-f.notify:
+		}
+
+		for {
+			records, first := f.buffer.peek()
+			if len(records) == 0 {
+				break
+			}
+
+			if conn == nil {
+				var err error
+				conn, err = net.Dial("unix", f.socketPath)
+				if err != nil {
+					logrus.Debugf("Unable to connect to log forward socket %s: %v", f.socketPath, err)
+					conn = nil
+					if !wait(retryInterval) {
+						return
+					}
+					continue
+				}
+				logrus.Debugf("Connected to log forward socket %s", f.socketPath)
+			}
+
+			sent, err := f.write(conn, records)
+			f.buffer.pop(first + sent)
+			if err != nil {
+				logrus.Debugf("Unable to write to log forward socket %s: %v", f.socketPath, err)
+				conn.Close()
+				conn = nil
+				if !wait(retryInterval) {
+					return
+				}
+				continue
+			}
+
+			if dropped := f.buffer.resetDropped(); dropped > 0 {
+				logrus.Warnf("Dropped %d log lines because the log forward buffer was full", dropped)
+			}
+		}
+	}
+}
+
+// write writes the provided records to conn and returns the number of
+// written records.
+func (f *Forwarder) write(conn net.Conn, records []*Record) (uint64, error) {
+	var sent uint64
+	for _, r := range records {
+		frame, err := f.encode(r)
+		if err != nil {
+			logrus.Debugf("Unable to encode log line of container %s: %v", r.ContainerID, err)
+			sent++
+			continue
+		}
+		if err := conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+			return sent, err
+		}
+		if _, err := conn.Write(frame); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// buffer is a bounded queue of records, which drops the oldest records if
+// full.
+type buffer struct {
+	lock    sync.Mutex
+	size    uint64
+	records []*Record
+	// first is the sequence number of the first record.
+	first   uint64
+	dropped uint64
+}
+
+func (b *buffer) push(r *Record) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.records = append(b.records, r)
+	if uint64(len(b.records)) > b.size {
+		b.records[0] = nil
+		b.records = b.records[1:]
+		b.first++
+		b.dropped++
+	}
+}
+
+// peek returns a copy of the queued records and the sequence number of the
+// first one.
+func (b *buffer) peek() ([]*Record, uint64) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return append([]*Record(nil), b.records...), b.first
+}
+
+// pop removes the records with a sequence number lower than the provided one.
+func (b *buffer) pop(until uint64) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for b.first < until && len(b.records) > 0 {
+		b.records[0] = nil
+		b.records = b.records[1:]
+		b.first++
+	}
+}
+
+// resetDropped returns the number of records dropped since the last call.
+func (b *buffer) resetDropped() uint64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	dropped := b.dropped
+	b.dropped = 0
+	return dropped
+}
+
+// watch follows the log file of a single container.
+type watch struct {
+	id       string
+	logPath  string
+	metadata Metadata
+	// lock serializes reading and closing the log files.
+	lock   sync.Mutex
+	closed bool
+	file   *os.File
+	// rotated is the previous log file after a rotation, which gets read
+	// until the container runtime writes to the new file.
+	rotated *os.File
+	// pending is the incomplete last line read from the file.
+	pending []byte
+	// partial are the messages of partial lines per stream.
+	partial map[string][]byte
+}
+
+// read emits the lines written since the last read. It follows the log
+// file if it got rotated or reopened.
+func (w *watch) read(emit func(*Record)) error {
+	if w.closed {
+		return nil
+	}
+	if w.file == nil {
+		file, err := os.Open(w.logPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		w.file = file
+	}
+
+	for {
+		if w.rotated != nil {
+			switched, err := w.readRotated(emit)
+			if err != nil || !switched {
+				return err
+			}
+		}
+		if err := w.readToEnd(w.file, emit); err != nil {
+			return err
+		}
+		rotated, err := w.reopen(emit)
+		if err != nil || !rotated {
+			return err
+		}
+	}
+}
+
+// readRotated emits the lines of the rotated log file and closes it once the
+// container runtime switched to the new log file. Until then, the new file
+// does not get read to keep the order of the lines.
+func (w *watch) readRotated(emit func(*Record)) (switched bool, err error) {
+	info, err := w.file.Stat()
+	if err != nil {
+		return false, err
+	}
+	// The size has to be checked before reading the rotated file to not
+	// miss lines written to it in between.
+	switched = info.Size() > 0
+	if err := w.readToEnd(w.rotated, emit); err != nil {
+		return false, err
+	}
+	if switched {
+		w.rotated.Close()
+		w.rotated = nil
+	}
+	return switched, nil
+}
+
+// reopen opens the log file if it got rotated or reopened, which is detected
+// by comparing the inodes of the open file and the log path. The previous
+// file is kept to read the lines written to it until the container runtime
+// switches to the new file.
+func (w *watch) reopen(emit func(*Record)) (bool, error) {
+	fileInfo, err := w.file.Stat()
+	if err != nil {
+		return false, err
+	}
+	pathInfo, err := os.Stat(w.logPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if os.SameFile(fileInfo, pathInfo) {
+		return false, nil
+	}
+
+	file, err := os.Open(w.logPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if w.rotated != nil {
+		// Drain the file rotated before, which does not get written to
+		// anymore, before replacing it.
+		if err := w.readToEnd(w.rotated, emit); err != nil {
+			logrus.Debugf("Unable to read rotated log file of container %s: %v", w.id, err)
+		}
+		w.rotated.Close()
+	}
+	w.rotated = w.file
+	w.file = file
+	return true, nil
+}
+
+// readToEnd emits the lines until the end of the provided file.
+func (w *watch) readToEnd(file *os.File, emit func(*Record)) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	pos, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if info.Size() < pos {
+		// The log file got truncated in place, for example by a copytruncate
+		// log rotation. The lines written since then start at the beginning
+		// of the file, while the rest of an incomplete line got lost.
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if len(w.pending) > 0 {
+			logrus.Debugf("Dropping incomplete log line of truncated log file of container %s", w.id)
+		}
+		w.pending = nil
+	}
+
+	buf := make([]byte, readBufferSize)
+	for {
+		n, err := file.Read(buf)
+		w.consume(buf[:n], emit)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// consume emits the complete lines of the provided data.
+func (w *watch) consume(data []byte, emit func(*Record)) {
+	w.pending = append(w.pending, data...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			return
+		}
+		line := w.pending[:i]
+		w.pending = w.pending[i+1:]
+
+		timestamp, stream, partial, msg, err := parseLine(line)
+		if err != nil {
+			logrus.Debugf("Skipping invalid log line of container %s: %v", w.id, err)
+			continue
+		}
+		message := append(w.partial[stream], msg...)
+		if partial && len(message) < maxMessageSize {
+			w.partial[stream] = message
+			continue
+		}
+		delete(w.partial, stream)
+
+		emit(&Record{
+			Metadata: w.metadata,
+			Time:     timestamp,
+			Stream:   stream,
+			Message:  string(message),
+		})
+	}
+}
+
+func (w *watch) close() {
+	w.closed = true
+	if w.rotated != nil {
+		w.rotated.Close()
+		w.rotated = nil
+	}
+	if w.file != nil {
+		w.file.Close()
+		w.file = nil
+	}
+}
@@ -0,0 +1,324 @@
+package logforward_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cri-o/cri-o/internal/logforward"
+	"github.com/cri-o/cri-o/pkg/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("Forwarder", func() {
+	const timestamp = "2023-01-02T03:04:05.123456789Z"
+
+	var (
+		tempDir    string
+		socketPath string
+		logPath    string
+		done       chan struct{}
+		metadata   = logforward.Metadata{
+			Pod:         "pod",
+			Namespace:   "namespace",
+			Container:   "container",
+			ContainerID: "id",
+		}
+	)
+
+	// listen starts a local log agent and returns its received connections.
+	listen := func() (net.Listener, <-chan net.Conn) {
+		listener, err := net.Listen("unix", socketPath)
+		Expect(err).To(BeNil())
+		conns := make(chan net.Conn, 10)
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				conns <- conn
+			}
+		}()
+		return listener, conns
+	}
+
+	appendLog := func(lines ...string) {
+		f, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+		Expect(err).To(BeNil())
+		defer f.Close()
+		for _, line := range lines {
+			_, err := f.WriteString(line + "\n")
+			Expect(err).To(BeNil())
+		}
+	}
+
+	readJSON := func(reader *bufio.Reader) map[string]string {
+		line, err := reader.ReadString('\n')
+		Expect(err).To(BeNil())
+		record := map[string]string{}
+		Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+		return record
+	}
+
+	newForwarder := func(format string, bufferSize uint64) *logforward.Forwarder {
+		sut, err := logforward.New(socketPath, format, bufferSize)
+		Expect(err).To(BeNil())
+		sut.Start(done)
+		return sut
+	}
+
+	BeforeEach(func() {
+		tempDir = t.MustTempDir("logforward")
+		socketPath = filepath.Join(tempDir, "agent.sock")
+		logPath = filepath.Join(tempDir, "ctr.log")
+		done = make(chan struct{})
+	})
+
+	AfterEach(func() {
+		close(done)
+	})
+
+	It("should fail with unsupported format", func() {
+		// Given
+		// When
+		sut, err := logforward.New(socketPath, "invalid", 1)
+
+		// Then
+		Expect(err).NotTo(BeNil())
+		Expect(sut).To(BeNil())
+	})
+
+	It("should forward lines as JSON", func() {
+		// Given
+		listener, conns := listen()
+		defer listener.Close()
+		sut := newForwarder(config.LogForwardFormatJSON, 10)
+		sut.Add("id", logPath, metadata, true)
+		appendLog(
+			timestamp+" stdout F hello",
+			timestamp+" stderr P part1 ",
+			timestamp+" stderr F part2",
+		)
+
+		// When
+		sut.Forward()
+
+		// Then
+		var conn net.Conn
+		Eventually(conns).Should(Receive(&conn))
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		Expect(readJSON(reader)).To(Equal(map[string]string{
+			"time":         timestamp,
+			"stream":       "stdout",
+			"pod":          "pod",
+			"namespace":    "namespace",
+			"container":    "container",
+			"container_id": "id",
+			"message":      "hello",
+		}))
+		record := readJSON(reader)
+		Expect(record["stream"]).To(Equal("stderr"))
+		Expect(record["message"]).To(Equal("part1 part2"))
+	})
+
+	It("should forward lines as syslog messages", func() {
+		// Given
+		listener, conns := listen()
+		defer listener.Close()
+		sut := newForwarder(config.LogForwardFormatSyslog, 10)
+		sut.Add("id", logPath, metadata, true)
+		appendLog(timestamp + " stderr F hello world")
+
+		// When
+		sut.Forward()
+
+		// Then
+		var conn net.Conn
+		Eventually(conns).Should(Receive(&conn))
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		length, err := reader.ReadString(' ')
+		Expect(err).To(BeNil())
+		size, err := strconv.Atoi(strings.TrimSpace(length))
+		Expect(err).To(BeNil())
+		msg := make([]byte, size)
+		_, err = io.ReadFull(reader, msg)
+		Expect(err).To(BeNil())
+		Expect(string(msg)).To(HavePrefix("<11>1 2023-01-02T03:04:05.123456Z "))
+		Expect(string(msg)).To(HaveSuffix(
+			` container - stderr [crio@32473 pod="pod" namespace="namespace" container_id="id"] hello world`,
+		))
+	})
+
+	It("should only forward new lines if not reading from start", func() {
+		// Given
+		listener, conns := listen()
+		defer listener.Close()
+		appendLog(timestamp + " stdout F old")
+		sut := newForwarder(config.LogForwardFormatJSON, 10)
+		sut.Add("id", logPath, metadata, false)
+		appendLog(timestamp + " stdout F new")
+
+		// When
+		sut.Forward()
+
+		// Then
+		var conn net.Conn
+		Eventually(conns).Should(Receive(&conn))
+		defer conn.Close()
+		Expect(readJSON(bufio.NewReader(conn))["message"]).To(Equal("new"))
+	})
+
+	It("should follow rotated log files", func() {
+		// Given
+		listener, conns := listen()
+		defer listener.Close()
+		sut := newForwarder(config.LogForwardFormatJSON, 10)
+		appendLog(timestamp + " stdout F first")
+		sut.Add("id", logPath, metadata, true)
+		sut.Forward()
+		Expect(os.Rename(logPath, logPath+".1")).To(Succeed())
+		appendLog(timestamp + " stdout F second")
+
+		// When
+		sut.Forward()
+
+		// Then
+		var conn net.Conn
+		Eventually(conns).Should(Receive(&conn))
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		Expect(readJSON(reader)["message"]).To(Equal("first"))
+		Expect(readJSON(reader)["message"]).To(Equal("second"))
+	})
+
+	It("should read rotated log files until the new one gets written", func() {
+		// Given
+		listener, conns := listen()
+		defer listener.Close()
+		sut := newForwarder(config.LogForwardFormatJSON, 10)
+		appendLog(timestamp + " stdout F first")
+		sut.Add("id", logPath, metadata, true)
+		sut.Forward()
+		rotated, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND, 0)
+		Expect(err).To(BeNil())
+		defer rotated.Close()
+		Expect(os.Rename(logPath, logPath+".1")).To(Succeed())
+		Expect(os.WriteFile(logPath, nil, 0o644)).To(Succeed())
+		sut.Forward()
+
+		// When
+		_, err = rotated.WriteString(timestamp + " stdout F second\n")
+		Expect(err).To(BeNil())
+		appendLog(timestamp + " stdout F third")
+		sut.Forward()
+
+		// Then
+		var conn net.Conn
+		Eventually(conns).Should(Receive(&conn))
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		Expect(readJSON(reader)["message"]).To(Equal("first"))
+		Expect(readJSON(reader)["message"]).To(Equal("second"))
+		Expect(readJSON(reader)["message"]).To(Equal("third"))
+	})
+
+	It("should follow log files truncated in place", func() {
+		// Given
+		listener, conns := listen()
+		defer listener.Close()
+		sut := newForwarder(config.LogForwardFormatJSON, 10)
+		appendLog(timestamp + " stdout F first")
+		sut.Add("id", logPath, metadata, true)
+		sut.Forward()
+		Expect(os.Truncate(logPath, 0)).To(Succeed())
+		appendLog(timestamp + " stdout F new")
+
+		// When
+		sut.Forward()
+
+		// Then
+		var conn net.Conn
+		Eventually(conns).Should(Receive(&conn))
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		Expect(readJSON(reader)["message"]).To(Equal("first"))
+		Expect(readJSON(reader)["message"]).To(Equal("new"))
+	})
+
+	It("should buffer lines until the log agent is available", func() {
+		// Given
+		sut := newForwarder(config.LogForwardFormatJSON, 2)
+		sut.Add("id", logPath, metadata, true)
+		for i := 0; i < 3; i++ {
+			appendLog(fmt.Sprintf("%s stdout F line%d", timestamp, i))
+		}
+		sut.Forward()
+
+		// When
+		listener, conns := listen()
+		defer listener.Close()
+
+		// Then
+		var conn net.Conn
+		Eventually(conns, "5s").Should(Receive(&conn))
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		// The oldest line got dropped because of the buffer size
+		Expect(readJSON(reader)["message"]).To(Equal("line1"))
+		Expect(readJSON(reader)["message"]).To(Equal("line2"))
+	})
+
+	It("should reconnect after the log agent restarted", func() {
+		// Given
+		listener, conns := listen()
+		sut := newForwarder(config.LogForwardFormatJSON, 10)
+		sut.Add("id", logPath, metadata, true)
+		appendLog(timestamp + " stdout F before")
+		sut.Forward()
+		var conn net.Conn
+		Eventually(conns).Should(Receive(&conn))
+		Expect(readJSON(bufio.NewReader(conn))["message"]).To(Equal("before"))
+		conn.Close()
+		listener.Close()
+
+		// When
+		appendLog(timestamp + " stdout F after")
+		sut.Forward()
+		listener, conns = listen()
+		defer listener.Close()
+
+		// Then
+		Eventually(conns, "5s").Should(Receive(&conn))
+		defer conn.Close()
+		Expect(readJSON(bufio.NewReader(conn))["message"]).To(Equal("after"))
+	})
+
+	It("should forward the remaining lines on removal", func() {
+		// Given
+		listener, conns := listen()
+		defer listener.Close()
+		sut := newForwarder(config.LogForwardFormatJSON, 10)
+		sut.Add("id", logPath, metadata, true)
+		appendLog(timestamp + " stdout F last")
+
+		// When
+		sut.Remove("id")
+
+		// Then
+		var conn net.Conn
+		Eventually(conns).Should(Receive(&conn))
+		defer conn.Close()
+		Expect(readJSON(bufio.NewReader(conn))["message"]).To(Equal("last"))
+	})
+})
@@ -0,0 +1,176 @@
+package logforward
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cri-o/cri-o/pkg/config"
+	json "github.com/json-iterator/go"
+)
+
+const (
+	// syslogTimeFormat is the RFC 5424 timestamp format, which allows at
+	// most six digits of fractional seconds.
+	syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+	// syslogSDID is the ID of the structured data element containing the
+	// container metadata. 32473 is the private enterprise number reserved
+	// for documentation.
+	syslogSDID = "crio@32473"
+
+	// syslogFacilityUser is the syslog facility of the forwarded lines.
+	syslogFacilityUser = 1
+
+	syslogSeverityError = 3
+	syslogSeverityInfo  = 6
+
+	// syslogMaxAppName is the maximum length of the syslog APP-NAME.
+	syslogMaxAppName = 48
+
+	// syslogMaxHostname is the maximum length of the syslog HOSTNAME.
+	syslogMaxHostname = 255
+)
+
+// Metadata describes the container a log line belongs to.
+type Metadata struct {
+	Pod         string
+	Namespace   string
+	Container   string
+	ContainerID string
+}
+
+// Record is a single log line of a container.
+type Record struct {
+	Metadata
+
+	// Time is the time the line got logged by the container.
+	Time time.Time
+
+	// Stream is the stream the line got logged to, either "stdout" or
+	// "stderr".
+	Stream string
+
+	// Message is the line without the trailing newline.
+	Message string
+}
+
+// jsonRecord is the representation of a Record in the json format.
+type jsonRecord struct {
+	Time        string `json:"time"`
+	Stream      string `json:"stream"`
+	Pod         string `json:"pod"`
+	Namespace   string `json:"namespace"`
+	Container   string `json:"container"`
+	ContainerID string `json:"container_id"`
+	Message     string `json:"message"`
+}
+
+// encoder encodes a record into a frame written to the log forward socket.
+type encoder func(*Record) ([]byte, error)
+
+// newEncoder returns the encoder for the provided format.
+func newEncoder(format, hostname string) (encoder, error) {
+	switch format {
+	case config.LogForwardFormatJSON:
+		return encodeJSON, nil
+	case config.LogForwardFormatSyslog:
+		hostname = syslogValue(hostname, syslogMaxHostname)
+		return func(r *Record) ([]byte, error) {
+			return encodeSyslog(r, hostname), nil
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported log forward format %q", format)
+}
+
+// encodeJSON encodes the record as JSON object terminated by a newline.
+func encodeJSON(r *Record) ([]byte, error) {
+	b, err := json.Marshal(&jsonRecord{
+		Time:        r.Time.Format(time.RFC3339Nano),
+		Stream:      r.Stream,
+		Pod:         r.Pod,
+		Namespace:   r.Namespace,
+		Container:   r.Container,
+		ContainerID: r.ContainerID,
+		Message:     r.Message,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// encodeSyslog encodes the record as RFC 5424 syslog message, framed by
+// octet counting as defined in RFC 6587.
+func encodeSyslog(r *Record, hostname string) []byte {
+	severity := syslogSeverityInfo
+	if r.Stream == "stderr" {
+		severity = syslogSeverityError
+	}
+
+	msg := fmt.Sprintf("<%d>1 %s %s %s - %s [%s pod=\"%s\" namespace=\"%s\" container_id=\"%s\"] %s",
+		syslogFacilityUser*8+severity,
+		r.Time.UTC().Format(syslogTimeFormat),
+		hostname,
+		syslogValue(r.Container, syslogMaxAppName),
+		syslogValue(r.Stream, 32),
+		syslogSDID,
+		syslogParamValue(r.Pod),
+		syslogParamValue(r.Namespace),
+		syslogParamValue(r.ContainerID),
+		r.Message,
+	)
+	return []byte(fmt.Sprintf("%d %s", len(msg), msg))
+}
+
+// syslogValue converts the provided value into a syslog header field, which
+// only allows printable US-ASCII characters.
+func syslogValue(value string, maxLen int) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, value)
+	if len(value) > maxLen {
+		value = value[:maxLen]
+	}
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// syslogParamValue escapes the provided value for the usage as syslog
+// structured data parameter value.
+func syslogParamValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+}
+
+// parseLine parses a line in the CRI log format
+// "<RFC3339Nano timestamp> <stream> <tag> <message>", where the tag is "P"
+// for partial lines and "F" for full ones.
+func parseLine(line []byte) (timestamp time.Time, stream string, partial bool, msg []byte, err error) {
+	fields := bytes.SplitN(line, []byte{' '}, 4)
+	if len(fields) < 3 {
+		return timestamp, "", false, nil, errors.New("missing log line fields")
+	}
+	timestamp, err = time.Parse(time.RFC3339Nano, string(fields[0]))
+	if err != nil {
+		return timestamp, "", false, nil, fmt.Errorf("parse timestamp: %w", err)
+	}
+	stream = string(fields[1])
+	if stream != "stdout" && stream != "stderr" {
+		return timestamp, "", false, nil, fmt.Errorf("unknown stream %q", stream)
+	}
+	// The tag can contain multiple values delimited by a colon, of which
+	// the first one indicates if the line is partial.
+	tags := bytes.Split(fields[2], []byte{':'})
+	partial = string(tags[0]) == "P"
+	if len(fields) == 4 {
+		msg = fields[3]
+	}
+	return timestamp, stream, partial, msg, nil
+}
@@ -0,0 +1,268 @@
+package loglimit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultInterval is the interval in which the log files get checked.
+	defaultInterval = time.Second
+
+	// maxReadSize is the maximum amount of new log content processed per
+	// container and interval. Content beyond is checked on the next
+	// interval.
+	maxReadSize = 16 * 1024 * 1024
+
+	// ThrottledMessage is the message of the marker line written to the log
+	// file when a container starts exceeding its log rate limit.
+	ThrottledMessage = "log rate limit exceeded"
+)
+
+// ExceededFunc is called with the amount of lines of a container exceeding
+// the log rate limit.
+type ExceededFunc func(lines uint64)
+
+// Enforcer periodically checks the log rate limits of the registered
+// containers. Conmon writes the logs directly into the log files and does not
+// support rate limits, which is why the enforcer cannot drop lines before
+// they reach the disk. Instead, it counts the lines exceeding the limit and
+// appends a marker line to the log file when a container starts exceeding
+// it. The log files are never rotated or rewritten, their size is bounded by
+// the quota, which the monitor enforces by truncating the log file, see
+// Limits.LogSizeMax.
+type Enforcer struct {
+	interval time.Duration
+	now      func() time.Time
+
+	// enforceLock serializes the enforcement, which modifies the state of
+	// the watches without holding lock.
+	enforceLock sync.Mutex
+
+	lock    sync.Mutex
+	watches map[string]*watch
+}
+
+type watch struct {
+	logPath    string
+	limits     *Limits
+	onExceeded ExceededFunc
+
+	offset     int64
+	last       time.Time
+	lineTokens float64
+	byteTokens float64
+	throttled  bool
+}
+
+// New creates a new log limit enforcer.
+func New() *Enforcer {
+	return NewWithInterval(defaultInterval)
+}
+
+// NewWithInterval creates a new log limit enforcer checking the log files in
+// the provided interval.
+func NewWithInterval(interval time.Duration) *Enforcer {
+	return &Enforcer{
+		interval: interval,
+		now:      time.Now,
+		watches:  make(map[string]*watch),
+	}
+}
+
+// Add starts checking the log rate limit of the container with the provided
+// ID. Content already present in the log file is not checked.
+func (e *Enforcer) Add(id, logPath string, limits *Limits, onExceeded ExceededFunc) {
+	var offset int64
+	if info, err := os.Stat(logPath); err == nil {
+		offset = info.Size()
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.watches[id] = &watch{
+		logPath:    logPath,
+		limits:     limits,
+		onExceeded: onExceeded,
+		offset:     offset,
+		last:       e.now(),
+		lineTokens: float64(limits.Lines * limits.Burst),
+		byteTokens: float64(limits.Bytes * limits.Burst),
+	}
+}
+
+// Remove stops checking the log rate limit of the container with the
+// provided ID.
+func (e *Enforcer) Remove(id string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	delete(e.watches, id)
+}
+
+// Start checks the log rate limits in the background until done is closed.
+func (e *Enforcer) Start(done <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				e.Enforce()
+			case <-done:
+				logrus.Debug("Closing log limit enforcer")
+				return
+			}
+		}
+	}()
+}
+
+// Enforce checks the log rate limits of all registered containers once.
+func (e *Enforcer) Enforce() {
+	e.enforceLock.Lock()
+	defer e.enforceLock.Unlock()
+
+	// The log files are read without holding the lock.
+	e.lock.Lock()
+	watches := make(map[string]*watch, len(e.watches))
+	for id, w := range e.watches {
+		watches[id] = w
+	}
+	e.lock.Unlock()
+
+	now := e.now()
+	for id, w := range watches {
+		if err := w.check(now); err != nil {
+			logrus.Debugf("Unable to check log rate limit for container %s: %v", id, err)
+		}
+	}
+}
+
+// check counts the lines written since the last check which exceed the rate
+// limit, and appends the marker line if the container starts exceeding it.
+func (w *watch) check(now time.Time) error {
+	w.refill(now)
+
+	f, err := os.Open(w.logPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() < w.offset {
+		// The log file got rotated by the kubelet or truncated by the
+		// monitor.
+		w.offset = 0
+	}
+	if info.Size() == w.offset {
+		w.throttled = false
+		return nil
+	}
+
+	readSize := info.Size() - w.offset
+	if readSize > maxReadSize {
+		readSize = maxReadSize
+	}
+	content := make([]byte, readSize)
+	n, err := f.ReadAt(content, w.offset)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	content = content[:n]
+
+	allowed, exceeded := w.consume(content)
+	if exceeded == 0 {
+		w.offset += int64(allowed)
+		w.throttled = false
+		return nil
+	}
+
+	// Skip the exceeding lines, a trailing partial line gets checked once
+	// complete.
+	w.offset += int64(bytes.LastIndexByte(content, '\n') + 1)
+	if !w.throttled {
+		if err := appendMarkerLine(w.logPath, now); err != nil {
+			return err
+		}
+		if info, err := os.Stat(w.logPath); err == nil && info.Size() >= w.offset {
+			w.offset = info.Size()
+		}
+	}
+	w.throttled = true
+	if w.onExceeded != nil {
+		w.onExceeded(exceeded)
+	}
+	return nil
+}
+
+// appendMarkerLine appends the marker line to the log file. The monitor
+// opens the log file for appending as well, which means that the marker line
+// does not overwrite any content.
+func appendMarkerLine(logPath string, now time.Time) error {
+	f, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(MarkerLine(now)); err != nil {
+		return fmt.Errorf("write marker line: %w", err)
+	}
+	return nil
+}
+
+// refill adds the tokens for the time elapsed since the last refill.
+func (w *watch) refill(now time.Time) {
+	elapsed := now.Sub(w.last).Seconds()
+	w.last = now
+	if elapsed <= 0 {
+		return
+	}
+	w.lineTokens += elapsed * float64(w.limits.Lines)
+	if maxTokens := float64(w.limits.Lines * w.limits.Burst); w.lineTokens > maxTokens {
+		w.lineTokens = maxTokens
+	}
+	w.byteTokens += elapsed * float64(w.limits.Bytes)
+	if maxTokens := float64(w.limits.Bytes * w.limits.Burst); w.byteTokens > maxTokens {
+		w.byteTokens = maxTokens
+	}
+}
+
+// consume takes the tokens for every complete line of content until the
+// limit is reached. It returns the length of the allowed content and the
+// amount of complete lines exceeding the limit. A trailing partial line is
+// neither allowed nor counted, because it gets checked once complete.
+func (w *watch) consume(content []byte) (allowed int, exceeded uint64) {
+	for allowed < len(content) {
+		end := bytes.IndexByte(content[allowed:], '\n')
+		if end < 0 {
+			return allowed, 0
+		}
+		lineLen := end + 1
+		if (w.limits.Lines > 0 && w.lineTokens < 1) ||
+			(w.limits.Bytes > 0 && w.byteTokens < float64(lineLen)) {
+			return allowed, uint64(bytes.Count(content[allowed:], []byte{'\n'}))
+		}
+		w.lineTokens--
+		w.byteTokens -= float64(lineLen)
+		allowed += lineLen
+	}
+	return allowed, 0
+}
+
+// MarkerLine returns the line in CRI log format written to the log file when
+// a container starts exceeding its log rate limit.
+func MarkerLine(now time.Time) []byte {
+	return []byte(fmt.Sprintf("%s stderr F %s\n", now.Format(time.RFC3339Nano), ThrottledMessage))
+}
@@ -0,0 +1,161 @@
+package loglimit_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/loglimit"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("Enforcer", func() {
+	var (
+		sut      *loglimit.Enforcer
+		logPath  string
+		exceeded uint64
+	)
+
+	onExceeded := func(lines uint64) { exceeded += lines }
+
+	appendLog := func(lines ...string) {
+		f, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+		Expect(err).To(BeNil())
+		defer f.Close()
+		for _, line := range lines {
+			_, err := f.WriteString(line + "\n")
+			Expect(err).To(BeNil())
+		}
+	}
+
+	readLog := func() []string {
+		content, err := os.ReadFile(logPath)
+		Expect(err).To(BeNil())
+		if len(content) == 0 {
+			return nil
+		}
+		return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	}
+
+	BeforeEach(func() {
+		sut = loglimit.NewWithInterval(time.Hour)
+		logPath = filepath.Join(t.MustTempDir("loglimit"), "ctr.log")
+		exceeded = 0
+	})
+
+	It("should keep lines within the rate limit", func() {
+		// Given
+		sut.Add("id", logPath, &loglimit.Limits{Lines: 10, Burst: 1}, onExceeded)
+		appendLog("a", "b", "c")
+
+		// When
+		sut.Enforce()
+
+		// Then
+		Expect(readLog()).To(Equal([]string{"a", "b", "c"}))
+		Expect(exceeded).To(BeZero())
+	})
+
+	It("should count and mark lines exceeding the rate limit", func() {
+		// Given
+		sut.Add("id", logPath, &loglimit.Limits{Lines: 2, Burst: 1}, onExceeded)
+		appendLog("a", "b", "c", "d", "e")
+
+		// When
+		sut.Enforce()
+
+		// Then
+		lines := readLog()
+		Expect(lines).To(HaveLen(6))
+		Expect(lines[:5]).To(Equal([]string{"a", "b", "c", "d", "e"}))
+		Expect(lines[5]).To(HaveSuffix(" stderr F " + loglimit.ThrottledMessage))
+		Expect(exceeded).To(BeEquivalentTo(3))
+		Expect(filepath.Glob(logPath + ".*")).To(BeEmpty())
+	})
+
+	It("should count a partial line once complete", func() {
+		// Given
+		sut.Add("id", logPath, &loglimit.Limits{Lines: 1, Burst: 1}, onExceeded)
+		appendLog("a")
+		f, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND, 0)
+		Expect(err).To(BeNil())
+		_, err = f.WriteString("partial")
+		Expect(err).To(BeNil())
+		Expect(f.Close()).To(Succeed())
+		sut.Enforce()
+		Expect(exceeded).To(BeZero())
+
+		// When
+		appendLog(" line")
+		sut.Enforce()
+
+		// Then
+		Expect(exceeded).To(BeEquivalentTo(1))
+		lines := readLog()
+		Expect(lines).To(HaveLen(3))
+		Expect(lines[1]).To(Equal("partial line"))
+	})
+
+	It("should write the marker line only once while throttled", func() {
+		// Given
+		sut.Add("id", logPath, &loglimit.Limits{Lines: 1, Burst: 1}, onExceeded)
+		appendLog("a", "b")
+		sut.Enforce()
+		appendLog("c", "d")
+
+		// When
+		sut.Enforce()
+
+		// Then
+		lines := readLog()
+		Expect(lines).To(HaveLen(5))
+		Expect(lines[2]).To(HaveSuffix(" stderr F " + loglimit.ThrottledMessage))
+		Expect(lines[3:]).To(Equal([]string{"c", "d"}))
+		Expect(exceeded).To(BeEquivalentTo(3))
+	})
+
+	It("should check the log file again after it got truncated", func() {
+		// Given
+		sut.Add("id", logPath, &loglimit.Limits{Lines: 1, Burst: 1}, onExceeded)
+		appendLog("a", "b", "c")
+		sut.Enforce()
+		Expect(os.Truncate(logPath, 0)).To(Succeed())
+		appendLog("d", "e")
+
+		// When
+		sut.Enforce()
+
+		// Then
+		Expect(exceeded).To(BeEquivalentTo(4))
+	})
+
+	It("should not check content written before adding the container", func() {
+		// Given
+		appendLog("a", "b", "c")
+		sut.Add("id", logPath, &loglimit.Limits{Lines: 1, Burst: 1}, onExceeded)
+
+		// When
+		sut.Enforce()
+
+		// Then
+		Expect(readLog()).To(Equal([]string{"a", "b", "c"}))
+		Expect(exceeded).To(BeZero())
+	})
+
+	It("should not check removed containers", func() {
+		// Given
+		sut.Add("id", logPath, &loglimit.Limits{Lines: 1, Burst: 1}, onExceeded)
+		sut.Remove("id")
+		appendLog("a", "b", "c")
+
+		// When
+		sut.Enforce()
+
+		// Then
+		Expect(readLog()).To(Equal([]string{"a", "b", "c"}))
+		Expect(exceeded).To(BeZero())
+	})
+})
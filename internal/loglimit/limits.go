@@ -0,0 +1,132 @@
+package loglimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cri-o/cri-o/pkg/annotations"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Limits are the log limits of a single container.
+type Limits struct {
+	// Lines is the maximum number of log lines per second, 0 for no limit.
+	Lines uint64
+
+	// Bytes is the maximum number of log bytes per second, 0 for no limit.
+	Bytes uint64
+
+	// Burst is the number of seconds worth of lines and bytes which can be
+	// written at once after the container has been quiet.
+	Burst uint64
+
+	// Quota is the maximum number of bytes of the log file, which gets
+	// truncated by the monitor once exceeded, 0 for no limit.
+	Quota int64
+}
+
+// minQuota is the minimum quota, which is the buffer size of the monitor.
+const minQuota = 8192
+
+// FromAnnotations returns the limits configured by the provided annotations,
+// or nil if no limits are configured. The provided workload rate limit and
+// quota apply if the corresponding annotation is not set.
+func FromAnnotations(annots map[string]string, workloadRateLimit, workloadQuota string) (*Limits, error) {
+	rateLimit, hasRateLimit := annots[annotations.LogRateLimitAnnotation]
+	if !hasRateLimit {
+		rateLimit = workloadRateLimit
+	}
+	quota, hasQuota := annots[annotations.LogQuotaAnnotation]
+	if !hasQuota {
+		quota = workloadQuota
+	}
+	limits, err := Parse(rateLimit, quota)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log limits: %w", err)
+	}
+	return limits, nil
+}
+
+// Parse returns the limits of the provided rate limit in the format
+// "lines=<lines>,bytes=<quantity>,burst=<seconds>" and the provided quota,
+// where empty values mean no limit. It returns nil if both are empty.
+func Parse(rateLimit, quota string) (*Limits, error) {
+	if rateLimit == "" && quota == "" {
+		return nil, nil
+	}
+
+	limits := &Limits{Burst: 1}
+	if rateLimit != "" {
+		if err := limits.parseRateLimit(rateLimit); err != nil {
+			return nil, fmt.Errorf("rate limit %q: %w", rateLimit, err)
+		}
+	}
+	if quota != "" {
+		q, err := resource.ParseQuantity(quota)
+		if err != nil {
+			return nil, fmt.Errorf("quota %q: %w", quota, err)
+		}
+		if q.Value() < minQuota {
+			return nil, fmt.Errorf("quota %q: has to be at least %d bytes", quota, minQuota)
+		}
+		limits.Quota = q.Value()
+	}
+	return limits, nil
+}
+
+// parseRateLimit parses a rate limit of the format
+// "lines=<lines>,bytes=<quantity>,burst=<seconds>", where every key is
+// optional.
+func (l *Limits) parseRateLimit(value string) error {
+	for _, field := range strings.Split(value, ",") {
+		key, val, found := strings.Cut(strings.TrimSpace(field), "=")
+		if !found {
+			return fmt.Errorf("missing value for %q", field)
+		}
+		switch key {
+		case "lines":
+			lines, err := strconv.ParseUint(val, 10, 64)
+			if err != nil {
+				return fmt.Errorf("parse lines: %w", err)
+			}
+			l.Lines = lines
+		case "bytes":
+			q, err := resource.ParseQuantity(val)
+			if err != nil {
+				return fmt.Errorf("parse bytes: %w", err)
+			}
+			if q.Sign() < 0 {
+				return fmt.Errorf("bytes have to be positive")
+			}
+			l.Bytes = uint64(q.Value())
+		case "burst":
+			burst, err := strconv.ParseUint(val, 10, 64)
+			if err != nil {
+				return fmt.Errorf("parse burst: %w", err)
+			}
+			if burst == 0 {
+				return fmt.Errorf("burst has to be positive")
+			}
+			l.Burst = burst
+		default:
+			return fmt.Errorf("unknown key %q", key)
+		}
+	}
+	return nil
+}
+
+// RateLimited returns true if the lines or bytes per second are limited.
+func (l *Limits) RateLimited() bool {
+	return l.Lines > 0 || l.Bytes > 0
+}
+
+// LogSizeMax returns the maximum size of the log file before it gets
+// truncated by the monitor, considering the provided configured maximum
+// size, where a negative value means no limit.
+func (l *Limits) LogSizeMax(logSizeMax int64) int64 {
+	if l.Quota > 0 && (logSizeMax < 0 || l.Quota < logSizeMax) {
+		return l.Quota
+	}
+	return logSizeMax
+}
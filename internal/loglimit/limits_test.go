@@ -0,0 +1,130 @@
+package loglimit_test
+
+import (
+	"github.com/cri-o/cri-o/internal/loglimit"
+	"github.com/cri-o/cri-o/pkg/annotations"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("Limits", func() {
+	t.Describe("FromAnnotations", func() {
+		It("should return nil without annotations", func() {
+			// Given
+			// When
+			limits, err := loglimit.FromAnnotations(map[string]string{}, "", "")
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(limits).To(BeNil())
+		})
+
+		It("should parse the rate limit and quota", func() {
+			// Given
+			annots := map[string]string{
+				annotations.LogRateLimitAnnotation: "lines=100, bytes=64Ki, burst=5",
+				annotations.LogQuotaAnnotation:     "10Mi",
+			}
+
+			// When
+			limits, err := loglimit.FromAnnotations(annots, "", "")
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(*limits).To(Equal(loglimit.Limits{
+				Lines: 100,
+				Bytes: 64 * 1024,
+				Burst: 5,
+				Quota: 10 * 1024 * 1024,
+			}))
+			Expect(limits.RateLimited()).To(BeTrue())
+		})
+
+		It("should default the burst to one second", func() {
+			// Given
+			annots := map[string]string{annotations.LogRateLimitAnnotation: "lines=10"}
+
+			// When
+			limits, err := loglimit.FromAnnotations(annots, "", "")
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(limits.Burst).To(BeEquivalentTo(1))
+			Expect(limits.Bytes).To(BeZero())
+		})
+
+		It("should use the workload limits without annotations", func() {
+			// Given
+			// When
+			limits, err := loglimit.FromAnnotations(map[string]string{}, "lines=10", "1Mi")
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(limits.Lines).To(BeEquivalentTo(10))
+			Expect(limits.Quota).To(BeEquivalentTo(1024 * 1024))
+		})
+
+		It("should prefer the annotations over the workload limits", func() {
+			// Given
+			annots := map[string]string{annotations.LogRateLimitAnnotation: "bytes=1Ki"}
+
+			// When
+			limits, err := loglimit.FromAnnotations(annots, "lines=10", "1Mi")
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(limits.Lines).To(BeZero())
+			Expect(limits.Bytes).To(BeEquivalentTo(1024))
+			Expect(limits.Quota).To(BeEquivalentTo(1024 * 1024))
+		})
+
+		It("should fail on invalid rate limits", func() {
+			for _, value := range []string{"lines", "lines=-1", "bytes=foo", "burst=0", "foo=1"} {
+				// Given
+				annots := map[string]string{annotations.LogRateLimitAnnotation: value}
+
+				// When
+				limits, err := loglimit.FromAnnotations(annots, "", "")
+
+				// Then
+				Expect(err).NotTo(BeNil(), value)
+				Expect(limits).To(BeNil())
+			}
+		})
+
+		It("should fail on too small quotas", func() {
+			// Given
+			annots := map[string]string{annotations.LogQuotaAnnotation: "1"}
+
+			// When
+			limits, err := loglimit.FromAnnotations(annots, "", "")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(limits).To(BeNil())
+		})
+	})
+
+	t.Describe("LogSizeMax", func() {
+		It("should use the quota if lower than the configured size", func() {
+			// Given
+			limits := &loglimit.Limits{Quota: 1024}
+
+			// When
+			// Then
+			Expect(limits.LogSizeMax(-1)).To(BeEquivalentTo(1024))
+			Expect(limits.LogSizeMax(2048)).To(BeEquivalentTo(1024))
+			Expect(limits.LogSizeMax(512)).To(BeEquivalentTo(512))
+		})
+
+		It("should use the configured size without quota", func() {
+			// Given
+			limits := &loglimit.Limits{Lines: 10}
+
+			// When
+			// Then
+			Expect(limits.LogSizeMax(-1)).To(BeEquivalentTo(-1))
+		})
+	})
+})
@@ -0,0 +1,30 @@
+package mcs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// levelRegexp matches an MLS/MCS level like "s0:c1,c2" or "s0-s0:c0.c1023".
+var levelRegexp = regexp.MustCompile(`^s[0-9]+(-s[0-9]+)?(:c[0-9]+([.,]c[0-9]+)*)?$`)
+
+// ParseFixedLevels parses a list of "<namespace>=<level>" entries into a map
+// from namespace to level.
+func ParseFixedLevels(entries []string) (map[string]string, error) {
+	levels := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		namespace, level, ok := strings.Cut(entry, "=")
+		if !ok || namespace == "" {
+			return nil, fmt.Errorf("%q is not in the format <namespace>=<level>", entry)
+		}
+		if !levelRegexp.MatchString(level) {
+			return nil, fmt.Errorf("invalid SELinux level %q for namespace %q", level, namespace)
+		}
+		if _, ok := levels[namespace]; ok {
+			return nil, fmt.Errorf("duplicate SELinux level for namespace %q", namespace)
+		}
+		levels[namespace] = level
+	}
+	return levels, nil
+}
@@ -0,0 +1,45 @@
+package mcs_test
+
+import (
+	"github.com/cri-o/cri-o/internal/mcs"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("ParseFixedLevels", func() {
+	It("should succeed with valid entries", func() {
+		// Given
+		// When
+		res, err := mcs.ParseFixedLevels([]string{
+			"kube-system=s0:c1,c2",
+			"monitoring=s0-s0:c0.c1023",
+			"default=s0",
+		})
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(map[string]string{
+			"kube-system": "s0:c1,c2",
+			"monitoring":  "s0-s0:c0.c1023",
+			"default":     "s0",
+		}))
+	})
+
+	DescribeTable("should fail with invalid entries",
+		func(entries ...string) {
+			// Given
+			// When
+			res, err := mcs.ParseFixedLevels(entries)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(res).To(BeNil())
+		},
+		Entry("missing separator", "kube-system"),
+		Entry("missing namespace", "=s0:c1,c2"),
+		Entry("missing sensitivity", "kube-system=c1,c2"),
+		Entry("invalid category", "kube-system=s0:c1,x2"),
+		Entry("duplicate namespace", "kube-system=s0:c1", "kube-system=s0:c2"),
+	)
+})
@@ -0,0 +1,309 @@
+package mcs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containers/storage/pkg/ioutils"
+	"github.com/sirupsen/logrus"
+)
+
+// Source describes how the MCS level of a pod got chosen.
+type Source string
+
+const (
+	// SourceAuto is a level chosen randomly for the pod.
+	SourceAuto Source = "auto"
+
+	// SourceRequested is a level requested by the SELinux options of the pod.
+	SourceRequested Source = "requested"
+
+	// SourceFixed is a level fixed for the namespace of the pod.
+	SourceFixed Source = "fixed"
+)
+
+// Reservation is the MCS level reserved for a pod.
+type Reservation struct {
+	// PodID is the ID of the pod sandbox.
+	PodID string `json:"pod_id"`
+
+	// Pod and Namespace are the name and namespace of the pod.
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+
+	// Label is the SELinux process label of the pod.
+	Label string `json:"label"`
+
+	// Level is the MCS level of the process label.
+	Level string `json:"level"`
+
+	// Source describes how the level got chosen.
+	Source Source `json:"source"`
+
+	// Created is the time of the reservation.
+	Created time.Time `json:"created"`
+}
+
+// Collision is an MCS level shared by pods which should be isolated from each
+// other.
+type Collision struct {
+	Level  string   `json:"level"`
+	PodIDs []string `json:"pod_ids"`
+}
+
+// Collides returns true if the pods of both reservations share a level
+// without being expected to. Pods of the same namespace may share a level if
+// both requested it or it is fixed for their namespace.
+func Collides(a, b *Reservation) bool {
+	if a.PodID == b.PodID || a.Level == "" || a.Level != b.Level {
+		return false
+	}
+	return a.Namespace != b.Namespace || a.Source == SourceAuto || b.Source == SourceAuto
+}
+
+// LevelOf returns the MCS level of the provided SELinux label, or an empty
+// string if it has none.
+func LevelOf(label string) string {
+	fields := strings.SplitN(label, ":", 4)
+	if len(fields) < 4 {
+		return ""
+	}
+	return fields[3]
+}
+
+// state is the persisted state of the tracker.
+type state struct {
+	Reservations []*Reservation `json:"reservations"`
+}
+
+// Tracker persists the MCS levels reserved for pods across restarts.
+type Tracker struct {
+	lock         sync.Mutex
+	stateFile    string
+	reservations map[string]*Reservation
+}
+
+// New creates a new tracker, which persists its reservations in stateFile,
+// and loads the reservations persisted before.
+func New(stateFile string) (*Tracker, error) {
+	t := &Tracker{
+		stateFile:    stateFile,
+		reservations: make(map[string]*Reservation),
+	}
+
+	data, err := os.ReadFile(stateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read MCS reservations: %w", err)
+	}
+	s := &state{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parse MCS reservations: %w", err)
+	}
+	for _, r := range s.Reservations {
+		if r.PodID == "" || r.Level == "" || LevelOf(r.Label) != r.Level {
+			logrus.Warnf("Dropping invalid MCS reservation of pod %s", r.PodID)
+			continue
+		}
+		t.reservations[r.PodID] = r
+	}
+	return t, nil
+}
+
+// Add tracks the reservation and returns the reservations colliding with it.
+// Reservations without level are ignored.
+func (t *Tracker) Add(r *Reservation) ([]*Reservation, error) {
+	if r.Level == "" {
+		return nil, nil
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	copied := *r
+	if copied.Created.IsZero() {
+		copied.Created = time.Now()
+	}
+	previous, existed := t.reservations[r.PodID]
+	t.reservations[r.PodID] = &copied
+	if err := t.save(); err != nil {
+		if existed {
+			t.reservations[r.PodID] = previous
+		} else {
+			delete(t.reservations, r.PodID)
+		}
+		return nil, err
+	}
+
+	collisions := []*Reservation{}
+	for _, other := range t.sorted() {
+		if Collides(&copied, other) {
+			c := *other
+			collisions = append(collisions, &c)
+		}
+	}
+	return collisions, nil
+}
+
+// Remove stops tracking the reservation of the provided pod and returns it,
+// or nil if the pod has none.
+func (t *Tracker) Remove(podID string) (*Reservation, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	r, ok := t.reservations[podID]
+	if !ok {
+		return nil, nil
+	}
+	delete(t.reservations, podID)
+	if err := t.save(); err != nil {
+		t.reservations[podID] = r
+		return nil, err
+	}
+	return r, nil
+}
+
+// Prune stops tracking the reservations of all pods for which keep returns
+// false and returns them.
+func (t *Tracker) Prune(keep func(podID string) bool) ([]*Reservation, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	pruned := []*Reservation{}
+	for podID, r := range t.reservations {
+		if !keep(podID) {
+			pruned = append(pruned, r)
+			delete(t.reservations, podID)
+		}
+	}
+	if len(pruned) == 0 {
+		return pruned, nil
+	}
+	if err := t.save(); err != nil {
+		for _, r := range pruned {
+			t.reservations[r.PodID] = r
+		}
+		return nil, err
+	}
+	return pruned, nil
+}
+
+// Get returns the reservation of the provided pod, or nil if it has none.
+func (t *Tracker) Get(podID string) *Reservation {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	r, ok := t.reservations[podID]
+	if !ok {
+		return nil
+	}
+	copied := *r
+	return &copied
+}
+
+// InUse returns true if any tracked pod uses the provided level.
+func (t *Tracker) InUse(level string) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, r := range t.reservations {
+		if r.Level == level {
+			return true
+		}
+	}
+	return false
+}
+
+// List returns all reservations ordered by their levels.
+func (t *Tracker) List() []*Reservation {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	res := []*Reservation{}
+	for _, r := range t.sorted() {
+		copied := *r
+		res = append(res, &copied)
+	}
+	return res
+}
+
+// Collisions returns the levels shared by pods which should be isolated from
+// each other, together with the IDs of all pods using them.
+func (t *Tracker) Collisions() []Collision {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	byLevel := make(map[string][]*Reservation)
+	levels := []string{}
+	for _, r := range t.sorted() {
+		if _, ok := byLevel[r.Level]; !ok {
+			levels = append(levels, r.Level)
+		}
+		byLevel[r.Level] = append(byLevel[r.Level], r)
+	}
+
+	res := []Collision{}
+	for _, level := range levels {
+		reservations := byLevel[level]
+		if !anyCollides(reservations) {
+			continue
+		}
+		c := Collision{Level: level}
+		for _, r := range reservations {
+			c.PodIDs = append(c.PodIDs, r.PodID)
+		}
+		res = append(res, c)
+	}
+	return res
+}
+
+// anyCollides returns true if any pair of the reservations collides.
+func anyCollides(reservations []*Reservation) bool {
+	for i := range reservations {
+		for j := i + 1; j < len(reservations); j++ {
+			if Collides(reservations[i], reservations[j]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sorted returns the reservations ordered by their levels and pod IDs.
+func (t *Tracker) sorted() []*Reservation {
+	res := make([]*Reservation, 0, len(t.reservations))
+	for _, r := range t.reservations {
+		res = append(res, r)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Level != res[j].Level {
+			return res[i].Level < res[j].Level
+		}
+		return res[i].PodID < res[j].PodID
+	})
+	return res
+}
+
+// save persists the reservations atomically.
+func (t *Tracker) save() error {
+	data, err := json.Marshal(&state{Reservations: t.sorted()})
+	if err != nil {
+		return fmt.Errorf("encode MCS reservations: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(t.stateFile), 0o700); err != nil {
+		return fmt.Errorf("create MCS reservations directory: %w", err)
+	}
+	if err := ioutils.AtomicWriteFile(t.stateFile, data, 0o600); err != nil {
+		return fmt.Errorf("write MCS reservations: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package mcs_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/cri-o/cri-o/internal/mcs"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("Tracker", func() {
+	var (
+		stateFile string
+		sut       *mcs.Tracker
+	)
+
+	reservation := func(podID, namespace, level string, source mcs.Source) *mcs.Reservation {
+		return &mcs.Reservation{
+			PodID:     podID,
+			Pod:       "pod" + podID,
+			Namespace: namespace,
+			Label:     "system_u:system_r:container_t:" + level,
+			Level:     level,
+			Source:    source,
+		}
+	}
+
+	BeforeEach(func() {
+		stateFile = filepath.Join(t.MustTempDir("mcs"), "state", "reservations.json")
+		var err error
+		sut, err = mcs.New(stateFile)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return the level of a label", func() {
+		// Given
+		// When
+		res := mcs.LevelOf("system_u:system_r:container_t:s0:c1,c2")
+
+		// Then
+		Expect(res).To(Equal("s0:c1,c2"))
+		Expect(mcs.LevelOf("system_u:system_r:container_t")).To(BeEmpty())
+	})
+
+	It("should track reservations without collisions", func() {
+		// Given
+		// When
+		first, err := sut.Add(reservation("1", "default", "s0:c1,c2", mcs.SourceAuto))
+		Expect(err).NotTo(HaveOccurred())
+		second, err := sut.Add(reservation("2", "default", "s0:c3,c4", mcs.SourceAuto))
+		Expect(err).NotTo(HaveOccurred())
+
+		// Then
+		Expect(first).To(BeEmpty())
+		Expect(second).To(BeEmpty())
+		Expect(sut.List()).To(HaveLen(2))
+		Expect(sut.Get("2").Pod).To(Equal("pod2"))
+		Expect(sut.Get("2").Created.IsZero()).To(BeFalse())
+		Expect(sut.Collisions()).To(BeEmpty())
+	})
+
+	It("should ignore reservations without level", func() {
+		// Given
+		// When
+		res, err := sut.Add(reservation("1", "default", "", mcs.SourceAuto))
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(BeEmpty())
+		Expect(sut.Get("1")).To(BeNil())
+	})
+
+	It("should report collisions of automatically chosen levels", func() {
+		// Given
+		_, err := sut.Add(reservation("1", "default", "s0:c1,c2", mcs.SourceAuto))
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		res, err := sut.Add(reservation("2", "default", "s0:c1,c2", mcs.SourceRequested))
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(HaveLen(1))
+		Expect(res[0].PodID).To(Equal("1"))
+		Expect(sut.Collisions()).To(Equal([]mcs.Collision{
+			{Level: "s0:c1,c2", PodIDs: []string{"1", "2"}},
+		}))
+	})
+
+	It("should report collisions across namespaces", func() {
+		// Given
+		_, err := sut.Add(reservation("1", "default", "s0:c1,c2", mcs.SourceFixed))
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		res, err := sut.Add(reservation("2", "kube-system", "s0:c1,c2", mcs.SourceFixed))
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(HaveLen(1))
+		Expect(sut.Collisions()).To(HaveLen(1))
+	})
+
+	It("should not report levels shared within a namespace on purpose", func() {
+		// Given
+		_, err := sut.Add(reservation("1", "kube-system", "s0:c1,c2", mcs.SourceFixed))
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		res, err := sut.Add(reservation("2", "kube-system", "s0:c1,c2", mcs.SourceRequested))
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(BeEmpty())
+		Expect(sut.Collisions()).To(BeEmpty())
+		Expect(sut.InUse("s0:c1,c2")).To(BeTrue())
+	})
+
+	It("should remove reservations", func() {
+		// Given
+		_, err := sut.Add(reservation("1", "default", "s0:c1,c2", mcs.SourceAuto))
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		removed, err := sut.Remove("1")
+		Expect(err).NotTo(HaveOccurred())
+		missing, err := sut.Remove("2")
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed.PodID).To(Equal("1"))
+		Expect(missing).To(BeNil())
+		Expect(sut.InUse("s0:c1,c2")).To(BeFalse())
+	})
+
+	It("should persist reservations across restarts", func() {
+		// Given
+		_, err := sut.Add(reservation("1", "default", "s0:c1,c2", mcs.SourceAuto))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = sut.Add(reservation("2", "kube-system", "s0:c3", mcs.SourceFixed))
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		restarted, err := mcs.New(stateFile)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restarted.List()).To(HaveLen(2))
+		Expect(restarted.Get("2").Source).To(Equal(mcs.SourceFixed))
+		Expect(restarted.Get("1").Label).To(Equal("system_u:system_r:container_t:s0:c1,c2"))
+	})
+
+	It("should drop invalid persisted reservations", func() {
+		// Given
+		Expect(os.MkdirAll(filepath.Dir(stateFile), 0o700)).To(Succeed())
+		Expect(os.WriteFile(stateFile, []byte(`{"reservations":[`+
+			`{"pod_id":"1","label":"u:r:t:s0:c1","level":"s0:c1"},`+
+			`{"pod_id":"2","label":"u:r:t:s0:c2","level":"s0:c3"}]}`,
+		), 0o600)).To(Succeed())
+
+		// When
+		res, err := mcs.New(stateFile)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Get("1")).NotTo(BeNil())
+		Expect(res.Get("2")).To(BeNil())
+	})
+
+	It("should fail with a corrupted state file", func() {
+		// Given
+		Expect(os.MkdirAll(filepath.Dir(stateFile), 0o700)).To(Succeed())
+		Expect(os.WriteFile(stateFile, []byte("{"), 0o600)).To(Succeed())
+
+		// When
+		res, err := mcs.New(stateFile)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(res).To(BeNil())
+	})
+
+	It("should prune reservations of removed pods", func() {
+		// Given
+		_, err := sut.Add(reservation("1", "default", "s0:c1", mcs.SourceAuto))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = sut.Add(reservation("2", "default", "s0:c2", mcs.SourceAuto))
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		pruned, err := sut.Prune(func(podID string) bool { return podID == "2" })
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(HaveLen(1))
+		Expect(pruned[0].PodID).To(Equal("1"))
+		Expect(sut.List()).To(HaveLen(1))
+	})
+})
@@ -18,6 +18,7 @@ import (
 	"github.com/containers/storage/pkg/idtools"
 	"github.com/cri-o/cri-o/internal/config/nsmgr"
 	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/loglimit"
 	ann "github.com/cri-o/cri-o/pkg/annotations"
 	json "github.com/json-iterator/go"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
@@ -79,6 +80,7 @@ type Container struct {
 	restoreArchive     string
 	restoreIsOCIImage  bool
 	resources          *types.ContainerResources
+	logLimits          *loglimit.Limits
 }
 
 func (c *Container) CRIAttributes() *types.ContainerAttributes {
@@ -344,6 +346,26 @@ func (c *Container) SetSeccompProfilePath(pp string) {
 	c.seccompProfilePath = pp
 }
 
+// SetLogLimits sets the log rate limits and quota of the container
+func (c *Container) SetLogLimits(limits *loglimit.Limits) {
+	c.logLimits = limits
+}
+
+// LogLimits returns the log rate limits and quota of the container, or nil
+// if the container log is not limited
+func (c *Container) LogLimits() *loglimit.Limits {
+	return c.logLimits
+}
+
+// logSizeMax returns the maximum size of the container log file, considering
+// the configured maximum size and the log quota of the container
+func (c *Container) logSizeMax(configured int64) int64 {
+	if c.logLimits == nil {
+		return configured
+	}
+	return c.logLimits.LogSizeMax(configured)
+}
+
 // SeccompProfilePath returns the seccomp profile path
 func (c *Container) SeccompProfilePath() string {
 	return c.seccompProfilePath
@@ -2,6 +2,7 @@ package oci
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"syscall"
 	"time"
 
+	conmonconfig "github.com/containers/conmon/runner/config"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/pkg/config"
 	rspec "github.com/opencontainers/runtime-spec/specs-go"
@@ -38,6 +40,10 @@ const (
 	// It is set to the amount of logs allowed in the dockershim implementation:
 	// https://github.com/kubernetes/kubernetes/pull/82514
 	maxExecSyncSize = 16 * 1024 * 1024
+
+	// execPidDir is the directory within the attach socket directory holding
+	// the PID files of exec processes.
+	execPidDir = "exec-pid-dir"
 )
 
 // Runtime is the generic structure holding both global and specific
@@ -46,6 +52,10 @@ type Runtime struct {
 	config              *config.Config
 	runtimeImplMap      map[string]RuntimeImpl
 	runtimeImplMapMutex sync.RWMutex
+
+	// execSyncSlots limits the amount of parallel exec sync requests if
+	// non-nil.
+	execSyncSlots chan struct{}
 }
 
 // RuntimeImpl is an interface used by the caller to interact with the
@@ -72,7 +82,7 @@ type RuntimeImpl interface {
 	AttachContainer(context.Context, *Container, io.Reader, io.WriteCloser, io.WriteCloser,
 		bool, <-chan remotecommand.TerminalSize) error
 	PortForwardContainer(context.Context, *Container, string,
-		int32, io.ReadWriteCloser) error
+		int32, types.Protocol, io.ReadWriteCloser) error
 	ReopenContainerLog(context.Context, *Container) error
 	CheckpointContainer(context.Context, *Container, *rspec.Spec, bool) error
 	RestoreContainer(context.Context, *Container, string, string) error
@@ -80,15 +90,19 @@ type RuntimeImpl interface {
 
 // New creates a new Runtime with options provided
 func New(c *config.Config) (*Runtime, error) {
-	execNotifyDir := filepath.Join(c.ContainerAttachSocketDir, "exec-pid-dir")
+	execNotifyDir := filepath.Join(c.ContainerAttachSocketDir, execPidDir)
 	if err := os.MkdirAll(execNotifyDir, 0o750); err != nil {
 		return nil, fmt.Errorf("create oci runtime pid dir: %w", err)
 	}
 
-	return &Runtime{
+	r := &Runtime{
 		config:         c,
 		runtimeImplMap: make(map[string]RuntimeImpl),
-	}, nil
+	}
+	if c.ExecSyncConcurrencyLimit > 0 {
+		r.execSyncSlots = make(chan struct{}, c.ExecSyncConcurrencyLimit)
+	}
+	return r, nil
 }
 
 // Runtimes returns the map of OCI runtimes.
@@ -257,9 +271,71 @@ func (r *Runtime) ExecSyncContainer(ctx context.Context, c *Container, command [
 		return nil, err
 	}
 
+	if r.execSyncSlots != nil {
+		waitStart := time.Now()
+		release, err := r.acquireExecSyncSlot(ctx, timeout)
+		if errors.Is(err, errExecSyncSlotTimeout) {
+			// Behave like a timed out command, for the same reason as the
+			// runtime implementations do.
+			log.Warnf(ctx, "Exec sync request for container %s timed out: %v", c.ID(), err)
+			return &types.ExecSyncResponse{
+				Stderr:   []byte(conmonconfig.TimedOutMessage),
+				ExitCode: -1,
+			}, nil
+		}
+		if err != nil {
+			return nil, &ExecSyncError{
+				ExitCode: -1,
+				Err:      err,
+			}
+		}
+		defer release()
+		// The time spent waiting for a slot counts against the timeout.
+		timeout = remainingExecSyncTimeout(timeout, time.Since(waitStart))
+	}
+
 	return impl.ExecSyncContainer(ctx, c, command, timeout)
 }
 
+// remainingExecSyncTimeout returns the timeout in seconds which is left after
+// waiting for the provided duration. It is rounded up and at least one second,
+// because a timeout of zero disables the timeout.
+func remainingExecSyncTimeout(timeout int64, waited time.Duration) int64 {
+	if timeout <= 0 {
+		return timeout
+	}
+	remaining := time.Duration(timeout)*time.Second - waited
+	seconds := int64((remaining + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+// errExecSyncSlotTimeout is returned if no exec sync slot got available
+// within the timeout of the request.
+var errExecSyncSlotTimeout = errors.New("no exec sync slot available within timeout")
+
+// acquireExecSyncSlot waits for a free exec sync slot. The returned release
+// function has to be called once the request is done.
+func (r *Runtime) acquireExecSyncSlot(ctx context.Context, timeout int64) (release func(), err error) {
+	var timeoutChan <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(time.Duration(timeout) * time.Second)
+		defer timer.Stop()
+		timeoutChan = timer.C
+	}
+
+	select {
+	case r.execSyncSlots <- struct{}{}:
+		return func() { <-r.execSyncSlots }, nil
+	case <-timeoutChan:
+		return nil, errExecSyncSlotTimeout
+	case <-ctx.Done():
+		return nil, fmt.Errorf("wait for exec sync slot: %w", ctx.Err())
+	}
+}
+
 // UpdateContainer updates container resources
 func (r *Runtime) UpdateContainer(ctx context.Context, c *Container, res *rspec.LinuxResources) error {
 	ctx, span := log.StartSpan(ctx)
@@ -381,7 +457,7 @@ func (r *Runtime) AttachContainer(ctx context.Context, c *Container, inputStream
 }
 
 // PortForwardContainer forwards the specified port provides statistics of a container.
-func (r *Runtime) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, stream io.ReadWriteCloser) error {
+func (r *Runtime) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, protocol types.Protocol, stream io.ReadWriteCloser) error {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
 	impl, err := r.RuntimeImpl(c)
@@ -389,7 +465,7 @@ func (r *Runtime) PortForwardContainer(ctx context.Context, c *Container, netNsP
 		return err
 	}
 
-	return impl.PortForwardContainer(ctx, c, netNsPath, port, stream)
+	return impl.PortForwardContainer(ctx, c, netNsPath, port, protocol, stream)
 }
 
 // ReopenContainerLog reopens the log file of a container.
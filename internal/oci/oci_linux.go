@@ -1,6 +1,9 @@
 package oci
 
 import (
+	"errors"
+	"fmt"
+	"net"
 	"os"
 	"syscall"
 	"time"
@@ -63,6 +66,43 @@ func newPipe() (parent, child *os.File, _ error) {
 	return os.NewFile(uintptr(fds[1]), "parent"), os.NewFile(uintptr(fds[0]), "child"), nil
 }
 
+// dialSCTP connects to the SCTP port on localhost, trying IPv4 before IPv6.
+func dialSCTP(port int32) (net.Conn, error) {
+	var errs []error
+	for _, sa := range []unix.Sockaddr{
+		&unix.SockaddrInet4{Port: int(port), Addr: [4]byte{127, 0, 0, 1}},
+		&unix.SockaddrInet6{Port: int(port), Addr: [16]byte{15: 1}},
+	} {
+		conn, err := dialSCTPAddr(sa)
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+func dialSCTPAddr(sa unix.Sockaddr) (net.Conn, error) {
+	domain := unix.AF_INET
+	if _, ok := sa.(*unix.SockaddrInet6); ok {
+		domain = unix.AF_INET6
+	}
+	fd, err := unix.Socket(domain, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, unix.IPPROTO_SCTP)
+	if err != nil {
+		return nil, fmt.Errorf("create SCTP socket: %w", err)
+	}
+	if err := unix.Connect(fd, sa); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("connect SCTP socket: %w", err)
+	}
+
+	// The file gets duplicated by net.FileConn, so it has to be closed in
+	// any case.
+	f := os.NewFile(uintptr(fd), "sctp")
+	defer f.Close()
+	return net.FileConn(f)
+}
+
 func (r *runtimeOCI) containerStats(ctr *Container, cgroup string) (*types.ContainerStats, error) {
 	stats := &types.ContainerStats{
 		Attributes: ctr.CRIAttributes(),
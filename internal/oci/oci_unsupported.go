@@ -5,6 +5,7 @@ package oci
 
 import (
 	"errors"
+	"net"
 	"os"
 	"syscall"
 )
@@ -20,3 +21,7 @@ func sysProcAttrPlatform() *syscall.SysProcAttr {
 func newPipe() (*os.File, *os.File, error) {
 	return os.Pipe()
 }
+
+func dialSCTP(port int32) (net.Conn, error) {
+	return nil, errors.New("not implemented")
+}
@@ -0,0 +1,102 @@
+package oci
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"golang.org/x/sys/unix"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+const (
+	// datagramHeaderSize is the size of the big endian length prefix which
+	// frames every datagram forwarded over a port forward stream.
+	datagramHeaderSize = 2
+
+	// maxDatagramSize is the maximum datagram payload which can be framed.
+	maxDatagramSize = 1<<(8*datagramHeaderSize) - 1
+)
+
+// dialPortForward connects to the port on localhost using the provided
+// protocol. It has to be called inside the target network namespace.
+func dialPortForward(protocol types.Protocol, port int32) (net.Conn, error) {
+	switch protocol {
+	case types.Protocol_UDP:
+		// Dialing UDP always succeeds, so there is no way to fall back to
+		// the IP family the application is listening on. IPv4 is used,
+		// which matches the applications listening on all addresses, too.
+		return net.Dial("udp4", fmt.Sprintf("localhost:%d", port))
+	case types.Protocol_TCP:
+		// localhost can resolve to both IPv4 and IPv6 addresses in dual-stack systems
+		// but the application can be listening in one of the IP families only.
+		// golang has enabled RFC 6555 Fast Fallback (aka HappyEyeballs) by default in 1.12
+		// It means that if a host resolves to both IPv6 and IPv4, it will try to connect to any
+		// of those addresses and use the working connection.
+		// xref https://github.com/golang/go/commit/efc185029bf770894defe63cec2c72a4c84b2ee9
+		// However, the implementation uses go routines to start both connections in parallel,
+		// and this has limitations when running inside a namespace, so we try to the connections
+		// serially disabling the Fast Fallback support.
+		// xref https://github.com/golang/go/issues/44922
+		var d net.Dialer
+		d.FallbackDelay = -1
+		return d.Dial("tcp", fmt.Sprintf("localhost:%d", port))
+	case types.Protocol_SCTP:
+		return dialSCTP(port)
+	}
+	return nil, fmt.Errorf("unsupported port forward protocol %s", protocol)
+}
+
+// isDatagramProtocol returns true if the protocol preserves message
+// boundaries, which requires framing the messages over the stream.
+func isDatagramProtocol(protocol types.Protocol) bool {
+	return protocol == types.Protocol_UDP || protocol == types.Protocol_SCTP
+}
+
+// copyDatagramsToStream reads the datagrams from conn and writes each of them
+// to the stream, prefixed by its length.
+func copyDatagramsToStream(stream io.Writer, conn io.Reader) error {
+	buf := make([]byte, datagramHeaderSize+maxDatagramSize)
+	for {
+		n, err := conn.Read(buf[datagramHeaderSize:])
+		if errors.Is(err, unix.ECONNREFUSED) {
+			// Nobody listens on the port (yet), which is reported for
+			// datagrams sent previously. Keep forwarding anyway.
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		binary.BigEndian.PutUint16(buf, uint16(n))
+		if _, err := stream.Write(buf[:datagramHeaderSize+n]); err != nil {
+			return err
+		}
+	}
+}
+
+// copyStreamToDatagrams reads the length prefixed datagrams from the stream
+// and writes each of them to conn.
+func copyStreamToDatagrams(conn io.Writer, stream io.Reader) error {
+	header := make([]byte, datagramHeaderSize)
+	buf := make([]byte, maxDatagramSize)
+	for {
+		if _, err := io.ReadFull(stream, header); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("read datagram header: %w", err)
+		}
+		size := binary.BigEndian.Uint16(header)
+		if _, err := io.ReadFull(stream, buf[:size]); err != nil {
+			return fmt.Errorf("read datagram of size %d: %w", size, err)
+		}
+		if _, err := conn.Write(buf[:size]); err != nil && !errors.Is(err, unix.ECONNREFUSED) {
+			return err
+		}
+	}
+}
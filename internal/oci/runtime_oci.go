@@ -2,6 +2,7 @@ package oci
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"syscall"
@@ -122,8 +124,8 @@ func (r *runtimeOCI) CreateContainer(ctx context.Context, c *Container, cgroupPa
 	if r.config.CgroupManager().IsSystemd() {
 		args = append(args, "-s")
 	}
-	if r.config.LogSizeMax >= 0 {
-		args = append(args, "--log-size-max", fmt.Sprintf("%v", r.config.LogSizeMax))
+	if logSizeMax := c.logSizeMax(r.config.LogSizeMax); logSizeMax >= 0 {
+		args = append(args, "--log-size-max", fmt.Sprintf("%v", logSizeMax))
 	}
 	if r.config.LogToJournald {
 		args = append(args, "--log-path", "journald:")
@@ -484,6 +486,12 @@ func (r *runtimeOCI) ExecSyncContainer(ctx context.Context, c *Container, comman
 		return nil, nil
 	}
 
+	// Containers using a terminal require the monitor to allocate it, as
+	// does moving the exec processes into the container cgroup.
+	if r.handler.ExecSyncMode == config.ExecSyncModeDirect && !c.terminal && !r.execInContainerCgroup() {
+		return r.execSyncContainerDirect(ctx, c, command, timeout)
+	}
+
 	pidFile, parentPipe, childPipe, err := prepareExec()
 	if err != nil {
 		return nil, &ExecSyncError{
@@ -736,6 +744,154 @@ func (r *runtimeOCI) ExecSyncContainer(ctx context.Context, c *Container, comman
 	}, nil
 }
 
+// execInContainerCgroup returns true if the exec processes have to be moved
+// into the container cgroup, like configured by monitor_exec_cgroup.
+func (r *runtimeOCI) execInContainerCgroup() bool {
+	return r.handler.MonitorExecCgroup == config.MonitorExecCgroupContainer && r.config.InfraCtrCPUSet != ""
+}
+
+// execSyncWaitDelay is the time to wait for the output of a direct exec sync
+// request after the runtime exited. Processes backgrounded by the command may
+// keep the output open for much longer.
+const execSyncWaitDelay = time.Second
+
+// execSyncContainerDirect execs a command in a container by invoking the
+// runtime directly instead of spawning the monitor. The runtime still gets
+// invoked for every request, but the process specification is cached in the
+// container directory, so that repeated requests, like exec probes, do not
+// have to prepare it again.
+func (r *runtimeOCI) execSyncContainerDirect(ctx context.Context, c *Container, command []string, timeout int64) (*types.ExecSyncResponse, error) {
+	ctx, span := log.StartSpan(ctx)
+	defer span.End()
+
+	processFile, err := cachedProcessExec(c, command)
+	if err != nil {
+		return nil, &ExecSyncError{
+			ExitCode: -1,
+			Err:      fmt.Errorf("prepare exec process: %w", err),
+		}
+	}
+
+	pidFile, err := os.CreateTemp(filepath.Join(r.config.ContainerAttachSocketDir, execPidDir), "exec-sync-")
+	if err != nil {
+		return nil, &ExecSyncError{
+			ExitCode: -1,
+			Err:      err,
+		}
+	}
+	pidFile.Close()
+	defer func() {
+		if err := os.Remove(pidFile.Name()); err != nil && !os.IsNotExist(err) {
+			log.Warnf(ctx, "Could not remove temporary PID file %s", pidFile.Name())
+		}
+	}()
+
+	args := r.defaultRuntimeArgs()
+	args = append(args, "exec", "--process", processFile, "--pid-file", pidFile.Name(), c.ID())
+	cmd := cmdrunner.Command(r.handler.RuntimePath, args...) // nolint: gosec
+	if v, found := os.LookupEnv("XDG_RUNTIME_DIR"); found {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("XDG_RUNTIME_DIR=%s", v))
+	}
+
+	stdoutBuf := &limitedBuffer{limit: maxExecSyncSize}
+	stderrBuf := &limitedBuffer{limit: maxExecSyncSize}
+	cmd.Stdout = stdoutBuf
+	cmd.Stderr = stderrBuf
+	cmd.WaitDelay = execSyncWaitDelay
+
+	if err := cmd.Start(); err != nil {
+		return nil, &ExecSyncError{
+			ExitCode: -1,
+			Err:      err,
+		}
+	}
+
+	waitChan := make(chan error, 1)
+	go func() {
+		waitChan <- cmd.Wait()
+	}()
+
+	var timeoutChan <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(time.Duration(timeout) * time.Second)
+		defer timer.Stop()
+		timeoutChan = timer.C
+	}
+
+	var waitErr error
+	select {
+	case waitErr = <-waitChan:
+	case <-timeoutChan:
+		// Killing the runtime does not terminate the exec'd process, which
+		// is why we have to kill it explicitly, too.
+		if pid, err := readPidFile(pidFile.Name()); err == nil {
+			if err := unix.Kill(pid, unix.SIGKILL); err != nil && !errors.Is(err, unix.ESRCH) {
+				log.Warnf(ctx, "Unable to kill timed out exec process %d: %v", pid, err)
+			}
+		}
+		if err := cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+			log.Warnf(ctx, "Unable to kill timed out runtime exec: %v", err)
+		}
+		<-waitChan
+
+		// Same as the monitor, we return a non-zero exit code instead of an
+		// error, because the kubelet prober would otherwise not restart
+		// the container.
+		return &types.ExecSyncResponse{
+			Stderr:   []byte(conmonconfig.TimedOutMessage),
+			ExitCode: -1,
+		}, nil
+	}
+
+	if errors.Is(waitErr, exec.ErrWaitDelay) {
+		// The command succeeded, but left a process holding its output.
+		log.Debugf(ctx, "Output of exec sync request for container %s is still open after exit", c.ID())
+		waitErr = nil
+	}
+
+	var exitCode int32
+	if waitErr != nil {
+		exitErr, ok := waitErr.(*exec.ExitError)
+		if !ok {
+			return nil, &ExecSyncError{
+				Stdout:   stdoutBuf.Buffer,
+				Stderr:   stderrBuf.Buffer,
+				ExitCode: -1,
+				Err:      waitErr,
+			}
+		}
+		exitCode = int32(exitErr.ExitCode())
+	}
+
+	return &types.ExecSyncResponse{
+		Stdout:   stdoutBuf.Bytes(),
+		Stderr:   stderrBuf.Bytes(),
+		ExitCode: exitCode,
+	}, nil
+}
+
+// limitedBuffer is a bytes.Buffer which discards everything written beyond
+// its limit.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	written := len(p)
+	remaining := b.limit - b.Len()
+	if remaining <= 0 {
+		return written, nil
+	}
+	if remaining < len(p) {
+		p = p[:remaining]
+	}
+	if _, err := b.Buffer.Write(p); err != nil {
+		return 0, err
+	}
+	return written, nil
+}
+
 func TruncateAndReadFile(ctx context.Context, path string, size int64) ([]byte, error) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
@@ -905,6 +1061,10 @@ func (r *runtimeOCI) DeleteContainer(ctx context.Context, c *Container) error {
 		return nil
 	}
 
+	if err := removeCachedProcessExecs(c); err != nil {
+		log.Warnf(ctx, "Unable to remove cached exec processes of container %s: %v", c.ID(), err)
+	}
+
 	_, err := r.runtimeCmd("delete", "--force", c.ID())
 	return err
 }
@@ -1187,11 +1347,11 @@ func (r *runtimeOCI) AttachContainer(ctx context.Context, c *Container, inputStr
 }
 
 // PortForwardContainer forwards the specified port into the provided container.
-func (r *runtimeOCI) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, stream io.ReadWriteCloser) error {
+func (r *runtimeOCI) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, protocol types.Protocol, stream io.ReadWriteCloser) error {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
 	log.Infof(ctx,
-		"Starting port forward for %s in network namespace %s", c.ID(), netNsPath,
+		"Starting %s port forward for %s in network namespace %s", protocol, c.ID(), netNsPath,
 	)
 
 	// Adapted reference implementation:
@@ -1199,21 +1359,9 @@ func (r *runtimeOCI) PortForwardContainer(ctx context.Context, c *Container, net
 	if err := ns.WithNetNSPath(netNsPath, func(_ ns.NetNS) error {
 		defer stream.Close()
 
-		// localhost can resolve to both IPv4 and IPv6 addresses in dual-stack systems
-		// but the application can be listening in one of the IP families only.
-		// golang has enabled RFC 6555 Fast Fallback (aka HappyEyeballs) by default in 1.12
-		// It means that if a host resolves to both IPv6 and IPv4, it will try to connect to any
-		// of those addresses and use the working connection.
-		// xref https://github.com/golang/go/commit/efc185029bf770894defe63cec2c72a4c84b2ee9
-		// However, the implementation uses go routines to start both connections in parallel,
-		// and this has limitations when running inside a namespace, so we try to the connections
-		// serially disabling the Fast Fallback support.
-		// xref https://github.com/golang/go/issues/44922
-		var d net.Dialer
-		d.FallbackDelay = -1
-		conn, err := d.Dial("tcp", fmt.Sprintf("localhost:%d", port))
+		conn, err := dialPortForward(protocol, port)
 		if err != nil {
-			return fmt.Errorf("failed to connect to localhost:%d inside namespace %s: %w", port, c.ID(), err)
+			return fmt.Errorf("failed to connect to localhost:%d/%s inside namespace %s: %w", port, protocol, c.ID(), err)
 		}
 		defer conn.Close()
 
@@ -1221,13 +1369,17 @@ func (r *runtimeOCI) PortForwardContainer(ctx context.Context, c *Container, net
 
 		debug := func(format string, args ...interface{}) {
 			log.Debugf(ctx, fmt.Sprintf(
-				"PortForward (id: %s, port: %d): %s", c.ID(), port, format,
+				"PortForward (id: %s, port: %d/%s): %s", c.ID(), port, protocol, format,
 			), args...)
 		}
 
 		// Copy from the namespace port connection to the client stream
 		go func() {
 			debug("copy data from container to client")
+			if isDatagramProtocol(protocol) {
+				errCh <- copyDatagramsToStream(stream, conn)
+				return
+			}
 			_, err := io.Copy(stream, conn)
 			errCh <- err
 		}()
@@ -1235,6 +1387,10 @@ func (r *runtimeOCI) PortForwardContainer(ctx context.Context, c *Container, net
 		// Copy from the client stream to the namespace port connection
 		go func() {
 			debug("copy data from client to container")
+			if isDatagramProtocol(protocol) {
+				errCh <- copyStreamToDatagrams(conn, stream)
+				return
+			}
 			_, err := io.Copy(conn, stream)
 			errCh <- err
 		}()
@@ -1275,7 +1431,7 @@ func (r *runtimeOCI) PortForwardContainer(ctx context.Context, c *Container, net
 		)
 	}
 
-	log.Infof(ctx, "Finished port forwarding for %q on port %d", c.ID(), port)
+	log.Infof(ctx, "Finished port forwarding for %q on port %d/%s", c.ID(), port, protocol)
 	return nil
 }
 
@@ -1365,6 +1521,20 @@ func prepareProcessExec(c *Container, cmd []string, tty bool) (processFile strin
 		}
 	}()
 
+	processJSON, err := processExecSpec(c, cmd, tty)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(processFile, processJSON, 0o644); err != nil {
+		return "", err
+	}
+	return processFile, nil
+}
+
+// processExecSpec returns the JSON encoded process specification for
+// executing cmd in the container.
+func processExecSpec(c *Container, cmd []string, tty bool) ([]byte, error) {
 	// It's important to make a spec copy here to not overwrite the initial
 	// process spec
 	pspec := *c.Spec().Process
@@ -1375,17 +1545,113 @@ func prepareProcessExec(c *Container, cmd []string, tty bool) (processFile strin
 	if tty {
 		pspec.Terminal = true
 	}
-	processJSON, err := json.Marshal(pspec)
+	return json.Marshal(pspec)
+}
+
+// maxCachedProcessExecs is the maximum number of process specifications
+// cached per container. The least recently used one gets removed once
+// exceeded, because the commands are not necessarily static.
+const maxCachedProcessExecs = 16
+
+// cachedProcessExecPattern matches the cached process specifications within
+// the container directory.
+const cachedProcessExecPattern = "exec-sync-*.json"
+
+// cachedProcessExec returns the path to the process specification for the
+// provided command within the container directory and creates it on first
+// use. The files get removed by removeCachedProcessExecs on container
+// deletion.
+func cachedProcessExec(c *Container, cmd []string) (string, error) {
+	key, err := json.Marshal(cmd)
 	if err != nil {
 		return "", err
 	}
+	processFile := filepath.Join(c.dir, fmt.Sprintf("exec-sync-%x.json", sha256.Sum256(key)))
+	now := time.Now()
+	if err := os.Chtimes(processFile, now, now); err == nil {
+		// The modification time tracks the last use for the eviction.
+		return processFile, nil
+	}
 
-	if err := os.WriteFile(processFile, processJSON, 0o644); err != nil {
+	if err := pruneCachedProcessExecs(c.dir, maxCachedProcessExecs-1); err != nil {
+		return "", fmt.Errorf("prune cached exec processes: %w", err)
+	}
+
+	processJSON, err := processExecSpec(c, cmd, false)
+	if err != nil {
+		return "", err
+	}
+
+	// Write the file atomically, because concurrent requests may use it
+	// right away.
+	f, err := os.CreateTemp(c.dir, ".exec-sync-")
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(processJSON); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := os.Rename(f.Name(), processFile); err != nil {
+		os.Remove(f.Name())
 		return "", err
 	}
 	return processFile, nil
 }
 
+// pruneCachedProcessExecs removes the least recently used process
+// specifications within dir until at most keep of them are left.
+func pruneCachedProcessExecs(dir string, keep int) error {
+	files, err := filepath.Glob(filepath.Join(dir, cachedProcessExecPattern))
+	if err != nil || len(files) <= keep {
+		return err
+	}
+
+	type cachedFile struct {
+		path    string
+		modTime time.Time
+	}
+	cached := make([]cachedFile, 0, len(files))
+	for _, file := range files {
+		fi, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		cached = append(cached, cachedFile{file, fi.ModTime()})
+	}
+	sort.Slice(cached, func(i, j int) bool {
+		return cached[i].modTime.Before(cached[j].modTime)
+	})
+
+	for len(cached) > keep {
+		if err := os.Remove(cached[0].path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		cached = cached[1:]
+	}
+	return nil
+}
+
+// removeCachedProcessExecs removes all process specifications cached for the
+// container.
+func removeCachedProcessExecs(c *Container) error {
+	return pruneCachedProcessExecs(c.dir, 0)
+}
+
+// readPidFile reads the PID written by the runtime to the provided file.
+func readPidFile(path string) (int, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(content)))
+}
+
 // ReadConmonPidFile attempts to read conmon's pid from its pid file
 // This function makes no verification that this file should exist
 // it is up to the caller to verify that this container has a conmon
@@ -2,11 +2,17 @@ package oci_test
 
 import (
 	"context"
+	"encoding/binary"
+	"io"
 	"math/rand"
+	"net"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"time"
 
+	conmonconfig "github.com/containers/conmon/runner/config"
 	"github.com/cri-o/cri-o/internal/oci"
 	libconfig "github.com/cri-o/cri-o/pkg/config"
 	runnerMock "github.com/cri-o/cri-o/test/mocks/cmdrunner"
@@ -14,6 +20,9 @@ import (
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/unix"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
 const (
@@ -178,6 +187,352 @@ var _ = t.Describe("Oci", func() {
 			verifyContainerNotStopped(sut)
 		})
 	})
+	Context("ExecSyncContainer with direct exec sync mode", func() {
+		var (
+			sut    *oci.Container
+			runner *runnerMock.MockCommandRunner
+			cfg    *libconfig.Config
+		)
+		BeforeEach(func() {
+			var err error
+			sut, err = oci.NewContainer("id", "name", "bundlePath", "logPath",
+				map[string]string{}, map[string]string{}, map[string]string{},
+				"image", "imageName", "imageRef", &types.ContainerMetadata{}, "sandbox",
+				false, false, false, "", t.MustTempDir("container"), time.Now(), "")
+			Expect(err).To(BeNil())
+			sut.SetSpec(&specs.Spec{Process: &specs.Process{}})
+
+			runner = runnerMock.NewMockCommandRunner(mockCtrl)
+			cmdrunner.SetMocked(runner)
+
+			cfg, err = libconfig.DefaultConfig()
+			Expect(err).To(BeNil())
+			cfg.ContainerAttachSocketDir = t.MustTempDir("crio")
+			cfg.Runtimes[cfg.DefaultRuntime].ExecSyncMode = libconfig.ExecSyncModeDirect
+		})
+		AfterEach(func() {
+			cmdrunner.ResetPrependedCmd()
+		})
+
+		It("should return the output and exit code", func() {
+			// Given
+			r, err := oci.New(cfg)
+			Expect(err).To(BeNil())
+			runner.EXPECT().Command(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ string, args ...string) interface{} {
+					Expect(args).To(ContainElements("exec", "--process", "id"))
+					return exec.Command("/bin/sh", "-c", "echo out; echo err >&2; exit 3")
+				},
+			)
+
+			// When
+			res, err := r.ExecSyncContainer(context.Background(), sut, []string{"true"}, 0)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(string(res.Stdout)).To(Equal("out\n"))
+			Expect(string(res.Stderr)).To(Equal("err\n"))
+			Expect(res.ExitCode).To(BeEquivalentTo(3))
+		})
+
+		It("should reuse the process specification", func() {
+			// Given
+			r, err := oci.New(cfg)
+			Expect(err).To(BeNil())
+			processFiles := []string{}
+			runner.EXPECT().Command(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ string, args ...string) interface{} {
+					for i, arg := range args {
+						if arg == "--process" {
+							processFiles = append(processFiles, args[i+1])
+						}
+					}
+					return exec.Command("/bin/true")
+				},
+			).Times(2)
+
+			// When
+			_, err = r.ExecSyncContainer(context.Background(), sut, []string{"true"}, 0)
+			Expect(err).To(BeNil())
+			_, err = r.ExecSyncContainer(context.Background(), sut, []string{"true"}, 0)
+			Expect(err).To(BeNil())
+
+			// Then
+			Expect(processFiles).To(HaveLen(2))
+			Expect(processFiles[0]).To(Equal(processFiles[1]))
+			Expect(processFiles[0]).To(BeAnExistingFile())
+		})
+
+		It("should limit the cached process specifications", func() {
+			// Given
+			r, err := oci.New(cfg)
+			Expect(err).To(BeNil())
+			processFiles := []string{}
+			runner.EXPECT().Command(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ string, args ...string) interface{} {
+					for i, arg := range args {
+						if arg == "--process" {
+							processFiles = append(processFiles, args[i+1])
+						}
+					}
+					return exec.Command("/bin/true")
+				},
+			).Times(20)
+
+			// When
+			for i := 0; i < 20; i++ {
+				_, err = r.ExecSyncContainer(context.Background(), sut, []string{"echo", strconv.Itoa(i)}, 0)
+				Expect(err).To(BeNil())
+			}
+
+			// Then
+			cached, err := filepath.Glob(filepath.Join(sut.Dir(), "exec-sync-*.json"))
+			Expect(err).To(BeNil())
+			Expect(cached).To(HaveLen(16))
+			Expect(processFiles[0]).NotTo(BeAnExistingFile())
+			Expect(processFiles[19]).To(BeAnExistingFile())
+		})
+
+		It("should time out", func() {
+			// Given
+			r, err := oci.New(cfg)
+			Expect(err).To(BeNil())
+			runner.EXPECT().Command(gomock.Any(), gomock.Any()).Return(
+				exec.Command("sleep", "100000"),
+			)
+
+			// When
+			res, err := r.ExecSyncContainer(context.Background(), sut, []string{"sleep"}, shortTimeout)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(res.ExitCode).To(BeEquivalentTo(-1))
+			Expect(string(res.Stderr)).To(Equal(conmonconfig.TimedOutMessage))
+		})
+
+		It("should time out waiting for a free slot", func() {
+			// Given
+			cfg.ExecSyncConcurrencyLimit = 1
+			r, err := oci.New(cfg)
+			Expect(err).To(BeNil())
+			started := make(chan struct{})
+			runner.EXPECT().Command(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ string, _ ...string) interface{} {
+					close(started)
+					return exec.Command("sleep", "3")
+				},
+			)
+			go func() {
+				defer GinkgoRecover()
+				_, err := r.ExecSyncContainer(context.Background(), sut, []string{"sleep"}, 0)
+				Expect(err).To(BeNil())
+			}()
+			<-started
+
+			// When
+			res, err := r.ExecSyncContainer(context.Background(), sut, []string{"true"}, shortTimeout)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(res.ExitCode).To(BeEquivalentTo(-1))
+			Expect(string(res.Stderr)).To(Equal(conmonconfig.TimedOutMessage))
+		})
+
+		It("should count the time waiting for a free slot against the timeout", func() {
+			// Given
+			cfg.ExecSyncConcurrencyLimit = 1
+			r, err := oci.New(cfg)
+			Expect(err).To(BeNil())
+			started := make(chan struct{})
+			gomock.InOrder(
+				runner.EXPECT().Command(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ string, _ ...string) interface{} {
+						close(started)
+						return exec.Command("sleep", "2")
+					},
+				),
+				runner.EXPECT().Command(gomock.Any(), gomock.Any()).Return(
+					exec.Command("sleep", "100000"),
+				),
+			)
+			go func() {
+				defer GinkgoRecover()
+				_, err := r.ExecSyncContainer(context.Background(), sut, []string{"sleep"}, 0)
+				Expect(err).To(BeNil())
+			}()
+			<-started
+			start := time.Now()
+
+			// When
+			res, err := r.ExecSyncContainer(context.Background(), sut, []string{"sleep"}, 3)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(res.ExitCode).To(BeEquivalentTo(-1))
+			Expect(string(res.Stderr)).To(Equal(conmonconfig.TimedOutMessage))
+			Expect(time.Since(start)).To(BeNumerically("<", 4500*time.Millisecond))
+		})
+
+		It("should not wait for processes holding the output", func() {
+			// Given
+			r, err := oci.New(cfg)
+			Expect(err).To(BeNil())
+			runner.EXPECT().Command(gomock.Any(), gomock.Any()).Return(
+				exec.Command("/bin/sh", "-c", "sleep 10 & echo out"),
+			)
+			start := time.Now()
+
+			// When
+			res, err := r.ExecSyncContainer(context.Background(), sut, []string{"true"}, mediumTimeout)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(res.ExitCode).To(BeEquivalentTo(0))
+			Expect(string(res.Stdout)).To(Equal("out\n"))
+			Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+		})
+	})
+	Context("PortForwardContainer", func() {
+		const netNsPath = "/proc/self/ns/net"
+		var (
+			sut     *oci.Container
+			runtime oci.RuntimeOCI
+		)
+		BeforeEach(func() {
+			sut = getTestContainer()
+			cfg, err := libconfig.DefaultConfig()
+			Expect(err).To(BeNil())
+			cfg.ContainerAttachSocketDir = t.MustTempDir("crio")
+			r, err := oci.New(cfg)
+			Expect(err).To(BeNil())
+			runtime = oci.NewRuntimeOCI(r, &libconfig.RuntimeHandler{})
+		})
+
+		It("should forward UDP datagrams", func() {
+			// Given
+			conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+			Expect(err).To(BeNil())
+			defer conn.Close()
+			go func() {
+				buf := make([]byte, 1024)
+				for {
+					n, addr, err := conn.ReadFrom(buf)
+					if err != nil {
+						return
+					}
+					conn.WriteTo(buf[:n], addr) // nolint: errcheck
+				}
+			}()
+			port := conn.LocalAddr().(*net.UDPAddr).Port
+			client, stream := net.Pipe()
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- runtime.PortForwardContainer(context.Background(), sut, netNsPath, int32(port), types.Protocol_UDP, stream)
+			}()
+
+			// When
+			writeDatagram(client, []byte("hello"))
+			writeDatagram(client, []byte("world"))
+
+			// Then
+			Expect(readDatagram(client)).To(Equal([]byte("hello")))
+			Expect(readDatagram(client)).To(Equal([]byte("world")))
+			Expect(client.Close()).To(BeNil())
+			Eventually(errCh, mediumTimeout).Should(Receive(BeNil()))
+		})
+
+		It("should forward TCP streams", func() {
+			// Given
+			listener, err := net.Listen("tcp", "localhost:0")
+			Expect(err).To(BeNil())
+			defer listener.Close()
+			go func() {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				io.Copy(conn, conn) // nolint: errcheck
+			}()
+			port := listener.Addr().(*net.TCPAddr).Port
+			client, stream := net.Pipe()
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- runtime.PortForwardContainer(context.Background(), sut, netNsPath, int32(port), types.Protocol_TCP, stream)
+			}()
+
+			// When
+			_, err = client.Write([]byte("hello"))
+			Expect(err).To(BeNil())
+
+			// Then
+			buf := make([]byte, 5)
+			_, err = io.ReadFull(client, buf)
+			Expect(err).To(BeNil())
+			Expect(buf).To(Equal([]byte("hello")))
+			Expect(client.Close()).To(BeNil())
+			Eventually(errCh, mediumTimeout).Should(Receive(BeNil()))
+		})
+
+		It("should forward SCTP messages", func() {
+			// Given
+			fd, err := unix.Socket(unix.AF_INET, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, unix.IPPROTO_SCTP)
+			if err != nil {
+				Skip("SCTP is not supported: " + err.Error())
+			}
+			defer unix.Close(fd)
+			Expect(unix.Bind(fd, &unix.SockaddrInet4{Addr: [4]byte{127, 0, 0, 1}})).To(Succeed())
+			Expect(unix.Listen(fd, 1)).To(Succeed())
+			sa, err := unix.Getsockname(fd)
+			Expect(err).To(BeNil())
+			port := sa.(*unix.SockaddrInet4).Port
+			go func() {
+				conn, _, err := unix.Accept(fd)
+				if err != nil {
+					return
+				}
+				defer unix.Close(conn)
+				buf := make([]byte, 1024)
+				for {
+					n, err := unix.Read(conn, buf)
+					if err != nil || n == 0 {
+						return
+					}
+					unix.Write(conn, buf[:n]) // nolint: errcheck
+				}
+			}()
+			client, stream := net.Pipe()
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- runtime.PortForwardContainer(context.Background(), sut, netNsPath, int32(port), types.Protocol_SCTP, stream)
+			}()
+
+			// When
+			writeDatagram(client, []byte("hello"))
+			Expect(readDatagram(client)).To(Equal([]byte("hello")))
+			writeDatagram(client, []byte("world"))
+
+			// Then
+			Expect(readDatagram(client)).To(Equal([]byte("world")))
+			Expect(client.Close()).To(BeNil())
+			Eventually(errCh, mediumTimeout).Should(Receive())
+		})
+
+		It("should fail if nothing listens on the TCP port", func() {
+			// Given
+			listener, err := net.Listen("tcp", "localhost:0")
+			Expect(err).To(BeNil())
+			port := listener.Addr().(*net.TCPAddr).Port
+			Expect(listener.Close()).To(BeNil())
+			_, stream := net.Pipe()
+
+			// When
+			err = runtime.PortForwardContainer(context.Background(), sut, netNsPath, int32(port), types.Protocol_TCP, stream)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+	})
 	Context("TruncateAndReadFile", func() {
 		tests := []struct {
 			title    string
@@ -218,6 +573,23 @@ var _ = t.Describe("Oci", func() {
 	})
 })
 
+func writeDatagram(w io.Writer, payload []byte) {
+	header := make([]byte, 2)
+	binary.BigEndian.PutUint16(header, uint16(len(payload)))
+	_, err := w.Write(append(header, payload...))
+	Expect(err).To(BeNil())
+}
+
+func readDatagram(r io.Reader) []byte {
+	header := make([]byte, 2)
+	_, err := io.ReadFull(r, header)
+	Expect(err).To(BeNil())
+	payload := make([]byte, binary.BigEndian.Uint16(header))
+	_, err = io.ReadFull(r, payload)
+	Expect(err).To(BeNil())
+	return payload
+}
+
 func containerIgnoreSignalCmdrunnerMock(sleepProcess *exec.Cmd, runner *runnerMock.MockCommandRunner) {
 	gomock.InOrder(
 		runner.EXPECT().Command(gomock.Any(), gomock.Any()).DoAndReturn(
@@ -118,8 +118,8 @@ func (r *runtimePod) CreateContainer(ctx context.Context, c *Container, cgroupPa
 		return nil
 	}
 	var maxSize uint64
-	if r.oci.config.LogSizeMax >= 0 {
-		maxSize = uint64(r.oci.config.LogSizeMax)
+	if logSizeMax := c.logSizeMax(r.oci.config.LogSizeMax); logSizeMax >= 0 {
+		maxSize = uint64(logSizeMax)
 	}
 	createConfig := &conmonClient.CreateContainerConfig{
 		ID:           c.ID(),
@@ -286,8 +286,8 @@ func (r *runtimePod) AttachContainer(ctx context.Context, c *Container, inputStr
 	})
 }
 
-func (r *runtimePod) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, stream io.ReadWriteCloser) error {
-	return r.oci.PortForwardContainer(ctx, c, netNsPath, port, stream)
+func (r *runtimePod) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, protocol types.Protocol, stream io.ReadWriteCloser) error {
+	return r.oci.PortForwardContainer(ctx, c, netNsPath, port, protocol, stream)
 }
 
 func (r *runtimePod) ReopenContainerLog(ctx context.Context, c *Container) error {
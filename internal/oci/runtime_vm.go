@@ -36,6 +36,8 @@ import (
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
 	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	anypb "google.golang.org/protobuf/types/known/anypb"
 	"k8s.io/client-go/tools/remotecommand"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
@@ -981,10 +983,13 @@ func (r *runtimeVM) AttachContainer(ctx context.Context, c *Container, inputStre
 }
 
 // PortForwardContainer forwards the specified port provides statistics of a container.
-func (r *runtimeVM) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, stream io.ReadWriteCloser) error {
+func (r *runtimeVM) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, protocol types.Protocol, stream io.ReadWriteCloser) error {
 	log.Debugf(ctx, "RuntimeVM.PortForwardContainer() start")
 	defer log.Debugf(ctx, "RuntimeVM.PortForwardContainer() end")
 
+	if protocol != types.Protocol_TCP {
+		return status.Errorf(codes.Unimplemented, "port forwarding via %s is not supported by VM runtimes", protocol)
+	}
 	return nil
 }
 
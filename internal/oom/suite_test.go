@@ -0,0 +1,26 @@
+package oom_test
+
+import (
+	"testing"
+
+	. "github.com/cri-o/cri-o/test/framework"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// TestOOM runs the created specs
+func TestOOM(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunFrameworkSpecs(t, "OOM")
+}
+
+var t *TestFramework
+
+var _ = BeforeSuite(func() {
+	t = NewTestFramework(NilFunc, NilFunc)
+	t.Setup()
+})
+
+var _ = AfterSuite(func() {
+	t.Teardown()
+})
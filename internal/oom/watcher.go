@@ -0,0 +1,434 @@
+package oom
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// cgroupRoot is the mount point of the cgroup v2 unified hierarchy.
+	cgroupRoot = "/sys/fs/cgroup"
+
+	// kmsgPath is the path to the kernel log buffer.
+	kmsgPath = "/dev/kmsg"
+
+	// memoryEventsFile is the cgroup v2 file which reports the memory events.
+	memoryEventsFile = "memory.events"
+
+	// oomKillKey is the key in memory.events counting the processes killed
+	// by the OOM killer.
+	oomKillKey = "oom_kill"
+
+	// oomKillRecordPrefix is the prefix of the kernel log record written for
+	// every OOM kill.
+	oomKillRecordPrefix = "oom-kill:"
+
+	eventsChanSize = 100
+
+	// maxKmsgRecords is the maximum number of kernel log records read per
+	// scan, further records are read by the next scan.
+	maxKmsgRecords = 4096
+
+	// maxKilledCgroups is the maximum number of cgroups whose killed process
+	// is kept until an event of the cgroup gets reported.
+	maxKilledCgroups = 1024
+)
+
+// Event describes new OOM kills observed in a watched cgroup.
+type Event struct {
+	// ID is the identifier the cgroup has been registered with.
+	ID string
+
+	// CgroupPath is the path of the cgroup relative to the cgroup root.
+	CgroupPath string
+
+	// Count is the amount of new OOM kills since the last event.
+	Count uint64
+
+	// Process is the latest killed process, if it could be determined.
+	Process *Process
+}
+
+// Process is a process killed by the OOM killer.
+type Process struct {
+	// PID is the process ID in the host PID namespace.
+	PID int
+
+	// Name is the command name of the process.
+	Name string
+}
+
+// String returns a human readable representation of the process.
+func (p *Process) String() string {
+	if p == nil {
+		return "unknown process"
+	}
+	return fmt.Sprintf("%s (PID %d)", p.Name, p.PID)
+}
+
+// Watcher watches the cgroup v2 memory.events files of registered cgroups
+// via inotify and reports OOM kills as soon as the kernel records them,
+// independently of the container runtime or monitor.
+type Watcher struct {
+	watcher    *fsnotify.Watcher
+	cgroupRoot string
+	events     chan *Event
+	kmsg       *kmsgScanner
+
+	lock    sync.Mutex
+	watches map[string]*watch // indexed by the memory.events path
+	paths   map[string]string // memory.events path indexed by ID
+}
+
+type watch struct {
+	id         string
+	cgroupPath string
+	oomKills   uint64
+}
+
+// New creates a new OOM watcher for the host cgroup v2 hierarchy.
+func New() (*Watcher, error) {
+	return NewForPaths(cgroupRoot, kmsgPath)
+}
+
+// NewForPaths creates a new OOM watcher for the provided cgroup v2 root and
+// kernel log path.
+func NewForPaths(root, kmsg string) (*Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("create OOM watcher: %w", err)
+	}
+	return &Watcher{
+		watcher:    watcher,
+		cgroupRoot: root,
+		events:     make(chan *Event, eventsChanSize),
+		kmsg:       newKmsgScanner(kmsg),
+		watches:    make(map[string]*watch),
+		paths:      make(map[string]string),
+	}, nil
+}
+
+// Events returns the channel on which new OOM events are delivered.
+func (w *Watcher) Events() <-chan *Event {
+	return w.events
+}
+
+// Add starts watching the cgroup at cgroupPath, relative to the cgroup root,
+// and reports its OOM kills under the provided ID.
+func (w *Watcher) Add(id, cgroupPath string) error {
+	eventsPath := filepath.Join(w.cgroupRoot, cgroupPath, memoryEventsFile)
+	oomKills, err := readOOMKills(eventsPath)
+	if err != nil {
+		return err
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if err := w.watcher.Add(eventsPath); err != nil {
+		return fmt.Errorf("watch %s: %w", eventsPath, err)
+	}
+	w.watches[eventsPath] = &watch{
+		id:         id,
+		cgroupPath: cgroupPath,
+		oomKills:   oomKills,
+	}
+	w.paths[id] = eventsPath
+	return nil
+}
+
+// Remove stops watching the cgroup registered for the provided ID.
+func (w *Watcher) Remove(id string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	eventsPath, ok := w.paths[id]
+	if !ok {
+		return
+	}
+	delete(w.paths, id)
+	delete(w.watches, eventsPath)
+
+	// The watch is removed by the kernel together with the cgroup, so
+	// failures are expected here.
+	if err := w.watcher.Remove(eventsPath); err != nil {
+		logrus.Debugf("Unable to remove OOM watch for %s: %v", id, err)
+	}
+}
+
+// Start processes the inotify events in the background until done is closed.
+func (w *Watcher) Start(done <-chan struct{}) {
+	go func() {
+		defer w.watcher.Close()
+		defer w.kmsg.close()
+		for {
+			select {
+			case event, ok := <-w.watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op&fsnotify.Write != fsnotify.Write {
+					continue
+				}
+				if oomEvent := w.handle(event.Name); oomEvent != nil {
+					// The kernel log is scanned without holding the lock,
+					// which would block adding and removing cgroups.
+					oomEvent.Process = w.kmsg.killedProcess(oomEvent.CgroupPath)
+					select {
+					case w.events <- oomEvent:
+					case <-done:
+						return
+					}
+				}
+			case err, ok := <-w.watcher.Errors:
+				if !ok {
+					return
+				}
+				logrus.Errorf("OOM watcher error: %v", err)
+			case <-done:
+				logrus.Debug("Closing OOM watcher")
+				return
+			}
+		}
+	}()
+}
+
+// handle re-reads the provided memory.events file and returns an event if
+// the OOM kill counter increased.
+func (w *Watcher) handle(eventsPath string) *Event {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	wt, ok := w.watches[eventsPath]
+	if !ok {
+		return nil
+	}
+
+	oomKills, err := readOOMKills(eventsPath)
+	if err != nil {
+		logrus.Debugf("Unable to read OOM kills for %s: %v", wt.id, err)
+		return nil
+	}
+	if oomKills <= wt.oomKills {
+		return nil
+	}
+
+	event := &Event{
+		ID:         wt.id,
+		CgroupPath: wt.cgroupPath,
+		Count:      oomKills - wt.oomKills,
+	}
+	wt.oomKills = oomKills
+	return event
+}
+
+// readOOMKills returns the oom_kill counter of the provided memory.events file.
+func readOOMKills(eventsPath string) (uint64, error) {
+	content, err := os.ReadFile(eventsPath)
+	if err != nil {
+		return 0, fmt.Errorf("read memory events: %w", err)
+	}
+	return ParseOOMKills(string(content))
+}
+
+// ParseOOMKills parses the oom_kill counter from the content of a cgroup v2
+// memory.events file.
+func ParseOOMKills(content string) (uint64, error) {
+	for _, line := range strings.Split(content, "\n") {
+		key, value, found := strings.Cut(line, " ")
+		if !found || key != oomKillKey {
+			continue
+		}
+		count, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("parse %s value %q: %w", oomKillKey, value, err)
+		}
+		return count, nil
+	}
+	return 0, nil
+}
+
+// kmsgScanner reads the OOM kill records of the kernel log incrementally,
+// starting at the end of the log at its creation, and keeps the latest killed
+// process per cgroup.
+type kmsgScanner struct {
+	lock    sync.Mutex
+	file    *os.File
+	records uint64
+	killed  map[string]*killedProcess // indexed by the cleaned cgroup path
+}
+
+// killedProcess is a killed process together with the sequence number of its
+// kernel log record.
+type killedProcess struct {
+	process *Process
+	record  uint64
+}
+
+// newKmsgScanner opens the kernel log at the provided path. Killed processes
+// cannot be determined if the kernel log is not accessible.
+func newKmsgScanner(path string) *kmsgScanner {
+	s := &kmsgScanner{killed: make(map[string]*killedProcess)}
+	file, err := os.OpenFile(path, os.O_RDONLY|unix.O_NONBLOCK, 0)
+	if err != nil {
+		logrus.Debugf("Unable to open kernel log, OOM killed processes will be unknown: %v", err)
+		return s
+	}
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		logrus.Debugf("Unable to seek to the end of the kernel log: %v", err)
+	}
+	s.file = file
+	return s
+}
+
+// killedProcess reads the kernel log records written since the last call and
+// returns the latest process killed in the provided cgroup or any of its
+// sub-cgroups, or nil if it is unknown.
+func (s *kmsgScanner) killedProcess(cgroupPath string) *Process {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.file == nil {
+		return nil
+	}
+
+	scanner := bufio.NewScanner(&kmsgReader{s.file})
+	for records := 0; records < maxKmsgRecords && scanner.Scan(); records++ {
+		cgroup, process := parseOOMKillRecord(scanner.Text())
+		if process == nil {
+			continue
+		}
+		if len(s.killed) >= maxKilledCgroups {
+			s.killed = make(map[string]*killedProcess)
+		}
+		s.records++
+		s.killed[cgroup] = &killedProcess{process: process, record: s.records}
+	}
+	if err := scanner.Err(); err != nil {
+		logrus.Debugf("Unable to read kernel log: %v", err)
+	}
+
+	var latest *killedProcess
+	for cgroup, killed := range s.killed {
+		if !isCgroupOrDescendant(cgroup, cgroupPath) {
+			continue
+		}
+		if latest == nil || killed.record > latest.record {
+			latest = killed
+		}
+		delete(s.killed, cgroup)
+	}
+	if latest == nil {
+		return nil
+	}
+	return latest.process
+}
+
+func (s *kmsgScanner) close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.file == nil {
+		return
+	}
+	if err := s.file.Close(); err != nil {
+		logrus.Debugf("Unable to close kernel log: %v", err)
+	}
+	s.file = nil
+}
+
+// ParseOOMKillRecord parses a kernel log record and returns the killed
+// process if the record reports an OOM kill in the provided cgroup path or
+// any of its sub-cgroups. Records look like:
+// 6,1234,5678,-;oom-kill:constraint=CONSTRAINT_MEMCG,...,oom_memcg=/a,task_memcg=/a/b,task=name,pid=1,uid=0
+func ParseOOMKillRecord(record, cgroupPath string) *Process {
+	cgroup, process := parseOOMKillRecord(record)
+	if process == nil || !isCgroupOrDescendant(cgroup, cgroupPath) {
+		return nil
+	}
+	return process
+}
+
+// isCgroupOrDescendant returns true if the cleaned cgroup path is the
+// provided parent cgroup path or one of its sub-cgroups. The task cgroup of a
+// kill record is the leaf cgroup of the process, which may be nested below
+// the registered cgroup, for example if the container manages cgroups
+// itself.
+func isCgroupOrDescendant(cgroup, parent string) bool {
+	parent = filepath.Clean(parent)
+	if cgroup == parent {
+		return true
+	}
+	if parent == "/" {
+		return strings.HasPrefix(cgroup, "/")
+	}
+	return strings.HasPrefix(cgroup, parent+"/")
+}
+
+// parseOOMKillRecord parses a kernel log record and returns the cleaned
+// cgroup path and the killed process if the record reports an OOM kill.
+func parseOOMKillRecord(record string) (cgroup string, process *Process) {
+	_, msg, found := strings.Cut(record, ";")
+	if !found {
+		msg = record
+	}
+	msg, found = strings.CutPrefix(strings.TrimSpace(msg), oomKillRecordPrefix)
+	if !found {
+		return "", nil
+	}
+
+	var killed Process
+	for _, field := range strings.Split(msg, ",") {
+		key, value, found := strings.Cut(field, "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "task_memcg":
+			cgroup = filepath.Clean(value)
+		case "task":
+			killed.Name = value
+		case "pid":
+			pid, err := strconv.Atoi(value)
+			if err != nil {
+				return "", nil
+			}
+			killed.PID = pid
+		}
+	}
+	if cgroup == "" {
+		return "", nil
+	}
+	return cgroup, &killed
+}
+
+// kmsgReader wraps the kernel log and converts its non-blocking read errors
+// into the semantics expected by a regular reader.
+type kmsgReader struct {
+	io.Reader
+}
+
+func (r *kmsgReader) Read(p []byte) (int, error) {
+	for {
+		n, err := r.Reader.Read(p)
+		switch {
+		case errors.Is(err, unix.EPIPE):
+			// The record got overwritten in the ring buffer, continue with
+			// the next available one.
+			continue
+		case errors.Is(err, unix.EAGAIN):
+			// No more records available.
+			return n, io.EOF
+		}
+		return n, err
+	}
+}
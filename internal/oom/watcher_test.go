@@ -0,0 +1,228 @@
+package oom_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/oom"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	cgroupPath = "/kubepods.slice/crio-123.scope"
+	kmsgRecord = "3,1234,5678,-;oom-kill:constraint=CONSTRAINT_MEMCG,nodemask=(null),cpuset=/,mems_allowed=0," +
+		"oom_memcg=/kubepods.slice,task_memcg=" + cgroupPath + ",task=stress,pid=42,uid=0\n"
+)
+
+func memoryEvents(oomKills int) string {
+	return fmt.Sprintf("low 0\nhigh 0\nmax 3\noom 1\noom_kill %d\noom_group_kill 0\n", oomKills)
+}
+
+// The actual test suite
+var _ = t.Describe("OOM", func() {
+	t.Describe("ParseOOMKills", func() {
+		It("should parse the oom_kill counter", func() {
+			// Given
+			// When
+			count, err := oom.ParseOOMKills(memoryEvents(2))
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(count).To(BeEquivalentTo(2))
+		})
+
+		It("should return zero if the counter is missing", func() {
+			// Given
+			// When
+			count, err := oom.ParseOOMKills("low 0\nhigh 0\n")
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(count).To(BeZero())
+		})
+
+		It("should fail on invalid counter", func() {
+			// Given
+			// When
+			_, err := oom.ParseOOMKills("oom_kill wrong\n")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	t.Describe("ParseOOMKillRecord", func() {
+		It("should parse the killed process for the cgroup", func() {
+			// Given
+			// When
+			process := oom.ParseOOMKillRecord(kmsgRecord, cgroupPath)
+
+			// Then
+			Expect(process).NotTo(BeNil())
+			Expect(process.Name).To(Equal("stress"))
+			Expect(process.PID).To(Equal(42))
+		})
+
+		It("should parse the killed process of a sub-cgroup", func() {
+			// Given
+			// When
+			process := oom.ParseOOMKillRecord(
+				strings.Replace(kmsgRecord, cgroupPath, cgroupPath+"/worker", 1), cgroupPath)
+
+			// Then
+			Expect(process).NotTo(BeNil())
+			Expect(process.PID).To(Equal(42))
+		})
+
+		It("should ignore records of cgroups sharing a prefix", func() {
+			// Given
+			// When
+			process := oom.ParseOOMKillRecord(kmsgRecord, "/kubepods.slice/crio-12")
+
+			// Then
+			Expect(process).To(BeNil())
+		})
+
+		It("should ignore records of other cgroups", func() {
+			// Given
+			// When
+			process := oom.ParseOOMKillRecord(kmsgRecord, "/kubepods.slice/crio-456.scope")
+
+			// Then
+			Expect(process).To(BeNil())
+		})
+
+		It("should ignore other records", func() {
+			// Given
+			// When
+			process := oom.ParseOOMKillRecord("6,1,2,-;Out of memory", cgroupPath)
+
+			// Then
+			Expect(process).To(BeNil())
+		})
+	})
+
+	t.Describe("Watcher", func() {
+		var (
+			sut        *oom.Watcher
+			eventsPath string
+			kmsg       string
+			done       chan struct{}
+		)
+
+		BeforeEach(func() {
+			root := t.MustTempDir("cgroup")
+			Expect(os.MkdirAll(filepath.Join(root, cgroupPath), 0o755)).To(BeNil())
+			eventsPath = filepath.Join(root, cgroupPath, "memory.events")
+			Expect(os.WriteFile(eventsPath, []byte(memoryEvents(1)), 0o644)).To(BeNil())
+
+			// Records written before the watcher got created are not
+			// scanned.
+			kmsg = filepath.Join(t.MustTempDir("kmsg"), "kmsg")
+			Expect(os.WriteFile(kmsg, []byte(strings.Replace(kmsgRecord, "pid=42", "pid=1", 1)+"\n"), 0o644)).To(BeNil())
+
+			var err error
+			sut, err = oom.NewForPaths(root, kmsg)
+			Expect(err).To(BeNil())
+
+			done = make(chan struct{})
+			sut.Start(done)
+		})
+
+		AfterEach(func() {
+			close(done)
+		})
+
+		It("should report new OOM kills", func() {
+			// Given
+			Expect(sut.Add("123", cgroupPath)).To(BeNil())
+			f, err := os.OpenFile(kmsg, os.O_APPEND|os.O_WRONLY, 0)
+			Expect(err).To(BeNil())
+			_, err = f.WriteString(kmsgRecord + "\n")
+			Expect(err).To(BeNil())
+			Expect(f.Close()).To(BeNil())
+
+			// When
+			Expect(os.WriteFile(eventsPath, []byte(memoryEvents(3)), 0o644)).To(BeNil())
+
+			// Then
+			var event *oom.Event
+			Eventually(sut.Events(), 5*time.Second).Should(Receive(&event))
+			Expect(event.ID).To(Equal("123"))
+			Expect(event.CgroupPath).To(Equal(cgroupPath))
+			Expect(event.Count).To(BeEquivalentTo(2))
+			Expect(event.Process).NotTo(BeNil())
+			Expect(event.Process.PID).To(Equal(42))
+		})
+
+		It("should report OOM kills in sub-cgroups", func() {
+			// Given
+			Expect(sut.Add("123", cgroupPath)).To(BeNil())
+			f, err := os.OpenFile(kmsg, os.O_APPEND|os.O_WRONLY, 0)
+			Expect(err).To(BeNil())
+			_, err = f.WriteString(strings.Replace(kmsgRecord, cgroupPath, cgroupPath+"/worker", 1) + "\n")
+			Expect(err).To(BeNil())
+			Expect(f.Close()).To(BeNil())
+
+			// When
+			Expect(os.WriteFile(eventsPath, []byte(memoryEvents(2)), 0o644)).To(BeNil())
+
+			// Then
+			var event *oom.Event
+			Eventually(sut.Events(), 5*time.Second).Should(Receive(&event))
+			Expect(event.ID).To(Equal("123"))
+			Expect(event.Process).NotTo(BeNil())
+			Expect(event.Process.PID).To(Equal(42))
+		})
+
+		It("should report OOM kills of unknown processes", func() {
+			// Given
+			Expect(sut.Add("123", cgroupPath)).To(BeNil())
+
+			// When
+			Expect(os.WriteFile(eventsPath, []byte(memoryEvents(2)), 0o644)).To(BeNil())
+
+			// Then
+			var event *oom.Event
+			Eventually(sut.Events(), 5*time.Second).Should(Receive(&event))
+			Expect(event.Count).To(BeEquivalentTo(1))
+			Expect(event.Process).To(BeNil())
+		})
+
+		It("should not report unchanged OOM kills", func() {
+			// Given
+			Expect(sut.Add("123", cgroupPath)).To(BeNil())
+
+			// When
+			Expect(os.WriteFile(eventsPath, []byte(memoryEvents(1)), 0o644)).To(BeNil())
+
+			// Then
+			Consistently(sut.Events(), time.Second).ShouldNot(Receive())
+		})
+
+		It("should not report removed cgroups", func() {
+			// Given
+			Expect(sut.Add("123", cgroupPath)).To(BeNil())
+			sut.Remove("123")
+
+			// When
+			Expect(os.WriteFile(eventsPath, []byte(memoryEvents(3)), 0o644)).To(BeNil())
+
+			// Then
+			Consistently(sut.Events(), time.Second).ShouldNot(Receive())
+		})
+
+		It("should fail to add a cgroup without memory events", func() {
+			// Given
+			// When
+			err := sut.Add("456", "/not-existing")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
@@ -0,0 +1,459 @@
+package seccompimage
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"sync"
+
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/image"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/types"
+	cstorage "github.com/containers/storage"
+	"github.com/containers/storage/pkg/archive"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/storage"
+	digest "github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// ProfileFile is the path of the seccomp profile within the image.
+	ProfileFile = "/seccomp.json"
+
+	// maxProfileSize is the maximum size of a seccomp profile read from an
+	// image.
+	maxProfileSize = 1024 * 1024
+)
+
+// Puller pulls seccomp profiles distributed as container images or OCI
+// artifacts and caches their content per manifest digest.
+type Puller struct {
+	lock     sync.Mutex
+	profiles map[cacheKey][]byte
+	// resolved contains the cache keys of the references per sandbox ID.
+	resolved map[string]map[string]cacheKey
+}
+
+// cacheKey identifies a cached profile by the manifest digest of its image
+// and the signature policy and authentication it has been retrieved with,
+// so that it is only reused where the same policy applies.
+type cacheKey struct {
+	policyPath   string
+	authFilePath string
+	digest       digest.Digest
+}
+
+// New creates a new seccomp profile image puller.
+func New() *Puller {
+	return &Puller{
+		profiles: make(map[cacheKey][]byte),
+		resolved: make(map[string]map[string]cacheKey),
+	}
+}
+
+// Pull returns the seccomp profile of the provided image reference for a
+// container of the provided sandbox.
+//
+// Profiles are cached per manifest digest, signature policy and
+// authentication of the system context, and are kept as long as a sandbox
+// uses them. References are resolved once per sandbox, which means that later
+// containers of the sandbox use the cached profile without contacting the
+// registry. Images referenced by digest are pulled like tagged ones, so that
+// the signature policy gets enforced, unless their profile is cached for the
+// policy already. If the registry cannot be reached, then the locally
+// available image is used without caching its profile. OCI artifacts are not
+// stored locally, their profile is read from the layer titled like
+// ProfileFile or their only layer. The provided system context is used for
+// pulling, which means that it has to contain the signature policy and
+// authentication to be applied.
+func (p *Puller) Pull(
+	ctx context.Context,
+	imageServer storage.ImageServer,
+	systemContext *types.SystemContext,
+	sandboxID, imageRef string,
+) ([]byte, error) {
+	ctx, span := log.StartSpan(ctx)
+	defer span.End()
+
+	named, err := reference.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return nil, fmt.Errorf("parse seccomp profile image reference %q: %w", imageRef, err)
+	}
+	key := cacheKey{
+		policyPath:   systemContext.SignaturePolicyPath,
+		authFilePath: systemContext.AuthFilePath,
+	}
+	if canonical, ok := named.(reference.Canonical); ok {
+		key.digest = canonical.Digest()
+	}
+
+	if profile, ok := p.cached(sandboxID, imageRef, key); ok {
+		log.Debugf(ctx, "Using cached seccomp profile of image %s", imageRef)
+		return profile, nil
+	}
+
+	names, err := imageServer.ResolveNames(systemContext, imageRef)
+	if err != nil {
+		return nil, fmt.Errorf("resolve seccomp profile image %s: %w", imageRef, err)
+	}
+
+	profile, manifestDigest, local, err := p.fetch(ctx, imageServer, systemContext, names, key)
+	if err != nil {
+		return nil, fmt.Errorf("get seccomp profile from image %s: %w", imageRef, err)
+	}
+	log.Infof(ctx, "Loaded seccomp profile from image %s (%s)", imageRef, manifestDigest)
+	if local {
+		return profile, nil
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if key.digest == "" {
+		key.digest = manifestDigest
+	}
+	if p.resolved[sandboxID] == nil {
+		p.resolved[sandboxID] = make(map[string]cacheKey)
+	}
+	p.resolved[sandboxID][imageRef] = key
+	p.profiles[key] = profile
+	return profile, nil
+}
+
+// Forget drops the references resolved for the provided sandbox, as well as
+// the cached profiles which are not used by any other sandbox.
+func (p *Puller) Forget(sandboxID string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.resolved, sandboxID)
+
+	used := make(map[cacheKey]bool)
+	for _, refs := range p.resolved {
+		for _, key := range refs {
+			used[key] = true
+		}
+	}
+	for key := range p.profiles {
+		if !used[key] {
+			delete(p.profiles, key)
+		}
+	}
+}
+
+// cached returns the cached profile of the provided reference, if it has been
+// resolved for the sandbox before or is pinned to a cached digest.
+func (p *Puller) cached(sandboxID, imageRef string, key cacheKey) ([]byte, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if resolved, ok := p.resolved[sandboxID][imageRef]; ok {
+		key = resolved
+	}
+	if key.digest == "" {
+		return nil, false
+	}
+	profile, ok := p.profiles[key]
+	return profile, ok
+}
+
+// cachedDigest returns the cached profile of the provided key.
+func (p *Puller) cachedDigest(key cacheKey) ([]byte, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	profile, ok := p.profiles[key]
+	return profile, ok
+}
+
+// fetch returns the profile and the manifest digest of the first available
+// name. If none of the names can be retrieved because the registry is not
+// reachable, then the locally available image is used, which is indicated by
+// the returned local flag.
+func (p *Puller) fetch(
+	ctx context.Context,
+	imageServer storage.ImageServer,
+	systemContext *types.SystemContext,
+	names []string,
+	key cacheKey,
+) ([]byte, digest.Digest, bool, error) {
+	var errs []error
+	unreachable := true
+	for _, name := range names {
+		profile, manifestDigest, imageID, err := p.fetchName(ctx, imageServer, systemContext, name, key)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			unreachable = unreachable && isTransportError(err)
+			continue
+		}
+		if imageID != "" {
+			profile, _, err = readImage(imageServer, imageID, key.digest)
+		}
+		return profile, manifestDigest, false, err
+	}
+	if len(errs) == 0 {
+		return nil, "", false, errors.New("no image name to pull")
+	}
+	err := errors.Join(errs...)
+	if !unreachable {
+		return nil, "", false, err
+	}
+
+	imageID := localImageID(imageServer, systemContext, names)
+	if imageID == "" {
+		return nil, "", false, err
+	}
+	log.Warnf(ctx, "Unable to reach registry of seccomp profile image, using local image %s: %v", imageID, err)
+	profile, manifestDigest, err := readImage(imageServer, imageID, key.digest)
+	return profile, manifestDigest, true, err
+}
+
+// isTransportError returns true if the error has been caused by the
+// connection to the registry, for example if it is not reachable or timed
+// out. Errors returned by the registry or by the signature policy are no
+// transport errors.
+func isTransportError(err error) bool {
+	var policyErr signature.PolicyRequirementError
+	if errors.As(err, &policyErr) {
+		return false
+	}
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if errors.As(err, &opErr) || errors.As(err, &dnsErr) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// fetchName returns the profile and the manifest digest of the provided
+// name. The manifest gets always fetched, while the content is only
+// retrieved if the profile of the manifest digest is not cached yet.
+// Container images get pulled into the local storage and their image ID is
+// returned instead of the profile.
+func (p *Puller) fetchName(
+	ctx context.Context,
+	imageServer storage.ImageServer,
+	systemContext *types.SystemContext,
+	name string,
+	key cacheKey,
+) (profile []byte, manifestDigest digest.Digest, imageID string, err error) {
+	log.Debugf(ctx, "Fetching manifest of seccomp profile image %s", name)
+	img, err := imageServer.PrepareImage(systemContext, name)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("get manifest: %w", err)
+	}
+	defer img.Close()
+	manifestBlob, _, err := img.Manifest(ctx)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("get manifest: %w", err)
+	}
+	manifestDigest, err = manifest.Digest(manifestBlob)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("digest manifest: %w", err)
+	}
+	key.digest = manifestDigest
+	if profile, ok := p.cachedDigest(key); ok {
+		return profile, manifestDigest, "", nil
+	}
+
+	if isArtifact(img) {
+		profile, err := readArtifact(ctx, systemContext, img)
+		return profile, manifestDigest, "", err
+	}
+
+	if _, err := imageServer.PullImage(systemContext, name, &storage.ImageCopyOptions{
+		SourceCtx: systemContext,
+	}); err != nil {
+		return nil, "", "", err
+	}
+	status, err := imageServer.ImageStatus(systemContext, name)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("get status of pulled image: %w", err)
+	}
+	return nil, manifestDigest, status.ID, nil
+}
+
+// readImage reads the profile from the image with the provided ID in the
+// local storage, which has to match the pinned digest if set. It returns the
+// profile and the manifest digest of the image.
+func readImage(imageServer storage.ImageServer, imageID string, pinned digest.Digest) ([]byte, digest.Digest, error) {
+	store := imageServer.GetStore()
+	img, err := store.Image(imageID)
+	if err != nil {
+		return nil, "", fmt.Errorf("get image %s: %w", imageID, err)
+	}
+	if pinned != "" && !hasDigest(img, pinned) {
+		return nil, "", fmt.Errorf("image %s does not match digest %s", imageID, pinned)
+	}
+	profile, err := readProfile(store, img)
+	if err != nil {
+		return nil, "", fmt.Errorf("read image %s: %w", imageID, err)
+	}
+	manifestDigest := img.Digest
+	if manifestDigest == "" {
+		manifestDigest = digest.NewDigestFromEncoded(digest.SHA256, img.ID)
+	}
+	return profile, manifestDigest, nil
+}
+
+// isArtifact returns true if the image is an OCI artifact instead of a
+// container image, which is the case if its config is no image config.
+func isArtifact(img types.Image) bool {
+	switch img.ConfigInfo().MediaType {
+	case "", imgspecv1.MediaTypeImageConfig, manifest.DockerV2Schema2ConfigMediaType:
+		return false
+	}
+	return true
+}
+
+// readArtifact reads the profile from the layer of the OCI artifact titled
+// like ProfileFile, or from its only layer. The signature policy of the
+// system context is enforced, because the artifact does not get pulled into
+// the local storage.
+func readArtifact(ctx context.Context, systemContext *types.SystemContext, img types.ImageCloser) ([]byte, error) {
+	layer, err := profileLayer(img.LayerInfos())
+	if err != nil {
+		return nil, err
+	}
+	if layer.Size > maxProfileSize {
+		return nil, fmt.Errorf("artifact layer %s exceeds the maximum size of %d bytes", layer.Digest, maxProfileSize)
+	}
+	if err := layer.Digest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid artifact layer digest: %w", err)
+	}
+
+	src, err := img.Reference().NewImageSource(ctx, systemContext)
+	if err != nil {
+		return nil, fmt.Errorf("open artifact: %w", err)
+	}
+	defer src.Close()
+
+	policy, err := signature.DefaultPolicy(systemContext)
+	if err != nil {
+		return nil, fmt.Errorf("get signature policy: %w", err)
+	}
+	policyContext, err := signature.NewPolicyContext(policy)
+	if err != nil {
+		return nil, fmt.Errorf("create signature policy context: %w", err)
+	}
+	defer policyContext.Destroy() // nolint: errcheck
+	if _, err := policyContext.IsRunningImageAllowed(ctx, image.UnparsedInstance(src, nil)); err != nil {
+		return nil, fmt.Errorf("artifact rejected by signature policy: %w", err)
+	}
+
+	blob, _, err := src.GetBlob(ctx, layer, none.NoCache)
+	if err != nil {
+		return nil, fmt.Errorf("get artifact layer %s: %w", layer.Digest, err)
+	}
+	defer blob.Close()
+	verifier := layer.Digest.Verifier()
+	profile, err := io.ReadAll(io.TeeReader(io.LimitReader(blob, maxProfileSize+1), verifier))
+	if err != nil {
+		return nil, fmt.Errorf("read artifact layer %s: %w", layer.Digest, err)
+	}
+	if len(profile) > maxProfileSize {
+		return nil, fmt.Errorf("artifact layer %s exceeds the maximum size of %d bytes", layer.Digest, maxProfileSize)
+	}
+	if !verifier.Verified() {
+		return nil, fmt.Errorf("artifact layer does not match digest %s", layer.Digest)
+	}
+	return profile, nil
+}
+
+// profileLayer returns the layer titled like ProfileFile, or the only layer.
+func profileLayer(layers []types.BlobInfo) (types.BlobInfo, error) {
+	for _, layer := range layers {
+		if path.Clean("/"+layer.Annotations[imgspecv1.AnnotationTitle]) == ProfileFile {
+			return layer, nil
+		}
+	}
+	if len(layers) == 1 {
+		return layers[0], nil
+	}
+	return types.BlobInfo{}, fmt.Errorf("artifact does not contain a layer titled %s", path.Base(ProfileFile))
+}
+
+// localImageID returns the ID of the first name available in the local
+// storage, or an empty string if none is available.
+func localImageID(imageServer storage.ImageServer, systemContext *types.SystemContext, names []string) string {
+	for _, name := range names {
+		if status, err := imageServer.ImageStatus(systemContext, name); err == nil {
+			return status.ID
+		}
+	}
+	return ""
+}
+
+// hasDigest returns true if one of the manifests of the image matches the
+// provided digest.
+func hasDigest(img *cstorage.Image, d digest.Digest) bool {
+	if img.Digest == d {
+		return true
+	}
+	for _, imgDigest := range img.Digests {
+		if imgDigest == d {
+			return true
+		}
+	}
+	return false
+}
+
+// readProfile reads the seccomp profile from the layers of the image,
+// starting with the top layer.
+func readProfile(store cstorage.Store, img *cstorage.Image) ([]byte, error) {
+	uncompressed := archive.Uncompressed
+	for layerID := img.TopLayer; layerID != ""; {
+		profile, found, err := func() ([]byte, bool, error) {
+			diff, err := store.Diff("", layerID, &cstorage.DiffOptions{Compression: &uncompressed})
+			if err != nil {
+				return nil, false, fmt.Errorf("get diff of layer %s: %w", layerID, err)
+			}
+			defer diff.Close()
+			return profileFromTar(diff)
+		}()
+		if err != nil || found {
+			return profile, err
+		}
+
+		layer, err := store.Layer(layerID)
+		if err != nil {
+			return nil, fmt.Errorf("get layer %s: %w", layerID, err)
+		}
+		layerID = layer.Parent
+	}
+	return nil, fmt.Errorf("image does not contain %s", ProfileFile)
+}
+
+// profileFromTar returns the content of the seccomp profile from the provided
+// tar stream, if it contains one.
+func profileFromTar(r io.Reader) ([]byte, bool, error) {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("read layer: %w", err)
+		}
+		if path.Clean("/"+hdr.Name) != ProfileFile {
+			continue
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, false, fmt.Errorf("%s is not a regular file", ProfileFile)
+		}
+		if hdr.Size > maxProfileSize {
+			return nil, false, fmt.Errorf("%s exceeds the maximum size of %d bytes", ProfileFile, maxProfileSize)
+		}
+		profile, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, false, fmt.Errorf("read %s: %w", ProfileFile, err)
+		}
+		return profile, true, nil
+	}
+}
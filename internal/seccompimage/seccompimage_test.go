@@ -0,0 +1,422 @@
+package seccompimage_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/image/v5/oci/layout"
+	"github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/types"
+	cstorage "github.com/containers/storage"
+	"github.com/cri-o/cri-o/internal/seccompimage"
+	"github.com/cri-o/cri-o/internal/storage"
+	imagetypesmock "github.com/cri-o/cri-o/test/mocks/containers/image/v5"
+	containerstoragemock "github.com/cri-o/cri-o/test/mocks/containerstorage"
+	criostoragemock "github.com/cri-o/cri-o/test/mocks/criostorage"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	digest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+var _ = t.Describe("Puller", func() {
+	const (
+		imageID     = "2a03a6059f21e150ae84b0973863609494aad70f0a80eaeb64bddd8d92465812"
+		imageTag    = "quay.io/crio/seccomp:v1"
+		topLayer    = "top"
+		baseLayer   = "base"
+		profile     = `{"defaultAction":"SCMP_ACT_ALLOW"}`
+		imageSHA    = "sha256:0123456789012345678901234567890123456789012345678901234567890123"
+		otherSHA    = "sha256:3210987654321098765432109876543210987654321098765432109876543210"
+		imagePinned = "quay.io/crio/seccomp@" + imageSHA
+		sandboxID   = "sandbox"
+		manifest    = `{"schemaVersion":2}`
+	)
+
+	var (
+		mockCtrl        *gomock.Controller
+		imageServerMock *criostoragemock.MockImageServer
+		storeMock       *containerstoragemock.MockStore
+		imageCloserMock *imagetypesmock.MockImageCloser
+		sut             *seccompimage.Puller
+		sys             *types.SystemContext
+	)
+
+	layer := func(files map[string]string) io.ReadCloser {
+		buf := &bytes.Buffer{}
+		tw := tar.NewWriter(buf)
+		for name, content := range files {
+			Expect(tw.WriteHeader(&tar.Header{
+				Name:     name,
+				Mode:     0o644,
+				Size:     int64(len(content)),
+				Typeflag: tar.TypeReg,
+			})).To(Succeed())
+			_, err := tw.Write([]byte(content))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(tw.Close()).To(Succeed())
+		return io.NopCloser(buf)
+	}
+
+	prepareImage := func(name string) {
+		imageServerMock.EXPECT().PrepareImage(sys, name).Return(imageCloserMock, nil)
+		imageCloserMock.EXPECT().Manifest(gomock.Any()).Return([]byte(manifest), "", nil)
+		imageCloserMock.EXPECT().ConfigInfo().
+			Return(types.BlobInfo{MediaType: imgspecv1.MediaTypeImageConfig}).AnyTimes()
+		imageCloserMock.EXPECT().Close().Return(nil)
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		imageServerMock = criostoragemock.NewMockImageServer(mockCtrl)
+		storeMock = containerstoragemock.NewMockStore(mockCtrl)
+		imageCloserMock = imagetypesmock.NewMockImageCloser(mockCtrl)
+		imageServerMock.EXPECT().GetStore().Return(storeMock).AnyTimes()
+		sut = seccompimage.New()
+		sys = &types.SystemContext{}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should resolve tagged images once per sandbox", func() {
+		// Given
+		imageServerMock.EXPECT().ResolveNames(sys, imageTag).Return([]string{imageTag}, nil)
+		prepareImage(imageTag)
+		imageServerMock.EXPECT().PullImage(sys, imageTag, gomock.Any()).Return(nil, nil)
+		imageServerMock.EXPECT().ImageStatus(sys, imageTag).
+			Return(&storage.ImageResult{ID: imageID}, nil)
+		storeMock.EXPECT().Image(imageID).
+			Return(&cstorage.Image{ID: imageID, TopLayer: topLayer}, nil)
+		storeMock.EXPECT().Diff("", topLayer, gomock.Any()).
+			Return(layer(map[string]string{"seccomp.json": profile}), nil)
+
+		// When
+		first, err := sut.Pull(context.Background(), imageServerMock, sys, sandboxID, imageTag)
+		Expect(err).NotTo(HaveOccurred())
+		second, err := sut.Pull(context.Background(), imageServerMock, sys, sandboxID, imageTag)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(first)).To(Equal(profile))
+		Expect(second).To(Equal(first))
+	})
+
+	It("should use the profile cached by digest for other sandboxes", func() {
+		// Given
+		imageServerMock.EXPECT().ResolveNames(sys, imageTag).
+			Return([]string{imageTag}, nil).Times(3)
+		prepareImage(imageTag)
+		imageServerMock.EXPECT().PullImage(sys, imageTag, gomock.Any()).Return(nil, nil)
+		imageServerMock.EXPECT().ImageStatus(sys, imageTag).
+			Return(&storage.ImageResult{ID: imageID}, nil)
+		storeMock.EXPECT().Image(imageID).
+			Return(&cstorage.Image{ID: imageID, TopLayer: topLayer}, nil)
+		storeMock.EXPECT().Diff("", topLayer, gomock.Any()).
+			Return(layer(map[string]string{"seccomp.json": profile}), nil)
+		_, err := sut.Pull(context.Background(), imageServerMock, sys, sandboxID, imageTag)
+		Expect(err).NotTo(HaveOccurred())
+		prepareImage(imageTag)
+		prepareImage(imageTag)
+
+		// When
+		other, err := sut.Pull(context.Background(), imageServerMock, sys, "other", imageTag)
+		Expect(err).NotTo(HaveOccurred())
+		sut.Forget(sandboxID)
+		res, err := sut.Pull(context.Background(), imageServerMock, sys, sandboxID, imageTag)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(other)).To(Equal(profile))
+		Expect(string(res)).To(Equal(profile))
+	})
+
+	It("should read the profile from OCI artifacts", func() {
+		// Given
+		dir := t.MustTempDir("artifact")
+		writeBlob := func(content []byte) digest.Digest {
+			d := digest.FromBytes(content)
+			Expect(os.MkdirAll(filepath.Join(dir, "blobs", "sha256"), 0o755)).To(Succeed())
+			Expect(os.WriteFile(
+				filepath.Join(dir, "blobs", "sha256", d.Encoded()), content, 0o644,
+			)).To(Succeed())
+			return d
+		}
+		config := []byte("{}")
+		artifactManifest, err := json.Marshal(&imgspecv1.Manifest{
+			Versioned: specs.Versioned{SchemaVersion: 2},
+			MediaType: imgspecv1.MediaTypeImageManifest,
+			Config: imgspecv1.Descriptor{
+				MediaType: "application/vnd.cncf.seccomp-profile.config.v1+json",
+				Digest:    writeBlob(config),
+				Size:      int64(len(config)),
+			},
+			Layers: []imgspecv1.Descriptor{
+				{
+					MediaType: "text/plain",
+					Digest:    writeBlob([]byte("README")),
+					Size:      6,
+					Annotations: map[string]string{
+						imgspecv1.AnnotationTitle: "README",
+					},
+				},
+				{
+					MediaType: "application/json",
+					Digest:    writeBlob([]byte(profile)),
+					Size:      int64(len(profile)),
+					Annotations: map[string]string{
+						imgspecv1.AnnotationTitle: "seccomp.json",
+					},
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		index, err := json.Marshal(&imgspecv1.Index{
+			Versioned: specs.Versioned{SchemaVersion: 2},
+			Manifests: []imgspecv1.Descriptor{{
+				MediaType: imgspecv1.MediaTypeImageManifest,
+				Digest:    writeBlob(artifactManifest),
+				Size:      int64(len(artifactManifest)),
+			}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(dir, "index.json"), index, 0o644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "oci-layout"),
+			[]byte(`{"imageLayoutVersion":"1.0.0"}`), 0o644)).To(Succeed())
+		sys.SignaturePolicyPath = filepath.Join(dir, "policy.json")
+		Expect(os.WriteFile(sys.SignaturePolicyPath,
+			[]byte(`{"default":[{"type":"insecureAcceptAnything"}]}`), 0o644)).To(Succeed())
+
+		ref, err := layout.NewReference(dir, "")
+		Expect(err).NotTo(HaveOccurred())
+		artifact, err := ref.NewImage(context.Background(), sys)
+		Expect(err).NotTo(HaveOccurred())
+		imageServerMock.EXPECT().ResolveNames(sys, imageTag).Return([]string{imageTag}, nil)
+		imageServerMock.EXPECT().PrepareImage(sys, imageTag).Return(artifact, nil)
+
+		// When
+		res, err := sut.Pull(context.Background(), imageServerMock, sys, sandboxID, imageTag)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(res)).To(Equal(profile))
+	})
+
+	It("should read the profile from parent layers", func() {
+		// Given
+		imageServerMock.EXPECT().ResolveNames(sys, imageTag).Return([]string{imageTag}, nil)
+		prepareImage(imageTag)
+		imageServerMock.EXPECT().PullImage(sys, imageTag, gomock.Any()).Return(nil, nil)
+		imageServerMock.EXPECT().ImageStatus(sys, imageTag).
+			Return(&storage.ImageResult{ID: imageID}, nil)
+		storeMock.EXPECT().Image(imageID).
+			Return(&cstorage.Image{ID: imageID, TopLayer: topLayer}, nil)
+		storeMock.EXPECT().Diff("", topLayer, gomock.Any()).
+			Return(layer(map[string]string{"README": "test"}), nil)
+		storeMock.EXPECT().Layer(topLayer).Return(&cstorage.Layer{Parent: baseLayer}, nil)
+		storeMock.EXPECT().Diff("", baseLayer, gomock.Any()).
+			Return(layer(map[string]string{"./seccomp.json": profile}), nil)
+
+		// When
+		res, err := sut.Pull(context.Background(), imageServerMock, sys, sandboxID, imageTag)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(res)).To(Equal(profile))
+	})
+
+	It("should use the local image if the registry is not reachable", func() {
+		// Given
+		imageServerMock.EXPECT().ResolveNames(sys, imageTag).Return([]string{imageTag}, nil)
+		prepareImage(imageTag)
+		imageServerMock.EXPECT().PullImage(sys, imageTag, gomock.Any()).
+			Return(nil, &net.OpError{Op: "dial", Err: errors.New("connection refused")})
+		imageServerMock.EXPECT().ImageStatus(sys, imageTag).
+			Return(&storage.ImageResult{ID: imageID}, nil)
+		storeMock.EXPECT().Image(imageID).
+			Return(&cstorage.Image{ID: imageID, TopLayer: topLayer}, nil)
+		storeMock.EXPECT().Diff("", topLayer, gomock.Any()).
+			Return(layer(map[string]string{"seccomp.json": profile}), nil)
+
+		// When
+		res, err := sut.Pull(context.Background(), imageServerMock, sys, sandboxID, imageTag)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(res)).To(Equal(profile))
+	})
+
+	It("should not use the local image if the signature policy rejects the image", func() {
+		// Given
+		imageServerMock.EXPECT().ResolveNames(sys, imageTag).Return([]string{imageTag}, nil)
+		prepareImage(imageTag)
+		imageServerMock.EXPECT().PullImage(sys, imageTag, gomock.Any()).
+			Return(nil, signature.PolicyRequirementError("signature verification failed"))
+
+		// When
+		res, err := sut.Pull(context.Background(), imageServerMock, sys, sandboxID, imageTag)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("signature verification failed"))
+		Expect(res).To(BeNil())
+	})
+
+	It("should pull images referenced by digest", func() {
+		// Given
+		imageServerMock.EXPECT().ResolveNames(sys, imagePinned).Return([]string{imagePinned}, nil)
+		prepareImage(imagePinned)
+		imageServerMock.EXPECT().PullImage(sys, imagePinned, gomock.Any()).Return(nil, nil)
+		imageServerMock.EXPECT().ImageStatus(sys, imagePinned).
+			Return(&storage.ImageResult{ID: imageID}, nil)
+		storeMock.EXPECT().Image(imageID).Return(&cstorage.Image{
+			ID:       imageID,
+			TopLayer: topLayer,
+			Digests:  []digest.Digest{digest.Digest(imageSHA)},
+		}, nil)
+		storeMock.EXPECT().Diff("", topLayer, gomock.Any()).
+			Return(layer(map[string]string{"seccomp.json": profile}), nil)
+
+		// When
+		first, err := sut.Pull(context.Background(), imageServerMock, sys, sandboxID, imagePinned)
+		Expect(err).NotTo(HaveOccurred())
+		second, err := sut.Pull(context.Background(), imageServerMock, sys, "other", imagePinned)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(first)).To(Equal(profile))
+		Expect(second).To(Equal(first))
+	})
+
+	It("should fail if the image does not match the digest", func() {
+		// Given
+		imageServerMock.EXPECT().ResolveNames(sys, imagePinned).Return([]string{imagePinned}, nil)
+		prepareImage(imagePinned)
+		imageServerMock.EXPECT().PullImage(sys, imagePinned, gomock.Any()).Return(nil, nil)
+		imageServerMock.EXPECT().ImageStatus(sys, imagePinned).
+			Return(&storage.ImageResult{ID: imageID}, nil)
+		storeMock.EXPECT().Image(imageID).Return(&cstorage.Image{
+			ID:       imageID,
+			TopLayer: topLayer,
+			Digest:   digest.Digest(otherSHA),
+		}, nil)
+
+		// When
+		res, err := sut.Pull(context.Background(), imageServerMock, sys, sandboxID, imagePinned)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("does not match digest"))
+		Expect(res).To(BeNil())
+	})
+
+	It("should fail if pulling an image referenced by digest fails", func() {
+		// Given
+		imageServerMock.EXPECT().ResolveNames(sys, imagePinned).Return([]string{imagePinned}, nil)
+		prepareImage(imagePinned)
+		imageServerMock.EXPECT().PullImage(sys, imagePinned, gomock.Any()).
+			Return(nil, errors.New("manifest unknown"))
+
+		// When
+		res, err := sut.Pull(context.Background(), imageServerMock, sys, sandboxID, imagePinned)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("manifest unknown"))
+		Expect(res).To(BeNil())
+	})
+
+	It("should not use the cached profile for another signature policy", func() {
+		// Given
+		otherSys := &types.SystemContext{SignaturePolicyPath: "/etc/crio/policies/other.json"}
+		imageServerMock.EXPECT().ResolveNames(sys, imagePinned).Return([]string{imagePinned}, nil)
+		prepareImage(imagePinned)
+		imageServerMock.EXPECT().PullImage(sys, imagePinned, gomock.Any()).Return(nil, nil)
+		imageServerMock.EXPECT().ImageStatus(sys, imagePinned).
+			Return(&storage.ImageResult{ID: imageID}, nil)
+		storeMock.EXPECT().Image(imageID).Return(&cstorage.Image{
+			ID:       imageID,
+			TopLayer: topLayer,
+			Digests:  []digest.Digest{digest.Digest(imageSHA)},
+		}, nil)
+		storeMock.EXPECT().Diff("", topLayer, gomock.Any()).
+			Return(layer(map[string]string{"seccomp.json": profile}), nil)
+		_, err := sut.Pull(context.Background(), imageServerMock, sys, sandboxID, imagePinned)
+		Expect(err).NotTo(HaveOccurred())
+		imageServerMock.EXPECT().ResolveNames(otherSys, imagePinned).Return([]string{imagePinned}, nil)
+		imageServerMock.EXPECT().PrepareImage(otherSys, imagePinned).Return(imageCloserMock, nil)
+		imageCloserMock.EXPECT().Manifest(gomock.Any()).Return([]byte(manifest), "", nil)
+		imageCloserMock.EXPECT().Close().Return(nil)
+		imageServerMock.EXPECT().PullImage(otherSys, imagePinned, gomock.Any()).
+			Return(nil, signature.PolicyRequirementError("signature verification failed"))
+
+		// When
+		res, err := sut.Pull(context.Background(), imageServerMock, otherSys, "other", imagePinned)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("signature verification failed"))
+		Expect(res).To(BeNil())
+	})
+
+	It("should drop cached profiles once no sandbox uses them", func() {
+		// Given
+		imageServerMock.EXPECT().ResolveNames(sys, imageTag).
+			Return([]string{imageTag}, nil).Times(2)
+		prepareImage(imageTag)
+		prepareImage(imageTag)
+		imageServerMock.EXPECT().PullImage(sys, imageTag, gomock.Any()).
+			Return(nil, nil).Times(2)
+		imageServerMock.EXPECT().ImageStatus(sys, imageTag).
+			Return(&storage.ImageResult{ID: imageID}, nil).Times(2)
+		storeMock.EXPECT().Image(imageID).
+			Return(&cstorage.Image{ID: imageID, TopLayer: topLayer}, nil).Times(2)
+		storeMock.EXPECT().Diff("", topLayer, gomock.Any()).
+			Return(layer(map[string]string{"seccomp.json": profile}), nil)
+		storeMock.EXPECT().Diff("", topLayer, gomock.Any()).
+			Return(layer(map[string]string{"seccomp.json": profile}), nil)
+		_, err := sut.Pull(context.Background(), imageServerMock, sys, sandboxID, imageTag)
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		sut.Forget(sandboxID)
+		res, err := sut.Pull(context.Background(), imageServerMock, sys, "other", imageTag)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(res)).To(Equal(profile))
+	})
+
+	It("should fail if the image does not contain a profile", func() {
+		// Given
+		imageServerMock.EXPECT().ResolveNames(sys, imageTag).Return([]string{imageTag}, nil)
+		prepareImage(imageTag)
+		imageServerMock.EXPECT().PullImage(sys, imageTag, gomock.Any()).Return(nil, nil)
+		imageServerMock.EXPECT().ImageStatus(sys, imageTag).
+			Return(&storage.ImageResult{ID: imageID}, nil)
+		storeMock.EXPECT().Image(imageID).
+			Return(&cstorage.Image{ID: imageID, TopLayer: topLayer}, nil)
+		storeMock.EXPECT().Diff("", topLayer, gomock.Any()).
+			Return(layer(map[string]string{"profile.json": profile}), nil)
+		storeMock.EXPECT().Layer(topLayer).Return(&cstorage.Layer{}, nil)
+
+		// When
+		res, err := sut.Pull(context.Background(), imageServerMock, sys, sandboxID, imageTag)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("does not contain " + seccompimage.ProfileFile))
+		Expect(res).To(BeNil())
+	})
+})
@@ -0,0 +1,26 @@
+package seccompimage_test
+
+import (
+	"testing"
+
+	. "github.com/cri-o/cri-o/test/framework"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// TestSeccompImage runs the created specs
+func TestSeccompImage(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunFrameworkSpecs(t, "SeccompImage")
+}
+
+var t *TestFramework
+
+var _ = BeforeSuite(func() {
+	t = NewTestFramework(NilFunc, NilFunc)
+	t.Setup()
+})
+
+var _ = AfterSuite(func() {
+	t.Teardown()
+})
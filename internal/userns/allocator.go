@@ -0,0 +1,314 @@
+package userns
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/containers/storage/pkg/idtools"
+	"github.com/containers/storage/pkg/ioutils"
+	"github.com/sirupsen/logrus"
+)
+
+// Allocation is a range of host IDs assigned to the user namespace of a pod.
+// The same range is used for UIDs and GIDs.
+type Allocation struct {
+	// PodID is the ID of the pod sandbox.
+	PodID string `json:"pod_id"`
+
+	// Pod and Namespace are the name and namespace of the pod.
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+
+	// HostID is the first host ID of the range, which gets mapped to the ID
+	// 0 in the user namespace.
+	HostID uint32 `json:"host_id"`
+
+	// Size is the number of IDs of the range.
+	Size uint32 `json:"size"`
+
+	// Created is the time of the allocation.
+	Created time.Time `json:"created"`
+}
+
+// end returns the first host ID after the range.
+func (a *Allocation) end() uint64 {
+	return uint64(a.HostID) + uint64(a.Size)
+}
+
+// IDMap returns the ID mappings of the allocation, including the provided
+// additional mappings. The container IDs covered by the additional mappings
+// are not mapped to the allocated range.
+func (a *Allocation) IDMap(additional []idtools.IDMap) []idtools.IDMap {
+	covered := append([]idtools.IDMap(nil), additional...)
+	sort.Slice(covered, func(i, j int) bool {
+		return covered[i].ContainerID < covered[j].ContainerID
+	})
+
+	res := []idtools.IDMap{}
+	next := 0
+	add := func(end int) {
+		if end > int(a.Size) {
+			end = int(a.Size)
+		}
+		if end > next {
+			res = append(res, idtools.IDMap{
+				ContainerID: next,
+				HostID:      int(a.HostID) + next,
+				Size:        end - next,
+			})
+		}
+	}
+	for _, m := range covered {
+		add(m.ContainerID)
+		if m.ContainerID+m.Size > next {
+			next = m.ContainerID + m.Size
+		}
+	}
+	add(int(a.Size))
+	return append(res, additional...)
+}
+
+// state is the persisted state of the allocator.
+type state struct {
+	Allocations []*Allocation `json:"allocations"`
+}
+
+// Allocator assigns non-overlapping ranges of host IDs to pods and persists
+// the assignments across restarts.
+type Allocator struct {
+	lock        sync.Mutex
+	stateFile   string
+	start       uint32
+	size        uint32
+	allocations map[string]*Allocation
+}
+
+// New creates a new allocator for the host IDs [start, start+size), which
+// persists its allocations in stateFile. Persisted allocations overlapping
+// other ones get dropped. Persisted allocations outside of a changed range are
+// kept until their pods get removed, because the pods still use their IDs.
+func New(stateFile string, start, size uint32) (*Allocator, error) {
+	if size == 0 {
+		return nil, errors.New("user namespace range is empty")
+	}
+	a := &Allocator{
+		stateFile:   stateFile,
+		start:       start,
+		size:        size,
+		allocations: make(map[string]*Allocation),
+	}
+
+	data, err := os.ReadFile(stateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return a, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read user namespace allocations: %w", err)
+	}
+	s := &state{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parse user namespace allocations: %w", err)
+	}
+
+	dropped := false
+	for _, alloc := range s.Allocations {
+		if err := a.validate(alloc); err != nil {
+			logrus.Warnf("Dropping user namespace allocation of pod %s: %v", alloc.PodID, err)
+			dropped = true
+			continue
+		}
+		if !a.contains(alloc) {
+			logrus.Warnf(
+				"Keeping user namespace allocation %d-%d of pod %s outside of the user namespace range until the pod gets removed",
+				alloc.HostID, alloc.end()-1, alloc.PodID,
+			)
+		}
+		a.allocations[alloc.PodID] = alloc
+	}
+	if dropped {
+		if err := a.save(); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// validate checks if the allocation is valid without overlapping the
+// existing ones.
+func (a *Allocator) validate(alloc *Allocation) error {
+	if alloc.PodID == "" || alloc.Size == 0 {
+		return errors.New("invalid allocation")
+	}
+	if _, ok := a.allocations[alloc.PodID]; ok {
+		return errors.New("duplicate allocation")
+	}
+	for _, other := range a.allocations {
+		if uint64(alloc.HostID) < other.end() && uint64(other.HostID) < alloc.end() {
+			return fmt.Errorf("range %d-%d overlaps with pod %s", alloc.HostID, alloc.end()-1, other.PodID)
+		}
+	}
+	return nil
+}
+
+// contains returns true if the allocation is within the range of the
+// allocator.
+func (a *Allocator) contains(alloc *Allocation) bool {
+	return alloc.HostID >= a.start && alloc.end() <= uint64(a.start)+uint64(a.size)
+}
+
+// Allocate assigns a range of size host IDs to the provided pod. It returns
+// the existing allocation if the pod already has one of the same size.
+func (a *Allocator) Allocate(podID, pod, namespace string, size uint32) (*Allocation, error) {
+	if size == 0 {
+		return nil, errors.New("user namespace size has to be greater than zero")
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if existing, ok := a.allocations[podID]; ok {
+		if existing.Size != size {
+			return nil, fmt.Errorf("pod %s already has a user namespace of size %d", podID, existing.Size)
+		}
+		copied := *existing
+		return &copied, nil
+	}
+
+	// First fit between the sorted allocations, including the ones kept
+	// outside of a changed range.
+	next := uint64(a.start)
+	for _, other := range a.sorted() {
+		if next+uint64(size) <= uint64(other.HostID) {
+			break
+		}
+		if other.end() > next {
+			next = other.end()
+		}
+	}
+	if next+uint64(size) > uint64(a.start)+uint64(a.size) {
+		return nil, fmt.Errorf("no free range of %d IDs available in the user namespace range %d-%d", size, a.start, uint64(a.start)+uint64(a.size)-1)
+	}
+
+	alloc := &Allocation{
+		PodID:     podID,
+		Pod:       pod,
+		Namespace: namespace,
+		HostID:    uint32(next),
+		Size:      size,
+		Created:   time.Now(),
+	}
+	a.allocations[podID] = alloc
+	if err := a.save(); err != nil {
+		delete(a.allocations, podID)
+		return nil, err
+	}
+	copied := *alloc
+	return &copied, nil
+}
+
+// Release frees the range of the provided pod, if it has one.
+func (a *Allocator) Release(podID string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	alloc, ok := a.allocations[podID]
+	if !ok {
+		return nil
+	}
+	delete(a.allocations, podID)
+	if err := a.save(); err != nil {
+		a.allocations[podID] = alloc
+		return err
+	}
+	return nil
+}
+
+// Prune releases the ranges of all pods for which keep returns false and
+// returns the released allocations.
+func (a *Allocator) Prune(keep func(podID string) bool) ([]*Allocation, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	pruned := []*Allocation{}
+	for podID, alloc := range a.allocations {
+		if !keep(podID) {
+			pruned = append(pruned, alloc)
+			delete(a.allocations, podID)
+		}
+	}
+	if len(pruned) == 0 {
+		return pruned, nil
+	}
+	if err := a.save(); err != nil {
+		for _, alloc := range pruned {
+			a.allocations[alloc.PodID] = alloc
+		}
+		return nil, err
+	}
+	return pruned, nil
+}
+
+// Get returns the allocation of the provided pod, or nil if it has none.
+func (a *Allocator) Get(podID string) *Allocation {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	alloc, ok := a.allocations[podID]
+	if !ok {
+		return nil
+	}
+	copied := *alloc
+	return &copied
+}
+
+// List returns all allocations ordered by their host IDs.
+func (a *Allocator) List() []*Allocation {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	res := []*Allocation{}
+	for _, alloc := range a.sorted() {
+		copied := *alloc
+		res = append(res, &copied)
+	}
+	return res
+}
+
+// Range returns the first host ID and size of the range managed by the
+// allocator.
+func (a *Allocator) Range() (start, size uint32) {
+	return a.start, a.size
+}
+
+// sorted returns the allocations ordered by their host IDs.
+func (a *Allocator) sorted() []*Allocation {
+	res := make([]*Allocation, 0, len(a.allocations))
+	for _, alloc := range a.allocations {
+		res = append(res, alloc)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].HostID < res[j].HostID
+	})
+	return res
+}
+
+// save persists the allocations atomically.
+func (a *Allocator) save() error {
+	data, err := json.Marshal(&state{Allocations: a.sorted()})
+	if err != nil {
+		return fmt.Errorf("encode user namespace allocations: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(a.stateFile), 0o700); err != nil {
+		return fmt.Errorf("create user namespace allocations directory: %w", err)
+	}
+	if err := ioutils.AtomicWriteFile(a.stateFile, data, 0o600); err != nil {
+		return fmt.Errorf("write user namespace allocations: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package userns_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/containers/storage/pkg/idtools"
+	"github.com/cri-o/cri-o/internal/userns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("Allocator", func() {
+	const (
+		start = 100000
+		size  = 4 * 65536
+	)
+
+	var (
+		stateFile string
+		sut       *userns.Allocator
+	)
+
+	BeforeEach(func() {
+		stateFile = filepath.Join(t.MustTempDir("userns"), "state", "allocations.json")
+		var err error
+		sut, err = userns.New(stateFile, start, size)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should allocate non-overlapping ranges", func() {
+		// Given
+		// When
+		first, err := sut.Allocate("1", "pod1", "default", 65536)
+		Expect(err).NotTo(HaveOccurred())
+		second, err := sut.Allocate("2", "pod2", "default", 1024)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Then
+		Expect(first.HostID).To(BeEquivalentTo(start))
+		Expect(second.HostID).To(BeEquivalentTo(start + 65536))
+		Expect(sut.List()).To(HaveLen(2))
+		Expect(sut.Get("2").Pod).To(Equal("pod2"))
+	})
+
+	It("should return the existing allocation of a pod", func() {
+		// Given
+		first, err := sut.Allocate("1", "pod1", "default", 65536)
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		again, err := sut.Allocate("1", "pod1", "default", 65536)
+		Expect(err).NotTo(HaveOccurred())
+		_, resizeErr := sut.Allocate("1", "pod1", "default", 1024)
+
+		// Then
+		Expect(again.HostID).To(Equal(first.HostID))
+		Expect(resizeErr).To(HaveOccurred())
+		Expect(sut.List()).To(HaveLen(1))
+	})
+
+	It("should reuse released ranges", func() {
+		// Given
+		_, err := sut.Allocate("1", "pod1", "default", 65536)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = sut.Allocate("2", "pod2", "default", 65536)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sut.Release("1")).To(Succeed())
+
+		// When
+		res, err := sut.Allocate("3", "pod3", "default", 1024)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.HostID).To(BeEquivalentTo(start))
+		Expect(sut.Get("1")).To(BeNil())
+	})
+
+	It("should fail if the range is exhausted", func() {
+		// Given
+		_, err := sut.Allocate("1", "pod1", "default", size-1024)
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		res, err := sut.Allocate("2", "pod2", "default", 2048)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(res).To(BeNil())
+		Expect(sut.Get("2")).To(BeNil())
+	})
+
+	It("should fail to allocate an empty range", func() {
+		// Given
+		// When
+		res, err := sut.Allocate("1", "pod1", "default", 0)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(res).To(BeNil())
+	})
+
+	It("should persist allocations across restarts", func() {
+		// Given
+		_, err := sut.Allocate("1", "pod1", "default", 65536)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = sut.Allocate("2", "pod2", "kube-system", 65536)
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		restarted, err := userns.New(stateFile, start, size)
+		Expect(err).NotTo(HaveOccurred())
+		next, err := restarted.Allocate("3", "pod3", "default", 65536)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(next.HostID).To(BeEquivalentTo(start + 2*65536))
+		Expect(restarted.Get("2").Namespace).To(Equal("kube-system"))
+	})
+
+	It("should keep persisted allocations outside of a changed range", func() {
+		// Given
+		_, err := sut.Allocate("1", "pod1", "default", 65536)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = sut.Allocate("2", "pod2", "default", 65536)
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		restarted, err := userns.New(stateFile, start+65536, size)
+		Expect(err).NotTo(HaveOccurred())
+		next, err := restarted.Allocate("3", "pod3", "default", 65536)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restarted.Get("1")).NotTo(BeNil())
+		Expect(restarted.Get("2")).NotTo(BeNil())
+		Expect(next.HostID).To(BeEquivalentTo(start + 2*65536))
+	})
+
+	It("should not allocate ranges overlapping allocations outside of a changed range", func() {
+		// Given
+		_, err := sut.Allocate("1", "pod1", "default", 65536)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = sut.Allocate("2", "pod2", "default", 65536)
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		restarted, err := userns.New(stateFile, start+32768, size)
+		Expect(err).NotTo(HaveOccurred())
+		next, err := restarted.Allocate("3", "pod3", "default", 65536)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(next.HostID).To(BeEquivalentTo(start + 2*65536))
+		Expect(restarted.Release("1")).To(Succeed())
+		Expect(restarted.Get("1")).To(BeNil())
+	})
+
+	It("should fail with a corrupted state file", func() {
+		// Given
+		Expect(os.MkdirAll(filepath.Dir(stateFile), 0o700)).To(Succeed())
+		Expect(os.WriteFile(stateFile, []byte("{"), 0o600)).To(Succeed())
+
+		// When
+		res, err := userns.New(stateFile, start, size)
+
+		// Then
+		Expect(err).To(HaveOccurred())
+		Expect(res).To(BeNil())
+	})
+
+	It("should prune allocations of removed pods", func() {
+		// Given
+		_, err := sut.Allocate("1", "pod1", "default", 1024)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = sut.Allocate("2", "pod2", "default", 1024)
+		Expect(err).NotTo(HaveOccurred())
+
+		// When
+		pruned, err := sut.Prune(func(podID string) bool { return podID == "2" })
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(HaveLen(1))
+		Expect(pruned[0].PodID).To(Equal("1"))
+		Expect(sut.List()).To(HaveLen(1))
+	})
+
+	t.Describe("IDMap", func() {
+		It("should map the whole range without additional mappings", func() {
+			// Given
+			alloc := &userns.Allocation{HostID: start, Size: 65536}
+
+			// When
+			res := alloc.IDMap(nil)
+
+			// Then
+			Expect(res).To(Equal([]idtools.IDMap{
+				{ContainerID: 0, HostID: start, Size: 65536},
+			}))
+		})
+
+		It("should not map container IDs of additional mappings", func() {
+			// Given
+			alloc := &userns.Allocation{HostID: start, Size: 65536}
+			additional := []idtools.IDMap{
+				{ContainerID: 1000, HostID: 1000, Size: 1},
+				{ContainerID: 0, HostID: 5000, Size: 1},
+			}
+
+			// When
+			res := alloc.IDMap(additional)
+
+			// Then
+			Expect(res).To(Equal([]idtools.IDMap{
+				{ContainerID: 1, HostID: start + 1, Size: 999},
+				{ContainerID: 1001, HostID: start + 1001, Size: 64535},
+				{ContainerID: 1000, HostID: 1000, Size: 1},
+				{ContainerID: 0, HostID: 5000, Size: 1},
+			}))
+		})
+	})
+})
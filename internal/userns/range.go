@@ -0,0 +1,153 @@
+package userns
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/containers/storage/pkg/idtools"
+)
+
+// ParseRange parses a range of host IDs in the format "<first ID>:<size>".
+func ParseRange(r string) (start, size uint32, err error) {
+	first, count, ok := strings.Cut(r, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid user namespace range %q, has to be in the format <first ID>:<size>", r)
+	}
+	parsedStart, err := strconv.ParseUint(first, 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid first ID of user namespace range %q: %w", r, err)
+	}
+	parsedSize, err := strconv.ParseUint(count, 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid size of user namespace range %q: %w", r, err)
+	}
+	if parsedStart == 0 {
+		return 0, 0, fmt.Errorf("user namespace range %q must not contain the root ID", r)
+	}
+	if parsedSize == 0 {
+		return 0, 0, fmt.Errorf("user namespace range %q is empty", r)
+	}
+	if parsedStart+parsedSize > math.MaxUint32 {
+		return 0, 0, fmt.Errorf("user namespace range %q exceeds the maximum ID", r)
+	}
+	return uint32(parsedStart), uint32(parsedSize), nil
+}
+
+// CheckHostIDs returns an error if any entry of the provided files in the
+// passwd(5) or group(5) format has an ID within the range [start,
+// start+size). Missing files are ignored.
+func CheckHostIDs(start, size uint32, files ...string) error {
+	for _, file := range files {
+		if err := checkHostIDs(start, size, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkHostIDs(start, size uint32, file string) error {
+	f, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ":")
+		if len(fields) < 3 {
+			continue
+		}
+		id, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			continue
+		}
+		if id >= uint64(start) && id < uint64(start)+uint64(size) {
+			return fmt.Errorf("ID %d of %q in %s is within the user namespace range %d-%d", id, fields[0], file, start, uint64(start)+uint64(size)-1)
+		}
+	}
+	return scanner.Err()
+}
+
+// CheckSubIDs returns an error if any range of the provided files in the
+// subuid(5) or subgid(5) format overlaps the range [start, start+size). This
+// includes the ranges used by containers/storage for automatic user
+// namespaces, which are assigned to its root_auto_ns_user. Missing files are
+// ignored.
+func CheckSubIDs(start, size uint32, files ...string) error {
+	for _, file := range files {
+		if err := checkSubIDs(start, size, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkSubIDs(start, size uint32, file string) error {
+	f, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ":")
+		if len(fields) != 3 {
+			continue
+		}
+		first, err := strconv.ParseUint(fields[1], 10, 32)
+		if err != nil {
+			continue
+		}
+		count, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			continue
+		}
+		if overlaps(start, size, first, count) {
+			return fmt.Errorf("range %d-%d of %q in %s overlaps the user namespace range %d-%d", first, first+count-1, fields[0], file, start, uint64(start)+uint64(size)-1)
+		}
+	}
+	return scanner.Err()
+}
+
+// CheckIDMaps returns an error if the host IDs of any of the provided
+// mappings, used by the named owner, overlap the range [start, start+size).
+func CheckIDMaps(start, size uint32, owner string, maps ...[]idtools.IDMap) error {
+	for _, idMap := range maps {
+		for _, m := range idMap {
+			if m.Size <= 0 || m.HostID < 0 {
+				continue
+			}
+			if overlaps(start, size, uint64(m.HostID), uint64(m.Size)) {
+				return fmt.Errorf("host IDs %d-%d of the %s overlap the user namespace range %d-%d", m.HostID, m.HostID+m.Size-1, owner, start, uint64(start)+uint64(size)-1)
+			}
+		}
+	}
+	return nil
+}
+
+// overlaps returns true if the range [first, first+count) overlaps the range
+// [start, start+size).
+func overlaps(start, size uint32, first, count uint64) bool {
+	return count > 0 && first < uint64(start)+uint64(size) && uint64(start) < first+count
+}
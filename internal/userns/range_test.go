@@ -0,0 +1,123 @@
+package userns_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/containers/storage/pkg/idtools"
+	"github.com/cri-o/cri-o/internal/userns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("Range", func() {
+	t.Describe("ParseRange", func() {
+		It("should succeed with valid range", func() {
+			// Given
+			// When
+			start, size, err := userns.ParseRange("100000:65536")
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(start).To(BeEquivalentTo(100000))
+			Expect(size).To(BeEquivalentTo(65536))
+		})
+
+		DescribeTable("should fail with invalid range",
+			func(r string) {
+				// Given
+				// When
+				_, _, err := userns.ParseRange(r)
+
+				// Then
+				Expect(err).To(HaveOccurred())
+			},
+			Entry("missing size", "100000"),
+			Entry("invalid start", "a:65536"),
+			Entry("invalid size", "100000:-1"),
+			Entry("root ID", "0:65536"),
+			Entry("empty", "100000:0"),
+			Entry("overflow", "4294967295:2"),
+		)
+	})
+
+	t.Describe("CheckHostIDs", func() {
+		var passwd string
+
+		BeforeEach(func() {
+			passwd = filepath.Join(t.MustTempDir("userns"), "passwd")
+			Expect(os.WriteFile(passwd, []byte(
+				"root:x:0:0:root:/root:/bin/bash\n"+
+					"# comment\n"+
+					"nobody:x:65534:65534:Nobody:/:/sbin/nologin\n",
+			), 0o644)).To(Succeed())
+		})
+
+		It("should succeed if no ID is within the range", func() {
+			// Given
+			// When
+			err := userns.CheckHostIDs(100000, 65536, passwd, "/does/not/exist")
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail if an ID is within the range", func() {
+			// Given
+			// When
+			err := userns.CheckHostIDs(65534, 1, passwd)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("nobody"))
+		})
+	})
+
+	t.Describe("CheckSubIDs", func() {
+		var subuid string
+
+		BeforeEach(func() {
+			subuid = filepath.Join(t.MustTempDir("userns"), "subuid")
+			Expect(os.WriteFile(subuid, []byte(
+				"# comment\n"+
+					"containers:2147483647:2147483648\n"+
+					"user:100000:65536\n",
+			), 0o644)).To(Succeed())
+		})
+
+		It("should succeed if no range overlaps", func() {
+			// Given
+			// When
+			err := userns.CheckSubIDs(165536, 65536, subuid, "/does/not/exist")
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail if a range overlaps", func() {
+			// Given
+			// When
+			err := userns.CheckSubIDs(2000000000, 200000000, subuid)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("containers"))
+		})
+	})
+
+	t.Describe("CheckIDMaps", func() {
+		It("should fail if a mapping overlaps", func() {
+			// Given
+			maps := []idtools.IDMap{{ContainerID: 0, HostID: 100000, Size: 65536}}
+
+			// When
+			err := userns.CheckIDMaps(160000, 65536, "storage remapping", nil, maps)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("storage remapping"))
+			Expect(userns.CheckIDMaps(165536, 65536, "storage remapping", maps)).To(Succeed())
+		})
+	})
+})
@@ -25,10 +25,18 @@ static int bind_ns(const char *pin_path, const char *filename, const char *ns_na
 static int directory_exists_or_create(const char* path);
 
 static int write_mapping_file(pid_t pid, const char *mapping, bool is_gidmapping);
+static int write_time_offsets(pid_t pid, char **offsets, int offsets_count);
+static int join_cgroup(const char *cgroup_path);
+
+#ifndef CLONE_NEWTIME
+#define CLONE_NEWTIME 0x00000080
+#endif
 
 enum {
       UID_MAPPING = 1000,
       GID_MAPPING = 1001,
+      TIME_OFFSET = 1002,
+      CGROUP_PATH = 1003,
 };
 
 const char* const HOSTNS = "host";
@@ -43,14 +51,18 @@ int main(int argc, char **argv) {
   pid_t pid;
   char *pin_path = NULL;
   char *filename = NULL;
+  char *cgroup_path = NULL;
   bool bind_net = false;
   bool bind_uts = false;
   bool bind_ipc = false;
   bool bind_user = false;
   bool bind_cgroup = false;
+  bool bind_time = false;
   bool bind_mount = false;
   char **sysctls = NULL;
   int sysctls_count = 0;
+  char **time_offsets = NULL;
+  int time_offsets_count = 0;
   char res;
 
   static const struct option long_options[] = {
@@ -60,6 +72,9 @@ int main(int argc, char **argv) {
       {"net", optional_argument, NULL, 'n'},
       {"user", optional_argument, NULL, 'U'},
       {"cgroup", optional_argument, NULL, 'c'},
+      {"time", optional_argument, NULL, 't'},
+      {"time-offset", required_argument, NULL, TIME_OFFSET},
+      {"cgroup-path", required_argument, NULL, CGROUP_PATH},
       {"mnt", optional_argument, NULL, 'm'},
       {"dir", required_argument, NULL, 'd'},
       {"filename", required_argument, NULL, 'f'},
@@ -72,7 +87,11 @@ int main(int argc, char **argv) {
   if (UNLIKELY(sysctls == NULL))
       pexit("Failed to calloc");
 
-  while ((c = getopt_long(argc, argv, "mpchuUind:f:s:", long_options, NULL)) != -1) {
+  time_offsets = calloc(argc/2, sizeof(char *));
+  if (UNLIKELY(time_offsets == NULL))
+      pexit("Failed to calloc");
+
+  while ((c = getopt_long(argc, argv, "mpchtuUind:f:s:", long_options, NULL)) != -1) {
     switch (c) {
     case 'u':
       if (!is_host_ns (optarg))
@@ -108,6 +127,12 @@ int main(int argc, char **argv) {
       break;
 #endif
       pexit("unsharing cgroups is not supported by this pinns version");
+    case 't':
+      if (!is_host_ns (optarg))
+        unshare_flags |= CLONE_NEWTIME;
+      bind_time = true;
+      num_unshares++;
+      break;
     case 'm':
       if (!is_host_ns (optarg))
         unshare_flags |= CLONE_NEWNS;
@@ -130,6 +155,13 @@ int main(int argc, char **argv) {
     case GID_MAPPING:
       gid_mapping = optarg;
       break;
+    case TIME_OFFSET:
+      time_offsets[time_offsets_count] = optarg;
+      time_offsets_count++;
+      break;
+    case CGROUP_PATH:
+      cgroup_path = optarg;
+      break;
     case 'h':
       // usage();
     default:
@@ -158,6 +190,18 @@ int main(int argc, char **argv) {
     nexit("Creating new user namespace but mappings not specified");
   if (!bind_user && (uid_mapping != NULL || gid_mapping != NULL))
     nexit("Mappings specified without creating a new user namespace");
+  if (time_offsets_count != 0 && !(unshare_flags & CLONE_NEWTIME))
+    nexit("Time offsets specified without creating a new time namespace");
+#ifdef CLONE_NEWCGROUP
+  if (cgroup_path != NULL && !(unshare_flags & CLONE_NEWCGROUP))
+    nexit("Cgroup path specified without creating a new cgroup namespace");
+#endif
+
+  /* The cgroup of the process creating a cgroup namespace becomes its root,
+     so move into the requested cgroup before unsharing.  The forked child
+     inherits the cgroup.  */
+  if (cgroup_path != NULL && join_cgroup(cgroup_path) < 0)
+    pexitf("Failed to join cgroup %s", cgroup_path);
 
   if (!bind_user && !bind_mount) {
     /* Use pid=0 to indicate using the current process.  */
@@ -245,6 +289,11 @@ int main(int argc, char **argv) {
     close(p[0]);
   }
 
+  /* The offsets must be set before any process enters the time namespace.  */
+  if (time_offsets_count != 0 && write_time_offsets(pid, time_offsets, time_offsets_count) < 0) {
+    pexit("Failed to write time offsets");
+  }
+
   if (sysctls_count != 0 && configure_sysctls(sysctls, sysctls_count) < 0) {
     pexit("Failed to configure sysctls after unshare");
   }
@@ -279,6 +328,12 @@ int main(int argc, char **argv) {
     }
   }
 
+  if (bind_time) {
+    if (bind_ns(pin_path, filename, "time", pid) < 0) {
+      return EXIT_FAILURE;
+    }
+  }
+
   if (bind_mount) {
     const char *ns_name = "mnt";
     if (setup_unbindable_bindpath(pin_path, ns_name) < 0) {
@@ -331,6 +386,7 @@ static int bind_ns(const char *pin_path, const char *filename, const char *ns_na
   char bind_path[PATH_MAX];
   int bind_path_len;
   char ns_path[PATH_MAX];
+  const char *proc_ns_name = ns_name;
   int fd;
 
   // first, verify the /$PATH/$NSns directory exists
@@ -353,10 +409,14 @@ static int bind_ns(const char *pin_path, const char *filename, const char *ns_na
   }
   close(fd);
 
+  /* unsharing the time namespace only moves the children into it.  */
+  if (!strcmp(ns_name, "time"))
+    proc_ns_name = "time_for_children";
+
   if (pid > 0)
-    snprintf(ns_path, PATH_MAX - 1, "/proc/%d/ns/%s", pid, ns_name);
+    snprintf(ns_path, PATH_MAX - 1, "/proc/%d/ns/%s", pid, proc_ns_name);
   else
-    snprintf(ns_path, PATH_MAX - 1, "/proc/self/ns/%s", ns_name);
+    snprintf(ns_path, PATH_MAX - 1, "/proc/self/ns/%s", proc_ns_name);
 
   if (mount(ns_path, bind_path, NULL, MS_BIND, NULL) < 0) {
     pwarnf("Failed to bind mount ns: %s", ns_path);
@@ -415,6 +475,49 @@ static int write_mapping_file(pid_t pid, const char *mapping, bool is_gidmapping
   return close (fd);
 }
 
+/* Write the offsets in the format "<clock>:<seconds>:<nanoseconds>" to the
+ * time namespace of the process, one offset per clock.  */
+static int write_time_offsets(pid_t pid, char **offsets, int offsets_count) {
+  char path[64];
+  int fd, i;
+
+  if (pid > 0)
+    snprintf(path, sizeof(path), "/proc/%d/timens_offsets", pid);
+  else
+    snprintf(path, sizeof(path), "/proc/self/timens_offsets");
+
+  fd = open(path, O_WRONLY | O_CLOEXEC);
+  if (fd < 0)
+    return -1;
+
+  for (i = 0; i < offsets_count; i++) {
+    char *it, *content;
+    ssize_t content_size;
+
+    content = strdup(offsets[i]);
+    if (content == NULL) {
+      close (fd);
+      return -1;
+    }
+    for (it = content; *it; it++) {
+      if (*it == ':')
+        *it = ' ';
+    }
+    content_size = it - content;
+
+    if (write(fd, content, content_size) != content_size) {
+      int saved_errno = errno;
+      free (content);
+      close (fd);
+      errno = saved_errno;
+      return -1;
+    }
+    free (content);
+  }
+
+  return close (fd);
+}
+
 static int directory_exists_or_create(const char* path) {
   struct stat sb;
   if (stat(path, &sb) != 0) {
@@ -439,3 +542,26 @@ static int create_bind_root(char *bind_root, size_t size, const char *pin_path,
   return len;
 }
 
+static int join_cgroup(const char *cgroup_path) {
+  char path[PATH_MAX];
+  int fd;
+
+  if (snprintf(path, sizeof(path), "%s/cgroup.procs", cgroup_path) >= (int) sizeof(path)) {
+    errno = ENAMETOOLONG;
+    return -1;
+  }
+
+  fd = open(path, O_WRONLY | O_CLOEXEC);
+  if (fd < 0)
+    return -1;
+
+  /* Writing 0 moves the writing process.  */
+  if (write(fd, "0", 1) != 1) {
+    int saved_errno = errno;
+    close (fd);
+    errno = saved_errno;
+    return -1;
+  }
+
+  return close (fd);
+}
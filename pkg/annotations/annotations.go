@@ -8,6 +8,10 @@ const (
 	// UsernsMode is the user namespace mode to use
 	UsernsModeAnnotation = "io.kubernetes.cri-o.userns-mode"
 
+	// UsernsSizeAnnotation is the number of IDs of the user namespace range
+	// assigned to a pod using the "auto" user namespace mode.
+	UsernsSizeAnnotation = "io.kubernetes.cri-o.userns-size"
+
 	// CgroupRW specifies mounting v2 cgroups as an rw filesystem.
 	Cgroup2RWAnnotation = "io.kubernetes.cri-o.cgroup2-mount-hierarchy-rw"
 
@@ -48,25 +52,88 @@ const (
 	// SeccompNotifierActionAnnotation indicates a container is allowed to use the seccomp notifier feature.
 	SeccompNotifierActionAnnotation = "io.kubernetes.cri-o.seccompNotifierAction"
 
+	// SeccompProfileRecordAnnotation indicates that the syscalls of the pod containers should be recorded into a
+	// seccomp profile, which gets written on container exit.
+	SeccompProfileRecordAnnotation = "io.kubernetes.cri-o.seccompProfileRecord"
+
+	// SeccompProfileImageAnnotation specifies a container image reference, from which the seccomp profile of the pod
+	// containers gets pulled. It can be set for a single container by using
+	// "io.kubernetes.cri-o.seccompProfileImage.$CTR_NAME".
+	SeccompProfileImageAnnotation = "io.kubernetes.cri-o.seccompProfileImage"
+
+	// GenerateAppArmorProfileAnnotation indicates whether an AppArmor profile derived from the container spec should
+	// be generated for the pod containers which would otherwise use the default profile.
+	GenerateAppArmorProfileAnnotation = "io.kubernetes.cri-o.generateAppArmorProfile"
+
+	// SeccompProfileRecordPath is the path of the seccomp profile recorded for a container
+	SeccompProfileRecordPath = "io.kubernetes.cri-o.SeccompProfileRecordPath"
+
 	// UmaskAnnotation is the umask to use in the container init process
 	UmaskAnnotation = "io.kubernetes.cri-o.umask"
 
 	// SeccompNotifierActionStop indicates that a container should be stopped if used via the SeccompNotifierActionAnnotation key.
 	SeccompNotifierActionStop = "stop"
 
+	// SeccompNotifierActionLog indicates that blocked syscalls should only be reported if used via the
+	// SeccompNotifierActionAnnotation key. This is the default action.
+	SeccompNotifierActionLog = "log"
+
+	// SeccompNotifierActionKill indicates that the process using the syscall should be killed synchronously and its
+	// container should be stopped immediately if used via the SeccompNotifierActionAnnotation key.
+	SeccompNotifierActionKill = "kill"
+
+	// SeccompNotifierActionAudit indicates that syscalls should be allowed and audited if used via the
+	// SeccompNotifierActionAnnotation key.
+	SeccompNotifierActionAudit = "audit"
+
 	// PodLinuxOverhead indicates the overheads associated with the pod
 	PodLinuxOverhead = "io.kubernetes.cri-o.PodLinuxOverhead"
 
 	// PodLinuxResources indicates the sum of container resources for this pod
 	PodLinuxResources = "io.kubernetes.cri-o.PodLinuxResources"
 
+	// ContainerPorts contains the ports declared by the containers of the pod, including the ones without a host port
+	ContainerPorts = "io.kubernetes.cri-o.ContainerPorts"
+
+	// DNSConfig contains the effective DNS configuration of the pod and the changes done by the DNS policy
+	DNSConfig = "io.kubernetes.cri-o.DNSConfig"
+
 	// LinkLogsAnnotations indicates that CRI-O should link the pod containers logs into the specified
 	// emptyDir volume
 	LinkLogsAnnotation = "io.kubernetes.cri-o.LinkLogs"
+
+	// LogRateLimitAnnotation sets the lines and bytes per second the pod containers can log, in the format
+	// "lines=<lines>,bytes=<quantity>,burst=<seconds>". Lines exceeding the limit are counted and marked in the log.
+	LogRateLimitAnnotation = "io.kubernetes.cri-o.LogRateLimit"
+
+	// LogQuotaAnnotation limits the size of a pod container log file, which gets truncated once exceeded
+	LogQuotaAnnotation = "io.kubernetes.cri-o.LogQuota"
+
+	// NetworksAnnotation lists the CNI networks to attach to the pod in addition to the default network, as JSON
+	// list of objects with the keys "name", "interface", "ip" and "mac", for example '[{"name":"storage"}]'.
+	NetworksAnnotation = "io.kubernetes.cri-o.Networks"
+
+	// HostPortRangesAnnotation maps ranges of host ports to the pod, as comma separated list in the format
+	// "<host port>[-<last host port>][:<container port>][/<protocol>][@<host IP>]", for example "10000-10099/udp".
+	// The number of mapped ports is limited by the hostport_ranges_max_ports option.
+	HostPortRangesAnnotation = "io.kubernetes.cri-o.HostPortRanges"
+
+	// TimeNamespaceAnnotation creates a time namespace shared by the pod containers, with the clock offsets
+	// relative to the host in the format "<clock>=<duration>[,<clock>=<duration>]" for the clocks "monotonic"
+	// and "boottime", for example "boottime=24h". An empty value creates the namespace without offsets.
+	TimeNamespaceAnnotation = "io.kubernetes.cri-o.TimeNamespace"
+
+	// CgroupNamespaceAnnotation set to "pod" creates a cgroup namespace rooted at the pod cgroup, which is shared
+	// by the pod containers instead of creating one per container. It requires cgroup v2.
+	CgroupNamespaceAnnotation = "io.kubernetes.cri-o.CgroupNamespace"
+
+	// CgroupNamespacePod is the only value of the CgroupNamespaceAnnotation.
+	CgroupNamespacePod = "pod"
 )
 
 var AllAllowedAnnotations = []string{
 	UsernsModeAnnotation,
+	UsernsSizeAnnotation,
 	Cgroup2RWAnnotation,
 	UnifiedCgroupAnnotation,
 	ShmSizeAnnotation,
@@ -80,8 +147,17 @@ var AllAllowedAnnotations = []string{
 	CPUCStatesAnnotation,
 	CPUFreqGovernorAnnotation,
 	SeccompNotifierActionAnnotation,
+	SeccompProfileRecordAnnotation,
+	SeccompProfileImageAnnotation,
+	GenerateAppArmorProfileAnnotation,
 	UmaskAnnotation,
 	PodLinuxOverhead,
 	PodLinuxResources,
 	LinkLogsAnnotation,
+	LogRateLimitAnnotation,
+	LogQuotaAnnotation,
+	NetworksAnnotation,
+	HostPortRangesAnnotation,
+	TimeNamespaceAnnotation,
+	CgroupNamespaceAnnotation,
 }
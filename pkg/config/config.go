@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -19,6 +20,7 @@ import (
 	"github.com/containers/image/v5/types"
 	"github.com/containers/podman/v4/pkg/rootless"
 	"github.com/containers/storage"
+	"github.com/cri-o/cri-o/internal/config/admission"
 	"github.com/cri-o/cri-o/internal/config/apparmor"
 	"github.com/cri-o/cri-o/internal/config/blockio"
 	"github.com/cri-o/cri-o/internal/config/capabilities"
@@ -32,6 +34,9 @@ import (
 	"github.com/cri-o/cri-o/internal/config/rdt"
 	"github.com/cri-o/cri-o/internal/config/seccomp"
 	"github.com/cri-o/cri-o/internal/config/ulimits"
+	"github.com/cri-o/cri-o/internal/dnspolicy"
+	"github.com/cri-o/cri-o/internal/mcs"
+	"github.com/cri-o/cri-o/internal/userns"
 	"github.com/cri-o/cri-o/pkg/annotations"
 	"github.com/cri-o/cri-o/server/otel-collector/collectors"
 	"github.com/cri-o/cri-o/server/useragent"
@@ -59,6 +64,8 @@ const (
 	defaultMonitorCgroup       = "system.slice"
 	MonitorExecCgroupDefault   = ""
 	MonitorExecCgroupContainer = "container"
+	ExecSyncModeMonitor        = "monitor"
+	ExecSyncModeDirect         = "direct"
 )
 
 // Config represents the entire set of configuration values that can be set for
@@ -105,6 +112,18 @@ const (
 	DefaultPauseImage string = "registry.k8s.io/pause:3.9"
 )
 
+// BandwidthShapingType describes how the bandwidth of pods is limited
+type BandwidthShapingType string
+
+const (
+	// BandwidthShapingCNI option is for passing the bandwidth limits to the
+	// CNI plugins, which requires the bandwidth plugin to be chained
+	BandwidthShapingCNI BandwidthShapingType = "cni"
+	// BandwidthShapingNative option is for limiting the bandwidth by the
+	// traffic control of the host side veth peer of the pod interface
+	BandwidthShapingNative BandwidthShapingType = "native"
+)
+
 const (
 	// DefaultPidsLimit is the default value for maximum number of processes
 	// allowed inside a container
@@ -113,6 +132,31 @@ const (
 	// DefaultLogSizeMax is the default value for the maximum log size
 	// allowed for a container. Negative values mean that no limit is imposed.
 	DefaultLogSizeMax = -1
+
+	// DefaultSeccompProfileRecordDir is the default directory the recorded
+	// seccomp profiles get written to.
+	DefaultSeccompProfileRecordDir = "/var/lib/crio/seccomp-profiles"
+
+	// DefaultLogForwardBufferSize is the default value for the maximum number
+	// of log lines buffered while the log forward socket is unavailable.
+	DefaultLogForwardBufferSize = 8192
+
+	// DefaultAuditLogMaxSize is the default size in bytes at which the audit
+	// log file gets rotated.
+	DefaultAuditLogMaxSize = 100 * 1024 * 1024
+
+	// DefaultAuditLogMaxFiles is the default number of rotated audit log
+	// files to keep.
+	DefaultAuditLogMaxFiles = 5
+)
+
+const (
+	// LogForwardFormatJSON forwards every log line as a JSON object
+	// terminated by a newline.
+	LogForwardFormatJSON = "json"
+	// LogForwardFormatSyslog forwards every log line as RFC 5424 syslog
+	// message using octet counting framing.
+	LogForwardFormatSyslog = "syslog"
 )
 
 const (
@@ -194,11 +238,20 @@ type RuntimeHandler struct {
 	// AllowedAnnotations is a slice of experimental annotations that this runtime handler is allowed to process.
 	// The currently recognized values are:
 	// "io.kubernetes.cri-o.userns-mode" for configuring a user namespace for the pod.
+	// "io.kubernetes.cri-o.userns-size" for requesting the size of the user namespace assigned from userns_range.
 	// "io.kubernetes.cri-o.Devices" for configuring devices for the pod.
 	// "io.kubernetes.cri-o.ShmSize" for configuring the size of /dev/shm.
 	// "io.kubernetes.cri-o.UnifiedCgroup.$CTR_NAME" for configuring the cgroup v2 unified block for a container.
 	// "io.containers.trace-syscall" for tracing syscalls via the OCI seccomp BPF hook.
 	// "io.kubernetes.cri-o.LinkLogs" for linking logs into the pod.
+	// "io.kubernetes.cri-o.LogRateLimit" for counting the log lines of the pod containers exceeding a rate.
+	// "io.kubernetes.cri-o.LogQuota" for limiting the log file size of the pod containers.
+	// "io.kubernetes.cri-o.seccompProfileImage" for pulling the seccomp profile of the pod containers from a registry.
+	// "io.kubernetes.cri-o.generateAppArmorProfile" for generating an AppArmor profile for each pod container.
+	// "io.kubernetes.cri-o.Networks" for attaching additional CNI networks to the pod.
+	// "io.kubernetes.cri-o.HostPortRanges" for mapping ranges of host ports to the pod.
+	// "io.kubernetes.cri-o.TimeNamespace" for creating a time namespace for the pod.
+	// "io.kubernetes.cri-o.CgroupNamespace" for creating a cgroup namespace rooted at the pod cgroup and shared by the pod containers.
 	AllowedAnnotations []string `toml:"allowed_annotations,omitempty"`
 
 	// DisallowedAnnotations is the slice of experimental annotations that are not allowed for this handler.
@@ -213,6 +266,39 @@ type RuntimeHandler struct {
 
 	// MonitorExecCgroup indicates whether to move exec probes to the container's cgroup.
 	MonitorExecCgroup string `toml:"monitor_exec_cgroup,omitempty"`
+
+	// ExecSyncMode defines how exec sync requests, like exec probes, are run.
+	// "monitor" (the default) spawns the monitor for every request, while
+	// "direct" invokes the runtime without a monitor and reuses the prepared
+	// process specification across requests to lower the per request overhead.
+	// The runtime is still invoked once per request.
+	ExecSyncMode string `toml:"exec_sync_mode,omitempty"`
+
+	// The following fields override the respective default security settings
+	// of the runtime table for the containers of this handler, if set.
+	// DefaultCapabilities overrides default_capabilities. An empty list
+	// drops all default capabilities.
+	DefaultCapabilities capabilities.Capabilities `toml:"default_capabilities,omitempty"`
+	// SeccompProfile overrides seccomp_profile.
+	SeccompProfile string `toml:"seccomp_profile,omitempty"`
+	// ApparmorProfile overrides apparmor_profile.
+	ApparmorProfile string `toml:"apparmor_profile,omitempty"`
+	// DefaultSysctls overrides default_sysctls.
+	DefaultSysctls []string `toml:"default_sysctls,omitempty"`
+	// DefaultUlimits overrides default_ulimits.
+	DefaultUlimits []string `toml:"default_ulimits,omitempty"`
+	// DeniedCapabilities overrides denied_capabilities. An empty list allows
+	// all capabilities.
+	DeniedCapabilities capabilities.Capabilities `toml:"denied_capabilities,omitempty"`
+	// DeniedSysctls overrides denied_sysctls. An empty list allows all
+	// sysctls.
+	DeniedSysctls []string `toml:"denied_sysctls,omitempty"`
+
+	// seccompConfig, apparmorConfig and ulimitsConfig hold the loaded
+	// overrides and are nil if the global settings apply.
+	seccompConfig  *seccomp.Config
+	apparmorConfig *apparmor.Config
+	ulimitsConfig  *ulimits.Config
 }
 
 // Multiple runtime Handlers in a map
@@ -225,16 +311,59 @@ type RuntimeConfig struct {
 	// This option is currently deprecated, and will be replaced by the SeccompDefault FeatureGate in Kubernetes.
 	SeccompUseDefaultWhenEmpty bool `toml:"seccomp_use_default_when_empty"`
 
+	// SeccompProfileRecordDir is the directory the seccomp profiles recorded
+	// for containers get written to.
+	SeccompProfileRecordDir string `toml:"seccomp_profile_record_dir"`
+
 	// NoPivot instructs the runtime to not use `pivot_root`, but instead use `MS_MOVE`
 	NoPivot bool `toml:"no_pivot"`
 
 	// SELinux determines whether or not SELinux is used for pod separation.
 	SELinux bool `toml:"selinux"`
 
+	// SELinuxFixedMCS is a list of "<namespace>=<level>" entries fixing the
+	// MCS level of the pods within a namespace, unless the pod requests a
+	// level itself.
+	SELinuxFixedMCS []string `toml:"selinux_fixed_mcs"`
+
+	// SELinuxMCSReservationsFile is the path to the file the MCS levels
+	// reserved for pods are persisted in.
+	SELinuxMCSReservationsFile string `toml:"selinux_mcs_reservations_file"`
+
 	// Whether container output should be logged to journald in addition
 	// to the kubernetes log file
 	LogToJournald bool `toml:"log_to_journald"`
 
+	// LogForwardSocket is the path to the unix socket the container log
+	// lines get forwarded to in addition to the kubernetes log file. An
+	// empty path disables the log forwarding.
+	LogForwardSocket string `toml:"log_forward_socket"`
+
+	// LogForwardFormat is the format of the forwarded log lines, either
+	// "json" or "syslog".
+	LogForwardFormat string `toml:"log_forward_format"`
+
+	// LogForwardBufferSize is the maximum number of log lines buffered while
+	// the log forward socket is unavailable.
+	LogForwardBufferSize uint64 `toml:"log_forward_buffer_size"`
+
+	// AuditLogPath is the path to the file the audit log of exec, attach,
+	// port forward and privileged container creation requests, as well as of
+	// syscalls audited by the seccomp notifier, gets appended to. An empty
+	// path disables the audit log file.
+	AuditLogPath string `toml:"audit_log_path"`
+
+	// AuditLogMaxSize is the size in bytes at which the audit log file gets
+	// rotated. Zero disables the rotation.
+	AuditLogMaxSize int64 `toml:"audit_log_max_size"`
+
+	// AuditLogMaxFiles is the number of rotated audit log files to keep.
+	AuditLogMaxFiles uint64 `toml:"audit_log_max_files"`
+
+	// AuditLogSocket is the path to a unix socket the audit log gets written
+	// to. An empty path disables the audit log socket.
+	AuditLogSocket string `toml:"audit_log_socket"`
+
 	// DropInfraCtr determines whether the infra container is dropped when appropriate.
 	DropInfraCtr bool `toml:"drop_infra_ctr"`
 
@@ -269,6 +398,14 @@ type RuntimeConfig struct {
 	// Sysctls to add to all containers.
 	DefaultSysctls []string `toml:"default_sysctls"`
 
+	// DeniedCapabilities are the capabilities non-privileged containers are
+	// not allowed to add, regardless of the request.
+	DeniedCapabilities capabilities.Capabilities `toml:"denied_capabilities"`
+
+	// DeniedSysctls are the sysctls non-privileged pods are not allowed to
+	// set, regardless of the request.
+	DeniedSysctls []string `toml:"denied_sysctls"`
+
 	// DefaultUlimits specifies the default ulimits to apply to containers
 	DefaultUlimits []string `toml:"default_ulimits"`
 
@@ -308,10 +445,20 @@ type RuntimeConfig struct {
 	// default for the runtime.
 	ApparmorProfile string `toml:"apparmor_profile"`
 
+	// GenerateApparmorProfiles enables generating and loading an AppArmor
+	// profile derived from the spec of every container which would otherwise
+	// use the default profile.
+	GenerateApparmorProfiles bool `toml:"generate_apparmor_profiles"`
+
 	// BlockIOConfigFile is the path to the blockio class configuration
 	// file for configuring the cgroup blockio controller.
 	BlockIOConfigFile string `toml:"blockio_config_file"`
 
+	// AdmissionPolicyFile is the path to the node-local admission policy
+	// file, which gets evaluated for RunPodSandbox and CreateContainer
+	// requests.
+	AdmissionPolicyFile string `toml:"admission_policy_file"`
+
 	// IrqBalanceConfigFile is the irqbalance service config file which is used
 	// for configuring irqbalance daemon.
 	IrqBalanceConfigFile string `toml:"irqbalance_config_file"`
@@ -357,6 +504,16 @@ type RuntimeConfig struct {
 	// to us via CRI, for a pod that isn't to be run as UID 0.
 	MinimumMappableGID int64 `toml:"minimum_mappable_gid"`
 
+	// UsernsRange is the range of host IDs CRI-O assigns to the user
+	// namespaces of pods using the "auto" user namespace mode, in the
+	// format "<first ID>:<size>". If empty, the ranges get assigned by
+	// containers/storage instead.
+	UsernsRange string `toml:"userns_range"`
+
+	// UsernsAllocationsFile is the path to the file the user namespace
+	// ranges assigned to pods are persisted in.
+	UsernsAllocationsFile string `toml:"userns_allocations_file"`
+
 	// LogLevel determines the verbosity of the logs based on the level it is set to.
 	// Options are fatal, panic, error (default), warn, info, debug, and trace.
 	LogLevel string `toml:"log_level"`
@@ -372,6 +529,11 @@ type RuntimeConfig struct {
 	// to manage namespace lifecycle
 	PinnsPath string `toml:"pinns_path"`
 
+	// NamespacesGCPeriod is the number of seconds between removing the
+	// namespaces pinned within the NamespacesDir which do not belong to any
+	// known pod. If set to 0, they are only removed on startup.
+	NamespacesGCPeriod int `toml:"namespaces_gc_period"`
+
 	// CriuPath is the path to find the criu binary, which is needed
 	// to checkpoint and restore containers
 	EnableCriuSupport bool `toml:"enable_criu_support"`
@@ -413,6 +575,11 @@ type RuntimeConfig struct {
 	// EnablePodEvents specifies if the container pod-level events should be generated to optimize the PLEG at Kubelet.
 	EnablePodEvents bool `toml:"enable_pod_events"`
 
+	// ExecSyncConcurrencyLimit is the maximum number of exec sync requests
+	// running in parallel on the node. Exceeding requests wait until a slot
+	// gets available. A value of 0 disables the limit.
+	ExecSyncConcurrencyLimit uint64 `toml:"exec_sync_concurrency_limit"`
+
 	// IrqBalanceConfigRestoreFile is the irqbalance service banned CPU list to restore.
 	// If empty, no restoration attempt will be done.
 	IrqBalanceConfigRestoreFile string `toml:"irqbalance_config_restore_file"`
@@ -426,12 +593,18 @@ type RuntimeConfig struct {
 	// blockioConfig is the internal blockio configuration
 	blockioConfig *blockio.Config
 
+	// admissionConfig is the internal admission policy configuration
+	admissionConfig *admission.Config
+
 	// rdtConfig is the internal Rdt configuration
 	rdtConfig *rdt.Config
 
 	// ulimitConfig is the internal ulimit configuration
 	ulimitsConfig *ulimits.Config
 
+	// selinuxFixedMCS maps namespaces to their fixed MCS levels
+	selinuxFixedMCS map[string]string
+
 	// deviceConfig is the internal additional devices configuration
 	deviceConfig *device.Config
 
@@ -463,6 +636,12 @@ type ImageConfig struct {
 	// containing credentials necessary for pulling images from secure
 	// registries.
 	GlobalAuthFile string `toml:"global_auth_file"`
+	// NamespacedAuthDir is the root path for pod namespace-separated files
+	// containing credentials necessary for pulling seccomp profile images.
+	// The file used for a pod is <NAMESPACED_AUTH_DIR>/<NAMESPACE>.json,
+	// falling back to GlobalAuthFile if non existent. Must be an absolute
+	// path if set.
+	NamespacedAuthDir string `toml:"namespaced_auth_dir"`
 	// PauseImage is the name of an image which we use to instantiate infra
 	// containers.
 	PauseImage string `toml:"pause_image"`
@@ -517,6 +696,64 @@ type NetworkConfig struct {
 	// PluginDirs is where CNI plugin binaries are stored.
 	PluginDirs []string `toml:"plugin_dirs"`
 
+	// CNICheckPeriod is the number of seconds between running CNI CHECK on
+	// the networks of all running pods. If set to 0, the networks are not
+	// checked periodically.
+	CNICheckPeriod int `toml:"cni_check_period"`
+
+	// CNIGCPeriod is the number of seconds between releasing the CNI
+	// attachments which do not belong to any known pod. If set to 0, the
+	// attachments are only released for pods failing to be restored.
+	CNIGCPeriod int `toml:"cni_gc_period"`
+
+	// CNIAttachmentsDir is the directory the pods whose CNI attachments have
+	// been created by CRI-O are recorded in. Only their attachments are
+	// released as stale.
+	CNIAttachmentsDir string `toml:"cni_attachments_dir"`
+
+	// HostportReservationsFile is the path to the file the host ports
+	// reserved by pods are persisted in.
+	HostportReservationsFile string `toml:"hostport_reservations_file"`
+
+	// HostportRangesMaxPorts is the maximum number of host ports a pod can
+	// map by the io.kubernetes.cri-o.HostPortRanges annotation. If set to 0,
+	// the number is not limited.
+	HostportRangesMaxPorts int `toml:"hostport_ranges_max_ports"`
+
+	// BandwidthShaping is how the bandwidth limits requested by the
+	// kubernetes.io/ingress-bandwidth and kubernetes.io/egress-bandwidth pod
+	// annotations are applied.
+	BandwidthShaping BandwidthShapingType `toml:"bandwidth_shaping"`
+
+	// DNSOptions are the resolver options added to the resolv.conf of all
+	// pods which do not set an option of the same name.
+	DNSOptions []string `toml:"dns_options"`
+
+	// DNSNamespaceOptions is a list of "<namespace>=<option>" entries, which
+	// replace the resolver options of the same name of all pods within a
+	// namespace.
+	DNSNamespaceOptions []string `toml:"dns_namespace_options"`
+
+	// DNSLocalCache is the IP address of a node-local DNS cache, which is
+	// added as first nameserver of all pods having nameservers.
+	DNSLocalCache string `toml:"dns_local_cache"`
+
+	// DNSMaxNameservers is the maximum number of nameservers of a pod. If
+	// set to 0, the number is not limited.
+	DNSMaxNameservers int `toml:"dns_max_nameservers"`
+
+	// DNSMaxSearches is the maximum number of search domains of a pod. If
+	// set to 0, the number is not limited.
+	DNSMaxSearches int `toml:"dns_max_searches"`
+
+	// WatchPodIPs enables watching the addresses of the pod network
+	// interfaces, to update the pod IPs if they change after the network
+	// setup.
+	WatchPodIPs bool `toml:"watch_pod_ips"`
+
+	// dnsPolicy is the DNS policy built from the DNS options
+	dnsPolicy *dnspolicy.Policy
+
 	// cniManager manages the internal ocicni plugin
 	cniManager *cnimgr.CNIManager
 }
@@ -832,7 +1069,13 @@ func DefaultConfig() (*Config, error) {
 			ContainerAttachSocketDir:    conmonconfig.ContainerAttachSocketDir,
 			MinimumMappableUID:          -1,
 			MinimumMappableGID:          -1,
+			UsernsAllocationsFile:       "/var/lib/crio/userns-allocations.json",
+			SELinuxMCSReservationsFile:  "/var/lib/crio/selinux-mcs-reservations.json",
 			LogSizeMax:                  DefaultLogSizeMax,
+			LogForwardFormat:            LogForwardFormatJSON,
+			LogForwardBufferSize:        DefaultLogForwardBufferSize,
+			AuditLogMaxSize:             DefaultAuditLogMaxSize,
+			AuditLogMaxFiles:            DefaultAuditLogMaxFiles,
 			CtrStopTimeout:              defaultCtrStopTimeout,
 			DefaultCapabilities:         capabilities.Default(),
 			LogLevel:                    "info",
@@ -841,10 +1084,12 @@ func DefaultConfig() (*Config, error) {
 			NamespacesDir:               defaultNamespacesDir,
 			DropInfraCtr:                true,
 			SeccompUseDefaultWhenEmpty:  seccompConfig.UseDefaultWhenEmpty(),
+			SeccompProfileRecordDir:     DefaultSeccompProfileRecordDir,
 			IrqBalanceConfigRestoreFile: DefaultIrqBalanceConfigRestoreFile,
 			seccompConfig:               seccomp.New(),
 			apparmorConfig:              apparmor.New(),
 			blockioConfig:               blockio.New(),
+			admissionConfig:             admission.New(),
 			cgroupManager:               cgroupManager,
 			deviceConfig:                device.New(),
 			namespaceManager:            nsmgr.New(defaultNamespacesDir, ""),
@@ -861,8 +1106,14 @@ func DefaultConfig() (*Config, error) {
 			SignaturePolicyDir: "/etc/crio/policies",
 		},
 		NetworkConfig: NetworkConfig{
-			NetworkDir: cniConfigDir,
-			PluginDirs: []string{cniBinDir},
+			NetworkDir:               cniConfigDir,
+			PluginDirs:               []string{cniBinDir},
+			CNIAttachmentsDir:        "/var/lib/crio/cni-attachments",
+			HostportReservationsFile: "/var/lib/crio/hostport-reservations.json",
+			HostportRangesMaxPorts:   128,
+			BandwidthShaping:         BandwidthShapingCNI,
+			DNSMaxNameservers:        3,
+			DNSMaxSearches:           32,
 		},
 		MetricsConfig: MetricsConfig{
 			MetricsPort:       9090,
@@ -1025,6 +1276,26 @@ func (c *RuntimeConfig) Validate(systemContext *types.SystemContext, onExecution
 		return fmt.Errorf("log size max should be negative or >= %d", OCIBufSize)
 	}
 
+	if c.NamespacesGCPeriod < 0 {
+		return fmt.Errorf("namespaces_gc_period must not be negative: %d", c.NamespacesGCPeriod)
+	}
+
+	if err := c.ValidateLogForward(); err != nil {
+		return err
+	}
+
+	if err := c.ValidateAuditLog(); err != nil {
+		return err
+	}
+
+	if err := c.ValidateUsernsRange(); err != nil {
+		return err
+	}
+
+	if err := c.ValidateSELinuxMCS(); err != nil {
+		return err
+	}
+
 	// We need to ensure the container termination will be properly waited
 	// for by defining a minimal timeout value. This will prevent timeout
 	// value defined in the configuration file to be too low.
@@ -1041,6 +1312,18 @@ func (c *RuntimeConfig) Validate(systemContext *types.SystemContext, onExecution
 		return fmt.Errorf("invalid capabilities: %w", err)
 	}
 
+	if err := c.DeniedCapabilities.ValidateDenyList(); err != nil {
+		return fmt.Errorf("invalid denied_capabilities: %w", err)
+	}
+
+	if err := validateDeniedSysctls(c.DeniedSysctls); err != nil {
+		return fmt.Errorf("invalid denied_sysctls: %w", err)
+	}
+
+	if err := c.ValidateDeniedDefaults(); err != nil {
+		return err
+	}
+
 	if c.InfraCtrCPUSet != "" {
 		set, err := cpuset.Parse(c.InfraCtrCPUSet)
 		if err != nil {
@@ -1141,9 +1424,33 @@ func (c *RuntimeConfig) Validate(systemContext *types.SystemContext, onExecution
 			return fmt.Errorf("blockio configuration: %w", err)
 		}
 
+		if err := c.admissionConfig.Load(c.AdmissionPolicyFile); err != nil {
+			return fmt.Errorf("admission policy configuration: %w", err)
+		}
+
 		if err := c.rdtConfig.Load(c.RdtConfigFile); err != nil {
 			return fmt.Errorf("rdt configuration: %w", err)
 		}
+
+		if c.UsernsRange != "" {
+			start, size, err := userns.ParseRange(c.UsernsRange)
+			if err != nil {
+				return err
+			}
+			if err := userns.CheckHostIDs(start, size, "/etc/passwd", "/etc/group"); err != nil {
+				return fmt.Errorf("invalid userns_range: %w", err)
+			}
+			if err := userns.CheckSubIDs(start, size, "/etc/subuid", "/etc/subgid"); err != nil {
+				return fmt.Errorf("invalid userns_range: %w", err)
+			}
+			storeOpts, err := storage.DefaultStoreOptions(rootless.IsRootless(), rootless.GetRootlessUID())
+			if err != nil {
+				return fmt.Errorf("get storage options: %w", err)
+			}
+			if err := userns.CheckIDMaps(start, size, "storage remapping", storeOpts.UIDMap, storeOpts.GIDMap); err != nil {
+				return fmt.Errorf("invalid userns_range: %w", err)
+			}
+		}
 	}
 
 	if err := c.TranslateMonitorFields(onExecution); err != nil {
@@ -1261,6 +1568,75 @@ func (c *RuntimeConfig) TranslateMonitorFieldsForHandler(handler *RuntimeHandler
 	return nil
 }
 
+// ValidateLogForward checks if the log forwarding configuration is valid.
+func (c *RuntimeConfig) ValidateLogForward() error {
+	if c.LogForwardSocket == "" {
+		return nil
+	}
+	if !filepath.IsAbs(c.LogForwardSocket) {
+		return fmt.Errorf("log forward socket %q has to be an absolute path", c.LogForwardSocket)
+	}
+	switch c.LogForwardFormat {
+	case LogForwardFormatJSON, LogForwardFormatSyslog:
+	default:
+		return fmt.Errorf("invalid log forward format %q, has to be %q or %q", c.LogForwardFormat, LogForwardFormatJSON, LogForwardFormatSyslog)
+	}
+	if c.LogForwardBufferSize == 0 {
+		return errors.New("log forward buffer size has to be greater than zero")
+	}
+	return nil
+}
+
+// ValidateAuditLog checks if the audit log configuration is valid.
+func (c *RuntimeConfig) ValidateAuditLog() error {
+	if c.AuditLogPath != "" && !filepath.IsAbs(c.AuditLogPath) {
+		return fmt.Errorf("audit log path %q has to be an absolute path", c.AuditLogPath)
+	}
+	if c.AuditLogSocket != "" && !filepath.IsAbs(c.AuditLogSocket) {
+		return fmt.Errorf("audit log socket %q has to be an absolute path", c.AuditLogSocket)
+	}
+	if c.AuditLogMaxSize < 0 {
+		return fmt.Errorf("audit log max size %d must not be negative", c.AuditLogMaxSize)
+	}
+	return nil
+}
+
+// ValidateUsernsRange checks if the user namespace range is valid and does
+// not contain IDs below the minimum mappable UID and GID.
+func (c *RuntimeConfig) ValidateUsernsRange() error {
+	if c.UsernsRange == "" {
+		return nil
+	}
+	start, _, err := userns.ParseRange(c.UsernsRange)
+	if err != nil {
+		return err
+	}
+	if c.MinimumMappableUID >= 0 && int64(start) < c.MinimumMappableUID {
+		return fmt.Errorf("userns_range %q starts below minimum mappable UID %d", c.UsernsRange, c.MinimumMappableUID)
+	}
+	if c.MinimumMappableGID >= 0 && int64(start) < c.MinimumMappableGID {
+		return fmt.Errorf("userns_range %q starts below minimum mappable GID %d", c.UsernsRange, c.MinimumMappableGID)
+	}
+	if !filepath.IsAbs(c.UsernsAllocationsFile) {
+		return fmt.Errorf("userns_allocations_file %q has to be an absolute path", c.UsernsAllocationsFile)
+	}
+	return nil
+}
+
+// ValidateSELinuxMCS checks the fixed MCS levels and the path of the MCS
+// reservations file.
+func (c *RuntimeConfig) ValidateSELinuxMCS() error {
+	levels, err := mcs.ParseFixedLevels(c.SELinuxFixedMCS)
+	if err != nil {
+		return fmt.Errorf("invalid selinux_fixed_mcs: %w", err)
+	}
+	if !filepath.IsAbs(c.SELinuxMCSReservationsFile) {
+		return fmt.Errorf("selinux_mcs_reservations_file %q has to be an absolute path", c.SELinuxMCSReservationsFile)
+	}
+	c.selinuxFixedMCS = levels
+	return nil
+}
+
 // ValidateConmonPath checks if `Conmon` is set within the `RuntimeConfig`.
 // If this is not the case, it tries to find it within the $PATH variable.
 // In any other case, it simply checks if `Conmon` is a valid file.
@@ -1306,11 +1682,135 @@ func (c *RuntimeConfig) AppArmor() *apparmor.Config {
 	return c.apparmorConfig
 }
 
+// runtimeHandler returns the runtime handler of the provided name, or the
+// default runtime if the name is empty. It returns nil if the handler does not
+// exist.
+func (c *RuntimeConfig) runtimeHandler(name string) *RuntimeHandler {
+	if name == "" {
+		name = c.DefaultRuntime
+	}
+	return c.Runtimes[name]
+}
+
+// SeccompForRuntime returns the seccomp configuration for the containers of
+// the provided runtime handler, using its seccomp_profile if set.
+func (c *RuntimeConfig) SeccompForRuntime(handler string) *seccomp.Config {
+	if r := c.runtimeHandler(handler); r != nil && r.seccompConfig != nil {
+		return c.seccompConfig.WithProfile(r.seccompConfig.Profile())
+	}
+	return c.seccompConfig
+}
+
+// AppArmorForRuntime returns the AppArmor configuration for the containers of
+// the provided runtime handler, using its apparmor_profile if set.
+func (c *RuntimeConfig) AppArmorForRuntime(handler string) *apparmor.Config {
+	if r := c.runtimeHandler(handler); r != nil && r.apparmorConfig != nil {
+		return r.apparmorConfig
+	}
+	return c.apparmorConfig
+}
+
+// DefaultCapabilitiesForRuntime returns the default capabilities for the
+// containers of the provided runtime handler.
+func (c *RuntimeConfig) DefaultCapabilitiesForRuntime(handler string) capabilities.Capabilities {
+	if r := c.runtimeHandler(handler); r != nil && r.DefaultCapabilities != nil {
+		return r.DefaultCapabilities
+	}
+	return c.DefaultCapabilities
+}
+
+// DeniedCapabilitiesForRuntime returns the capabilities the non-privileged
+// containers of the provided runtime handler are not allowed to add.
+func (c *RuntimeConfig) DeniedCapabilitiesForRuntime(handler string) capabilities.Capabilities {
+	if r := c.runtimeHandler(handler); r != nil && r.DeniedCapabilities != nil {
+		return r.DeniedCapabilities
+	}
+	return c.DeniedCapabilities
+}
+
+// ValidateDeniedDefaults checks that neither the default capabilities nor the
+// default sysctls of the runtime table and of every runtime handler are part
+// of the respective deny list, because they would be granted to every
+// container regardless.
+func (c *RuntimeConfig) ValidateDeniedDefaults() error {
+	if err := validateDeniedDefaults(c.DefaultCapabilities, c.DeniedCapabilities, c.DefaultSysctls, c.DeniedSysctls); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(c.Runtimes))
+	for name := range c.Runtimes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r := c.Runtimes[name]
+		defaultCaps, deniedCaps := r.DefaultCapabilities, r.DeniedCapabilities
+		if defaultCaps == nil {
+			defaultCaps = c.DefaultCapabilities
+		}
+		if deniedCaps == nil {
+			deniedCaps = c.DeniedCapabilities
+		}
+		defaultSysctls, deniedSysctls := r.DefaultSysctls, r.DeniedSysctls
+		if defaultSysctls == nil {
+			defaultSysctls = c.DefaultSysctls
+		}
+		if deniedSysctls == nil {
+			deniedSysctls = c.DeniedSysctls
+		}
+		if err := validateDeniedDefaults(defaultCaps, deniedCaps, defaultSysctls, deniedSysctls); err != nil {
+			return fmt.Errorf("runtime %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func validateDeniedDefaults(defaultCaps, deniedCaps capabilities.Capabilities, defaultSysctls, deniedSysctls []string) error {
+	if denied := deniedCaps.Denied(defaultCaps); len(denied) > 0 {
+		return fmt.Errorf("default_capabilities contain denied capabilities: %s", strings.Join(denied, ", "))
+	}
+
+	sysctls, err := parseSysctls(defaultSysctls)
+	if err != nil {
+		return fmt.Errorf("invalid default_sysctls: %w", err)
+	}
+	denied := []string{}
+	for _, sysctl := range sysctls {
+		if IsSysctlDenied(deniedSysctls, sysctl.Key()) {
+			denied = append(denied, sysctl.Key())
+		}
+	}
+	if len(denied) > 0 {
+		return fmt.Errorf("default_sysctls contain denied sysctls: %s", strings.Join(denied, ", "))
+	}
+	return nil
+}
+
+// UlimitsForRuntime returns the default ulimits for the containers of the
+// provided runtime handler.
+func (c *RuntimeConfig) UlimitsForRuntime(handler string) []ulimits.Ulimit {
+	if r := c.runtimeHandler(handler); r != nil && r.ulimitsConfig != nil {
+		return r.ulimitsConfig.Ulimits()
+	}
+	return c.Ulimits()
+}
+
+// SELinuxFixedMCSForNamespace returns the MCS level fixed for the pods of the
+// provided namespace, or an empty string if there is none.
+func (c *RuntimeConfig) SELinuxFixedMCSForNamespace(namespace string) string {
+	return c.selinuxFixedMCS[namespace]
+}
+
 // BlockIO returns the blockio configuration
 func (c *RuntimeConfig) BlockIO() *blockio.Config {
 	return c.blockioConfig
 }
 
+// Admission returns the admission policy configuration
+func (c *RuntimeConfig) Admission() *admission.Config {
+	return c.admissionConfig
+}
+
 // Rdt returns the RDT configuration
 func (c *RuntimeConfig) Rdt() *rdt.Config {
 	return c.rdtConfig
@@ -1361,6 +1861,9 @@ func (c *ImageConfig) Validate(onExecution bool) error {
 	if !filepath.IsAbs(c.SignaturePolicyDir) {
 		return fmt.Errorf("signature policy dir %q is not absolute", c.SignaturePolicyDir)
 	}
+	if c.NamespacedAuthDir != "" && !filepath.IsAbs(c.NamespacedAuthDir) {
+		return fmt.Errorf("namespaced auth dir %q is not absolute", c.NamespacedAuthDir)
+	}
 	if onExecution {
 		if err := os.MkdirAll(c.SignaturePolicyDir, 0o755); err != nil {
 			return fmt.Errorf("cannot create signature policy dir: %w", err)
@@ -1374,6 +1877,30 @@ func (c *ImageConfig) Validate(onExecution bool) error {
 // execution checks. It returns an `error` on validation failure, otherwise
 // `nil`.
 func (c *NetworkConfig) Validate(onExecution bool) error {
+	if c.CNICheckPeriod < 0 {
+		return fmt.Errorf("cni_check_period must not be negative: %d", c.CNICheckPeriod)
+	}
+	if c.CNIGCPeriod < 0 {
+		return fmt.Errorf("cni_gc_period must not be negative: %d", c.CNIGCPeriod)
+	}
+	if !filepath.IsAbs(c.CNIAttachmentsDir) {
+		return fmt.Errorf("cni_attachments_dir %q has to be an absolute path", c.CNIAttachmentsDir)
+	}
+	if !filepath.IsAbs(c.HostportReservationsFile) {
+		return fmt.Errorf("hostport_reservations_file %q has to be an absolute path", c.HostportReservationsFile)
+	}
+	if c.HostportRangesMaxPorts < 0 {
+		return fmt.Errorf("hostport_ranges_max_ports must not be negative: %d", c.HostportRangesMaxPorts)
+	}
+	switch c.BandwidthShaping {
+	case BandwidthShapingCNI, BandwidthShapingNative:
+	default:
+		return fmt.Errorf("unrecognized bandwidth_shaping option %q", c.BandwidthShaping)
+	}
+	if err := c.ValidateDNSPolicy(); err != nil {
+		return err
+	}
+
 	if onExecution {
 		err := utils.IsDirectory(c.NetworkDir)
 		if err != nil {
@@ -1430,7 +1957,13 @@ func (r *RuntimeHandler) Validate(name string) error {
 	if err := r.ValidateRuntimeAllowedAnnotations(); err != nil {
 		return err
 	}
-	return r.ValidateRuntimeType(name)
+	if err := r.ValidateRuntimeType(name); err != nil {
+		return err
+	}
+	if err := r.ValidateExecSyncMode(name); err != nil {
+		return err
+	}
+	return r.ValidateSecurityDefaults(name)
 }
 
 func (r *RuntimeHandler) ValidateRuntimeVMBinaryPattern() bool {
@@ -1485,6 +2018,73 @@ func (r *RuntimeHandler) ValidateRuntimeType(name string) error {
 	return nil
 }
 
+// ValidateExecSyncMode checks if the `ExecSyncMode` is valid and supported by
+// the runtime type.
+func (r *RuntimeHandler) ValidateExecSyncMode(name string) error {
+	switch r.ExecSyncMode {
+	case "", ExecSyncModeMonitor:
+		return nil
+	case ExecSyncModeDirect:
+		if r.RuntimeType != "" && r.RuntimeType != DefaultRuntimeType {
+			return fmt.Errorf("exec_sync_mode %q can only be used with the %q runtime type for runtime %q",
+				r.ExecSyncMode, DefaultRuntimeType, name)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid `exec_sync_mode` %q for runtime %q", r.ExecSyncMode, name)
+}
+
+// ValidateSecurityDefaults checks and loads the default security settings
+// overriding the ones of the runtime table.
+func (r *RuntimeHandler) ValidateSecurityDefaults(name string) error {
+	if r.DefaultCapabilities != nil {
+		if err := r.DefaultCapabilities.Validate(); err != nil {
+			return fmt.Errorf("invalid default_capabilities for runtime %q: %w", name, err)
+		}
+	}
+
+	if _, err := parseSysctls(r.DefaultSysctls); err != nil {
+		return fmt.Errorf("invalid default_sysctls for runtime %q: %w", name, err)
+	}
+
+	if err := r.DeniedCapabilities.ValidateDenyList(); err != nil {
+		return fmt.Errorf("invalid denied_capabilities for runtime %q: %w", name, err)
+	}
+
+	if err := validateDeniedSysctls(r.DeniedSysctls); err != nil {
+		return fmt.Errorf("invalid denied_sysctls for runtime %q: %w", name, err)
+	}
+
+	r.ulimitsConfig = nil
+	if r.DefaultUlimits != nil {
+		ulimitsConfig := ulimits.New()
+		if err := ulimitsConfig.LoadUlimits(r.DefaultUlimits); err != nil {
+			return fmt.Errorf("invalid default_ulimits for runtime %q: %w", name, err)
+		}
+		r.ulimitsConfig = ulimitsConfig
+	}
+
+	r.seccompConfig = nil
+	if r.SeccompProfile != "" {
+		seccompConfig := seccomp.New()
+		if err := seccompConfig.LoadProfile(r.SeccompProfile); err != nil {
+			return fmt.Errorf("unable to load seccomp profile for runtime %q: %w", name, err)
+		}
+		r.seccompConfig = seccompConfig
+	}
+
+	r.apparmorConfig = nil
+	if r.ApparmorProfile != "" {
+		apparmorConfig := apparmor.New()
+		if err := apparmorConfig.LoadProfile(r.ApparmorProfile); err != nil {
+			return fmt.Errorf("unable to load AppArmor profile for runtime %q: %w", name, err)
+		}
+		r.apparmorConfig = apparmorConfig
+	}
+
+	return nil
+}
+
 // ValidateRuntimeConfigPath checks if the `RuntimeConfigPath` exists.
 func (r *RuntimeHandler) ValidateRuntimeConfigPath(name string) error {
 	if r.RuntimeConfigPath == "" {
@@ -1530,6 +2130,45 @@ func validateAllowedAndGenerateDisallowedAnnotations(allowed []string) (disallow
 	return disallowed, nil
 }
 
+// ValidateDNSPolicy checks the DNS options and builds the DNS policy of the
+// pods from them.
+func (c *NetworkConfig) ValidateDNSPolicy() error {
+	for _, option := range c.DNSOptions {
+		if err := dnspolicy.ValidateOption(option); err != nil {
+			return fmt.Errorf("invalid dns_options: %w", err)
+		}
+	}
+	namespaceOptions, err := dnspolicy.ParseNamespaceOptions(c.DNSNamespaceOptions)
+	if err != nil {
+		return fmt.Errorf("invalid dns_namespace_options: %w", err)
+	}
+	if c.DNSLocalCache != "" {
+		if err := dnspolicy.ValidateNameserver(c.DNSLocalCache); err != nil {
+			return fmt.Errorf("invalid dns_local_cache: %w", err)
+		}
+	}
+	if c.DNSMaxNameservers < 0 {
+		return fmt.Errorf("dns_max_nameservers must not be negative: %d", c.DNSMaxNameservers)
+	}
+	if c.DNSMaxSearches < 0 {
+		return fmt.Errorf("dns_max_searches must not be negative: %d", c.DNSMaxSearches)
+	}
+	c.dnsPolicy = &dnspolicy.Policy{
+		DefaultOptions:   c.DNSOptions,
+		NamespaceOptions: namespaceOptions,
+		LocalCache:       c.DNSLocalCache,
+		MaxNameservers:   c.DNSMaxNameservers,
+		MaxSearches:      c.DNSMaxSearches,
+	}
+	return nil
+}
+
+// DNSPolicy returns the DNS policy applied to the pods, which is nil if the
+// configuration has not been validated yet.
+func (c *NetworkConfig) DNSPolicy() *dnspolicy.Policy {
+	return c.dnsPolicy
+}
+
 // CNIPlugin returns the network configuration CNI plugin
 func (c *NetworkConfig) CNIPlugin() ocicni.CNIPlugin {
 	return c.cniManager.Plugin()
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"github.com/containers/storage"
+	"github.com/cri-o/cri-o/internal/config/capabilities"
 	crioann "github.com/cri-o/cri-o/pkg/annotations"
 	"github.com/cri-o/cri-o/pkg/config"
 	"github.com/cri-o/cri-o/utils/cmdrunner"
@@ -209,6 +210,84 @@ var _ = t.Describe("Config", func() {
 			Expect(err).To(BeNil())
 		})
 
+		It("should fail with invalid denied capabilities", func() {
+			// Given
+			sut.DeniedCapabilities = capabilities.Capabilities{"NOT_A_CAP"}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with invalid denied sysctls", func() {
+			// Given
+			sut.DeniedSysctls = []string{"net.ipv4.ip_forward=1"}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with denied default capabilities", func() {
+			// Given
+			sut.DefaultCapabilities = capabilities.Capabilities{"CHOWN", "NET_RAW"}
+			sut.DeniedCapabilities = capabilities.Capabilities{"NET_RAW"}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with denied default sysctls", func() {
+			// Given
+			sut.DefaultSysctls = []string{"net.ipv4.ip_forward=1"}
+			sut.DeniedSysctls = []string{"net.ipv4.*"}
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with denied default capabilities of a runtime handler", func() {
+			// Given
+			sut.DefaultCapabilities = capabilities.Capabilities{"CHOWN"}
+			sut.Runtimes["kata"] = &config.RuntimeHandler{
+				RuntimePath:         validFilePath,
+				DefaultCapabilities: capabilities.Capabilities{"SYS_ADMIN"},
+				DeniedCapabilities:  capabilities.Capabilities{"ALL"},
+			}
+
+			// When
+			err := sut.RuntimeConfig.ValidateDeniedDefaults()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should succeed if the runtime handler overrides the denied defaults", func() {
+			// Given
+			sut.DefaultSysctls = []string{"net.ipv4.ip_forward=1"}
+			sut.Runtimes["kata"] = &config.RuntimeHandler{
+				RuntimePath:    validFilePath,
+				DefaultSysctls: []string{},
+				DeniedSysctls:  []string{"net.*"},
+			}
+
+			// When
+			err := sut.RuntimeConfig.ValidateDeniedDefaults()
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
 		It("should succeed with additional devices", func() {
 			// Given
 			sut = runtimeValidConfig()
@@ -456,6 +535,17 @@ var _ = t.Describe("Config", func() {
 			Expect(err).NotTo(BeNil())
 		})
 
+		It("should fail on negative NamespacesGCPeriod", func() {
+			// Given
+			sut.RuntimeConfig.NamespacesGCPeriod = -1
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
 		It("should inherit from .Conmon even if bogus", func() {
 			// Given
 			sut.Conmon = invalidPath
@@ -740,6 +830,154 @@ var _ = t.Describe("Config", func() {
 			Expect(err).To(BeNil())
 		})
 
+		It("should succeed with positive reconcile periods", func() {
+			// Given
+			sut.NetworkConfig.CNICheckPeriod = 30
+			sut.NetworkConfig.CNIGCPeriod = 600
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail on negative CNICheckPeriod", func() {
+			// Given
+			sut.NetworkConfig.CNICheckPeriod = -1
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail on negative CNIGCPeriod", func() {
+			// Given
+			sut.NetworkConfig.CNIGCPeriod = -1
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail on relative CNIAttachmentsDir", func() {
+			// Given
+			sut.NetworkConfig.CNIAttachmentsDir = "cni-attachments"
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail on relative HostportReservationsFile", func() {
+			// Given
+			sut.NetworkConfig.HostportReservationsFile = "hostport-reservations.json"
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail on negative HostportRangesMaxPorts", func() {
+			// Given
+			sut.NetworkConfig.HostportRangesMaxPorts = -1
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should succeed with native BandwidthShaping", func() {
+			// Given
+			sut.NetworkConfig.BandwidthShaping = config.BandwidthShapingNative
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail on invalid BandwidthShaping", func() {
+			// Given
+			sut.NetworkConfig.BandwidthShaping = "tc"
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should succeed with DNS policy", func() {
+			// Given
+			sut.NetworkConfig.DNSOptions = []string{"ndots:2", "single-request-reopen"}
+			sut.NetworkConfig.DNSNamespaceOptions = []string{"team-a=ndots:1"}
+			sut.NetworkConfig.DNSLocalCache = "169.254.20.10"
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.NetworkConfig.DNSPolicy()).NotTo(BeNil())
+			Expect(sut.NetworkConfig.DNSPolicy().NamespaceOptions).To(HaveKey("team-a"))
+		})
+
+		It("should fail on invalid DNSOptions", func() {
+			// Given
+			sut.NetworkConfig.DNSOptions = []string{"ndots:2 timeout:1"}
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail on invalid DNSNamespaceOptions", func() {
+			// Given
+			sut.NetworkConfig.DNSNamespaceOptions = []string{"ndots:1"}
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail on invalid DNSLocalCache", func() {
+			// Given
+			sut.NetworkConfig.DNSLocalCache = "dns.local"
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail on negative DNSMaxNameservers", func() {
+			// Given
+			sut.NetworkConfig.DNSMaxNameservers = -1
+
+			// When
+			err := sut.NetworkConfig.Validate(false)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
 		It("should create the  NetworkDir", func() {
 			// Given
 			tmpDir := path.Join(os.TempDir(), invalidPath)
@@ -1312,4 +1550,449 @@ var _ = t.Describe("Config", func() {
 			Expect(err).To(BeNil())
 		})
 	})
+
+	t.Describe("ValidateExecSyncMode", func() {
+		It("should succeed without exec_sync_mode", func() {
+			// Given
+			handler := &config.RuntimeHandler{RuntimeType: config.DefaultRuntimeType}
+
+			// When
+			err := handler.ValidateExecSyncMode("runc")
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should succeed with direct exec_sync_mode and OCI runtime type", func() {
+			// Given
+			handler := &config.RuntimeHandler{
+				RuntimeType: config.DefaultRuntimeType, ExecSyncMode: config.ExecSyncModeDirect,
+			}
+
+			// When
+			err := handler.ValidateExecSyncMode("runc")
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail with direct exec_sync_mode and VM runtime type", func() {
+			// Given
+			handler := &config.RuntimeHandler{
+				RuntimeType: config.RuntimeTypeVM, ExecSyncMode: config.ExecSyncModeDirect,
+			}
+
+			// When
+			err := handler.ValidateExecSyncMode("kata")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with invalid exec_sync_mode", func() {
+			// Given
+			handler := &config.RuntimeHandler{ExecSyncMode: "invalid"}
+
+			// When
+			err := handler.ValidateExecSyncMode("runc")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	t.Describe("ValidateLogForward", func() {
+		It("should succeed without log forward socket", func() {
+			// Given
+			sut.LogForwardFormat = "invalid"
+
+			// When
+			err := sut.RuntimeConfig.ValidateLogForward()
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should succeed with syslog format", func() {
+			// Given
+			sut.LogForwardSocket = "/run/log-agent.sock"
+			sut.LogForwardFormat = config.LogForwardFormatSyslog
+
+			// When
+			err := sut.RuntimeConfig.ValidateLogForward()
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail with relative socket path", func() {
+			// Given
+			sut.LogForwardSocket = "log-agent.sock"
+
+			// When
+			err := sut.RuntimeConfig.ValidateLogForward()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with invalid format", func() {
+			// Given
+			sut.LogForwardSocket = "/run/log-agent.sock"
+			sut.LogForwardFormat = "invalid"
+
+			// When
+			err := sut.RuntimeConfig.ValidateLogForward()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail without buffer", func() {
+			// Given
+			sut.LogForwardSocket = "/run/log-agent.sock"
+			sut.LogForwardBufferSize = 0
+
+			// When
+			err := sut.RuntimeConfig.ValidateLogForward()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	t.Describe("ValidateAuditLog", func() {
+		It("should succeed with default config", func() {
+			// Given
+			// When
+			err := sut.RuntimeConfig.ValidateAuditLog()
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should succeed with file and socket", func() {
+			// Given
+			sut.AuditLogPath = "/var/log/crio/audit.log"
+			sut.AuditLogSocket = "/run/audit.sock"
+
+			// When
+			err := sut.RuntimeConfig.ValidateAuditLog()
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail with relative path", func() {
+			// Given
+			sut.AuditLogPath = "audit.log"
+
+			// When
+			err := sut.RuntimeConfig.ValidateAuditLog()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with relative socket path", func() {
+			// Given
+			sut.AuditLogSocket = "audit.sock"
+
+			// When
+			err := sut.RuntimeConfig.ValidateAuditLog()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with negative max size", func() {
+			// Given
+			sut.AuditLogPath = "/var/log/crio/audit.log"
+			sut.AuditLogMaxSize = -1
+
+			// When
+			err := sut.RuntimeConfig.ValidateAuditLog()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	t.Describe("ValidateUsernsRange", func() {
+		It("should succeed without range", func() {
+			// Given
+			// When
+			err := sut.RuntimeConfig.ValidateUsernsRange()
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should succeed with valid range", func() {
+			// Given
+			sut.UsernsRange = "1000000:1000000"
+			sut.MinimumMappableUID = 1000
+			sut.MinimumMappableGID = 1000
+
+			// When
+			err := sut.RuntimeConfig.ValidateUsernsRange()
+
+			// Then
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail with invalid range", func() {
+			// Given
+			sut.UsernsRange = "1000000"
+
+			// When
+			err := sut.RuntimeConfig.ValidateUsernsRange()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with range below minimum mappable GID", func() {
+			// Given
+			sut.UsernsRange = "1000:1000000"
+			sut.MinimumMappableGID = 100000
+
+			// When
+			err := sut.RuntimeConfig.ValidateUsernsRange()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with relative allocations file", func() {
+			// Given
+			sut.UsernsRange = "1000000:1000000"
+			sut.UsernsAllocationsFile = "userns.json"
+
+			// When
+			err := sut.RuntimeConfig.ValidateUsernsRange()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	t.Describe("ValidateSELinuxMCS", func() {
+		It("should succeed without fixed levels", func() {
+			// Given
+			// When
+			err := sut.RuntimeConfig.ValidateSELinuxMCS()
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.SELinuxFixedMCSForNamespace("default")).To(BeEmpty())
+		})
+
+		It("should succeed with fixed levels", func() {
+			// Given
+			sut.SELinuxFixedMCS = []string{"kube-system=s0:c1,c2", "monitoring=s0:c3"}
+
+			// When
+			err := sut.RuntimeConfig.ValidateSELinuxMCS()
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.SELinuxFixedMCSForNamespace("kube-system")).To(Equal("s0:c1,c2"))
+			Expect(sut.SELinuxFixedMCSForNamespace("default")).To(BeEmpty())
+		})
+
+		It("should fail with invalid fixed level", func() {
+			// Given
+			sut.SELinuxFixedMCS = []string{"kube-system=c1,c2"}
+
+			// When
+			err := sut.RuntimeConfig.ValidateSELinuxMCS()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with relative reservations file", func() {
+			// Given
+			sut.SELinuxMCSReservationsFile = "mcs.json"
+
+			// When
+			err := sut.RuntimeConfig.ValidateSELinuxMCS()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	t.Describe("ValidateSecurityDefaults", func() {
+		It("should succeed without overrides", func() {
+			// Given
+			sut.Runtimes["runc"] = &config.RuntimeHandler{
+				RuntimePath: validFilePath,
+			}
+
+			// When
+			err := sut.RuntimeConfig.ValidateRuntimes()
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.DefaultCapabilitiesForRuntime("runc")).To(Equal(sut.DefaultCapabilities))
+			Expect(sut.UlimitsForRuntime("runc")).To(Equal(sut.Ulimits()))
+			Expect(sut.SeccompForRuntime("runc")).To(Equal(sut.Seccomp()))
+			Expect(sut.AppArmorForRuntime("runc")).To(Equal(sut.AppArmor()))
+		})
+
+		It("should use the overrides of the runtime handler", func() {
+			// Given
+			sut.Runtimes["kata"] = &config.RuntimeHandler{
+				RuntimePath:         validFilePath,
+				DefaultCapabilities: capabilities.Capabilities{"CHOWN", "NET_RAW"},
+				DefaultSysctls:      []string{"net.ipv4.ping_group_range=0 2147483647"},
+				DefaultUlimits:      []string{"nofile=1024:2048"},
+			}
+
+			// When
+			err := sut.Runtimes["kata"].ValidateSecurityDefaults("kata")
+			sysctls, sysctlsErr := sut.SysctlsForRuntime("kata")
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sysctlsErr).To(BeNil())
+			Expect(sysctls).To(HaveLen(1))
+			Expect(sysctls[0].Key()).To(Equal("net.ipv4.ping_group_range"))
+			Expect(sut.DefaultCapabilitiesForRuntime("kata")).To(HaveLen(2))
+			Expect(sut.UlimitsForRuntime("kata")).To(HaveLen(1))
+			Expect(sut.UlimitsForRuntime("kata")[0].Name).To(Equal("RLIMIT_NOFILE"))
+			Expect(sut.DefaultCapabilitiesForRuntime("")).To(Equal(sut.DefaultCapabilities))
+		})
+
+		It("should drop all default capabilities with an empty override", func() {
+			// Given
+			sut.Runtimes["kata"] = &config.RuntimeHandler{
+				RuntimePath:         validFilePath,
+				DefaultCapabilities: capabilities.Capabilities{},
+			}
+
+			// When
+			err := sut.Runtimes["kata"].ValidateSecurityDefaults("kata")
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.DefaultCapabilitiesForRuntime("kata")).To(BeEmpty())
+		})
+
+		It("should use the seccomp profile of the runtime handler", func() {
+			// Given
+			profile := t.MustTempFile("seccomp.json")
+			Expect(os.WriteFile(profile, []byte(`{"defaultAction": "SCMP_ACT_LOG"}`), 0o644)).To(BeNil())
+			sut.Runtimes["kata"] = &config.RuntimeHandler{
+				RuntimePath:    validFilePath,
+				SeccompProfile: profile,
+			}
+
+			// When
+			err := sut.Runtimes["kata"].ValidateSecurityDefaults("kata")
+
+			// Then
+			Expect(err).To(BeNil())
+			if !sut.Seccomp().IsDisabled() {
+				Expect(sut.SeccompForRuntime("kata").Profile().DefaultAction).To(BeEquivalentTo("SCMP_ACT_LOG"))
+				Expect(sut.SeccompForRuntime("kata").NotifierPath()).To(Equal(sut.Seccomp().NotifierPath()))
+				Expect(sut.Seccomp().Profile().DefaultAction).NotTo(BeEquivalentTo("SCMP_ACT_LOG"))
+			}
+		})
+
+		It("should fail with invalid sysctls", func() {
+			// Given
+			sut.Runtimes["runc"] = &config.RuntimeHandler{
+				RuntimePath:    validFilePath,
+				DefaultSysctls: []string{"net.ipv4.ping_group_range"},
+			}
+
+			// When
+			err := sut.RuntimeConfig.ValidateRuntimes()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with invalid ulimits", func() {
+			// Given
+			sut.Runtimes["runc"] = &config.RuntimeHandler{
+				RuntimePath:    validFilePath,
+				DefaultUlimits: []string{"invalid=1:2"},
+			}
+
+			// When
+			err := sut.RuntimeConfig.ValidateRuntimes()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should use the deny lists of the runtime handler", func() {
+			// Given
+			sut.DeniedCapabilities = capabilities.Capabilities{"SYS_ADMIN"}
+			sut.Runtimes["kata"] = &config.RuntimeHandler{
+				RuntimePath:        validFilePath,
+				DeniedCapabilities: capabilities.Capabilities{"ALL"},
+				DeniedSysctls:      []string{"net.*"},
+			}
+
+			// When
+			err := sut.Runtimes["kata"].ValidateSecurityDefaults("kata")
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.DeniedCapabilitiesForRuntime("kata")).To(Equal(capabilities.Capabilities{"ALL"}))
+			Expect(sut.DeniedCapabilitiesForRuntime("")).To(Equal(sut.DeniedCapabilities))
+			Expect(sut.DeniedSysctlsForRuntime("kata")).To(Equal([]string{"net.*"}))
+		})
+
+		It("should fail with invalid denied sysctls", func() {
+			// Given
+			sut.Runtimes["kata"] = &config.RuntimeHandler{
+				RuntimePath:   validFilePath,
+				DeniedSysctls: []string{"net.*.forwarding"},
+			}
+
+			// When
+			err := sut.Runtimes["kata"].ValidateSecurityDefaults("kata")
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with invalid capabilities", func() {
+			// Given
+			sut.Runtimes["runc"] = &config.RuntimeHandler{
+				RuntimePath:         validFilePath,
+				DefaultCapabilities: capabilities.Capabilities{"NOT_A_CAP"},
+			}
+
+			// When
+			err := sut.RuntimeConfig.ValidateRuntimes()
+
+			// Then
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail with a not existing seccomp profile", func() {
+			// Given
+			sut.Runtimes["runc"] = &config.RuntimeHandler{
+				RuntimePath:    validFilePath,
+				SeccompProfile: invalidPath,
+			}
+
+			// When
+			err := sut.RuntimeConfig.ValidateRuntimes()
+
+			// Then
+			if sut.Seccomp().IsDisabled() {
+				Expect(err).To(BeNil())
+			} else {
+				Expect(err).NotTo(BeNil())
+			}
+		})
+	})
 })
@@ -67,6 +67,9 @@ func (c *Config) Reload() error {
 	if err := c.ReloadRdtConfig(newConfig); err != nil {
 		return err
 	}
+	if err := c.ReloadAdmissionPolicy(newConfig); err != nil {
+		return err
+	}
 	if err := c.ReloadRuntimes(newConfig); err != nil {
 		return err
 	}
@@ -234,6 +237,19 @@ func (c *Config) ReloadRdtConfig(newConfig *Config) error {
 	return nil
 }
 
+// ReloadAdmissionPolicy reloads the admission policy. The policy file gets
+// read again even if its path did not change, to apply updated rules.
+func (c *Config) ReloadAdmissionPolicy(newConfig *Config) error {
+	if err := c.Admission().Load(newConfig.AdmissionPolicyFile); err != nil {
+		return fmt.Errorf("unable to reload admission_policy_file: %w", err)
+	}
+	if c.AdmissionPolicyFile != newConfig.AdmissionPolicyFile {
+		c.AdmissionPolicyFile = newConfig.AdmissionPolicyFile
+		logConfig("admission_policy_file", c.AdmissionPolicyFile)
+	}
+	return nil
+}
+
 // ReloadRuntimes reloads the runtimes configuration if changed
 func (c *Config) ReloadRuntimes(newConfig *Config) error {
 	var updated bool
@@ -296,6 +296,50 @@ var _ = t.Describe("Config", func() {
 		})
 	})
 
+	t.Describe("ReloadAdmissionPolicy", func() {
+		It("should succeed without any config change", func() {
+			// Given
+			// When
+			err := sut.ReloadAdmissionPolicy(sut)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.Admission().Enabled()).To(BeFalse())
+		})
+
+		It("should succeed with config change", func() {
+			// Given
+			filePath := t.MustTempFile("admission")
+			Expect(os.WriteFile(filePath, []byte(
+				"rules:\n- name: deny-all\n  action: deny\n",
+			), 0o644)).To(BeNil())
+
+			newConfig := defaultConfig()
+			newConfig.AdmissionPolicyFile = filePath
+
+			// When
+			err := sut.ReloadAdmissionPolicy(newConfig)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(sut.AdmissionPolicyFile).To(Equal(filePath))
+			Expect(sut.Admission().Enabled()).To(BeTrue())
+		})
+
+		It("should fail with invalid admission_policy_file path", func() {
+			// Given
+			newConfig := defaultConfig()
+			newConfig.AdmissionPolicyFile = invalidPath
+
+			// When
+			err := sut.ReloadAdmissionPolicy(newConfig)
+
+			// Then
+			Expect(err).NotTo(BeNil())
+			Expect(sut.AdmissionPolicyFile).To(BeEmpty())
+		})
+	})
+
 	t.Describe("ReloadAppArmorProfile", func() {
 		BeforeEach(func() {
 			if !apparmor.IsEnabled() {
@@ -27,8 +27,59 @@ func (s *Sysctl) Value() string {
 // Sysctls returns the parsed sysctl slice and an error if not parsable
 // Some validation based on https://github.com/containers/common/blob/main/pkg/sysctl/sysctl.go
 func (c *RuntimeConfig) Sysctls() ([]Sysctl, error) {
-	sysctls := make([]Sysctl, 0, len(c.DefaultSysctls))
-	for _, sysctl := range c.DefaultSysctls {
+	return parseSysctls(c.DefaultSysctls)
+}
+
+// SysctlsForRuntime returns the parsed default sysctls of the provided runtime
+// handler, or the global ones if the handler does not override them.
+func (c *RuntimeConfig) SysctlsForRuntime(handler string) ([]Sysctl, error) {
+	if r := c.runtimeHandler(handler); r != nil && r.DefaultSysctls != nil {
+		return parseSysctls(r.DefaultSysctls)
+	}
+	return c.Sysctls()
+}
+
+// DeniedSysctlsForRuntime returns the sysctl deny list of the provided
+// runtime handler, or the global one if the handler does not override it.
+func (c *RuntimeConfig) DeniedSysctlsForRuntime(handler string) []string {
+	if r := c.runtimeHandler(handler); r != nil && r.DeniedSysctls != nil {
+		return r.DeniedSysctls
+	}
+	return c.DeniedSysctls
+}
+
+// IsSysctlDenied returns true if the provided sysctl key matches any entry of
+// the deny list. Entries ending with "*" match all keys with the same prefix,
+// while "/" and "." are equivalent separators.
+func IsSysctlDenied(denied []string, key string) bool {
+	key = strings.ReplaceAll(key, "/", ".")
+	for _, entry := range denied {
+		entry = strings.ReplaceAll(entry, "/", ".")
+		if prefix, ok := strings.CutSuffix(entry, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// validateDeniedSysctls checks that the entries of a sysctl deny list are
+// either keys or prefixes ending with "*".
+func validateDeniedSysctls(denied []string) error {
+	for _, entry := range denied {
+		if entry == "" || strings.ContainsAny(entry, " =") || strings.Contains(strings.TrimSuffix(entry, "*"), "*") {
+			return fmt.Errorf("%q is not a sysctl key or prefix ending with \"*\"", entry)
+		}
+	}
+	return nil
+}
+
+func parseSysctls(defaultSysctls []string) ([]Sysctl, error) {
+	sysctls := make([]Sysctl, 0, len(defaultSysctls))
+	for _, sysctl := range defaultSysctls {
 		// skip empty values for sake of backwards compatibility
 		if sysctl == "" {
 			continue
@@ -1,6 +1,7 @@
 package config_test
 
 import (
+	"github.com/cri-o/cri-o/pkg/config"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -156,4 +157,30 @@ var _ = t.Describe("Sysctl", func() {
 		// Then
 		Expect(err).NotTo(BeNil())
 	})
+
+	It("should match denied sysctls by key and prefix", func() {
+		// Given
+		denied := []string{"net.ipv4.ip_forward", "net.ipv4.conf.*", "kernel/shm*"}
+
+		// When
+		// Then
+		Expect(config.IsSysctlDenied(denied, "net.ipv4.ip_forward")).To(BeTrue())
+		Expect(config.IsSysctlDenied(denied, "net/ipv4/ip_forward")).To(BeTrue())
+		Expect(config.IsSysctlDenied(denied, "net.ipv4.conf.all.forwarding")).To(BeTrue())
+		Expect(config.IsSysctlDenied(denied, "kernel.shmmax")).To(BeTrue())
+		Expect(config.IsSysctlDenied(denied, "net.ipv4.ip_forward_use_pmtu")).To(BeFalse())
+		Expect(config.IsSysctlDenied(denied, "net.ipv4.ping_group_range")).To(BeFalse())
+		Expect(config.IsSysctlDenied(nil, "net.ipv4.ip_forward")).To(BeFalse())
+	})
+
+	It("should use the sysctl deny list of the runtime handler", func() {
+		// Given
+		sut.DeniedSysctls = []string{"net.ipv4.ip_forward"}
+		sut.Runtimes["kata"] = &config.RuntimeHandler{DeniedSysctls: []string{}}
+
+		// When
+		// Then
+		Expect(sut.DeniedSysctlsForRuntime("kata")).To(BeEmpty())
+		Expect(sut.DeniedSysctlsForRuntime("")).To(Equal(sut.DeniedSysctls))
+	})
 })
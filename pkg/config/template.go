@@ -240,6 +240,16 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.SELinux, c.SELinux),
 		},
+		{
+			templateString: templateStringCrioRuntimeSELinuxFixedMCS,
+			group:          crioRuntimeConfig,
+			isDefaultValue: stringSliceEqual(dc.SELinuxFixedMCS, c.SELinuxFixedMCS),
+		},
+		{
+			templateString: templateStringCrioRuntimeSELinuxMCSReservationsFile,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.SELinuxMCSReservationsFile, c.SELinuxMCSReservationsFile),
+		},
 		{
 			templateString: templateStringCrioRuntimeSeccompProfile,
 			group:          crioRuntimeConfig,
@@ -250,16 +260,31 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.SeccompUseDefaultWhenEmpty, c.SeccompUseDefaultWhenEmpty),
 		},
+		{
+			templateString: templateStringCrioRuntimeSeccompProfileRecordDir,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.SeccompProfileRecordDir, c.SeccompProfileRecordDir),
+		},
 		{
 			templateString: templateStringCrioRuntimeApparmorProfile,
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.ApparmorProfile, c.ApparmorProfile),
 		},
+		{
+			templateString: templateStringCrioRuntimeGenerateApparmorProfiles,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.GenerateApparmorProfiles, c.GenerateApparmorProfiles),
+		},
 		{
 			templateString: templateStringCrioRuntimeBlockIOConfigFile,
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.BlockIOConfigFile, c.BlockIOConfigFile),
 		},
+		{
+			templateString: templateStringCrioRuntimeAdmissionPolicyFile,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.AdmissionPolicyFile, c.AdmissionPolicyFile),
+		},
 		{
 			templateString: templateStringCrioRuntimeIrqBalanceConfigFile,
 			group:          crioRuntimeConfig,
@@ -300,6 +325,16 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: stringSliceEqual(dc.DefaultSysctls, c.DefaultSysctls),
 		},
+		{
+			templateString: templateStringCrioRuntimeDeniedCapabilities,
+			group:          crioRuntimeConfig,
+			isDefaultValue: stringSliceEqual(dc.DeniedCapabilities, c.DeniedCapabilities),
+		},
+		{
+			templateString: templateStringCrioRuntimeDeniedSysctls,
+			group:          crioRuntimeConfig,
+			isDefaultValue: stringSliceEqual(dc.DeniedSysctls, c.DeniedSysctls),
+		},
 		{
 			templateString: templateStringCrioRuntimeAllowedDevices,
 			group:          crioRuntimeConfig,
@@ -345,6 +380,41 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.LogToJournald, c.LogToJournald),
 		},
+		{
+			templateString: templateStringCrioRuntimeLogForwardSocket,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.LogForwardSocket, c.LogForwardSocket),
+		},
+		{
+			templateString: templateStringCrioRuntimeLogForwardFormat,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.LogForwardFormat, c.LogForwardFormat),
+		},
+		{
+			templateString: templateStringCrioRuntimeLogForwardBufferSize,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.LogForwardBufferSize, c.LogForwardBufferSize),
+		},
+		{
+			templateString: templateStringCrioRuntimeAuditLogPath,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.AuditLogPath, c.AuditLogPath),
+		},
+		{
+			templateString: templateStringCrioRuntimeAuditLogMaxSize,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.AuditLogMaxSize, c.AuditLogMaxSize),
+		},
+		{
+			templateString: templateStringCrioRuntimeAuditLogMaxFiles,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.AuditLogMaxFiles, c.AuditLogMaxFiles),
+		},
+		{
+			templateString: templateStringCrioRuntimeAuditLogSocket,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.AuditLogSocket, c.AuditLogSocket),
+		},
 		{
 			templateString: templateStringCrioRuntimeContainerExitsDir,
 			group:          crioRuntimeConfig,
@@ -395,6 +465,16 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.MinimumMappableGID, c.MinimumMappableGID),
 		},
+		{
+			templateString: templateStringCrioRuntimeUsernsRange,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.UsernsRange, c.UsernsRange),
+		},
+		{
+			templateString: templateStringCrioRuntimeUsernsAllocationsFile,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.UsernsAllocationsFile, c.UsernsAllocationsFile),
+		},
 		{
 			templateString: templateStringCrioRuntimeCtrStopTimeout,
 			group:          crioRuntimeConfig,
@@ -420,6 +500,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.PinnsPath, c.PinnsPath),
 		},
+		{
+			templateString: templateStringCrioRuntimeNamespacesGCPeriod,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.NamespacesGCPeriod, c.NamespacesGCPeriod),
+		},
 		{
 			templateString: templateStringCrioRuntimeEnableCriuSupport,
 			group:          crioRuntimeConfig,
@@ -430,6 +515,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.EnablePodEvents, c.EnablePodEvents),
 		},
+		{
+			templateString: templateStringCrioRuntimeExecSyncConcurrencyLimit,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.ExecSyncConcurrencyLimit, c.ExecSyncConcurrencyLimit),
+		},
 		{
 			templateString: templateStringCrioRuntimeDefaultRuntime,
 			group:          crioRuntimeConfig,
@@ -470,6 +560,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioImageConfig,
 			isDefaultValue: simpleEqual(dc.GlobalAuthFile, c.GlobalAuthFile),
 		},
+		{
+			templateString: templateStringCrioImageNamespacedAuthDir,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.NamespacedAuthDir, c.NamespacedAuthDir),
+		},
 		{
 			templateString: templateStringCrioImagePauseImage,
 			group:          crioImageConfig,
@@ -530,6 +625,66 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioNetworkConfig,
 			isDefaultValue: stringSliceEqual(dc.PluginDirs, c.PluginDirs),
 		},
+		{
+			templateString: templateStringCrioNetworkCNICheckPeriod,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.CNICheckPeriod, c.CNICheckPeriod),
+		},
+		{
+			templateString: templateStringCrioNetworkCNIGCPeriod,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.CNIGCPeriod, c.CNIGCPeriod),
+		},
+		{
+			templateString: templateStringCrioNetworkCNIAttachmentsDir,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.CNIAttachmentsDir, c.CNIAttachmentsDir),
+		},
+		{
+			templateString: templateStringCrioNetworkHostportReservationsFile,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.HostportReservationsFile, c.HostportReservationsFile),
+		},
+		{
+			templateString: templateStringCrioNetworkHostportRangesMaxPorts,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.HostportRangesMaxPorts, c.HostportRangesMaxPorts),
+		},
+		{
+			templateString: templateStringCrioNetworkBandwidthShaping,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.BandwidthShaping, c.BandwidthShaping),
+		},
+		{
+			templateString: templateStringCrioNetworkDNSOptions,
+			group:          crioNetworkConfig,
+			isDefaultValue: stringSliceEqual(dc.DNSOptions, c.DNSOptions),
+		},
+		{
+			templateString: templateStringCrioNetworkDNSNamespaceOptions,
+			group:          crioNetworkConfig,
+			isDefaultValue: stringSliceEqual(dc.DNSNamespaceOptions, c.DNSNamespaceOptions),
+		},
+		{
+			templateString: templateStringCrioNetworkDNSLocalCache,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.DNSLocalCache, c.DNSLocalCache),
+		},
+		{
+			templateString: templateStringCrioNetworkDNSMaxNameservers,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.DNSMaxNameservers, c.DNSMaxNameservers),
+		},
+		{
+			templateString: templateStringCrioNetworkDNSMaxSearches,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.DNSMaxSearches, c.DNSMaxSearches),
+		},
+		{
+			templateString: templateStringCrioNetworkWatchPodIPs,
+			group:          crioNetworkConfig,
+			isDefaultValue: simpleEqual(dc.WatchPodIPs, c.WatchPodIPs),
+		},
 		{
 			templateString: templateStringCrioMetricsEnableMetrics,
 			group:          crioMetricsConfig,
@@ -660,7 +815,7 @@ func RuntimesEqual(a, b Runtimes) bool {
 		if !ok {
 			return false
 		}
-		if !reflect.DeepEqual(valueA, valueB) {
+		if !runtimeHandlersEqual(valueA, valueB) {
 			return false
 		}
 	}
@@ -668,6 +823,21 @@ func RuntimesEqual(a, b Runtimes) bool {
 	return true
 }
 
+// runtimeHandlersEqual compares the configured fields of the runtime
+// handlers, ignoring the settings loaded from them.
+func runtimeHandlersEqual(a, b *RuntimeHandler) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	configuredA, configuredB := *a, *b
+	for _, handler := range []*RuntimeHandler{&configuredA, &configuredB} {
+		handler.seccompConfig = nil
+		handler.apparmorConfig = nil
+		handler.ulimitsConfig = nil
+	}
+	return reflect.DeepEqual(configuredA, configuredB)
+}
+
 func WorkloadsEqual(a, b Workloads) bool {
 	if len(a) != len(b) {
 		return false
@@ -885,6 +1055,22 @@ const templateStringCrioRuntimeSelinux = `# If true, SELinux will be used for po
 
 `
 
+const templateStringCrioRuntimeSELinuxFixedMCS = `# List of "<namespace>=<level>" entries, which fix the SELinux MCS level of all
+# pods within a namespace, unless a pod requests a level itself, for example
+# "kube-system=s0:c1,c2". Pods of the same namespace sharing a fixed level are
+# not reported as label collisions.
+{{ $.Comment }}selinux_fixed_mcs = [
+{{ range $entry := .SELinuxFixedMCS }}{{ $.Comment }}{{ printf "\t%q,\n" $entry }}{{ end }}{{ $.Comment }}]
+
+`
+
+const templateStringCrioRuntimeSELinuxMCSReservationsFile = `# Path to the file the SELinux MCS levels reserved for pods are persisted in.
+# The levels are reserved again on restart, before the pods get restored, and
+# can be inspected using the "/selinux" endpoint of the CRI-O socket.
+{{ $.Comment }}selinux_mcs_reservations_file = "{{ .SELinuxMCSReservationsFile }}"
+
+`
+
 const templateStringCrioRuntimeSeccompProfile = `# Path to the seccomp.json profile which is used as the default seccomp profile
 # for the runtime. If not specified, then the internal default seccomp profile
 # will be used. This option supports live configuration reload.
@@ -902,6 +1088,14 @@ const templateStringCrioRuntimeSeccompUseDefaultWhenEmpty = `# Changes the meani
 
 `
 
+const templateStringCrioRuntimeSeccompProfileRecordDir = `# Directory the seccomp profiles recorded for containers are written to, once
+# they exit. The recording is enabled per pod by the allowed annotation
+# "io.kubernetes.cri-o.seccompProfileRecord" and the profiles are written to
+# "<namespace>/<pod>/<container>.json" within the directory.
+{{ $.Comment }}seccomp_profile_record_dir = "{{ .SeccompProfileRecordDir }}"
+
+`
+
 const templateStringCrioRuntimeApparmorProfile = `# Used to change the name of the default AppArmor profile of CRI-O. The default
 # profile name is "crio-default". This profile only takes effect if the user
 # does not specify a profile via the Kubernetes Pod's metadata annotation. If
@@ -911,12 +1105,30 @@ const templateStringCrioRuntimeApparmorProfile = `# Used to change the name of t
 
 `
 
+const templateStringCrioRuntimeGenerateApparmorProfiles = `# Generate and load an AppArmor profile derived from the spec of every container
+# which would otherwise use the default profile. The generated profile allows
+# only the capabilities of the container and write access only to its writable
+# root filesystem and mounts. It gets unloaded when the container is removed.
+# No profile is generated for runtime handlers with an unconfined apparmor_profile.
+# Pods can request the same by setting the
+# "io.kubernetes.cri-o.generateAppArmorProfile" annotation to "true".
+{{ $.Comment }}generate_apparmor_profiles = {{ .GenerateApparmorProfiles }}
+
+`
+
 const templateStringCrioRuntimeBlockIOConfigFile = `# Path to the blockio class configuration file for configuring
 # the cgroup blockio controller.
 {{ $.Comment }}blockio_config_file = "{{ .BlockIOConfigFile }}"
 
 `
 
+const templateStringCrioRuntimeAdmissionPolicyFile = `# Path to the node-local admission policy file, which can deny or mutate
+# RunPodSandbox and CreateContainer requests. The policy gets reloaded on
+# SIGHUP. An empty path disables the admission policy.
+{{ $.Comment }}admission_policy_file = "{{ .AdmissionPolicyFile }}"
+
+`
+
 const templateStringCrioRuntimeIrqBalanceConfigFile = `# Used to change irqbalance service config file path which is used for configuring
 # irqbalance daemon.
 {{ $.Comment }}irqbalance_config_file = "{{ .IrqBalanceConfigFile }}"
@@ -960,6 +1172,24 @@ const templateStringCrioRuntimeDefaultSysctls = `# List of default sysctls. If i
 
 `
 
+const templateStringCrioRuntimeDeniedCapabilities = `# List of capabilities non-privileged containers are not allowed to add,
+# regardless of the request. Requests adding one of them fail with a
+# PermissionDenied error. "ALL" denies adding any capability. The list must not
+# contain any of the default_capabilities.
+{{ $.Comment }}denied_capabilities = [
+{{ range $capability := .DeniedCapabilities}}{{ $.Comment }}{{ printf "\t%q,\n" $capability}}{{ end }}{{ $.Comment }}]
+
+`
+
+const templateStringCrioRuntimeDeniedSysctls = `# List of sysctls non-privileged pods are not allowed to set, regardless of the
+# request. Requests setting one of them fail with a PermissionDenied error.
+# Entries ending with "*" deny all sysctls with the same prefix, for example
+# "net.ipv4.conf.*". The list must not contain any of the default_sysctls.
+{{ $.Comment }}denied_sysctls = [
+{{ range $sysctl := .DeniedSysctls}}{{ $.Comment }}{{ printf "\t%q,\n" $sysctl}}{{ end }}{{ $.Comment }}]
+
+`
+
 const templateStringCrioRuntimeAllowedDevices = `# List of devices on the host that a
 # user can specify with the "io.kubernetes.cri-o.Devices" allowed annotation.
 {{ $.Comment }}allowed_devices = [
@@ -1032,6 +1262,55 @@ const templateStringCrioRuntimeLogToJournald = `# Whether container output shoul
 
 `
 
+const templateStringCrioRuntimeLogForwardSocket = `# Path to a unix socket the container log lines get forwarded to in addition to
+# the kubernetes log file, including the pod, namespace, container and stream of
+# every line. An empty path disables the log forwarding.
+{{ $.Comment }}log_forward_socket = "{{ .LogForwardSocket }}"
+
+`
+
+const templateStringCrioRuntimeLogForwardFormat = `# Format of the lines forwarded to the log_forward_socket. Supported values are:
+# - "json": every line is a JSON object terminated by a newline.
+# - "syslog": every line is a RFC 5424 syslog message using octet counting
+#   framing (RFC 6587).
+{{ $.Comment }}log_forward_format = "{{ .LogForwardFormat }}"
+
+`
+
+const templateStringCrioRuntimeLogForwardBufferSize = `# Maximum number of log lines buffered while the log_forward_socket is
+# unavailable, for example while the log agent restarts. The oldest lines get
+# dropped when the buffer is full.
+{{ $.Comment }}log_forward_buffer_size = {{ .LogForwardBufferSize }}
+
+`
+
+const templateStringCrioRuntimeAuditLogPath = `# Path to the file the audit log gets appended to. Every exec, exec sync,
+# attach, port forward and privileged container creation request, as well as
+# every syscall allowed by the "audit" action of the seccomp notifier, is
+# recorded as JSON object terminated by a newline. An empty path disables the
+# audit log file.
+{{ $.Comment }}audit_log_path = "{{ .AuditLogPath }}"
+
+`
+
+const templateStringCrioRuntimeAuditLogMaxSize = `# Size in bytes at which the audit_log_path gets rotated. Zero disables the
+# rotation.
+{{ $.Comment }}audit_log_max_size = {{ .AuditLogMaxSize }}
+
+`
+
+const templateStringCrioRuntimeAuditLogMaxFiles = `# Number of rotated audit log files to keep.
+{{ $.Comment }}audit_log_max_files = {{ .AuditLogMaxFiles }}
+
+`
+
+const templateStringCrioRuntimeAuditLogSocket = `# Path to a unix socket the audit log gets written to, in addition to the
+# audit_log_path. An empty path disables the audit log socket. Events are
+# written asynchronously and dropped if the socket does not keep up.
+{{ $.Comment }}audit_log_socket = "{{ .AuditLogSocket }}"
+
+`
+
 const templateStringCrioRuntimeContainerExitsDir = `# Path to directory in which container exit files are written to by conmon.
 {{ $.Comment }}container_exits_dir = "{{ .ContainerExitsDir }}"
 
@@ -1093,6 +1372,21 @@ const templateStringCrioRuntimeMinimumMappableGID = `# If set, CRI-O will reject
 
 `
 
+const templateStringCrioRuntimeUsernsRange = `# Range of host IDs assigned to the user namespaces of pods using the "auto"
+# user namespace mode, in the format "<first ID>:<size>". The same range is
+# used for UIDs and GIDs and must not contain any ID of /etc/passwd or
+# /etc/group, nor overlap any range of /etc/subuid, /etc/subgid or the
+# containers/storage remapping. If empty, the ranges get assigned by
+# containers/storage instead.
+{{ $.Comment }}userns_range = "{{ .UsernsRange }}"
+
+`
+
+const templateStringCrioRuntimeUsernsAllocationsFile = `# Path to the file the user namespace ranges assigned to pods are persisted in.
+{{ $.Comment }}userns_allocations_file = "{{ .UsernsAllocationsFile }}"
+
+`
+
 const templateStringCrioRuntimeCtrStopTimeout = `# The minimal amount of time in seconds to wait before issuing a timeout
 # regarding the proper termination of the container. The lowest possible
 # value is 30s, whereas lower values are not considered by CRI-O.
@@ -1133,6 +1427,15 @@ const templateStringCrioRuntimePinnsPath = `# pinns_path is the path to find the
 
 `
 
+const templateStringCrioRuntimeNamespacesGCPeriod = `# The number of seconds between removing the namespaces pinned within the
+# namespaces_dir which neither belong to any known pod nor are entered by any
+# process, for example because CRI-O stopped while tearing down a pod. The
+# namespaces are always removed on startup. If set to 0, they are only removed
+# on startup.
+{{ $.Comment }}namespaces_gc_period = {{ .NamespacesGCPeriod }}
+
+`
+
 const templateStringCrioRuntimeEnableCriuSupport = `# Globally enable/disable CRIU support which is necessary to
 # checkpoint and restore container or pods (even if CRIU is found in $PATH).
 {{ $.Comment }}enable_criu_support = {{ .EnableCriuSupport }}
@@ -1145,6 +1448,14 @@ const templateStringCrioRuntimeEnablePodEvents = `# Enable/disable the generatio
 
 `
 
+const templateStringCrioRuntimeExecSyncConcurrencyLimit = `# Maximum number of exec sync requests, like exec probes, running in parallel
+# on the node. Additional requests wait until a running one finishes or their
+# timeout expires. The time spent waiting counts against the timeout of the
+# request. A value of 0 disables the limit.
+{{ $.Comment }}exec_sync_concurrency_limit = {{ .ExecSyncConcurrencyLimit }}
+
+`
+
 const templateStringCrioRuntimeDefaultRuntime = `# default_runtime is the _name_ of the OCI runtime to be used as the default.
 # default_runtime is the _name_ of the OCI runtime to be used as the default.
 # The name is matched against the runtimes map below.
@@ -1178,6 +1489,14 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 # monitor_env = []
 # privileged_without_host_devices = false
 # allowed_annotations = []
+# exec_sync_mode = "monitor"
+# default_capabilities = []
+# seccomp_profile = ""
+# apparmor_profile = ""
+# default_sysctls = []
+# default_ulimits = []
+# denied_capabilities = []
+# denied_sysctls = []
 # Where:
 # - runtime-handler: Name used to identify the runtime.
 # - runtime_path (optional, string): Absolute path to the runtime executable in
@@ -1196,12 +1515,21 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 #   a list of experimental annotations that this runtime handler is allowed to process.
 #   The currently recognized values are:
 #   "io.kubernetes.cri-o.userns-mode" for configuring a user namespace for the pod.
+#   "io.kubernetes.cri-o.userns-size" for requesting the size of the user namespace
+#   assigned from userns_range.
 #   "io.kubernetes.cri-o.cgroup2-mount-hierarchy-rw" for mounting cgroups writably when set to "true".
 #   "io.kubernetes.cri-o.Devices" for configuring devices for the pod.
 #   "io.kubernetes.cri-o.ShmSize" for configuring the size of /dev/shm.
 #   "io.kubernetes.cri-o.UnifiedCgroup.$CTR_NAME" for configuring the cgroup v2 unified block for a container.
 #   "io.containers.trace-syscall" for tracing syscalls via the OCI seccomp BPF hook.
 #   "io.kubernetes.cri.rdt-class" for setting the RDT class of a container
+#   "io.kubernetes.cri-o.LogRateLimit" for counting the log lines of the pod containers exceeding a rate, e.g. "lines=100,bytes=64Ki,burst=5".
+#   "io.kubernetes.cri-o.LogQuota" for limiting the log file size of the pod containers.
+#   "io.kubernetes.cri-o.seccompProfileImage" for pulling the seccomp profile of the pod containers from a registry.
+#   "io.kubernetes.cri-o.generateAppArmorProfile" for generating an AppArmor profile for each pod container.
+#   "io.kubernetes.cri-o.Networks" for attaching additional CNI networks to the pod.
+#   "io.kubernetes.cri-o.TimeNamespace" for creating a time namespace for the pod, e.g. "boottime=24h".
+#   "io.kubernetes.cri-o.CgroupNamespace" for creating a cgroup namespace rooted at the pod cgroup and shared by the pod containers.
 # - monitor_path (optional, string): The path of the monitor binary. Replaces
 #   deprecated option "conmon".
 # - monitor_cgroup (optional, string): The cgroup the container monitor process will be put in.
@@ -1210,6 +1538,20 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 #   should be moved to the container's cgroup
 # - monitor_env (optional, array of strings): Environment variables to pass to the montior.
 #   Replaces deprecated option "conmon_env".
+# - exec_sync_mode (optional, string): How exec sync requests, like exec probes,
+#   are run. "monitor" (the default) spawns the monitor for every request, while
+#   "direct" invokes the runtime without a monitor and reuses the prepared process
+#   specification across requests. The runtime is still invoked once per request.
+#   "direct" is only supported by the "oci" runtime type and falls back to
+#   "monitor" if monitor_exec_cgroup is set to "container".
+# - default_capabilities, seccomp_profile, apparmor_profile, default_sysctls and
+#   default_ulimits (optional): Override the respective options of the
+#   "crio.runtime" table for the containers of this runtime handler, for example
+#   to use stricter defaults for a runc handler than for a VM isolated one. An
+#   empty default_capabilities list drops all default capabilities.
+# - denied_capabilities and denied_sysctls (optional): Override the respective
+#   deny lists of the "crio.runtime" table for this runtime handler. An empty
+#   list allows all capabilities or sysctls.
 #
 # Using the seccomp notifier feature:
 #
@@ -1237,6 +1579,14 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 # Please be aware that CRI-O is not able to get notified if a syscall gets
 # blocked based on the seccomp defaultAction, which is a general runtime
 # limitation.
+#
+# The annotation value is a comma separated list of an optional default action
+# for the blocked syscalls and per syscall rules, for example
+# "audit,ptrace=kill,mount=audit". Supported actions are "log" (the default),
+# "stop", "kill" (stop the workload immediately) and "audit" (allow the syscall
+# and log an audit record containing the pid, syscall and its arguments, which
+# requires at least Linux 5.5). Syscalls with a rule get notified independently
+# of the profile.
 
 {{ range $runtime_name, $runtime_handler := .Runtimes  }}
 {{ $.Comment }}[crio.runtime.runtimes.{{ $runtime_name }}]
@@ -1252,14 +1602,28 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 {{ if $runtime_handler.AllowedAnnotations }}{{ $.Comment }}allowed_annotations = [
 {{ range $opt := $runtime_handler.AllowedAnnotations }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]{{ end }}
 {{ $.Comment }}privileged_without_host_devices = {{ $runtime_handler.PrivilegedWithoutHostDevices }}
-{{ end }}
+{{ if $runtime_handler.ExecSyncMode }}{{ $.Comment }}exec_sync_mode = "{{ $runtime_handler.ExecSyncMode }}"
+{{ end }}{{ if $runtime_handler.DefaultCapabilities }}{{ $.Comment }}default_capabilities = [
+{{ range $opt := $runtime_handler.DefaultCapabilities }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]
+{{ end }}{{ if $runtime_handler.SeccompProfile }}{{ $.Comment }}seccomp_profile = "{{ $runtime_handler.SeccompProfile }}"
+{{ end }}{{ if $runtime_handler.ApparmorProfile }}{{ $.Comment }}apparmor_profile = "{{ $runtime_handler.ApparmorProfile }}"
+{{ end }}{{ if $runtime_handler.DefaultSysctls }}{{ $.Comment }}default_sysctls = [
+{{ range $opt := $runtime_handler.DefaultSysctls }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]
+{{ end }}{{ if $runtime_handler.DefaultUlimits }}{{ $.Comment }}default_ulimits = [
+{{ range $opt := $runtime_handler.DefaultUlimits }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]
+{{ end }}{{ if $runtime_handler.DeniedCapabilities }}{{ $.Comment }}denied_capabilities = [
+{{ range $opt := $runtime_handler.DeniedCapabilities }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]
+{{ end }}{{ if $runtime_handler.DeniedSysctls }}{{ $.Comment }}denied_sysctls = [
+{{ range $opt := $runtime_handler.DeniedSysctls }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]
+{{ end }}{{ end }}
 `
 
 const templateStringCrioRuntimeWorkloads = `# The workloads table defines ways to customize containers with different resources
 # that work based on annotations, rather than the CRI.
 # Note, the behavior of this table is EXPERIMENTAL and may change at any time.
 # Each workload, has a name, activation_annotation, annotation_prefix and set of resources it supports mutating.
-# The currently supported resources are "cpu" (to configure the cpu shares) and "cpuset" to configure the cpuset.
+# The currently supported resources are "cpu" (to configure the cpu shares), "cpuset" to configure the cpuset,
+# "lograte" to configure the log rate limit and "logquota" to configure the log quota.
 # Each resource can have a default value specified, or be empty.
 # For a container to opt-into this workload, the pod should be configured with the annotation $activation_annotation (key only, value is ignored).
 # To customize per-container, an annotation of the form $annotation_prefix.$resource/$ctrName = "value" can be specified
@@ -1285,7 +1649,9 @@ const templateStringCrioRuntimeWorkloads = `# The workloads table defines ways t
 {{ $.Comment }}annotation_prefix = "{{ $workload_config.AnnotationPrefix }}"
 {{ if $workload_config.Resources }}{{ $.Comment }}[crio.runtime.workloads.{{ $workload_type }}.resources]
 {{ $.Comment }}cpuset = "{{ $workload_config.Resources.CPUSet }}"
-{{ $.Comment }}cpushares = {{ $workload_config.Resources.CPUShares }}{{ end }}
+{{ $.Comment }}cpushares = {{ $workload_config.Resources.CPUShares }}
+{{ $.Comment }}lograte = "{{ $workload_config.Resources.LogRateLimit }}"
+{{ $.Comment }}logquota = "{{ $workload_config.Resources.LogQuota }}"{{ end }}
 {{ end }}
 `
 
@@ -1325,6 +1691,13 @@ const templateStringCrioImageGlobalAuthFile = `# The path to a file containing c
 
 `
 
+const templateStringCrioImageNamespacedAuthDir = `# Root path for pod namespace-separated credentials used for pulling seccomp
+# profile images. The file used for a pod is <NAMESPACED_AUTH_DIR>/<NAMESPACE>.json,
+# while the global_auth_file is used if it does not exist. Must be an absolute path.
+{{ $.Comment }}namespaced_auth_dir = "{{ .NamespacedAuthDir }}"
+
+`
+
 const templateStringCrioImagePauseImage = `# The image used to instantiate infra containers.
 # This option supports live configuration reload.
 {{ $.Comment }}pause_image = "{{ .PauseImage }}"
@@ -1417,6 +1790,98 @@ const templateStringCrioNetworkPluginDirs = `# Paths to directories where CNI pl
 
 `
 
+const templateStringCrioNetworkCNICheckPeriod = `# The number of seconds between running CNI CHECK on the networks of all running
+# pods. Failed checks are logged, counted by the network_check_failures_total
+# metric and reported in the verbose pod sandbox status. If set to 0, the
+# networks are not checked periodically.
+{{ $.Comment }}cni_check_period = {{ .CNICheckPeriod }}
+
+`
+
+const templateStringCrioNetworkCNIGCPeriod = `# The number of seconds between releasing the CNI attachments recorded in the
+# CNI cache which have been created by CRI-O, but do not belong to any known
+# pod, for example because their teardown failed. If set to 0, the attachments are only released for pods
+# which fail to be restored on startup.
+{{ $.Comment }}cni_gc_period = {{ .CNIGCPeriod }}
+
+`
+
+const templateStringCrioNetworkCNIAttachmentsDir = `# Directory the pods whose CNI attachments have been created by CRI-O are
+# recorded in. The CNI cache is shared with other CNI users on the node, which
+# is why only the attachments of recorded pods are released as stale.
+{{ $.Comment }}cni_attachments_dir = "{{ .CNIAttachmentsDir }}"
+
+`
+
+const templateStringCrioNetworkHostportReservationsFile = `# Path to the file the host ports reserved by pods are persisted in. Pods
+# requesting a host port already reserved by another pod for the same protocol
+# and an overlapping host IP fail to be created.
+{{ $.Comment }}hostport_reservations_file = "{{ .HostportReservationsFile }}"
+
+`
+
+const templateStringCrioNetworkHostportRangesMaxPorts = `# Maximum number of host ports a pod can map by the
+# io.kubernetes.cri-o.HostPortRanges annotation. Every mapped port holds an
+# open socket and its own iptables rules. If set to 0, the number is not limited.
+{{ $.Comment }}hostport_ranges_max_ports = {{ .HostportRangesMaxPorts }}
+
+`
+
+const templateStringCrioNetworkBandwidthShaping = `# How the bandwidth limits requested by the kubernetes.io/ingress-bandwidth and
+# kubernetes.io/egress-bandwidth pod annotations are applied:
+# - cni: The limits are passed to the CNI plugins, which requires the bandwidth
+#   plugin to be chained in the CNI configuration.
+# - native: The limits are applied by CRI-O using traffic control on the host
+#   side veth peer of the pod interface. The limits are not passed to the CNI
+#   plugins.
+# The limits are only applied when the pod network is created, changing the
+# annotations of a running pod requires restarting the pod.
+{{ $.Comment }}bandwidth_shaping = "{{ .BandwidthShaping }}"
+
+`
+
+const templateStringCrioNetworkDNSOptions = `# Resolver options added to the resolv.conf of all pods which do not set an
+# option of the same name, for example "ndots:2" or "single-request-reopen".
+{{ $.Comment }}dns_options = [
+{{ range $opt := .DNSOptions }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]
+
+`
+
+const templateStringCrioNetworkDNSNamespaceOptions = `# List of "<namespace>=<option>" entries, which replace the resolver options of
+# the same name of all pods within a namespace, for example "team-a=ndots:2".
+# A namespace may be listed more than once to set multiple options.
+{{ $.Comment }}dns_namespace_options = [
+{{ range $entry := .DNSNamespaceOptions }}{{ $.Comment }}{{ printf "\t%q,\n" $entry }}{{ end }}{{ $.Comment }}]
+
+`
+
+const templateStringCrioNetworkDNSLocalCache = `# IP address of a node-local DNS cache, which is added as first nameserver of
+# all pods having nameservers. The nameservers of the pods are kept as fallback.
+{{ $.Comment }}dns_local_cache = "{{ .DNSLocalCache }}"
+
+`
+
+const templateStringCrioNetworkDNSMaxNameservers = `# Maximum number of nameservers of a pod. Additional nameservers, which the
+# resolver would ignore, are dropped with a warning. If set to 0, the number is
+# not limited.
+{{ $.Comment }}dns_max_nameservers = {{ .DNSMaxNameservers }}
+
+`
+
+const templateStringCrioNetworkDNSMaxSearches = `# Maximum number of search domains of a pod. Additional search domains are
+# dropped with a warning. Older glibc versions only support 6 search domains.
+# If set to 0, the number is not limited.
+{{ $.Comment }}dns_max_searches = {{ .DNSMaxSearches }}
+
+`
+
+const templateStringCrioNetworkWatchPodIPs = `# Watch the addresses of the network interface of the pods and update the pod
+# IPs if the addresses change after the network setup, for example by IPv6
+# stateless address autoconfiguration.
+{{ $.Comment }}watch_pod_ips = {{ .WatchPodIPs }}
+
+`
+
 const templateStringCrioMetrics = `# A necessary configuration for Prometheus based metrics retrieval
 [crio.metrics]
 
@@ -94,6 +94,23 @@ var _ = t.Describe("Config", func() {
 				},
 			}
 
+			// Then
+			Expect(config.RuntimesEqual(r1, r2)).To(BeTrue())
+		})
+		It("equal if same values and loaded settings", func() {
+			// When
+			r1 := config.Runtimes{
+				"1": &config.RuntimeHandler{
+					DefaultUlimits: []string{"nofile=1024:2048"},
+				},
+			}
+			r2 := config.Runtimes{
+				"1": &config.RuntimeHandler{
+					DefaultUlimits: []string{"nofile=1024:2048"},
+				},
+			}
+			Expect(r1["1"].ValidateSecurityDefaults("1")).To(Succeed())
+
 			// Then
 			Expect(config.RuntimesEqual(r1, r2)).To(BeTrue())
 		})
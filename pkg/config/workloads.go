@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/cri-o/cri-o/internal/loglimit"
 	"github.com/opencontainers/runtime-tools/generate"
 	"github.com/sirupsen/logrus"
 	"k8s.io/utils/cpuset"
@@ -47,6 +48,11 @@ type Resources struct {
 	CPUShares uint64 `json:"cpushares,omitempty"`
 	// Specifies the cpuset this pod has access to.
 	CPUSet string `json:"cpuset,omitempty"`
+	// Specifies the log rate limit of the container in the format
+	// "lines=<lines>,bytes=<quantity>,burst=<seconds>".
+	LogRateLimit string `json:"lograte,omitempty" toml:"lograte"`
+	// Specifies the maximum size of the log file of the container.
+	LogQuota string `json:"logquota,omitempty" toml:"logquota"`
 }
 
 func (w Workloads) Validate() error {
@@ -123,6 +129,21 @@ func (w Workloads) MutateSpecGivenAnnotations(ctrName string, specgen *generate.
 	return nil
 }
 
+// LogLimitsGivenAnnotations returns the log rate limit and quota of the
+// workload activated by the sandbox annotations for the container with the
+// provided name.
+func (w Workloads) LogLimitsGivenAnnotations(ctrName string, sboxAnnotations map[string]string) (rateLimit, quota string, err error) {
+	workload := w.workloadGivenActivationAnnotation(sboxAnnotations)
+	if workload == nil {
+		return "", "", nil
+	}
+	resources, err := resourcesFromAnnotation(workload.AnnotationPrefix, ctrName, sboxAnnotations, workload.Resources)
+	if err != nil || resources == nil {
+		return "", "", err
+	}
+	return resources.LogRateLimit, resources.LogQuota, nil
+}
+
 func (w Workloads) workloadGivenActivationAnnotation(sboxAnnotations map[string]string) *WorkloadConfig {
 	for _, wc := range w {
 		for annotation := range sboxAnnotations {
@@ -148,6 +169,9 @@ func resourcesFromAnnotation(prefix, ctrName string, allAnnotations map[string]s
 	if resources == nil {
 		return nil, nil
 	}
+	if defaultResources == nil {
+		return resources, nil
+	}
 
 	if resources.CPUSet == "" {
 		resources.CPUSet = defaultResources.CPUSet
@@ -155,6 +179,12 @@ func resourcesFromAnnotation(prefix, ctrName string, allAnnotations map[string]s
 	if resources.CPUShares == 0 {
 		resources.CPUShares = defaultResources.CPUShares
 	}
+	if resources.LogRateLimit == "" {
+		resources.LogRateLimit = defaultResources.LogRateLimit
+	}
+	if resources.LogQuota == "" {
+		resources.LogQuota = defaultResources.LogQuota
+	}
 
 	return resources, nil
 }
@@ -163,6 +193,9 @@ func (r *Resources) ValidateDefaults() error {
 	if r == nil {
 		return nil
 	}
+	if _, err := loglimit.Parse(r.LogRateLimit, r.LogQuota); err != nil {
+		return fmt.Errorf("invalid log limits: %w", err)
+	}
 	if r.CPUSet == "" {
 		return nil
 	}
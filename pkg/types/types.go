@@ -1,6 +1,8 @@
 package types
 
 import (
+	"encoding/json"
+
 	"github.com/containers/storage/pkg/idtools"
 )
 
@@ -33,3 +35,127 @@ type CrioInfo struct {
 	CgroupDriver      string     `json:"cgroup_driver"`
 	DefaultIDMappings IDMappings `json:"default_id_mappings"`
 }
+
+// UsernsAllocation stores the user namespace range assigned to a pod
+type UsernsAllocation struct {
+	PodID       string `json:"pod_id"`
+	Pod         string `json:"pod"`
+	Namespace   string `json:"namespace"`
+	HostID      uint32 `json:"host_id"`
+	Size        uint32 `json:"size"`
+	CreatedTime int64  `json:"created_time"`
+}
+
+// UsernsInfo stores information about the user namespace ranges assigned by
+// CRI-O
+type UsernsInfo struct {
+	FirstID     uint32             `json:"first_id"`
+	Size        uint32             `json:"size"`
+	Allocations []UsernsAllocation `json:"allocations"`
+}
+
+// SELinuxReservation stores the SELinux MCS level reserved for a pod
+type SELinuxReservation struct {
+	PodID       string `json:"pod_id"`
+	Pod         string `json:"pod"`
+	Namespace   string `json:"namespace"`
+	Label       string `json:"label"`
+	Level       string `json:"level"`
+	Source      string `json:"source"`
+	CreatedTime int64  `json:"created_time"`
+}
+
+// SELinuxCollision stores an SELinux MCS level shared by pods which should be
+// isolated from each other
+type SELinuxCollision struct {
+	Level  string   `json:"level"`
+	PodIDs []string `json:"pod_ids"`
+}
+
+// SELinuxInfo stores information about the SELinux MCS levels reserved by
+// CRI-O
+type SELinuxInfo struct {
+	Enabled      bool                 `json:"enabled"`
+	Reservations []SELinuxReservation `json:"reservations"`
+	Collisions   []SELinuxCollision   `json:"collisions"`
+}
+
+// NetworkResult stores the cached CNI result of a network attached to a pod
+type NetworkResult struct {
+	Network   string          `json:"network"`
+	Interface string          `json:"interface"`
+	Result    json.RawMessage `json:"result"`
+}
+
+// BandwidthInfo stores the traffic shaping configured for a pod in bits per
+// second
+type BandwidthInfo struct {
+	IngressRate  uint64 `json:"ingress_rate,omitempty"`
+	IngressBurst uint64 `json:"ingress_burst,omitempty"`
+	EgressRate   uint64 `json:"egress_rate,omitempty"`
+	EgressBurst  uint64 `json:"egress_burst,omitempty"`
+}
+
+// HostportChain stores an iptables chain owned by a host port mapping
+type HostportChain struct {
+	Name   string `json:"name"`
+	Exists bool   `json:"exists"`
+}
+
+// HostportMapping stores the state of a host port mapping of a pod
+type HostportMapping struct {
+	IPFamily      string          `json:"ip_family"`
+	HostIP        string          `json:"host_ip,omitempty"`
+	HostPort      int32           `json:"host_port"`
+	ContainerPort int32           `json:"container_port"`
+	Protocol      string          `json:"protocol"`
+	Open          bool            `json:"open"`
+	Chains        []HostportChain `json:"chains"`
+}
+
+// SandboxNetworkInfo stores information about the network of a pod sandbox
+type SandboxNetworkInfo struct {
+	PodID          string            `json:"pod_id"`
+	Pod            string            `json:"pod"`
+	Namespace      string            `json:"namespace"`
+	HostNetwork    bool              `json:"host_network"`
+	NetNSPath      string            `json:"netns_path"`
+	IPs            []string          `json:"ip_addresses"`
+	Networks       []NetworkResult   `json:"networks"`
+	Bandwidth      *BandwidthInfo    `json:"bandwidth,omitempty"`
+	Hostports      []HostportMapping `json:"hostports"`
+	LastCheckTime  int64             `json:"last_check_time,omitempty"`
+	LastCheckError string            `json:"last_check_error,omitempty"`
+}
+
+// HostportAllocation stores a host port allocated on the node
+type HostportAllocation struct {
+	IPFamily      string `json:"ip_family,omitempty"`
+	HostIP        string `json:"host_ip,omitempty"`
+	HostPort      int32  `json:"host_port"`
+	ContainerPort int32  `json:"container_port,omitempty"`
+	Protocol      string `json:"protocol"`
+	PodID         string `json:"pod_id,omitempty"`
+	Pod           string `json:"pod,omitempty"`
+	Namespace     string `json:"namespace,omitempty"`
+	Open          bool   `json:"open"`
+}
+
+// HostportReservation stores a range of host ports reserved by a pod
+type HostportReservation struct {
+	PodID     string `json:"pod_id"`
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+	Protocol  string `json:"protocol"`
+	HostIP    string `json:"host_ip,omitempty"`
+	FirstPort int32  `json:"first_port"`
+	LastPort  int32  `json:"last_port"`
+}
+
+// HostportsInfo stores the host ports allocated on the node. Open host ports
+// which do not belong to any pod are reported without pod. The reservations
+// are the host ports reserved by the pods in the host port registry.
+type HostportsInfo struct {
+	Hostports    []HostportAllocation  `json:"hostports"`
+	Reservations []HostportReservation `json:"reservations"`
+}
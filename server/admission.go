@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+
+	"github.com/cri-o/cri-o/internal/config/admission"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/server/metrics"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// admitPodSandbox evaluates the admission policy for the provided pod sandbox
+// request and applies the mutations of the policy to it.
+func (s *Server) admitPodSandbox(ctx context.Context, req *types.RunPodSandboxRequest) error {
+	if req.Config == nil || !s.config.Admission().Enabled() {
+		return nil
+	}
+	decision, err := s.admit(ctx, admission.NewSandboxRequest(req.Config, req.RuntimeHandler))
+	if err != nil {
+		return err
+	}
+	decision.MutateSandbox(req.Config)
+	return nil
+}
+
+// admitContainer evaluates the admission policy for the provided container
+// request and applies the mutations of the policy to it.
+func (s *Server) admitContainer(ctx context.Context, sb *sandbox.Sandbox, req *types.CreateContainerRequest) error {
+	if !s.config.Admission().Enabled() {
+		return nil
+	}
+
+	// Match the image by its tags and digests as well, because the kubelet
+	// usually requests the image by its ID.
+	images := []string{req.Config.Image.Image}
+	if req.Config.Image.UserSpecifiedImage != "" {
+		images = append(images, req.Config.Image.UserSpecifiedImage)
+	}
+	if imgResult, err := s.StorageImageServer().ImageStatus(s.config.SystemContext, req.Config.Image.Image); err == nil {
+		images = append(images, imgResult.RepoTags...)
+		images = append(images, imgResult.RepoDigests...)
+	}
+
+	decision, err := s.admit(ctx, admission.NewContainerRequest(req.Config, sb.Namespace(), sb.RuntimeHandler(), images))
+	if err != nil {
+		return err
+	}
+	decision.MutateContainer(req.Config)
+	return nil
+}
+
+// admit evaluates the admission policy for the provided request. It returns a
+// PermissionDenied error if the request is denied.
+func (s *Server) admit(ctx context.Context, req *admission.Request) (*admission.Decision, error) {
+	decision := s.config.Admission().Evaluate(req)
+	for _, result := range decision.Results {
+		switch {
+		case result.Action == admission.ActionDeny:
+			metrics.Instance().MetricAdmissionPolicyDenialsTotalInc(req.Operation, result.Rule, result.DryRun)
+			if result.DryRun {
+				log.Warnf(ctx, "Admission policy rule %q would deny %s of %s in namespace %s (dry run)", result.Rule, req.Operation, req.Name, req.Namespace)
+			}
+		case result.DryRun:
+			log.Infof(ctx, "Admission policy rule %q would mutate %s of %s in namespace %s (dry run)", result.Rule, req.Operation, req.Name, req.Namespace)
+		default:
+			log.Infof(ctx, "Admission policy rule %q mutates %s of %s in namespace %s", result.Rule, req.Operation, req.Name, req.Namespace)
+		}
+	}
+
+	if denial := decision.Denial(); denial != nil {
+		log.Warnf(ctx, "Admission policy rule %q denied %s of %s in namespace %s", denial.Rule, req.Operation, req.Name, req.Namespace)
+		return nil, status.Error(codes.PermissionDenied, denial.Reason())
+	}
+	return decision, nil
+}
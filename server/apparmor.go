@@ -0,0 +1,52 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cri-o/cri-o/internal/config/apparmor"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/pkg/annotations"
+	"golang.org/x/net/context"
+	v1 "k8s.io/api/core/v1"
+)
+
+// shouldGenerateAppArmorProfile returns whether an AppArmor profile should be
+// generated for a container of the provided sandbox, which requests the
+// provided profile and would otherwise run with the applied one of its
+// runtime handler. Only containers which would otherwise use the confining
+// default profile qualify.
+func (s *Server) shouldGenerateAppArmorProfile(sb *sandbox.Sandbox, requested, applied string) (bool, error) {
+	if requested != "" && requested != v1.AppArmorBetaProfileRuntimeDefault {
+		return false, nil
+	}
+	if applied == v1.AppArmorBetaProfileNameUnconfined {
+		return false, nil
+	}
+	generate := s.config.GenerateApparmorProfiles
+	if v, ok := sb.Annotations()[annotations.GenerateAppArmorProfileAnnotation]; ok {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("invalid %s annotation %q: %w", annotations.GenerateAppArmorProfileAnnotation, v, err)
+		}
+		generate = parsed
+	}
+	return generate, nil
+}
+
+// removeGeneratedAppArmorProfile unloads the AppArmor profile generated for
+// the provided container.
+func (s *Server) removeGeneratedAppArmorProfile(ctx context.Context, containerID string) {
+	if err := s.config.AppArmor().RemoveGenerated(containerID); err != nil {
+		log.Warnf(ctx, "Unable to remove generated AppArmor profile of container %s: %v", containerID, err)
+	}
+}
+
+// usesGeneratedAppArmorProfile returns true if the container runs with the
+// AppArmor profile generated for it.
+func usesGeneratedAppArmorProfile(c *oci.Container) bool {
+	spec := c.Spec()
+	return spec.Process != nil && spec.Process.ApparmorProfile == apparmor.GeneratedProfileName(c.ID())
+}
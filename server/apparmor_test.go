@@ -0,0 +1,48 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/pkg/annotations"
+	v1 "k8s.io/api/core/v1"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+func TestShouldGenerateAppArmorProfile(t *testing.T) {
+	s := &Server{}
+	s.config.GenerateApparmorProfiles = true
+	sb, err := sandbox.New("id1", "ns1", "", "pod1", ".",
+		map[string]string{}, map[string]string{}, "", "",
+		&types.PodSandboxMetadata{}, "", "/cgroup", false, "", "", "",
+		nil, true, time.Now(), "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		requested, applied string
+		expected           bool
+	}{
+		{"", "crio-default", true},
+		{v1.AppArmorBetaProfileRuntimeDefault, "crio-default", true},
+		{v1.AppArmorBetaProfileNamePrefix + "custom", "custom", false},
+		// the runtime handler does not confine its containers
+		{"", v1.AppArmorBetaProfileNameUnconfined, false},
+		{v1.AppArmorBetaProfileRuntimeDefault, v1.AppArmorBetaProfileNameUnconfined, false},
+	} {
+		generate, err := s.shouldGenerateAppArmorProfile(sb, tc.requested, tc.applied)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if generate != tc.expected {
+			t.Errorf("expected generate %v for requested %q and applied %q, got %v", tc.expected, tc.requested, tc.applied, generate)
+		}
+	}
+
+	sb.Annotations()[annotations.GenerateAppArmorProfileAnnotation] = "false"
+	if generate, err := s.shouldGenerateAppArmorProfile(sb, "", "crio-default"); err != nil || generate {
+		t.Fatalf("expected no generation with disabling annotation, got %v: %v", generate, err)
+	}
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/audit"
+	"github.com/cri-o/cri-o/internal/config/seccomp"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	kubetypes "k8s.io/kubernetes/pkg/kubelet/types"
+	utilexec "k8s.io/utils/exec"
+)
+
+// startAuditLogger opens the configured audit log file and socket.
+func (s *Server) startAuditLogger() error {
+	if s.config.AuditLogPath == "" && s.config.AuditLogSocket == "" {
+		return nil
+	}
+
+	logrus.Infof("Writing audit log to file %q and socket %q", s.config.AuditLogPath, s.config.AuditLogSocket)
+	logger, err := audit.New(
+		s.config.AuditLogPath,
+		s.config.AuditLogMaxSize,
+		s.config.AuditLogMaxFiles,
+		s.config.AuditLogSocket,
+	)
+	if err != nil {
+		return err
+	}
+	s.auditLogger = logger
+	return nil
+}
+
+// newAuditEvent returns a new audit event for the provided CRI call, which
+// starts now.
+func newAuditEvent(criCall, stage string) *audit.Event {
+	return &audit.Event{
+		Time:    time.Now(),
+		CRICall: criCall,
+		Stage:   stage,
+	}
+}
+
+// newContainerAuditEvent returns a new audit event for the container with the
+// provided ID, including its pod and container metadata if it exists.
+func (s *Server) newContainerAuditEvent(ctx context.Context, criCall, stage, containerID string) *audit.Event {
+	event := newAuditEvent(criCall, stage)
+	event.ContainerID = containerID
+	if s.auditLogger == nil {
+		return event
+	}
+	if c, err := s.GetContainerFromShortID(ctx, containerID); err == nil {
+		setAuditContainer(event, c)
+	}
+	return event
+}
+
+// newSandboxAuditEvent returns a new audit event for the pod sandbox with the
+// provided ID, including its metadata if it exists.
+func (s *Server) newSandboxAuditEvent(criCall, stage, podSandboxID string) *audit.Event {
+	event := newAuditEvent(criCall, stage)
+	event.PodID = podSandboxID
+	if s.auditLogger == nil {
+		return event
+	}
+	if id, err := s.PodIDIndex().Get(podSandboxID); err == nil {
+		if sb := s.GetSandbox(id); sb != nil {
+			setAuditSandbox(event, sb)
+		}
+	}
+	return event
+}
+
+// setAuditContainer sets the pod and container metadata of the event.
+func setAuditContainer(event *audit.Event, c *oci.Container) {
+	labels := c.Labels()
+	event.Pod = labels[kubetypes.KubernetesPodNameLabel]
+	event.Namespace = labels[kubetypes.KubernetesPodNamespaceLabel]
+	event.PodID = c.Sandbox()
+	event.Container = labels[kubetypes.KubernetesContainerNameLabel]
+	event.ContainerID = c.ID()
+	event.Image = c.ImageName()
+}
+
+// setAuditSandbox sets the pod metadata of the event.
+func setAuditSandbox(event *audit.Event, sb *sandbox.Sandbox) {
+	event.Pod = sb.Metadata().Name
+	event.Namespace = sb.Namespace()
+	event.PodID = sb.ID()
+}
+
+// auditSeccompSyscall writes the syscall of the notification, which got
+// allowed and audited by the seccomp notifier of the container, to the audit
+// log.
+func (s *Server) auditSeccompSyscall(c *oci.Container, msg *seccomp.Notification) {
+	if s.auditLogger == nil {
+		return
+	}
+	event := newAuditEvent(audit.CRICallSeccompNotifier, audit.StageAllowed)
+	setAuditContainer(event, c)
+	event.Syscall = msg.Syscall()
+	event.Pid = msg.Pid()
+	event.SyscallArgs = msg.Args()
+	s.auditLogger.Log(event)
+}
+
+// auditRequested writes the provided event of a requested streaming
+// endpoint to the audit log.
+func (s *Server) auditRequested(event *audit.Event, err error) {
+	if s.auditLogger == nil {
+		return
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	s.auditLogger.Log(event)
+}
+
+// auditCompleted completes the provided event and writes it to the audit
+// log.
+func (s *Server) auditCompleted(event *audit.Event, err error) {
+	if s.auditLogger == nil {
+		return
+	}
+	s.auditLogger.Log(event.Complete(time.Now(), err))
+}
+
+// auditExecCompleted completes the provided event of an exec or attach
+// session and writes it to the audit log. The exit code gets derived from the
+// error of the session.
+func (s *Server) auditExecCompleted(event *audit.Event, err error) {
+	if s.auditLogger == nil {
+		return
+	}
+	var exitErr utilexec.ExitError
+	switch {
+	case err == nil:
+		exitCode := int32(0)
+		event.ExitCode = &exitCode
+	case errors.As(err, &exitErr):
+		exitCode := int32(exitErr.ExitStatus())
+		event.ExitCode = &exitCode
+		// The exit code is not an error of the session.
+		err = nil
+	}
+	s.auditLogger.Log(event.Complete(time.Now(), err))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/cri-o/cri-o/internal/audit"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/internal/oci"
 	"golang.org/x/net/context"
@@ -15,7 +16,12 @@ import (
 
 // Attach prepares a streaming endpoint to attach to a running container.
 func (s *Server) Attach(ctx context.Context, req *types.AttachRequest) (*types.AttachResponse, error) {
+	event := s.newContainerAuditEvent(ctx, audit.CRICallAttach, audit.StageRequested, req.ContainerId)
+	event.TTY = req.Tty
+	event.Stdin = req.Stdin
+
 	resp, err := s.getAttach(req)
+	s.auditRequested(event, err)
 	if err != nil {
 		return nil, fmt.Errorf("unable to prepare attach endpoint")
 	}
@@ -24,13 +30,23 @@ func (s *Server) Attach(ctx context.Context, req *types.AttachRequest) (*types.A
 }
 
 // Attach endpoint for streaming.Runtime
-func (s StreamService) Attach(ctx context.Context, containerID string, inputStream io.Reader, outputStream, errorStream io.WriteCloser, tty bool, resizeChan <-chan remotecommand.TerminalSize) error {
+func (s StreamService) Attach(ctx context.Context, containerID string, inputStream io.Reader, outputStream, errorStream io.WriteCloser, tty bool, resizeChan <-chan remotecommand.TerminalSize) (retErr error) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
+
+	event := newAuditEvent(audit.CRICallAttach, audit.StageCompleted)
+	event.ContainerID = containerID
+	event.TTY = tty
+	event.Stdin = inputStream != nil
+	defer func() {
+		s.runtimeServer.auditCompleted(event, retErr)
+	}()
+
 	c, err := s.runtimeServer.GetContainerFromShortID(ctx, containerID)
 	if err != nil {
 		return status.Errorf(codes.NotFound, "could not find container %q: %v", containerID, err)
 	}
+	setAuditContainer(event, c)
 
 	if err := s.runtimeServer.Runtime().UpdateContainerStatus(s.ctx, c); err != nil {
 		return err
@@ -12,6 +12,7 @@ import (
 	"github.com/containers/storage/pkg/idtools"
 	"github.com/containers/storage/pkg/mount"
 	"github.com/containers/storage/pkg/stringid"
+	"github.com/cri-o/cri-o/internal/audit"
 	"github.com/cri-o/cri-o/internal/factory/container"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/log"
@@ -374,6 +375,28 @@ func (s *Server) CreateContainer(ctx context.Context, req *types.CreateContainer
 		return nil, fmt.Errorf("CreateContainer failed as the sandbox was stopped: %s", sb.ID())
 	}
 
+	if err := s.admitContainer(ctx, sb, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkDeniedCapabilities(ctx, sb, req); err != nil {
+		return nil, err
+	}
+
+	if req.Config.GetLinux().GetSecurityContext().GetPrivileged() {
+		event := newAuditEvent(audit.CRICallCreateContainer, audit.StageCompleted)
+		setAuditSandbox(event, sb)
+		event.Container = req.Config.GetMetadata().GetName()
+		event.Image = req.Config.Image.Image
+		event.Privileged = true
+		defer func() {
+			if res != nil {
+				event.ContainerID = res.ContainerId
+			}
+			s.auditCompleted(event, retErr)
+		}()
+	}
+
 	ctr, err := container.New()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create container: %w", err)
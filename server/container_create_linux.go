@@ -21,6 +21,7 @@ import (
 	"github.com/cri-o/cri-o/internal/config/device"
 	"github.com/cri-o/cri-o/internal/config/node"
 	"github.com/cri-o/cri-o/internal/config/rdt"
+	"github.com/cri-o/cri-o/internal/config/seccomp"
 	ctrfactory "github.com/cri-o/cri-o/internal/factory/container"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/linklogs"
@@ -178,7 +179,7 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 	specgen.HostSpecific = true
 	specgen.ClearProcessRlimits()
 
-	for _, u := range s.config.Ulimits() {
+	for _, u := range s.config.UlimitsForRuntime(sb.RuntimeHandler()) {
 		specgen.AddProcessRlimits(u.Name, u.Hard, u.Soft)
 	}
 
@@ -370,14 +371,21 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 	}
 
 	// set this container's apparmor profile if it is set by sandbox
-	if s.Config().AppArmor().IsEnabled() && !ctr.Privileged() {
-		profile, err := s.Config().AppArmor().Apply(
+	generateAppArmorProfile := false
+	apparmorConfig := s.Config().AppArmorForRuntime(sb.RuntimeHandler())
+	if apparmorConfig.IsEnabled() && !ctr.Privileged() {
+		profile, err := apparmorConfig.Apply(
 			securityContext.ApparmorProfile,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("applying apparmor profile to container %s: %w", containerID, err)
 		}
 
+		generateAppArmorProfile, err = s.shouldGenerateAppArmorProfile(sb, securityContext.ApparmorProfile, profile)
+		if err != nil {
+			return nil, err
+		}
+
 		log.Debugf(ctx, "Applied AppArmor profile %s to container %s", profile, containerID)
 		specgen.SetProcessApparmorProfile(profile)
 	}
@@ -464,7 +472,7 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 			specgen.SetupPrivileged(true)
 		} else {
 			capabilities := securityContext.Capabilities
-			if err := ctr.SpecSetupCapabilities(capabilities, s.config.DefaultCapabilities, s.config.AddInheritableCapabilities); err != nil {
+			if err := ctr.SpecSetupCapabilities(capabilities, s.config.DefaultCapabilitiesForRuntime(sb.RuntimeHandler()), s.config.AddInheritableCapabilities); err != nil {
 				return nil, err
 			}
 		}
@@ -550,8 +558,9 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 		return nil, err
 	}
 
-	// When running on cgroupv2, automatically add a cgroup namespace for not privileged containers.
-	if !ctr.Privileged() && node.CgroupIsV2() {
+	// When running on cgroupv2, automatically add a cgroup namespace for not privileged containers,
+	// unless they join the cgroup namespace of the pod.
+	if !ctr.Privileged() && node.CgroupIsV2() && sb.CgroupNsPath() == "" {
 		if err := specgen.AddOrReplaceLinuxNamespace(string(rspec.CgroupNamespace), ""); err != nil {
 			return nil, err
 		}
@@ -625,18 +634,46 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 	created := time.Now()
 	seccompRef := types.SecurityProfile_Unconfined.String()
 	if !ctr.Privileged() {
-		notifier, ref, err := s.config.Seccomp().Setup(
-			ctx,
-			s.seccompNotifierChan,
-			containerID,
-			sb.Annotations(),
-			specgen,
-			securityContext.Seccomp,
-		)
+		profileImage, profile, err := s.seccompProfileFromImage(ctx, sb, metadata.Name, securityContext.Seccomp)
+		if err != nil {
+			return nil, err
+		}
+		var notifier *seccomp.Notifier
+		var ref string
+		if profileImage != "" {
+			notifier, ref, err = s.config.SeccompForRuntime(sb.RuntimeHandler()).SetupFromContent(
+				ctx,
+				s.seccompNotifierChan,
+				containerID,
+				sb.Annotations(),
+				specgen,
+				profile,
+				profileImage,
+			)
+		} else {
+			notifier, ref, err = s.config.SeccompForRuntime(sb.RuntimeHandler()).Setup(
+				ctx,
+				s.seccompNotifierChan,
+				containerID,
+				sb.Annotations(),
+				specgen,
+				securityContext.Seccomp,
+			)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("setup seccomp: %w", err)
 		}
 		if notifier != nil {
+			if notifier.Recording() {
+				profilePath, err := seccompProfileRecordPath(s.config.SeccompProfileRecordDir, sb.Namespace(), sb.KubeName(), metadata.Name)
+				if err != nil {
+					if closeErr := notifier.Close(); closeErr != nil {
+						log.Errorf(ctx, "Unable to close seccomp notifier: %v", closeErr)
+					}
+					return nil, err
+				}
+				specgen.AddAnnotation(crioann.SeccompProfileRecordPath, profilePath)
+			}
 			s.seccompNotifiers.Store(containerID, notifier)
 		}
 		seccompRef = ref
@@ -774,6 +811,12 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 		return nil, err
 	}
 
+	logLimits, err := s.containerLogLimits(metadata.Name, sb.Annotations())
+	if err != nil {
+		return nil, err
+	}
+	ociContainer.SetLogLimits(logLimits)
+
 	specgen.SetLinuxMountLabel(mountLabel)
 	specgen.SetProcessSelinuxLabel(processLabel)
 
@@ -855,6 +898,22 @@ func (s *Server) createSandboxContainer(ctx context.Context, ctr ctrfactory.Cont
 		}
 	}
 
+	// The generated AppArmor profile is derived from the final spec
+	if generateAppArmorProfile {
+		profile, err := apparmorConfig.InstallGenerated(containerID, specgen.Config)
+		if err != nil {
+			return nil, fmt.Errorf("generating apparmor profile for container %s: %w", containerID, err)
+		}
+		defer func() {
+			if retErr != nil {
+				s.removeGeneratedAppArmorProfile(ctx, containerID)
+			}
+		}()
+
+		log.Debugf(ctx, "Applied generated AppArmor profile %s to container %s", profile, containerID)
+		specgen.SetProcessApparmorProfile(profile)
+	}
+
 	saveOptions := generate.ExportOptions{}
 	if err := specgen.SaveToFile(filepath.Join(containerInfo.Dir, "config.json"), saveOptions); err != nil {
 		return nil, err
@@ -914,6 +973,19 @@ func clearReadOnly(m *rspec.Mount) {
 	m.Options = append(m.Options, "rw")
 }
 
+// seccompProfileRecordPath returns the path of the recorded seccomp profile
+// for the provided container below the record directory. The path components
+// originate from the CRI metadata and get rejected if they would escape the
+// record directory.
+func seccompProfileRecordPath(recordDir, namespace, pod, container string) (string, error) {
+	for _, component := range []string{namespace, pod, container} {
+		if component == "" || component == "." || component == ".." || strings.ContainsRune(component, filepath.Separator) {
+			return "", fmt.Errorf("invalid path component %q for seccomp profile recording", component)
+		}
+	}
+	return filepath.Join(recordDir, namespace, pod, container+".json"), nil
+}
+
 func addOCIBindMounts(ctx context.Context, ctr ctrfactory.Container, mountLabel, bindMountPrefix string, absentMountSourcesToReject []string, maybeRelabel, skipRelabel, cgroup2RW bool) ([]oci.ContainerVolume, []rspec.Mount, error) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
@@ -1192,3 +1264,59 @@ func newLinuxContainerSecurityContext() *types.LinuxContainerSecurityContext {
 		Apparmor:         &types.SecurityProfile{},
 	}
 }
+
+// seccompProfileFromImage pulls the seccomp profile of the container, if it
+// references one by the seccomp profile image annotation. The container
+// specific annotation takes precedence over the one for the whole pod. The
+// pull uses the signature policy and auth file of the pod namespace. The
+// annotation conflicts with a requested localhost profile, while a requested
+// unconfined profile gets overridden with a warning. Returns an empty image
+// reference if no profile image is requested or if the syscalls of the
+// container get recorded.
+func (s *Server) seccompProfileFromImage(ctx context.Context, sb *sandbox.Sandbox, containerName string, requested *types.SecurityProfile) (imageRef string, profile []byte, err error) {
+	annotations := sb.Annotations()
+	if _, ok := annotations[crioann.SeccompProfileRecordAnnotation]; ok {
+		return "", nil, nil
+	}
+	imageRef, ok := annotations[crioann.SeccompProfileImageAnnotation+"."+containerName]
+	if !ok {
+		imageRef = annotations[crioann.SeccompProfileImageAnnotation]
+	}
+	if imageRef == "" {
+		return "", nil, nil
+	}
+
+	if requested != nil {
+		switch requested.ProfileType {
+		case types.SecurityProfile_Localhost:
+			return "", nil, fmt.Errorf(
+				"seccomp profile image %s conflicts with the requested localhost profile %s",
+				imageRef, requested.LocalhostRef,
+			)
+		case types.SecurityProfile_Unconfined:
+			log.Warnf(ctx, "Overriding the requested unconfined seccomp profile with the profile of image %s", imageRef)
+		}
+	}
+
+	sourceCtx := *s.config.SystemContext // A shallow copy we can modify
+	policyPath, err := s.namespaceSignaturePolicyPath(sb.Namespace())
+	if err != nil {
+		return "", nil, err
+	}
+	if policyPath != "" {
+		sourceCtx.SignaturePolicyPath = policyPath
+	}
+	authFilePath, err := s.namespaceAuthFilePath(sb.Namespace())
+	if err != nil {
+		return "", nil, err
+	}
+	if authFilePath != "" {
+		sourceCtx.AuthFilePath = authFilePath
+	}
+
+	profile, err = s.seccompProfilePuller.Pull(ctx, s.StorageImageServer(), &sourceCtx, sb.ID(), imageRef)
+	if err != nil {
+		return "", nil, fmt.Errorf("get seccomp profile from image: %w", err)
+	}
+	return imageRef, profile, nil
+}
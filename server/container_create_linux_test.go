@@ -158,3 +158,25 @@ func TestAddOCIBindsCGroupRW(t *testing.T) {
 		t.Error("Cgroup mount not added with RO.")
 	}
 }
+
+func TestSeccompProfileRecordPath(t *testing.T) {
+	path, err := seccompProfileRecordPath("/var/lib/crio/seccomp", "default", "pod", "ctr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/var/lib/crio/seccomp/default/pod/ctr.json" {
+		t.Errorf("unexpected record path %q", path)
+	}
+
+	for _, tc := range [][3]string{
+		{"..", "pod", "ctr"},
+		{"default", "../..", "ctr"},
+		{"default", "pod", "../../etc/ctr"},
+		{"default", "pod", ""},
+		{".", "pod", "ctr"},
+	} {
+		if path, err := seccompProfileRecordPath("/var/lib/crio/seccomp", tc[0], tc[1], tc[2]); err == nil {
+			t.Errorf("expected %v to be rejected, got %q", tc, path)
+		}
+	}
+}
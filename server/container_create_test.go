@@ -6,6 +6,8 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
@@ -173,6 +175,28 @@ var _ = t.Describe("ContainerCreate", func() {
 			Expect(response).To(BeNil())
 		})
 
+		It("should fail when adding a denied capability", func() {
+			// Given
+			serverConfig.DeniedCapabilities = []string{"SYS_ADMIN"}
+			setupSUT()
+			addContainerAndSandbox()
+			config := newContainerConfig()
+			config.Linux.SecurityContext.Capabilities.AddCapabilities = []string{"CAP_SYS_ADMIN"}
+
+			// When
+			response, err := sut.CreateContainer(context.Background(),
+				&types.CreateContainerRequest{
+					PodSandboxId:  testSandbox.ID(),
+					Config:        config,
+					SandboxConfig: newPodSandboxConfig(),
+				})
+
+			// Then
+			Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+			Expect(err.Error()).To(ContainSubstring("SYS_ADMIN"))
+			Expect(response).To(BeNil())
+		})
+
 		It("should fail when sandbox not found", func() {
 			// Given
 			Expect(sut.PodIDIndex().Add(testSandbox.ID())).To(BeNil())
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/cri-o/cri-o/internal/audit"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/internal/oci"
 	"golang.org/x/net/context"
@@ -15,7 +16,13 @@ import (
 
 // Exec prepares a streaming endpoint to execute a command in the container.
 func (s *Server) Exec(ctx context.Context, req *types.ExecRequest) (*types.ExecResponse, error) {
+	event := s.newContainerAuditEvent(ctx, audit.CRICallExec, audit.StageRequested, req.ContainerId)
+	event.Command = req.Cmd
+	event.TTY = req.Tty
+	event.Stdin = req.Stdin
+
 	resp, err := s.getExec(req)
+	s.auditRequested(event, err)
 	if err != nil {
 		return nil, fmt.Errorf("unable to prepare exec endpoint: %w", err)
 	}
@@ -24,13 +31,24 @@ func (s *Server) Exec(ctx context.Context, req *types.ExecRequest) (*types.ExecR
 }
 
 // Exec endpoint for streaming.Runtime
-func (s StreamService) Exec(ctx context.Context, containerID string, cmd []string, stdin io.Reader, stdout, stderr io.WriteCloser, tty bool, resizeChan <-chan remotecommand.TerminalSize) error {
+func (s StreamService) Exec(ctx context.Context, containerID string, cmd []string, stdin io.Reader, stdout, stderr io.WriteCloser, tty bool, resizeChan <-chan remotecommand.TerminalSize) (retErr error) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
+
+	event := newAuditEvent(audit.CRICallExec, audit.StageCompleted)
+	event.ContainerID = containerID
+	event.Command = cmd
+	event.TTY = tty
+	event.Stdin = stdin != nil
+	defer func() {
+		s.runtimeServer.auditExecCompleted(event, retErr)
+	}()
+
 	c, err := s.runtimeServer.GetContainerFromShortID(ctx, containerID)
 	if err != nil {
 		return status.Errorf(codes.NotFound, "could not find container %q: %v", containerID, err)
 	}
+	setAuditContainer(event, c)
 
 	if err := s.runtimeServer.Runtime().UpdateContainerStatus(s.ctx, c); err != nil {
 		return err
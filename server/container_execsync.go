@@ -2,22 +2,39 @@ package server
 
 import (
 	"errors"
+	"time"
 
+	"github.com/cri-o/cri-o/internal/audit"
 	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/server/metrics"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+	kubetypes "k8s.io/kubernetes/pkg/kubelet/types"
 )
 
 // ExecSync runs a command in a container synchronously.
-func (s *Server) ExecSync(ctx context.Context, req *types.ExecSyncRequest) (*types.ExecSyncResponse, error) {
+func (s *Server) ExecSync(ctx context.Context, req *types.ExecSyncRequest) (res *types.ExecSyncResponse, retErr error) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
+
+	event := newAuditEvent(audit.CRICallExecSync, audit.StageCompleted)
+	event.ContainerID = req.ContainerId
+	event.Command = req.Cmd
+	defer func() {
+		if res != nil {
+			exitCode := res.ExitCode
+			event.ExitCode = &exitCode
+		}
+		s.auditCompleted(event, retErr)
+	}()
+
 	c, err := s.GetContainerFromShortID(ctx, req.ContainerId)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "could not find container %q: %v", req.ContainerId, err)
 	}
+	setAuditContainer(event, c)
 
 	if err := c.Living(); err != nil {
 		return nil, status.Errorf(codes.NotFound, "container is not created or running: %v", err)
@@ -28,5 +45,13 @@ func (s *Server) ExecSync(ctx context.Context, req *types.ExecSyncRequest) (*typ
 		return nil, errors.New("exec command cannot be empty")
 	}
 
+	labels := c.Labels()
+	defer metrics.Instance().MetricContainersExecSyncLatencySecondsObserve(
+		labels[kubetypes.KubernetesContainerNameLabel],
+		labels[kubetypes.KubernetesPodNameLabel],
+		labels[kubetypes.KubernetesPodNamespaceLabel],
+		time.Now(),
+	)
+
 	return s.Runtime().ExecSyncContainer(ctx, c, cmd, req.Timeout)
 }
@@ -3,16 +3,25 @@ package server
 import (
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
 
 	"github.com/containers/storage/pkg/pools"
+	"github.com/cri-o/cri-o/internal/audit"
+	"github.com/cri-o/cri-o/internal/hostport"
 	"github.com/cri-o/cri-o/internal/log"
 	"golang.org/x/net/context"
+	v1 "k8s.io/api/core/v1"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
 // PortForward prepares a streaming endpoint to forward ports from a PodSandbox.
 func (s *Server) PortForward(ctx context.Context, req *types.PortForwardRequest) (*types.PortForwardResponse, error) {
+	event := s.newSandboxAuditEvent(audit.CRICallPortForward, audit.StageRequested, req.PodSandboxId)
+	event.Ports = req.Port
+
 	resp, err := s.getPortForward(req)
+	s.auditRequested(event, err)
 	if err != nil {
 		return nil, fmt.Errorf("unable to prepare portforward endpoint")
 	}
@@ -20,10 +29,17 @@ func (s *Server) PortForward(ctx context.Context, req *types.PortForwardRequest)
 	return resp, nil
 }
 
-func (s StreamService) PortForward(ctx context.Context, podSandboxID string, port int32, stream io.ReadWriteCloser) error {
+func (s StreamService) PortForward(ctx context.Context, podSandboxID string, port int32, stream io.ReadWriteCloser) (retErr error) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
 
+	event := newAuditEvent(audit.CRICallPortForward, audit.StageCompleted)
+	event.PodID = podSandboxID
+	event.Ports = []int32{port}
+	defer func() {
+		s.runtimeServer.auditCompleted(event, retErr)
+	}()
+
 	// if we error in this function before Copying all of the content out of the stream,
 	// this stream will eventually get full, which causes leakages and can eventually brick CRI-O
 	// ref https://bugzilla.redhat.com/show_bug.cgi?id=1798193
@@ -46,6 +62,7 @@ func (s StreamService) PortForward(ctx context.Context, podSandboxID string, por
 	if sb == nil {
 		return fmt.Errorf("could not find sandbox %s", podSandboxID)
 	}
+	setAuditSandbox(event, sb)
 
 	if !sb.Ready(true) {
 		return fmt.Errorf("sandbox %s is not running", podSandboxID)
@@ -58,8 +75,56 @@ func (s StreamService) PortForward(ctx context.Context, podSandboxID string, por
 		)
 	}
 
+	protocol, err := portForwardProtocol(stream, sb.ContainerPorts(), port)
+	if err != nil {
+		return err
+	}
+
 	// defer responsibility of emptying stream to PortForwardContainer
 	emptyStreamOnError = false
 
-	return s.runtimeServer.Runtime().PortForwardContainer(ctx, sb.InfraContainer(), netNsPath, port, stream)
+	return s.runtimeServer.Runtime().PortForwardContainer(ctx, sb.InfraContainer(), netNsPath, port, protocol, stream)
+}
+
+// portForwardProtocolHeader is the header of a port forward data stream which
+// selects the protocol to forward the port with, either "tcp", "udp" or
+// "sctp". Neither the kubelet nor kubectl set it and streams upgraded via
+// websockets do not carry headers, so for them the protocol can only be
+// inferred from the ports declared by the containers, see cri.md.
+const portForwardProtocolHeader = "protocol"
+
+// portForwardProtocol selects the protocol to forward the port with. The
+// protocol requested by the stream is used if set. Otherwise TCP is used,
+// unless the containers of the sandbox declare the port only for a single
+// other protocol.
+func portForwardProtocol(stream io.ReadWriteCloser, containerPorts []*hostport.PortMapping, port int32) (types.Protocol, error) {
+	if s, ok := stream.(interface{ Headers() http.Header }); ok {
+		if requested := s.Headers().Get(portForwardProtocolHeader); requested != "" {
+			switch strings.ToLower(requested) {
+			case "tcp":
+				return types.Protocol_TCP, nil
+			case "udp":
+				return types.Protocol_UDP, nil
+			case "sctp":
+				return types.Protocol_SCTP, nil
+			}
+			return types.Protocol_TCP, fmt.Errorf("unsupported port forward protocol %q", requested)
+		}
+	}
+
+	declared := map[v1.Protocol]bool{}
+	for _, p := range containerPorts {
+		if p.ContainerPort == port {
+			declared[p.Protocol] = true
+		}
+	}
+	if len(declared) == 1 {
+		switch {
+		case declared[v1.ProtocolUDP]:
+			return types.Protocol_UDP, nil
+		case declared[v1.ProtocolSCTP]:
+			return types.Protocol_SCTP, nil
+		}
+	}
+	return types.Protocol_TCP, nil
 }
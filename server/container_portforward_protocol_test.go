@@ -0,0 +1,63 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/cri-o/cri-o/internal/hostport"
+	v1 "k8s.io/api/core/v1"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+type headerStream struct {
+	io.ReadWriteCloser
+	headers http.Header
+}
+
+func (s *headerStream) Headers() http.Header {
+	return s.headers
+}
+
+func TestPortForwardProtocol(t *testing.T) {
+	dnsPorts := []*hostport.PortMapping{
+		{ContainerPort: 53, Protocol: v1.ProtocolTCP},
+		{ContainerPort: 53, Protocol: v1.ProtocolUDP},
+		{ContainerPort: 5000, Protocol: v1.ProtocolUDP},
+		{ContainerPort: 9000, Protocol: v1.ProtocolSCTP},
+	}
+
+	for _, tc := range []struct {
+		requested string
+		port      int32
+		expected  types.Protocol
+	}{
+		{"", 53, types.Protocol_TCP},
+		{"udp", 53, types.Protocol_UDP},
+		{"UDP", 53, types.Protocol_UDP},
+		{"tcp", 5000, types.Protocol_TCP},
+		{"sctp", 53, types.Protocol_SCTP},
+		// the port is only declared for a single protocol
+		{"", 5000, types.Protocol_UDP},
+		{"", 9000, types.Protocol_SCTP},
+		{"", 8080, types.Protocol_TCP},
+	} {
+		stream := &headerStream{headers: http.Header{}}
+		if tc.requested != "" {
+			stream.headers.Set(portForwardProtocolHeader, tc.requested)
+		}
+		protocol, err := portForwardProtocol(stream, dnsPorts, tc.port)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if protocol != tc.expected {
+			t.Errorf("expected protocol %s for port %d and requested %q, got %s", tc.expected, tc.port, tc.requested, protocol)
+		}
+	}
+
+	stream := &headerStream{headers: http.Header{}}
+	stream.headers.Set(portForwardProtocolHeader, "icmp")
+	if _, err := portForwardProtocol(stream, dnsPorts, 53); err == nil {
+		t.Fatal("expected error for unsupported protocol")
+	}
+}
@@ -66,6 +66,10 @@ func (s *Server) removeContainerInPod(ctx context.Context, sb *sandbox.Sandbox,
 		return fmt.Errorf("failed to delete container %s in pod sandbox %s: %v", c.Name(), sb.ID(), err)
 	}
 
+	if usesGeneratedAppArmorProfile(c) {
+		s.removeGeneratedAppArmorProfile(ctx, c.ID())
+	}
+
 	if err := os.Remove(filepath.Join(s.config.ContainerExitsDir, c.ID())); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to remove container exit file %s: %w", c.ID(), err)
 	}
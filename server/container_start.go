@@ -104,6 +104,9 @@ func (s *Server) StartContainer(ctx context.Context, req *types.StartContainerRe
 		return nil, fmt.Errorf("failed to start container %s: %w", c.ID(), err)
 	}
 	s.generateCRIEvent(ctx, c, types.ContainerEventType_CONTAINER_STARTED_EVENT)
+	s.watchOOM(ctx, sandbox, c)
+	s.enforceLogLimits(c)
+	s.forwardLogs(c, true)
 
 	if err := s.nri.postStartContainer(ctx, sandbox, c); err != nil {
 		log.Warnf(ctx, "NRI post-start failed for container %q: %v", c.ID(), err)
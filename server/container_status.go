@@ -2,11 +2,13 @@ package server
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/cri-o/cri-o/internal/log"
 	oci "github.com/cri-o/cri-o/internal/oci"
 	"github.com/cri-o/cri-o/internal/storage"
+	crioann "github.com/cri-o/cri-o/pkg/annotations"
 	json "github.com/json-iterator/go"
 	spec "github.com/opencontainers/runtime-spec/specs-go"
 	"golang.org/x/net/context"
@@ -123,10 +125,11 @@ func (s *Server) ContainerStatus(ctx context.Context, req *types.ContainerStatus
 }
 
 type containerInfo struct {
-	SandboxID   string    `json:"sandboxID"`
-	Pid         int       `json:"pid"`
-	RuntimeSpec spec.Spec `json:"runtimeSpec"`
-	Privileged  bool      `json:"privileged"`
+	SandboxID                string    `json:"sandboxID"`
+	Pid                      int       `json:"pid"`
+	RuntimeSpec              spec.Spec `json:"runtimeSpec"`
+	Privileged               bool      `json:"privileged"`
+	SeccompProfileRecordPath string    `json:"seccompProfileRecordPath,omitempty"`
 }
 
 type containerInfoCheckpointRestore struct {
@@ -148,6 +151,14 @@ func (s *Server) createContainerInfo(container *oci.Container) (map[string]strin
 			Privileged:  metadata.Privileged,
 		}
 
+		// The recorded seccomp profile is only available after the
+		// container exited.
+		if profilePath, ok := container.CrioAnnotations()[crioann.SeccompProfileRecordPath]; ok {
+			if _, err := os.Stat(profilePath); err == nil {
+				localContainerInfo.SeccompProfileRecordPath = profilePath
+			}
+		}
+
 		if s.config.CheckpointRestore() {
 			localContainerInfoCheckpointRestore := containerInfoCheckpointRestore{
 				CheckpointedAt: container.CheckpointedAt(),
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	libconfig "github.com/cri-o/cri-o/pkg/config"
+	"github.com/cri-o/cri-o/server/metrics"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+const (
+	// denialTypeCapability and denialTypeSysctl are the types of the deny
+	// list denials metric.
+	denialTypeCapability = "capability"
+	denialTypeSysctl     = "sysctl"
+)
+
+// checkDeniedSysctls returns a PermissionDenied error if a non-privileged pod
+// sandbox requests sysctls of the deny list of its runtime handler.
+func (s *Server) checkDeniedSysctls(ctx context.Context, req *types.RunPodSandboxRequest) error {
+	if req.Config.GetLinux().GetSecurityContext().GetPrivileged() {
+		return nil
+	}
+	deniedSysctls := s.config.DeniedSysctlsForRuntime(req.RuntimeHandler)
+	if len(deniedSysctls) == 0 {
+		return nil
+	}
+
+	denied := []string{}
+	for key := range req.Config.GetLinux().GetSysctls() {
+		if libconfig.IsSysctlDenied(deniedSysctls, key) {
+			denied = append(denied, key)
+		}
+	}
+	sort.Strings(denied)
+	return s.denyRequest(ctx, "RunPodSandbox", denialTypeSysctl, denied,
+		req.Config.GetMetadata().GetName(), req.Config.GetMetadata().GetNamespace())
+}
+
+// checkDeniedCapabilities returns a PermissionDenied error if a
+// non-privileged container requests to add capabilities of the deny list of
+// its runtime handler.
+func (s *Server) checkDeniedCapabilities(ctx context.Context, sb *sandbox.Sandbox, req *types.CreateContainerRequest) error {
+	securityContext := req.Config.GetLinux().GetSecurityContext()
+	if securityContext.GetPrivileged() {
+		return nil
+	}
+	denied := s.config.DeniedCapabilitiesForRuntime(sb.RuntimeHandler()).Denied(securityContext.GetCapabilities().GetAddCapabilities())
+	return s.denyRequest(ctx, "CreateContainer", denialTypeCapability, denied,
+		req.Config.GetMetadata().GetName(), sb.Namespace())
+}
+
+// denyRequest records the denied values of a request and returns the
+// PermissionDenied error for them, or nil if nothing got denied.
+func (s *Server) denyRequest(ctx context.Context, operation, denialType string, denied []string, name, namespace string) error {
+	if len(denied) == 0 {
+		return nil
+	}
+	for _, value := range denied {
+		metrics.Instance().MetricDenyListDenialsTotalInc(operation, denialType, value)
+	}
+	msg := fmt.Sprintf("%s of %s in namespace %s requests denied %s(s): %s", operation, name, namespace, denialType, strings.Join(denied, ", "))
+	log.Warnf(ctx, "%s", msg)
+	return status.Error(codes.PermissionDenied, msg)
+}
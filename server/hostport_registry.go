@@ -0,0 +1,110 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cri-o/cri-o/internal/hostport"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/pkg/annotations"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// startHostportRegistry loads the persisted host port reservations.
+func (s *Server) startHostportRegistry() error {
+	registry, err := hostport.NewRegistry(s.config.HostportReservationsFile)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("Tracking host ports, %d pods currently holding host ports", len(registry.List()))
+	s.hostportRegistry = registry
+	return nil
+}
+
+// reconcileHostportReservations releases the host ports of pods which do not
+// exist anymore or whose network has been stopped, and reserves the host
+// ports of restored pods which are not tracked yet.
+func (s *Server) reconcileHostportReservations(ctx context.Context) {
+	if s.hostportRegistry == nil {
+		return
+	}
+	sandboxes := make(map[string]bool)
+	for _, sb := range s.ListSandboxes() {
+		if !sb.NetworkStopped() {
+			sandboxes[sb.ID()] = true
+		}
+	}
+	pruned, err := s.hostportRegistry.Prune(func(podID string) bool {
+		return sandboxes[podID]
+	})
+	if err != nil {
+		log.Warnf(ctx, "Unable to release host ports of removed pods: %v", err)
+	}
+	for _, r := range pruned {
+		log.Infof(ctx, "Released host ports of removed or stopped pod %s", r.PodID)
+	}
+
+	for _, sb := range s.ListSandboxes() {
+		if !sandboxes[sb.ID()] || s.hostportRegistry.Get(sb.ID()) != nil {
+			continue
+		}
+		ports := hostport.PortRanges(sb.PortMappings())
+		if len(ports) == 0 {
+			continue
+		}
+		if _, err := s.hostportRegistry.Reserve(sb.ID(), sb.KubeName(), sb.Namespace(), ports); err != nil {
+			log.Warnf(ctx, "Unable to reserve host ports of pod %s: %v", sb.ID(), err)
+		}
+	}
+}
+
+// sandboxPortMappings returns the provided port mappings of a pod together
+// with the ones of the host port ranges requested by its annotations, which
+// may map at most maxPorts ports.
+func sandboxPortMappings(portMappings []*hostport.PortMapping, podAnnotations map[string]string, maxPorts int) ([]*hostport.PortMapping, error) {
+	value, ok := podAnnotations[annotations.HostPortRangesAnnotation]
+	if !ok {
+		return portMappings, nil
+	}
+	ranges, err := hostport.ParsePortRanges(value, maxPorts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", annotations.HostPortRangesAnnotation, err)
+	}
+	return append(portMappings, ranges...), nil
+}
+
+// reserveSandboxHostports reserves the host ports of a newly created pod. It
+// fails with an AlreadyExists error if any of them is reserved by another
+// pod.
+func (s *Server) reserveSandboxHostports(ctx context.Context, podID, pod, namespace string, portMappings []*hostport.PortMapping) error {
+	if s.hostportRegistry == nil {
+		return nil
+	}
+	ports := hostport.PortRanges(portMappings)
+	if len(ports) == 0 {
+		return nil
+	}
+	if _, err := s.hostportRegistry.Reserve(podID, pod, namespace, ports); err != nil {
+		var conflict *hostport.ConflictError
+		if errors.As(err, &conflict) {
+			return status.Errorf(codes.AlreadyExists, "pod %s/%s: %v", namespace, pod, err)
+		}
+		return err
+	}
+	log.Debugf(ctx, "Reserved host ports %v of pod %s/%s", ports, namespace, pod)
+	return nil
+}
+
+// releaseSandboxHostports releases the host ports of the provided pod, if it
+// has any.
+func (s *Server) releaseSandboxHostports(ctx context.Context, podID string) {
+	if s.hostportRegistry == nil {
+		return
+	}
+	if err := s.hostportRegistry.Release(podID); err != nil {
+		log.Warnf(ctx, "Unable to release host ports of pod %s: %v", podID, err)
+	}
+}
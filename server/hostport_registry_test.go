@@ -0,0 +1,95 @@
+package server
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/hostport"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/pkg/annotations"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+func TestSandboxPortMappings(t *testing.T) {
+	portMappings := []*hostport.PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP}}
+
+	res, err := sandboxPortMappings(portMappings, map[string]string{}, 128)
+	if err != nil || len(res) != 1 {
+		t.Fatalf("expected unchanged port mappings, got %+v: %v", res, err)
+	}
+
+	res, err = sandboxPortMappings(portMappings, map[string]string{annotations.HostPortRangesAnnotation: "10000-10009/udp"}, 128)
+	if err != nil || len(res) != 11 {
+		t.Fatalf("expected 11 port mappings, got %+v: %v", res, err)
+	}
+
+	if _, err := sandboxPortMappings(portMappings, map[string]string{annotations.HostPortRangesAnnotation: "10000-"}, 128); err == nil {
+		t.Fatal("expected error for invalid host port ranges")
+	}
+
+	if _, err := sandboxPortMappings(portMappings, map[string]string{annotations.HostPortRangesAnnotation: "10000-10009/udp"}, 5); err == nil {
+		t.Fatal("expected error for host port ranges exceeding the limit")
+	}
+}
+
+func TestReserveSandboxHostports(t *testing.T) {
+	ctx := context.Background()
+	registry, err := hostport.NewRegistry(filepath.Join(t.TempDir(), "hostport-reservations.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{hostportRegistry: registry}
+
+	if err := s.reserveSandboxHostports(ctx, "id1", "pod1", "ns1", []*hostport.PortMapping{
+		{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	err = s.reserveSandboxHostports(ctx, "id2", "pod2", "ns2", []*hostport.PortMapping{
+		{HostPort: 8080, ContainerPort: 8080, Protocol: v1.ProtocolTCP, HostIP: "127.0.0.1"},
+	})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("expected AlreadyExists error, got %v", err)
+	}
+
+	s.releaseSandboxHostports(ctx, "id1")
+	if err := s.reserveSandboxHostports(ctx, "id2", "pod2", "ns2", []*hostport.PortMapping{
+		{HostPort: 8080, ContainerPort: 8080, Protocol: v1.ProtocolTCP, HostIP: "127.0.0.1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStoppedSandboxReleasesHostports(t *testing.T) {
+	ctx := context.Background()
+	registry, err := hostport.NewRegistry(filepath.Join(t.TempDir(), "hostport-reservations.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{hostportRegistry: registry}
+	portMappings := []*hostport.PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP}}
+	sb, err := sandbox.New("id1", "ns1", "", "pod1", ".",
+		map[string]string{}, map[string]string{}, "", "",
+		&types.PodSandboxMetadata{}, "", "/cgroup", false, "", "", "",
+		portMappings, true, time.Now(), "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.reserveSandboxHostports(ctx, sb.ID(), "pod1", "ns1", portMappings); err != nil {
+		t.Fatal(err)
+	}
+
+	// the replacement of the restarted pod is created before the stopped
+	// pod is removed
+	if err := s.networkStop(ctx, sb); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.reserveSandboxHostports(ctx, "id2", "pod1", "ns1", portMappings); err != nil {
+		t.Fatal(err)
+	}
+}
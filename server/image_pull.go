@@ -122,6 +122,39 @@ func (s *Server) PullImage(ctx context.Context, req *types.PullImageRequest) (*t
 	}, nil
 }
 
+// namespaceSignaturePolicyPath returns the signature policy path of the
+// provided pod namespace, or an empty string if no namespace specific policy
+// exists.
+func (s *Server) namespaceSignaturePolicyPath(namespace string) (string, error) {
+	if namespace == "" {
+		return "", nil
+	}
+	policyPath := filepath.Join(s.config.SignaturePolicyDir, namespace+".json")
+	if _, err := os.Stat(policyPath); err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("read policy path %s: %w", policyPath, err)
+	}
+	return policyPath, nil
+}
+
+// namespaceAuthFilePath returns the auth file path of the provided pod
+// namespace, or an empty string if no namespace specific auth file exists.
+func (s *Server) namespaceAuthFilePath(namespace string) (string, error) {
+	if namespace == "" || s.config.NamespacedAuthDir == "" {
+		return "", nil
+	}
+	authFilePath := filepath.Join(s.config.NamespacedAuthDir, namespace+".json")
+	if _, err := os.Stat(authFilePath); err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("read auth file path %s: %w", authFilePath, err)
+	}
+	return authFilePath, nil
+}
+
 // pullImage performs the actual pull operation of PullImage. Used to separate
 // the pull implementation from the pullCache logic in PullImage and improve
 // readability and maintainability.
@@ -136,13 +169,12 @@ func (s *Server) pullImage(ctx context.Context, pullArgs *pullArguments) (string
 		sourceCtx.DockerAuthConfig = &pullArgs.credentials
 	}
 
-	if pullArgs.namespace != "" {
-		policyPath := filepath.Join(s.config.SignaturePolicyDir, pullArgs.namespace+".json")
-		if _, err := os.Stat(policyPath); err == nil {
-			sourceCtx.SignaturePolicyPath = policyPath
-		} else if !os.IsNotExist(err) {
-			return "", fmt.Errorf("read policy path %s: %w", policyPath, err)
-		}
+	policyPath, err := s.namespaceSignaturePolicyPath(pullArgs.namespace)
+	if err != nil {
+		return "", err
+	}
+	if policyPath != "" {
+		sourceCtx.SignaturePolicyPath = policyPath
 	}
 	log.Debugf(ctx, "Using pull policy path for image %s: %s", pullArgs.image, sourceCtx.SignaturePolicyPath)
 
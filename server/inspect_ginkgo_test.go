@@ -44,6 +44,73 @@ var _ = t.Describe("Inspect", func() {
 			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
 		})
 
+		It("should succeed with /userns route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/userns", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"allocations":[]`))
+		})
+
+		It("should succeed with /selinux route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/selinux", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"reservations":[]`))
+		})
+
+		It("should succeed with valid /network route", func() {
+			ctx := context.TODO()
+			// Given
+			Expect(sut.AddSandbox(ctx, testSandbox)).To(BeNil())
+
+			// When
+			request, err := http.NewRequest(http.MethodGet,
+				"/network/"+testSandbox.ID(), http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"pod_id":"` + testSandbox.ID() + `"`))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"hostports":[]`))
+		})
+
+		It("should fail with invalid sandbox ID on /network route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/network/123", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusNotFound))
+		})
+
+		It("should succeed with /hostports route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/hostports", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).To(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"hostports":[`))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"reservations":[]`))
+		})
+
 		It("should succeed with valid /containers route", func() {
 			ctx := context.TODO()
 			// Given
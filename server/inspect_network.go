@@ -0,0 +1,168 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/cri-o/cri-o/internal/config/cnimgr"
+	"github.com/cri-o/cri-o/internal/hostport"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/pkg/types"
+)
+
+// getSandboxNetworkInfo returns the network state of the provided sandbox,
+// including the cached CNI results of its networks and its host ports.
+func (s *Server) getSandboxNetworkInfo(sb *sandbox.Sandbox) (types.SandboxNetworkInfo, error) {
+	info := types.SandboxNetworkInfo{
+		PodID:       sb.ID(),
+		Pod:         sb.KubeName(),
+		Namespace:   sb.Namespace(),
+		HostNetwork: sb.HostNetwork(),
+		NetNSPath:   sb.NetNsPath(),
+		IPs:         sb.IPs(),
+		Networks:    []types.NetworkResult{},
+		Hostports:   []types.HostportMapping{},
+	}
+	if check := sb.NetworkCheck(); check != nil {
+		info.LastCheckTime = check.Time.UnixNano()
+		info.LastCheckError = check.Error
+	}
+
+	bwConfig, err := podBandwidthConfig(sb)
+	if err != nil {
+		return info, err
+	}
+	if bwConfig != nil {
+		info.Bandwidth = &types.BandwidthInfo{
+			IngressRate:  bwConfig.IngressRate,
+			IngressBurst: bwConfig.IngressBurst,
+			EgressRate:   bwConfig.EgressRate,
+			EgressBurst:  bwConfig.EgressBurst,
+		}
+	}
+
+	if !sb.HostNetwork() {
+		attachments, err := cnimgr.CachedPodAttachments("", sb.ID())
+		if err != nil {
+			return info, err
+		}
+		for _, a := range attachments {
+			info.Networks = append(info.Networks, types.NetworkResult{
+				Network:   a.Network,
+				Interface: a.Ifname,
+				Result:    a.Result,
+			})
+		}
+	}
+
+	for _, status := range s.hostportManager.Status(map[string]*hostport.PodPortMapping{
+		sb.ID(): {
+			Name:         sb.Name(),
+			PortMappings: sb.PortMappings(),
+			HostNetwork:  sb.HostNetwork(),
+		},
+	})[sb.ID()] {
+		mapping := types.HostportMapping{
+			IPFamily:      status.IPFamily,
+			HostIP:        status.Mapping.HostIP,
+			HostPort:      status.Mapping.HostPort,
+			ContainerPort: status.Mapping.ContainerPort,
+			Protocol:      strings.ToLower(string(status.Mapping.Protocol)),
+			Open:          status.Open,
+			Chains:        []types.HostportChain{},
+		}
+		for _, chain := range status.Chains {
+			mapping.Chains = append(mapping.Chains, types.HostportChain{
+				Name:   chain.Name,
+				Exists: chain.Exists,
+			})
+		}
+		info.Hostports = append(info.Hostports, mapping)
+	}
+
+	return info, nil
+}
+
+// getHostportsInfo returns the host ports requested by all pods on the node
+// together with the host ports held open which do not belong to any of them
+// and the host ports reserved in the registry.
+func (s *Server) getHostportsInfo() types.HostportsInfo {
+	info := types.HostportsInfo{
+		Hostports:    []types.HostportAllocation{},
+		Reservations: []types.HostportReservation{},
+	}
+
+	openPorts := s.hostportManager.OpenHostports()
+	open := make(map[hostport.OpenHostport]bool)
+	for _, port := range openPorts {
+		open[port] = true
+	}
+
+	sandboxes := []*sandbox.Sandbox{}
+	pods := make(map[string]*hostport.PodPortMapping)
+	for _, sb := range s.ListSandboxes() {
+		if sb.HostNetwork() {
+			continue
+		}
+		sandboxes = append(sandboxes, sb)
+		pods[sb.ID()] = &hostport.PodPortMapping{
+			Name:         sb.Name(),
+			PortMappings: sb.PortMappings(),
+		}
+	}
+
+	// The iptables chains are read once for all sandboxes.
+	statuses := s.hostportManager.Status(pods)
+	for _, sb := range sandboxes {
+		for _, status := range statuses[sb.ID()] {
+			port := hostport.OpenHostport{
+				IPFamily: status.IPFamily,
+				IP:       status.Mapping.HostIP,
+				Port:     status.Mapping.HostPort,
+				Protocol: strings.ToLower(string(status.Mapping.Protocol)),
+			}
+			delete(open, port)
+			info.Hostports = append(info.Hostports, types.HostportAllocation{
+				IPFamily:      status.IPFamily,
+				HostIP:        status.Mapping.HostIP,
+				HostPort:      status.Mapping.HostPort,
+				ContainerPort: status.Mapping.ContainerPort,
+				Protocol:      port.Protocol,
+				PodID:         sb.ID(),
+				Pod:           sb.KubeName(),
+				Namespace:     sb.Namespace(),
+				Open:          status.Open,
+			})
+		}
+	}
+
+	for _, port := range openPorts {
+		if !open[port] {
+			continue
+		}
+		info.Hostports = append(info.Hostports, types.HostportAllocation{
+			IPFamily: port.IPFamily,
+			HostIP:   port.IP,
+			HostPort: port.Port,
+			Protocol: port.Protocol,
+			Open:     true,
+		})
+	}
+
+	if s.hostportRegistry != nil {
+		for _, r := range s.hostportRegistry.List() {
+			for _, ports := range r.Ports {
+				info.Reservations = append(info.Reservations, types.HostportReservation{
+					PodID:     r.PodID,
+					Pod:       r.Pod,
+					Namespace: r.Namespace,
+					Protocol:  ports.Protocol,
+					HostIP:    ports.HostIP,
+					FirstPort: ports.Start,
+					LastPort:  ports.End,
+				})
+			}
+		}
+	}
+
+	return info
+}
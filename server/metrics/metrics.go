@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"sync"
 	"time"
 
@@ -90,6 +91,19 @@ type Metrics struct {
 	metricContainersOOMCountTotal             *prometheus.CounterVec
 	metricContainersSeccompNotifierCountTotal *prometheus.CounterVec
 	metricResourcesStalledAtStage             *prometheus.CounterVec
+	metricContainersOOMKillsTotal             *prometheus.CounterVec
+	metricContainersExecSyncLatencySeconds    *prometheus.HistogramVec
+	metricContainersLogExceededLinesTotal     *prometheus.CounterVec
+	metricAdmissionPolicyDenialsTotal         *prometheus.CounterVec
+	metricDenyListDenialsTotal                *prometheus.CounterVec
+	metricNetworkCheckFailuresTotal           *prometheus.CounterVec
+	metricNetworkGCReleasedTotal              *prometheus.CounterVec
+	metricNamespacesGCRemovedTotal            *prometheus.CounterVec
+	metricPodNetworkInterfaceStatistics       *prometheus.GaugeVec
+	metricPodNetworkQueueStatistics           *prometheus.GaugeVec
+	metricPodNetworkSockets                   *prometheus.GaugeVec
+	metricPodNetworkConntrackEntries          *prometheus.GaugeVec
+	metricSeccompNotifierActionsTotal         *prometheus.CounterVec
 }
 
 var instance *Metrics
@@ -328,6 +342,113 @@ func New(config *libconfig.MetricsConfig) *Metrics {
 			},
 			[]string{"stage"},
 		),
+		metricContainersOOMKillsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.ContainersOOMKillsTotal.String(),
+				Help:      "Amount of processes killed by the OOM killer by container name, pod and namespace",
+			},
+			[]string{"name", "pod", "namespace"},
+		),
+		metricContainersExecSyncLatencySeconds: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.ContainersExecSyncLatencySeconds.String(),
+				Help:      "Latency in seconds of exec sync requests, like probes, by container name, pod and namespace",
+				Buckets: []float64{ // in seconds
+					0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60,
+				},
+			},
+			[]string{"name", "pod", "namespace"},
+		),
+		metricContainersLogExceededLinesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.ContainersLogExceededLinesTotal.String(),
+				Help:      "Amount of log lines exceeding the log rate limit by container name, pod and namespace",
+			},
+			[]string{"name", "pod", "namespace"},
+		),
+		metricAdmissionPolicyDenialsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.AdmissionPolicyDenialsTotal.String(),
+				Help:      "Amount of requests denied by the admission policy by operation, rule and dry run mode",
+			},
+			[]string{"operation", "rule", "dry_run"},
+		),
+		metricDenyListDenialsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.DenyListDenialsTotal.String(),
+				Help:      "Amount of requests denied by the capability and sysctl deny lists by operation, type and value",
+			},
+			[]string{"operation", "type", "value"},
+		),
+		metricNetworkCheckFailuresTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.NetworkCheckFailuresTotal.String(),
+				Help:      "Amount of failed CNI CHECKs of pod networks by pod and namespace",
+			},
+			[]string{"pod", "namespace"},
+		),
+		metricNetworkGCReleasedTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.NetworkGCReleasedTotal.String(),
+				Help:      "Amount of stale CNI attachments released by the CNI garbage collection by network",
+			},
+			[]string{"network"},
+		),
+		metricNamespacesGCRemovedTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.NamespacesGCRemovedTotal.String(),
+				Help:      "Amount of orphaned pinned namespaces removed by the namespace garbage collection by namespace type",
+			},
+			[]string{"type"},
+		),
+		metricPodNetworkInterfaceStatistics: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.PodNetworkInterfaceStatistics.String(),
+				Help:      "Statistics of the network interfaces within pod sandboxes by pod, namespace, sandbox, interface and statistic",
+			},
+			[]string{"pod", "namespace", "sandbox", "interface", "statistic"},
+		),
+		metricPodNetworkQueueStatistics: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.PodNetworkQueueStatistics.String(),
+				Help:      "Per-queue statistics of the network interfaces within pod sandboxes by pod, namespace, sandbox, interface, queue and statistic",
+			},
+			[]string{"pod", "namespace", "sandbox", "interface", "queue", "statistic"},
+		),
+		metricPodNetworkSockets: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.PodNetworkSockets.String(),
+				Help:      "Number of sockets within pod sandbox network namespaces by pod, namespace, sandbox and protocol",
+			},
+			[]string{"pod", "namespace", "sandbox", "protocol"},
+		),
+		metricPodNetworkConntrackEntries: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.PodNetworkConntrackEntries.String(),
+				Help:      "Number of connection tracking entries of pod sandbox network namespaces by pod, namespace and sandbox",
+			},
+			[]string{"pod", "namespace", "sandbox"},
+		),
+		metricSeccompNotifierActionsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.SeccompNotifierActionsTotal.String(),
+				Help:      "Number of forbidden and audited syscalls by syscall, container name and seccomp notifier action",
+			},
+			[]string{"name", "syscall", "action"},
+		),
 	}
 	return Instance()
 }
@@ -458,6 +579,124 @@ func (m *Metrics) MetricContainersOOMTotalInc() {
 	m.metricContainersOOMTotal.Inc()
 }
 
+func (m *Metrics) MetricContainersOOMKillsTotalAdd(add float64, name, pod, namespace string) {
+	c, err := m.metricContainersOOMKillsTotal.GetMetricWithLabelValues(name, pod, namespace)
+	if err != nil {
+		logrus.Warnf("Unable to write container OOM kills metric: %v", err)
+		return
+	}
+	c.Add(add)
+}
+
+func (m *Metrics) MetricContainersExecSyncLatencySecondsObserve(name, pod, namespace string, start time.Time) {
+	h, err := m.metricContainersExecSyncLatencySeconds.GetMetricWithLabelValues(name, pod, namespace)
+	if err != nil {
+		logrus.Warnf("Unable to write container exec sync latency metric: %v", err)
+		return
+	}
+	h.Observe(time.Since(start).Seconds())
+}
+
+func (m *Metrics) MetricContainersLogExceededLinesTotalAdd(add float64, name, pod, namespace string) {
+	c, err := m.metricContainersLogExceededLinesTotal.GetMetricWithLabelValues(name, pod, namespace)
+	if err != nil {
+		logrus.Warnf("Unable to write container log exceeded lines metric: %v", err)
+		return
+	}
+	c.Add(add)
+}
+
+func (m *Metrics) MetricAdmissionPolicyDenialsTotalInc(operation, rule string, dryRun bool) {
+	c, err := m.metricAdmissionPolicyDenialsTotal.GetMetricWithLabelValues(operation, rule, strconv.FormatBool(dryRun))
+	if err != nil {
+		logrus.Warnf("Unable to write admission policy denials metric: %v", err)
+		return
+	}
+	c.Inc()
+}
+
+func (m *Metrics) MetricDenyListDenialsTotalInc(operation, denialType, value string) {
+	c, err := m.metricDenyListDenialsTotal.GetMetricWithLabelValues(operation, denialType, value)
+	if err != nil {
+		logrus.Warnf("Unable to write deny list denials metric: %v", err)
+		return
+	}
+	c.Inc()
+}
+
+func (m *Metrics) MetricNetworkCheckFailuresTotalInc(pod, namespace string) {
+	c, err := m.metricNetworkCheckFailuresTotal.GetMetricWithLabelValues(pod, namespace)
+	if err != nil {
+		logrus.Warnf("Unable to write network check failures metric: %v", err)
+		return
+	}
+	c.Inc()
+}
+
+func (m *Metrics) MetricNetworkGCReleasedTotalInc(network string) {
+	c, err := m.metricNetworkGCReleasedTotal.GetMetricWithLabelValues(network)
+	if err != nil {
+		logrus.Warnf("Unable to write network garbage collection metric: %v", err)
+		return
+	}
+	c.Inc()
+}
+
+func (m *Metrics) MetricNamespacesGCRemovedTotalInc(nsType string) {
+	c, err := m.metricNamespacesGCRemovedTotal.GetMetricWithLabelValues(nsType)
+	if err != nil {
+		logrus.Warnf("Unable to write namespace garbage collection metric: %v", err)
+		return
+	}
+	c.Inc()
+}
+
+func (m *Metrics) MetricPodNetworkInterfaceStatisticsSet(value uint64, pod, namespace, sandboxID, iface, statistic string) {
+	g, err := m.metricPodNetworkInterfaceStatistics.GetMetricWithLabelValues(pod, namespace, sandboxID, iface, statistic)
+	if err != nil {
+		logrus.Warnf("Unable to write pod network interface statistics metric: %v", err)
+		return
+	}
+	g.Set(float64(value))
+}
+
+func (m *Metrics) MetricPodNetworkQueueStatisticsSet(value uint64, pod, namespace, sandboxID, iface, queue, statistic string) {
+	g, err := m.metricPodNetworkQueueStatistics.GetMetricWithLabelValues(pod, namespace, sandboxID, iface, queue, statistic)
+	if err != nil {
+		logrus.Warnf("Unable to write pod network queue statistics metric: %v", err)
+		return
+	}
+	g.Set(float64(value))
+}
+
+func (m *Metrics) MetricPodNetworkSocketsSet(value uint64, pod, namespace, sandboxID, protocol string) {
+	g, err := m.metricPodNetworkSockets.GetMetricWithLabelValues(pod, namespace, sandboxID, protocol)
+	if err != nil {
+		logrus.Warnf("Unable to write pod network sockets metric: %v", err)
+		return
+	}
+	g.Set(float64(value))
+}
+
+func (m *Metrics) MetricPodNetworkConntrackEntriesSet(value uint64, pod, namespace, sandboxID string) {
+	g, err := m.metricPodNetworkConntrackEntries.GetMetricWithLabelValues(pod, namespace, sandboxID)
+	if err != nil {
+		logrus.Warnf("Unable to write pod network conntrack entries metric: %v", err)
+		return
+	}
+	g.Set(float64(value))
+}
+
+// MetricPodNetworkDelete removes the network metrics of the provided
+// sandbox, keeping the ones of other sandboxes of the same pod.
+func (m *Metrics) MetricPodNetworkDelete(sandboxID string) {
+	labels := prometheus.Labels{"sandbox": sandboxID}
+	m.metricPodNetworkInterfaceStatistics.DeletePartialMatch(labels)
+	m.metricPodNetworkQueueStatistics.DeletePartialMatch(labels)
+	m.metricPodNetworkSockets.DeletePartialMatch(labels)
+	m.metricPodNetworkConntrackEntries.DeletePartialMatch(labels)
+}
+
 func (m *Metrics) MetricContainersSeccompNotifierCountTotalInc(name, syscall string) {
 	c, err := m.metricContainersSeccompNotifierCountTotal.GetMetricWithLabelValues(name, syscall)
 	if err != nil {
@@ -467,6 +706,15 @@ func (m *Metrics) MetricContainersSeccompNotifierCountTotalInc(name, syscall str
 	c.Inc()
 }
 
+func (m *Metrics) MetricSeccompNotifierActionsTotalInc(name, syscall, action string) {
+	c, err := m.metricSeccompNotifierActionsTotal.GetMetricWithLabelValues(name, syscall, action)
+	if err != nil {
+		logrus.Warnf("Unable to write seccomp notifier actions metric: %v", err)
+		return
+	}
+	c.Inc()
+}
+
 func (m *Metrics) MetricImagePullsLayerSizeObserve(size int64) {
 	m.metricImagePullsLayerSize.Observe(float64(size))
 }
@@ -598,6 +846,19 @@ func (m *Metrics) createEndpoint() (*http.ServeMux, error) {
 		collectors.ContainersOOMCountTotal:             m.metricContainersOOMCountTotal,
 		collectors.ContainersSeccompNotifierCountTotal: m.metricContainersSeccompNotifierCountTotal,
 		collectors.ResourcesStalledAtStage:             m.metricResourcesStalledAtStage,
+		collectors.ContainersOOMKillsTotal:             m.metricContainersOOMKillsTotal,
+		collectors.ContainersExecSyncLatencySeconds:    m.metricContainersExecSyncLatencySeconds,
+		collectors.ContainersLogExceededLinesTotal:     m.metricContainersLogExceededLinesTotal,
+		collectors.AdmissionPolicyDenialsTotal:         m.metricAdmissionPolicyDenialsTotal,
+		collectors.DenyListDenialsTotal:                m.metricDenyListDenialsTotal,
+		collectors.NetworkCheckFailuresTotal:           m.metricNetworkCheckFailuresTotal,
+		collectors.NetworkGCReleasedTotal:              m.metricNetworkGCReleasedTotal,
+		collectors.NamespacesGCRemovedTotal:            m.metricNamespacesGCRemovedTotal,
+		collectors.PodNetworkInterfaceStatistics:       m.metricPodNetworkInterfaceStatistics,
+		collectors.PodNetworkQueueStatistics:           m.metricPodNetworkQueueStatistics,
+		collectors.PodNetworkSockets:                   m.metricPodNetworkSockets,
+		collectors.PodNetworkConntrackEntries:          m.metricPodNetworkConntrackEntries,
+		collectors.SeccompNotifierActionsTotal:         m.metricSeccompNotifierActionsTotal,
 	} {
 		if m.config.MetricsCollectors.Contains(collector) {
 			logrus.Debugf("Enabling metric: %s", collector.Stripped())
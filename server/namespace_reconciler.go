@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/server/metrics"
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// startNamespaceReconciler removes the orphaned pinned namespaces and keeps
+// removing them periodically, if configured.
+func (s *Server) startNamespaceReconciler(ctx context.Context) {
+	if s.config.NamespacesGCPeriod <= 0 {
+		return
+	}
+	period := time.Duration(s.config.NamespacesGCPeriod) * time.Second
+	log.Infof(ctx, "Removing orphaned namespaces every %v", period)
+
+	// No pod is being created before the server starts serving, so all
+	// namespaces not belonging to a restored pod are orphaned.
+	s.gcNamespaces(ctx, time.Now())
+
+	go func() {
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.gcNamespaces(ctx, time.Now().Add(-period))
+			case <-s.monitorsChan:
+				return
+			}
+		}
+	}()
+}
+
+// gcNamespaces removes the pinned namespaces which neither belong to a known
+// pod or container nor have been created after the provided time or the
+// start of any sandbox creation in progress.
+func (s *Server) gcNamespaces(ctx context.Context, createdBefore time.Time) {
+	createdBefore = s.sandboxesCreatingSince(createdBefore)
+	inUse, err := s.namespacesInUse()
+	if err != nil {
+		log.Warnf(ctx, "Unable to find the namespaces in use: %v", err)
+		return
+	}
+	removed, err := s.config.NamespaceManager().RemoveOrphanedNamespaces(func(path string) bool {
+		return inUse[path]
+	}, createdBefore)
+	if err != nil {
+		log.Warnf(ctx, "Unable to remove orphaned namespaces: %v", err)
+		return
+	}
+	for _, ns := range removed {
+		log.Infof(ctx, "Removed orphaned %s namespace %s", ns.Type, ns.Path)
+		metrics.Instance().MetricNamespacesGCRemovedTotalInc(string(ns.Type))
+	}
+}
+
+// sandboxesCreatingSince returns the start time of the oldest sandbox
+// creation in progress, if it is before the provided time. The namespaces of
+// a sandbox are only known once they have been created, which is why all
+// namespaces created since then have to be kept.
+func (s *Server) sandboxesCreatingSince(since time.Time) time.Time {
+	s.sandboxesCreating.Range(func(_, value interface{}) bool {
+		if started, ok := value.(time.Time); ok && started.Before(since) {
+			since = started
+		}
+		return true
+	})
+	return since
+}
+
+// namespacesInUse returns the paths of the namespaces of all known pods and
+// containers.
+func (s *Server) namespacesInUse() (map[string]bool, error) {
+	inUse := make(map[string]bool)
+	for _, sb := range s.ListSandboxes() {
+		for _, ns := range sb.NamespacePaths() {
+			inUse[ns.Path()] = true
+		}
+		if infra := sb.InfraContainer(); infra != nil {
+			addSpecNamespaces(inUse, infra.Spec().Linux)
+		}
+	}
+	ctrs, err := s.ContainerServer.ListContainers()
+	if err != nil {
+		return nil, fmt.Errorf("list containers: %w", err)
+	}
+	for _, ctr := range ctrs {
+		addSpecNamespaces(inUse, ctr.Spec().Linux)
+	}
+	return inUse, nil
+}
+
+// addSpecNamespaces adds the paths of the namespaces joined by a container
+// according to its runtime spec.
+func addSpecNamespaces(inUse map[string]bool, linux *specs.Linux) {
+	if linux == nil {
+		return
+	}
+	for _, ns := range linux.Namespaces {
+		if ns.Path != "" {
+			inUse[ns.Path] = true
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSandboxesCreatingSince(t *testing.T) {
+	s := &Server{}
+	now := time.Now()
+
+	if since := s.sandboxesCreatingSince(now); !since.Equal(now) {
+		t.Fatalf("expected %v without sandboxes being created, got %v", now, since)
+	}
+
+	oldest := now.Add(-time.Hour)
+	s.sandboxesCreating.Store("old", oldest)
+	s.sandboxesCreating.Store("new", now.Add(-time.Minute))
+	if since := s.sandboxesCreatingSince(now); !since.Equal(oldest) {
+		t.Fatalf("expected start of the oldest sandbox creation %v, got %v", oldest, since)
+	}
+
+	before := now.Add(-2 * time.Hour)
+	if since := s.sandboxesCreatingSince(before); !since.Equal(before) {
+		t.Fatalf("expected %v before any sandbox creation, got %v", before, since)
+	}
+
+	s.sandboxesCreating.Delete("old")
+	s.sandboxesCreating.Delete("new")
+	if since := s.sandboxesCreatingSince(now); !since.Equal(now) {
+		t.Fatalf("expected %v after the sandboxes got created, got %v", now, since)
+	}
+}
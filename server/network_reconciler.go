@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	storageTypes "github.com/containers/storage/types"
+	"github.com/cri-o/cri-o/internal/config/cnimgr"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/resourcestore"
+	"github.com/cri-o/cri-o/server/metrics"
+	"github.com/cri-o/ocicni/pkg/ocicni"
+)
+
+// networkReconcileTimeout is the time a single CNI CHECK or DEL issued by the
+// network reconciler may take.
+const networkReconcileTimeout = time.Minute
+
+// startNetworkReconciler starts checking the networks of the running pods and
+// releasing the stale CNI attachments periodically, if configured.
+func (s *Server) startNetworkReconciler(ctx context.Context) {
+	if s.config.CNICheckPeriod > 0 {
+		period := time.Duration(s.config.CNICheckPeriod) * time.Second
+		log.Infof(ctx, "Checking pod networks every %v", period)
+		go s.reconcileNetworksPeriodically(ctx, period, s.checkNetworks)
+	}
+	if s.config.CNIGCPeriod > 0 {
+		period := time.Duration(s.config.CNIGCPeriod) * time.Second
+		log.Infof(ctx, "Releasing stale CNI attachments every %v", period)
+		go s.reconcileNetworksPeriodically(ctx, period, func(ctx context.Context) {
+			s.gcNetworks(ctx, time.Now().Add(-period))
+		})
+	}
+}
+
+func (s *Server) reconcileNetworksPeriodically(ctx context.Context, period time.Duration, reconcile func(context.Context)) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.config.CNIPluginReadyOrError(); err != nil {
+				log.Debugf(ctx, "Skipping network reconciliation, CNI plugin not ready: %v", err)
+				continue
+			}
+			reconcile(ctx)
+		case <-s.monitorsChan:
+			return
+		}
+	}
+}
+
+// checkNetworks runs CNI CHECK on the networks of all running pods and
+// records the result in the sandbox.
+func (s *Server) checkNetworks(ctx context.Context) {
+	for _, sb := range s.ListSandboxes() {
+		if !sb.Created() || sb.Stopped() || sb.HostNetwork() || sb.NetworkStopped() {
+			continue
+		}
+		s.checkNetwork(ctx, sb)
+	}
+}
+
+func (s *Server) checkNetwork(ctx context.Context, sb *sandbox.Sandbox) {
+	podNetwork, err := s.newPodNetwork(ctx, sb)
+	if err == nil {
+		checkCtx, cancel := context.WithTimeout(ctx, networkReconcileTimeout)
+		// GetPodNetworkStatus runs CNI CHECK for all networks supporting it
+		// and verifies the interfaces of the other ones.
+		_, err = s.config.CNIPlugin().GetPodNetworkStatusWithContext(checkCtx, podNetwork)
+		cancel()
+	}
+	// The pod may have been stopped in the meantime.
+	if err != nil && (sb.Stopped() || sb.NetworkStopped()) {
+		return
+	}
+
+	previous := sb.NetworkCheck()
+	sb.SetNetworkCheck(err)
+	if err != nil {
+		log.Warnf(ctx, "CNI CHECK of pod sandbox %s(%s) failed: %v", sb.Name(), sb.ID(), err)
+		metrics.Instance().MetricNetworkCheckFailuresTotalInc(sb.KubeName(), sb.Namespace())
+		return
+	}
+	if previous != nil && previous.Error != "" {
+		log.Infof(ctx, "CNI CHECK of pod sandbox %s(%s) succeeded again", sb.Name(), sb.ID())
+	}
+}
+
+// gcNetworks releases the CNI attachments recorded in the CNI cache which
+// have been created by CRI-O, but neither belong to a known pod nor have been
+// modified after the provided time, for example because their teardown
+// failed.
+func (s *Server) gcNetworks(ctx context.Context, modifiedBefore time.Time) {
+	// The CNI cache is shared with other CNI users on the node, like podman
+	// or other container runtimes, whose attachments must be left alone.
+	marked, err := cnimgr.MarkedPods(s.config.CNIAttachmentsDir)
+	if err != nil {
+		log.Warnf(ctx, "Unable to list CNI attachments created by CRI-O: %v", err)
+		return
+	}
+	attachments, err := cnimgr.CachedAttachments("")
+	if err != nil {
+		log.Warnf(ctx, "Unable to list CNI attachments: %v", err)
+		return
+	}
+	created := func(id string) bool {
+		_, ok := marked[id]
+		return ok
+	}
+
+	for _, podNetwork := range staleNetworks(attachments, created, s.ownsNetwork, modifiedBefore) {
+		podNetwork := podNetwork
+		cleaner := resourcestore.NewResourceCleaner()
+		cleaner.Add(ctx, fmt.Sprintf("release stale network of pod sandbox %s", podNetwork.ID), func() error {
+			stopCtx, cancel := context.WithTimeout(ctx, networkReconcileTimeout)
+			defer cancel()
+			return s.config.CNIPlugin().TearDownPodWithContext(stopCtx, podNetwork)
+		})
+		if err := cleaner.Cleanup(); err != nil {
+			log.Errorf(ctx, "Unable to release stale network of pod sandbox %s(%s_%s): %v",
+				podNetwork.ID, podNetwork.Namespace, podNetwork.Name, err)
+			continue
+		}
+		for _, network := range podNetwork.Networks {
+			log.Infof(ctx, "Released stale CNI attachment %s of network %s of pod sandbox %s(%s_%s)",
+				network.Ifname, network.Name, podNetwork.ID, podNetwork.Namespace, podNetwork.Name)
+			metrics.Instance().MetricNetworkGCReleasedTotalInc(network.Name)
+		}
+		if err := cnimgr.UnmarkPodAttachments(s.config.CNIAttachmentsDir, podNetwork.ID); err != nil {
+			log.Warnf(ctx, "Unable to remove network record of pod sandbox %s: %v", podNetwork.ID, err)
+		}
+	}
+
+	// Records of pods without any attachment left are not needed anymore.
+	cached := make(map[string]bool, len(attachments))
+	for i := range attachments {
+		cached[attachments[i].ContainerID] = true
+	}
+	for id, markedAt := range marked {
+		if cached[id] || markedAt.After(modifiedBefore) || s.ownsNetwork(id) {
+			continue
+		}
+		if err := cnimgr.UnmarkPodAttachments(s.config.CNIAttachmentsDir, id); err != nil {
+			log.Warnf(ctx, "Unable to remove network record of pod sandbox %s: %v", id, err)
+		}
+	}
+}
+
+// ownsNetwork returns true if the provided ID belongs to a known pod or to a
+// container in storage, which includes the pods being created.
+func (s *Server) ownsNetwork(id string) bool {
+	if s.HasSandbox(id) {
+		return true
+	}
+	if _, err := s.Store().Container(id); !errors.Is(err, storageTypes.ErrContainerUnknown) {
+		return true
+	}
+	return false
+}
+
+// staleNetworks groups the cached attachments which have been created by
+// CRI-O, but are neither owned nor modified after the provided time by their
+// pod, in the order they have to be torn down.
+func staleNetworks(attachments []cnimgr.CachedAttachment, created, owned func(id string) bool, modifiedBefore time.Time) []ocicni.PodNetwork {
+	ownedByID := make(map[string]bool)
+	podNetworks := []ocicni.PodNetwork{}
+	indexByID := make(map[string]int)
+	for i := range attachments {
+		a := &attachments[i]
+		if !created(a.ContainerID) {
+			continue
+		}
+		isOwned, ok := ownedByID[a.ContainerID]
+		if !ok {
+			isOwned = owned(a.ContainerID)
+			ownedByID[a.ContainerID] = isOwned
+		}
+		if isOwned {
+			continue
+		}
+		idx, ok := indexByID[a.ContainerID]
+		if !ok {
+			idx = len(podNetworks)
+			indexByID[a.ContainerID] = idx
+			podNetworks = append(podNetworks, ocicni.PodNetwork{
+				Name:          a.PodName,
+				Namespace:     a.PodNamespace,
+				UID:           a.PodUID,
+				ID:            a.ContainerID,
+				RuntimeConfig: map[string]ocicni.RuntimeConfig{},
+			})
+		}
+		podNetworks[idx].Networks = append(podNetworks[idx].Networks, ocicni.NetAttachment{
+			Name:   a.Network,
+			Ifname: a.Ifname,
+		})
+		// A recently modified attachment may belong to a pod being set up
+		// by another process, so the whole pod is skipped.
+		if a.Modified.After(modifiedBefore) {
+			ownedByID[a.ContainerID] = true
+		}
+	}
+
+	stale := []ocicni.PodNetwork{}
+	for i := range podNetworks {
+		if ownedByID[podNetworks[i].ID] {
+			continue
+		}
+		reverseNetworks(&podNetworks[i])
+		stale = append(stale, podNetworks[i])
+	}
+	return stale
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/config/cnimgr"
+	"github.com/cri-o/ocicni/pkg/ocicni"
+)
+
+func TestStaleNetworks(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-time.Hour)
+	attachments := []cnimgr.CachedAttachment{
+		{ContainerID: "known", Network: "default", Ifname: "eth0", Modified: old},
+		{ContainerID: "recent", Network: "default", Ifname: "eth0", Modified: old},
+		{ContainerID: "recent", Network: "storage", Ifname: "net1", Modified: now},
+		{ContainerID: "stale", Network: "default", Ifname: "eth0", PodName: "pod", PodNamespace: "ns", PodUID: "uid", Modified: old},
+		{ContainerID: "stale", Network: "storage", Ifname: "net1", PodName: "pod", PodNamespace: "ns", PodUID: "uid", Modified: old},
+		{ContainerID: "foreign", Network: "default", Ifname: "eth0", PodName: "other", PodNamespace: "ns", PodUID: "other", Modified: old},
+	}
+
+	stale := staleNetworks(attachments, func(id string) bool {
+		return id != "foreign"
+	}, func(id string) bool {
+		return id == "known"
+	}, now.Add(-time.Minute))
+
+	expected := []ocicni.PodNetwork{{
+		Name:      "pod",
+		Namespace: "ns",
+		UID:       "uid",
+		ID:        "stale",
+		Networks: []ocicni.NetAttachment{
+			{Name: "storage", Ifname: "net1"},
+			{Name: "default", Ifname: "eth0"},
+		},
+		RuntimeConfig: map[string]ocicni.RuntimeConfig{},
+	}}
+	if !reflect.DeepEqual(stale, expected) {
+		t.Fatalf("expected stale networks %+v, got %+v", expected, stale)
+	}
+}
+
+func TestStaleNetworksForeign(t *testing.T) {
+	old := time.Now().Add(-time.Hour)
+	attachments := []cnimgr.CachedAttachment{
+		{ContainerID: "podman", Network: "podman", Ifname: "eth0", Modified: old},
+		{ContainerID: "containerd", Network: "default", Ifname: "eth0", PodName: "pod", PodNamespace: "ns", PodUID: "uid", Modified: old},
+	}
+
+	stale := staleNetworks(attachments, func(string) bool {
+		return false
+	}, func(string) bool {
+		return false
+	}, time.Now())
+	if len(stale) != 0 {
+		t.Fatalf("expected foreign attachments to be left alone, got %+v", stale)
+	}
+}
+
+func TestStaleNetworksNone(t *testing.T) {
+	stale := staleNetworks(nil, func(string) bool { return true }, func(string) bool { return false }, time.Now())
+	if len(stale) != 0 {
+		t.Fatalf("expected no stale networks, got %+v", stale)
+	}
+}
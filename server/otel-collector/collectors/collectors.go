@@ -104,6 +104,45 @@ const (
 
 	// ResourcesStalledAtStage is the key for the resources stalled at different stages in container and pod creation.
 	ResourcesStalledAtStage Collector = crioPrefix + "resources_stalled_at_stage"
+
+	// ContainersOOMKillsTotal is the key for the CRI-O processes killed by the OOM killer per container, pod and namespace.
+	ContainersOOMKillsTotal Collector = crioPrefix + "containers_oom_kills_total"
+
+	// ContainersExecSyncLatencySeconds is the key for the CRI-O exec sync latency per container, pod and namespace.
+	ContainersExecSyncLatencySeconds Collector = crioPrefix + "containers_exec_sync_latency_seconds"
+
+	// ContainersLogExceededLinesTotal is the key for the CRI-O container log lines exceeding the log rate limit per container, pod and namespace.
+	ContainersLogExceededLinesTotal Collector = crioPrefix + "containers_log_exceeded_lines_total"
+
+	// AdmissionPolicyDenialsTotal is the key for the CRI-O requests denied by the admission policy per operation, rule and dry run mode.
+	AdmissionPolicyDenialsTotal Collector = crioPrefix + "admission_policy_denials_total"
+
+	// DenyListDenialsTotal is the key for the CRI-O requests denied by the capability and sysctl deny lists per operation, type and value.
+	DenyListDenialsTotal Collector = crioPrefix + "deny_list_denials_total"
+
+	// NetworkCheckFailuresTotal is the key for the failed CNI CHECKs of pod networks per pod and namespace.
+	NetworkCheckFailuresTotal Collector = crioPrefix + "network_check_failures_total"
+
+	// NetworkGCReleasedTotal is the key for the stale CNI attachments released by the CNI garbage collection per network.
+	NetworkGCReleasedTotal Collector = crioPrefix + "network_gc_released_total"
+
+	// NamespacesGCRemovedTotal is the key for the orphaned pinned namespaces removed by the namespace garbage collection per namespace type.
+	NamespacesGCRemovedTotal Collector = crioPrefix + "namespaces_gc_removed_total"
+
+	// PodNetworkInterfaceStatistics is the key for the statistics of the network interfaces within pod sandboxes per pod, namespace, interface and statistic.
+	PodNetworkInterfaceStatistics Collector = crioPrefix + "pod_network_interface_statistics"
+
+	// PodNetworkQueueStatistics is the key for the per-queue statistics of the network interfaces within pod sandboxes per pod, namespace, interface, queue and statistic.
+	PodNetworkQueueStatistics Collector = crioPrefix + "pod_network_queue_statistics"
+
+	// PodNetworkSockets is the key for the sockets within pod sandbox network namespaces per pod, namespace and protocol.
+	PodNetworkSockets Collector = crioPrefix + "pod_network_sockets"
+
+	// PodNetworkConntrackEntries is the key for the connection tracking entries of pod sandbox network namespaces per pod and namespace.
+	PodNetworkConntrackEntries Collector = crioPrefix + "pod_network_conntrack_entries"
+
+	// SeccompNotifierActionsTotal is the key for the CRI-O container seccomp notifier actions per container name, syscall and action.
+	SeccompNotifierActionsTotal Collector = crioPrefix + "containers_seccomp_notifier_actions_total"
 )
 
 // FromSlice converts a string slice to a Collectors type.
@@ -152,6 +191,19 @@ func All() Collectors {
 		ContainersOOMCountTotal.Stripped(),
 		ContainersSeccompNotifierCountTotal.Stripped(),
 		ResourcesStalledAtStage.Stripped(),
+		ContainersOOMKillsTotal.Stripped(),
+		ContainersExecSyncLatencySeconds.Stripped(),
+		ContainersLogExceededLinesTotal.Stripped(),
+		AdmissionPolicyDenialsTotal.Stripped(),
+		DenyListDenialsTotal.Stripped(),
+		NetworkCheckFailuresTotal.Stripped(),
+		NetworkGCReleasedTotal.Stripped(),
+		NamespacesGCRemovedTotal.Stripped(),
+		PodNetworkInterfaceStatistics.Stripped(),
+		PodNetworkQueueStatistics.Stripped(),
+		PodNetworkSockets.Stripped(),
+		PodNetworkConntrackEntries.Stripped(),
+		SeccompNotifierActionsTotal.Stripped(),
 	}
 }
 
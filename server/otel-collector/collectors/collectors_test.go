@@ -62,11 +62,23 @@ var _ = t.Describe("Collectors", func() {
 				collectors.ContainersOOMCountTotal,
 				collectors.ContainersSeccompNotifierCountTotal,
 				collectors.ResourcesStalledAtStage,
+				collectors.ContainersOOMKillsTotal,
+				collectors.ContainersExecSyncLatencySeconds,
+				collectors.ContainersLogExceededLinesTotal,
+				collectors.AdmissionPolicyDenialsTotal,
+				collectors.DenyListDenialsTotal,
+				collectors.NetworkCheckFailuresTotal,
+				collectors.NetworkGCReleasedTotal,
+				collectors.PodNetworkInterfaceStatistics,
+				collectors.PodNetworkQueueStatistics,
+				collectors.PodNetworkSockets,
+				collectors.PodNetworkConntrackEntries,
+				collectors.SeccompNotifierActionsTotal,
 			} {
 				Expect(all.Contains(collector)).To(BeTrue())
 			}
 
-			Expect(all).To(HaveLen(26))
+			Expect(all).To(HaveLen(39))
 		})
 	})
 
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cri-o/cri-o/internal/bandwidth"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	libconfig "github.com/cri-o/cri-o/pkg/config"
+)
+
+// nativeBandwidthShaping returns true if the bandwidth of the sandbox is
+// limited by CRI-O instead of the CNI plugins.
+func (s *Server) nativeBandwidthShaping(sb *sandbox.Sandbox) bool {
+	return s.config.BandwidthShaping == libconfig.BandwidthShapingNative && !sb.HostNetwork()
+}
+
+// applySandboxBandwidth limits the bandwidth of the default interface of the
+// sandbox as requested by its bandwidth annotations, replacing the limits
+// applied before, and records the applied limits in the sandbox. It is called
+// when the network is created and when the sandbox is restored, because the
+// annotations cannot change during the lifetime of the sandbox.
+func (s *Server) applySandboxBandwidth(ctx context.Context, sb *sandbox.Sandbox) error {
+	if !s.nativeBandwidthShaping(sb) {
+		return nil
+	}
+	bwConfig, err := podBandwidthConfig(sb)
+	if err != nil {
+		return err
+	}
+	limits := &bandwidth.Limits{}
+	if bwConfig != nil {
+		limits = &bandwidth.Limits{
+			IngressRate:  bwConfig.IngressRate,
+			IngressBurst: bwConfig.IngressBurst,
+			EgressRate:   bwConfig.EgressRate,
+			EgressBurst:  bwConfig.EgressBurst,
+		}
+	}
+
+	// Applying unlimited limits removes the ones left behind before.
+	if err := bandwidth.Apply(sb.ID(), sb.NetNsPath(), defaultNetworkInterface, limits); err != nil {
+		return fmt.Errorf("limit bandwidth of pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
+	}
+	if !limits.Limited() {
+		sb.SetBandwidthLimits(nil)
+		return nil
+	}
+	applied, err := bandwidth.Current(sb.ID(), sb.NetNsPath(), defaultNetworkInterface)
+	if err != nil {
+		return fmt.Errorf("get bandwidth limits of pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
+	}
+	log.Debugf(ctx, "Limited bandwidth of pod sandbox %s to %+v", sb.ID(), *applied)
+	sb.SetBandwidthLimits(applied)
+	return nil
+}
+
+// removeSandboxBandwidth removes the bandwidth limits applied to the sandbox.
+func (s *Server) removeSandboxBandwidth(ctx context.Context, sb *sandbox.Sandbox) {
+	if !s.nativeBandwidthShaping(sb) || sb.NetNsPath() == "" {
+		return
+	}
+	if err := bandwidth.Remove(sb.ID(), sb.NetNsPath(), defaultNetworkInterface); err != nil {
+		log.Warnf(ctx, "Failed to remove bandwidth limits of pod sandbox %s(%s): %v", sb.Name(), sb.ID(), err)
+	}
+	sb.SetBandwidthLimits(nil)
+}
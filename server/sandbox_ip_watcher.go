@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+
+	"github.com/cri-o/cri-o/internal/addrwatch"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// watchSandboxIPs watches the addresses of the default interface of the
+// sandbox, if configured, and updates the sandbox IPs when they change.
+func (s *Server) watchSandboxIPs(ctx context.Context, sb *sandbox.Sandbox) {
+	if !s.config.WatchPodIPs || sb.HostNetwork() || sb.NetNsPath() == "" {
+		return
+	}
+	s.stopWatchingSandboxIPs(sb)
+
+	// the watcher outlives the request starting it
+	ctx = context.Background()
+	watcher, err := addrwatch.Watch(sb.NetNsPath(), defaultNetworkInterface, func(change *addrwatch.Change) {
+		s.updateSandboxIPs(ctx, sb, change)
+	})
+	if err != nil {
+		log.Warnf(ctx, "Unable to watch the IPs of pod sandbox %s(%s): %v", sb.Name(), sb.ID(), err)
+		return
+	}
+	s.sandboxIPWatchers.Store(sb.ID(), watcher)
+}
+
+// stopWatchingSandboxIPs stops watching the addresses of the sandbox.
+func (s *Server) stopWatchingSandboxIPs(sb *sandbox.Sandbox) {
+	if watcher, ok := s.sandboxIPWatchers.LoadAndDelete(sb.ID()); ok {
+		watcher.(*addrwatch.Watcher).Stop()
+	}
+}
+
+// updateSandboxIPs applies the change of the addresses of the default
+// interface to the sandbox IPs and notifies about the new IPs.
+func (s *Server) updateSandboxIPs(ctx context.Context, sb *sandbox.Sandbox, change *addrwatch.Change) {
+	previous := sb.IPs()
+	ips := change.Apply(previous)
+	if equalIPs(previous, ips) {
+		return
+	}
+	log.Infof(ctx, "IPs of pod sandbox %s(%s) changed from %v to %v", sb.Name(), sb.ID(), previous, ips)
+	sb.AddIPs(ips)
+	if len(sb.PortMappings()) > 0 {
+		log.Warnf(ctx, "Host port mappings of pod sandbox %s(%s) are not updated to the changed IPs", sb.Name(), sb.ID())
+	}
+	// The CRI has no event for changed pod IPs, but the pod sandbox status
+	// sent with the event contains them.
+	s.generateCRIEvent(ctx, sb.InfraContainer(), types.ContainerEventType_CONTAINER_STARTED_EVENT)
+}
+
+func equalIPs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
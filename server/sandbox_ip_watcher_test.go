@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/addrwatch"
+	"github.com/cri-o/cri-o/internal/hostport"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+func TestUpdateSandboxIPs(t *testing.T) {
+	sb, err := sandbox.New("id", "", "", "", ".",
+		map[string]string{}, map[string]string{}, "", "",
+		&types.PodSandboxMetadata{}, "", "/cgroup", false, "", "", "",
+		[]*hostport.PortMapping{}, false, time.Now(), "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb.AddIPs([]string{"10.0.0.2", "fd00::2"})
+	s := &Server{}
+
+	// the addresses present when starting to watch are kept in order
+	s.updateSandboxIPs(context.Background(), sb, &addrwatch.Change{Added: []string{"10.0.0.2", "fd00::2"}})
+	if expected := []string{"10.0.0.2", "fd00::2"}; !reflect.DeepEqual(sb.IPs(), expected) {
+		t.Fatalf("expected IPs %v, got %v", expected, sb.IPs())
+	}
+
+	s.updateSandboxIPs(context.Background(), sb, &addrwatch.Change{
+		Added:   []string{"fd00::3"},
+		Removed: []string{"fd00::2"},
+	})
+	if expected := []string{"10.0.0.2", "fd00::3"}; !reflect.DeepEqual(sb.IPs(), expected) {
+		t.Fatalf("expected IPs %v, got %v", expected, sb.IPs())
+	}
+
+	// watching is disabled by default
+	s.watchSandboxIPs(context.Background(), sb)
+	if _, ok := s.sandboxIPWatchers.Load(sb.ID()); ok {
+		t.Fatal("expected no IP watcher")
+	}
+	s.stopWatchingSandboxIPs(sb)
+}
@@ -8,6 +8,7 @@ import (
 
 	cnitypes "github.com/containernetworking/cni/pkg/types"
 	cnicurrent "github.com/containernetworking/cni/pkg/types/100"
+	"github.com/cri-o/cri-o/internal/config/cnimgr"
 	"github.com/cri-o/cri-o/internal/hostport"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/log"
@@ -58,6 +59,12 @@ func (s *Server) networkStart(ctx context.Context, sb *sandbox.Sandbox) (podIPs
 		}
 	}()
 
+	// The pod gets recorded before its network is created, to release its
+	// attachments even if CRI-O exits in between.
+	if err := cnimgr.MarkPodAttachments(s.config.CNIAttachmentsDir, sb.ID()); err != nil {
+		return nil, nil, fmt.Errorf("failed to record network of pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
+	}
+
 	podSetUpStart := time.Now()
 	_, err = s.config.CNIPlugin().SetUpPodWithContext(startCtx, podNetwork)
 	if err != nil {
@@ -71,10 +78,17 @@ func (s *Server) networkStart(ctx context.Context, sb *sandbox.Sandbox) (podIPs
 		return nil, nil, fmt.Errorf("failed to get network status for pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
 	}
 
-	// only one cnitypes.Result is returned since newPodNetwork sets Networks list empty
+	// the first cnitypes.Result is the one of the default network, followed by
+	// the additional networks of the pod
 	result = podNetworkStatus[0].Result
 	log.Debugf(ctx, "CNI setup result: %v", result)
 
+	attachments, err := networkAttachments(podNetworkStatus)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get network attachments for pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
+	}
+	sb.SetNetworkAttachments(attachments)
+
 	network, err := cnicurrent.GetResult(result)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get network JSON for pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
@@ -123,6 +137,10 @@ func (s *Server) networkStart(ctx context.Context, sb *sandbox.Sandbox) (podIPs
 	}
 	log.Debugf(ctx, "Found POD IPs: %v", podIPs)
 
+	if err := s.applySandboxBandwidth(ctx, sb); err != nil {
+		return nil, nil, err
+	}
+
 	// metric about the whole network setup operation
 	metrics.Instance().MetricOperationsLatencySet("network_setup_overall", overallStart)
 	return podIPs, result, err
@@ -151,6 +169,12 @@ func (s *Server) getSandboxIPs(ctx context.Context, sb *sandbox.Sandbox) ([]stri
 		return nil, fmt.Errorf("failed to get network JSON for pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
 	}
 
+	attachments, err := networkAttachments(podNetworkStatus)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network attachments for pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
+	}
+	sb.SetNetworkAttachments(attachments)
+
 	podIPs := make([]string, 0, len(res.IPs))
 	for _, podIPConfig := range res.IPs {
 		podIPs = append(podIPs, podIPConfig.Address.IP.String())
@@ -164,6 +188,11 @@ func (s *Server) getSandboxIPs(ctx context.Context, sb *sandbox.Sandbox) ([]stri
 func (s *Server) networkStop(ctx context.Context, sb *sandbox.Sandbox) error {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
+	// the addresses removed by the CNI plugins are not pod IP changes
+	s.stopWatchingSandboxIPs(sb)
+	// the replacement of a stopped pod is created before the stopped one is
+	// removed, so it must be able to reserve the same host ports
+	s.releaseSandboxHostports(ctx, sb.ID())
 	if sb.HostNetwork() || sb.NetworkStopped() {
 		return nil
 	}
@@ -182,13 +211,19 @@ func (s *Server) networkStop(ctx context.Context, sb *sandbox.Sandbox) error {
 			sb.Name(), sb.ID(), err)
 	}
 
+	s.removeSandboxBandwidth(ctx, sb)
+
 	podNetwork, err := s.newPodNetwork(ctx, sb)
 	if err != nil {
 		return err
 	}
+	reverseNetworks(&podNetwork)
 	if err := s.config.CNIPlugin().TearDownPodWithContext(stopCtx, podNetwork); err != nil {
 		return fmt.Errorf("failed to destroy network for pod sandbox %s(%s): %w", sb.Name(), sb.ID(), err)
 	}
+	if err := cnimgr.UnmarkPodAttachments(s.config.CNIAttachmentsDir, sb.ID()); err != nil {
+		log.Warnf(ctx, "Unable to remove network record of pod sandbox %s(%s): %v", sb.Name(), sb.ID(), err)
+	}
 
 	return sb.SetNetworkStopped(ctx, true)
 }
@@ -197,11 +232,44 @@ func (s *Server) newPodNetwork(ctx context.Context, sb *sandbox.Sandbox) (ocicni
 	_, span := log.StartSpan(ctx)
 	defer span.End()
 
+	bwConfig, err := podBandwidthConfig(sb)
+	if err != nil {
+		return ocicni.PodNetwork{}, err
+	}
+	if s.nativeBandwidthShaping(sb) {
+		// the bandwidth is limited by applySandboxBandwidth instead
+		bwConfig = nil
+	}
+
+	network := s.config.CNIPlugin().GetDefaultNetworkName()
+	podNetwork := ocicni.PodNetwork{
+		Name:      sb.KubeName(),
+		Namespace: sb.Namespace(),
+		UID:       sb.Metadata().Uid,
+		Networks:  []ocicni.NetAttachment{},
+		ID:        sb.ID(),
+		NetNS:     sb.NetNsPath(),
+		RuntimeConfig: map[string]ocicni.RuntimeConfig{
+			network: {
+				Bandwidth:  bwConfig,
+				CgroupPath: sb.CgroupParent(),
+			},
+		},
+	}
+	if err := addNetworkAttachments(sb, &podNetwork, network); err != nil {
+		return ocicni.PodNetwork{}, err
+	}
+	return podNetwork, nil
+}
+
+// podBandwidthConfig returns the bandwidth configuration requested by the
+// bandwidth annotations of the pod, or nil if it is not limited.
+func podBandwidthConfig(sb *sandbox.Sandbox) (*ocicni.BandwidthConfig, error) {
 	var egress, ingress int64
 	if val, ok := sb.Annotations()["kubernetes.io/egress-bandwidth"]; ok {
 		egressQ, err := resource.ParseQuantity(val)
 		if err != nil {
-			return ocicni.PodNetwork{}, fmt.Errorf("failed to parse egress bandwidth: %w", err)
+			return nil, fmt.Errorf("failed to parse egress bandwidth: %w", err)
 		} else if iegress, isok := egressQ.AsInt64(); isok {
 			egress = iegress
 		}
@@ -209,7 +277,7 @@ func (s *Server) newPodNetwork(ctx context.Context, sb *sandbox.Sandbox) (ocicni
 	if val, ok := sb.Annotations()["kubernetes.io/ingress-bandwidth"]; ok {
 		ingressQ, err := resource.ParseQuantity(val)
 		if err != nil {
-			return ocicni.PodNetwork{}, fmt.Errorf("failed to parse ingress bandwidth: %w", err)
+			return nil, fmt.Errorf("failed to parse ingress bandwidth: %w", err)
 		} else if iingress, isok := ingressQ.AsInt64(); isok {
 			ingress = iingress
 		}
@@ -229,19 +297,5 @@ func (s *Server) newPodNetwork(ctx context.Context, sb *sandbox.Sandbox) (ocicni
 		}
 	}
 
-	network := s.config.CNIPlugin().GetDefaultNetworkName()
-	return ocicni.PodNetwork{
-		Name:      sb.KubeName(),
-		Namespace: sb.Namespace(),
-		UID:       sb.Metadata().Uid,
-		Networks:  []ocicni.NetAttachment{},
-		ID:        sb.ID(),
-		NetNS:     sb.NetNsPath(),
-		RuntimeConfig: map[string]ocicni.RuntimeConfig{
-			network: {
-				Bandwidth:  bwConfig,
-				CgroupPath: sb.CgroupParent(),
-			},
-		},
-	}, nil
+	return bwConfig, nil
 }
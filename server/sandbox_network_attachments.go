@@ -0,0 +1,143 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+
+	cnicurrent "github.com/containernetworking/cni/pkg/types/100"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/pkg/annotations"
+	"github.com/cri-o/ocicni/pkg/ocicni"
+	json "github.com/json-iterator/go"
+)
+
+// defaultNetworkInterface is the interface name of the default network if
+// additional networks are attached.
+const defaultNetworkInterface = "eth0"
+
+// networkAttachmentRequest is an additional network requested by the
+// NetworksAnnotation.
+type networkAttachmentRequest struct {
+	Name      string `json:"name"`
+	Interface string `json:"interface,omitempty"`
+	IP        string `json:"ip,omitempty"`
+	MAC       string `json:"mac,omitempty"`
+}
+
+// parseNetworksAnnotation parses and validates the additional networks
+// requested by the NetworksAnnotation. Interfaces without name are named
+// "net1", "net2" and so on.
+func parseNetworksAnnotation(value, defaultNetwork string) ([]networkAttachmentRequest, error) {
+	requests := []networkAttachmentRequest{}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&requests); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", annotations.NetworksAnnotation, err)
+	}
+
+	networks := map[string]bool{defaultNetwork: true}
+	interfaces := map[string]bool{defaultNetworkInterface: true, "lo": true}
+	for i := range requests {
+		req := &requests[i]
+		if req.Name == "" {
+			return nil, fmt.Errorf("network %d of %s annotation has no name", i, annotations.NetworksAnnotation)
+		}
+		if networks[req.Name] {
+			return nil, fmt.Errorf("network %q is attached more than once", req.Name)
+		}
+		networks[req.Name] = true
+
+		if req.Interface == "" {
+			req.Interface = fmt.Sprintf("net%d", i+1)
+		}
+		if len(req.Interface) > 15 || strings.ContainsAny(req.Interface, "/: \t\n") {
+			return nil, fmt.Errorf("invalid interface name %q for network %q", req.Interface, req.Name)
+		}
+		if interfaces[req.Interface] {
+			return nil, fmt.Errorf("interface name %q of network %q is already used", req.Interface, req.Name)
+		}
+		interfaces[req.Interface] = true
+
+		if req.IP != "" && net.ParseIP(req.IP) == nil {
+			return nil, fmt.Errorf("invalid IP %q for network %q", req.IP, req.Name)
+		}
+		if req.MAC != "" {
+			if _, err := net.ParseMAC(req.MAC); err != nil {
+				return nil, fmt.Errorf("invalid MAC %q for network %q: %w", req.MAC, req.Name, err)
+			}
+		}
+	}
+	return requests, nil
+}
+
+// addNetworkAttachments adds the networks requested by the NetworksAnnotation
+// of the sandbox after the default network to the pod network.
+func addNetworkAttachments(sb *sandbox.Sandbox, podNetwork *ocicni.PodNetwork, defaultNetwork string) error {
+	value, ok := sb.Annotations()[annotations.NetworksAnnotation]
+	if !ok {
+		return nil
+	}
+	requests, err := parseNetworksAnnotation(value, defaultNetwork)
+	if err != nil {
+		return err
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+
+	podNetwork.Networks = append(podNetwork.Networks, ocicni.NetAttachment{
+		Name:   defaultNetwork,
+		Ifname: defaultNetworkInterface,
+	})
+	for _, req := range requests {
+		podNetwork.Networks = append(podNetwork.Networks, ocicni.NetAttachment{
+			Name:   req.Name,
+			Ifname: req.Interface,
+		})
+		podNetwork.RuntimeConfig[req.Name] = ocicni.RuntimeConfig{
+			IP:         req.IP,
+			MAC:        req.MAC,
+			CgroupPath: sb.CgroupParent(),
+		}
+	}
+	return nil
+}
+
+// reverseNetworks reverses the order of the networks of the pod network, so
+// that they get detached in the reverse order of attaching them.
+func reverseNetworks(podNetwork *ocicni.PodNetwork) {
+	networks := podNetwork.Networks
+	for i, j := 0, len(networks)-1; i < j; i, j = i+1, j-1 {
+		networks[i], networks[j] = networks[j], networks[i]
+	}
+}
+
+// networkAttachments converts the CNI results of all networks attached to a
+// pod into its network attachments.
+func networkAttachments(results []ocicni.NetResult) ([]sandbox.NetworkAttachment, error) {
+	attachments := make([]sandbox.NetworkAttachment, 0, len(results))
+	for _, res := range results {
+		result, err := cnicurrent.GetResult(res.Result)
+		if err != nil {
+			return nil, fmt.Errorf("get result of network %q: %w", res.Name, err)
+		}
+		attachment := sandbox.NetworkAttachment{
+			Network:   res.Name,
+			Interface: res.Ifname,
+			IPs:       []string{},
+		}
+		for _, ipConfig := range result.IPs {
+			attachment.IPs = append(attachment.IPs, ipConfig.Address.String())
+		}
+		for _, iface := range result.Interfaces {
+			if iface.Name == res.Ifname && iface.Sandbox != "" {
+				attachment.MAC = iface.Mac
+				break
+			}
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
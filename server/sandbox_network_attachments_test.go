@@ -0,0 +1,121 @@
+package server
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	cnitypes "github.com/containernetworking/cni/pkg/types"
+	cnicurrent "github.com/containernetworking/cni/pkg/types/100"
+	"github.com/cri-o/cri-o/internal/hostport"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/pkg/annotations"
+	"github.com/cri-o/ocicni/pkg/ocicni"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+func TestParseNetworksAnnotation(t *testing.T) {
+	requests, err := parseNetworksAnnotation(
+		`[{"name":"storage","ip":"10.10.0.5","mac":"02:42:ac:11:00:02"},{"name":"backup","interface":"bk0"}]`,
+		"default",
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 networks, got %d", len(requests))
+	}
+	if requests[0].Interface != "net1" || requests[0].IP != "10.10.0.5" {
+		t.Fatalf("unexpected first network %+v", requests[0])
+	}
+	if requests[1].Interface != "bk0" {
+		t.Fatalf("unexpected second network %+v", requests[1])
+	}
+
+	for _, invalid := range []string{
+		`{"name":"storage"}`,
+		`[{"name":""}]`,
+		`[{"name":"default"}]`,
+		`[{"name":"storage"},{"name":"storage","interface":"net2"}]`,
+		`[{"name":"storage","interface":"eth0"}]`,
+		`[{"name":"storage","interface":"a/b"}]`,
+		`[{"name":"storage","interface":"averyverylongname"}]`,
+		`[{"name":"storage","ip":"10.10.0"}]`,
+		`[{"name":"storage","mac":"02:42"}]`,
+		`[{"name":"storage","unknown":"value"}]`,
+	} {
+		if _, err := parseNetworksAnnotation(invalid, "default"); err == nil {
+			t.Fatalf("expected error for %s", invalid)
+		}
+	}
+}
+
+func TestAddNetworkAttachments(t *testing.T) {
+	sb, err := sandbox.New("id", "", "", "", ".",
+		map[string]string{}, map[string]string{
+			annotations.NetworksAnnotation: `[{"name":"storage","ip":"10.10.0.5"}]`,
+		}, "", "",
+		&types.PodSandboxMetadata{}, "", "/cgroup", false, "", "", "",
+		[]*hostport.PortMapping{}, false, time.Now(), "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	podNetwork := ocicni.PodNetwork{RuntimeConfig: map[string]ocicni.RuntimeConfig{}}
+
+	if err := addNetworkAttachments(sb, &podNetwork, "default"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []ocicni.NetAttachment{
+		{Name: "default", Ifname: "eth0"},
+		{Name: "storage", Ifname: "net1"},
+	}
+	if len(podNetwork.Networks) != len(expected) {
+		t.Fatalf("expected networks %v, got %v", expected, podNetwork.Networks)
+	}
+	for i := range expected {
+		if podNetwork.Networks[i] != expected[i] {
+			t.Fatalf("expected networks %v, got %v", expected, podNetwork.Networks)
+		}
+	}
+	if rc := podNetwork.RuntimeConfig["storage"]; rc.IP != "10.10.0.5" || rc.CgroupPath != "/cgroup" {
+		t.Fatalf("unexpected runtime config %+v", rc)
+	}
+
+	reverseNetworks(&podNetwork)
+	if podNetwork.Networks[0].Name != "storage" || podNetwork.Networks[1].Name != "default" {
+		t.Fatalf("expected reversed networks, got %v", podNetwork.Networks)
+	}
+}
+
+func TestNetworkAttachments(t *testing.T) {
+	idx := 0
+	result := &cnicurrent.Result{
+		CNIVersion: cnicurrent.ImplementedSpecVersion,
+		Interfaces: []*cnicurrent.Interface{
+			{Name: "net1", Mac: "02:42:ac:11:00:01"},
+			{Name: "net1", Mac: "02:42:ac:11:00:02", Sandbox: "/var/run/netns/ns"},
+		},
+		IPs: []*cnicurrent.IPConfig{{
+			Interface: &idx,
+			Address:   net.IPNet{IP: net.ParseIP("10.10.0.5"), Mask: net.CIDRMask(24, 32)},
+		}},
+	}
+
+	attachments, err := networkAttachments([]ocicni.NetResult{{
+		Result:        cnitypes.Result(result),
+		NetAttachment: ocicni.NetAttachment{Name: "storage", Ifname: "net1"},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(attachments) != 1 {
+		t.Fatalf("expected one attachment, got %v", attachments)
+	}
+	a := attachments[0]
+	if a.Network != "storage" || a.Interface != "net1" || a.MAC != "02:42:ac:11:00:02" ||
+		len(a.IPs) != 1 || a.IPs[0] != "10.10.0.5/24" {
+		t.Fatalf("unexpected attachment %+v", a)
+	}
+}
@@ -73,6 +73,7 @@ func (s *Server) removePodSandbox(ctx context.Context, sb *sandbox.Sandbox) erro
 	}
 
 	s.ReleasePodName(sb.Name())
+	s.seccompProfilePuller.Forget(sb.ID())
 	if err := s.removeSandbox(ctx, sb.ID()); err != nil {
 		log.Warnf(ctx, "Failed to remove sandbox: %v", err)
 	}
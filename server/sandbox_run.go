@@ -65,6 +65,14 @@ func (s *Server) runtimeHandler(req *types.RunPodSandboxRequest) (string, error)
 
 // RunPodSandbox creates and runs a pod-level sandbox.
 func (s *Server) RunPodSandbox(ctx context.Context, req *types.RunPodSandboxRequest) (*types.RunPodSandboxResponse, error) {
+	if err := s.admitPodSandbox(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkDeniedSysctls(ctx, req); err != nil {
+		return nil, err
+	}
+
 	// platform dependent call
 	return s.runPodSandbox(ctx, req)
 }
@@ -85,6 +93,21 @@ func convertPortMappings(in []*types.PortMapping) []*hostport.PortMapping {
 	return out
 }
 
+// convertContainerPorts converts all port mappings, including those without a
+// host port, in order to keep track of the ports declared by the containers.
+func convertContainerPorts(in []*types.PortMapping) []*hostport.PortMapping {
+	out := make([]*hostport.PortMapping, 0, len(in))
+	for _, v := range in {
+		out = append(out, &hostport.PortMapping{
+			HostPort:      v.HostPort,
+			ContainerPort: v.ContainerPort,
+			Protocol:      v1.Protocol(v.Protocol.String()),
+			HostIP:        v.HostIp,
+		})
+	}
+	return out
+}
+
 func getHostname(id, hostname string, hostNetwork bool) (string, error) {
 	if hostNetwork {
 		if hostname == "" {
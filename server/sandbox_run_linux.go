@@ -18,12 +18,14 @@ import (
 	"github.com/containers/storage/pkg/idtools"
 	"github.com/cri-o/cri-o/internal/config/node"
 	"github.com/cri-o/cri-o/internal/config/nsmgr"
+	"github.com/cri-o/cri-o/internal/dnspolicy"
 	ctrfactory "github.com/cri-o/cri-o/internal/factory/container"
 	sboxfactory "github.com/cri-o/cri-o/internal/factory/sandbox"
 	"github.com/cri-o/cri-o/internal/lib"
 	libsandbox "github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/linklogs"
 	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/mcs"
 	oci "github.com/cri-o/cri-o/internal/oci"
 	"github.com/cri-o/cri-o/internal/resourcestore"
 	"github.com/cri-o/cri-o/internal/runtimehandlerhooks"
@@ -393,6 +395,9 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 		return nil
 	})
 
+	s.sandboxesCreating.Store(sbox.ID(), time.Now())
+	defer s.sandboxesCreating.Delete(sbox.ID())
+
 	s.resourceStore.SetStageForResource(ctx, sbox.Name(), "sandbox creating")
 
 	securityContext := sbox.Config().Linux.SecurityContext
@@ -426,12 +431,32 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 
 	kubeAnnotations := sbox.Config().Annotations
 
+	portMappings, err := sandboxPortMappings(convertPortMappings(sbox.Config().PortMappings), kubeAnnotations, s.config.HostportRangesMaxPorts)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reserveSandboxHostports(ctx, sbox.ID(), kubeName, namespace, portMappings); err != nil {
+		return nil, err
+	}
+	resourceCleaner.Add(ctx, "runSandbox: releasing host ports of pod sandbox: "+sbox.ID(), func() error {
+		s.releaseSandboxHostports(ctx, sbox.ID())
+		return nil
+	})
+
 	usernsMode := kubeAnnotations[ann.UsernsModeAnnotation]
 
 	idMappingsOptions, err := s.configureSandboxIDMappings(usernsMode, sbox.Config().Linux.SecurityContext)
 	if err != nil {
 		return nil, err
 	}
+	idMappingsOptions, err = s.allocateSandboxUserns(ctx, sbox.ID(), kubeName, namespace, kubeAnnotations, idMappingsOptions)
+	if err != nil {
+		return nil, err
+	}
+	resourceCleaner.Add(ctx, "runSandbox: releasing user namespace of pod sandbox: "+sbox.ID(), func() error {
+		s.releaseUsernsAllocation(ctx, sbox.ID())
+		return nil
+	})
 
 	containerName, err := s.ReserveSandboxContainerIDAndName(sbox.Config())
 	if err != nil {
@@ -443,9 +468,17 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	})
 
 	var labelOptions []string
+	mcsSource := mcs.SourceAuto
 	selinuxConfig := securityContext.SelinuxOptions
 	if selinuxConfig != nil {
 		labelOptions = utils.GetLabelOptions(selinuxConfig)
+		if selinuxConfig.Level != "" {
+			mcsSource = mcs.SourceRequested
+		}
+	}
+	if fixed := s.config.SELinuxFixedMCSForNamespace(namespace); fixed != "" && mcsSource == mcs.SourceAuto {
+		labelOptions = append(labelOptions, "level:"+fixed)
+		mcsSource = mcs.SourceFixed
 	}
 
 	privileged := s.privilegedSandbox(req)
@@ -497,9 +530,14 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	}
 
 	// TODO: factor generating/updating the spec into something other projects can vendor
-	if err := sbox.InitInfraContainer(&s.config, &podContainer); err != nil {
+	if err := sbox.InitInfraContainer(&s.config, &podContainer, runtimeHandler); err != nil {
 		return nil, err
 	}
+	if dns := sbox.DNS(); dns != nil {
+		for _, warning := range dns.Warnings {
+			log.Warnf(ctx, "DNS configuration of pod sandbox %s: %s", sbox.Name(), warning)
+		}
+	}
 	pathsToChown = append(pathsToChown, sbox.ResolvPath())
 
 	// add metadata
@@ -543,6 +581,14 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	if hostPID || hostIPC {
 		processLabel, mountLabel = "", ""
 	}
+	if err := s.reserveSandboxMCS(ctx, sbox.ID(), kubeName, namespace, processLabel, mcsSource); err != nil {
+		return nil, fmt.Errorf("reserve SELinux MCS level: %w", err)
+	}
+	resourceCleaner.Add(ctx, "runSandbox: removing SELinux MCS reservation of pod sandbox: "+sbox.ID(), func() error {
+		s.removeSandboxMCS(ctx, sbox.ID())
+		return nil
+	})
+
 	g := sbox.Spec()
 	g.SetProcessSelinuxLabel(processLabel)
 	g.SetLinuxMountLabel(mountLabel)
@@ -658,13 +704,19 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	created := time.Now()
 	g.AddAnnotation(annotations.Created, created.Format(time.RFC3339Nano))
 
-	portMappings := convertPortMappings(sbox.Config().PortMappings)
 	portMappingsJSON, err := json.Marshal(portMappings)
 	if err != nil {
 		return nil, err
 	}
 	g.AddAnnotation(annotations.PortMappings, string(portMappingsJSON))
 
+	containerPorts := convertContainerPorts(sbox.Config().PortMappings)
+	containerPortsJSON, err := json.Marshal(containerPorts)
+	if err != nil {
+		return nil, err
+	}
+	g.AddAnnotation(ann.ContainerPorts, string(containerPortsJSON))
+
 	cgroupParent, cgroupPath, err := s.config.CgroupManager().SandboxCgroupPath(sbox.Config().Linux.CgroupParent, sbox.ID())
 	if err != nil {
 		return nil, err
@@ -705,7 +757,30 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 		return nil, err
 	}
 
-	sb.SetDNSConfig(sbox.Config().DnsConfig)
+	dns := sbox.DNS()
+	if dns == nil && sbox.Config().DnsConfig != nil {
+		dns = &dnspolicy.Result{
+			Servers:  sbox.Config().DnsConfig.Servers,
+			Searches: sbox.Config().DnsConfig.Searches,
+			Options:  sbox.Config().DnsConfig.Options,
+		}
+	}
+	if dns != nil {
+		sb.SetDNSConfig(&types.DNSConfig{
+			Servers:  dns.Servers,
+			Searches: dns.Searches,
+			Options:  dns.Options,
+		})
+		sb.SetDNSWarnings(dns.Warnings)
+
+		// The DNS configuration is persisted to report it after a restart.
+		dnsJSON, err := json.Marshal(dns)
+		if err != nil {
+			return nil, err
+		}
+		g.AddAnnotation(ann.DNSConfig, string(dnsJSON))
+	}
+	sb.SetContainerPorts(containerPorts)
 
 	if err := s.addSandbox(ctx, sb); err != nil {
 		return nil, err
@@ -735,7 +810,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	}
 
 	// Add default sysctls given in crio.conf
-	sysctls := s.configureGeneratorForSysctls(ctx, g, hostNetwork, hostIPC, req.Config.Linux.Sysctls)
+	sysctls := s.configureGeneratorForSysctls(ctx, g, runtimeHandler, hostNetwork, hostIPC, req.Config.Linux.Sysctls)
 
 	// set up namespaces
 	s.resourceStore.SetStageForResource(ctx, sbox.Name(), "sandbox namespace creation")
@@ -884,7 +959,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 
 	seccompRef := types.SecurityProfile_Unconfined.String()
 	if !privileged {
-		_, ref, err := s.config.Seccomp().Setup(
+		_, ref, err := s.config.SeccompForRuntime(runtimeHandler).Setup(
 			ctx,
 			nil,
 			"",
@@ -910,6 +985,10 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 		strings.Contains(strings.ToLower(runtimeHandler), "kata") ||
 		(runtimeHandler == "" && strings.Contains(strings.ToLower(s.config.DefaultRuntime), "kata"))
 
+	if podIsKernelSeparated && sb.Annotations()[ann.CgroupNamespaceAnnotation] != "" {
+		return nil, fmt.Errorf("%s annotation is not supported for kernel separated pods", ann.CgroupNamespaceAnnotation)
+	}
+
 	var container *oci.Container
 	// In the case of kernel separated containers, we need the infra container to create the VM for the pod
 	if sb.NeedsInfra(s.config.DropInfraCtr) || podIsKernelSeparated {
@@ -1035,6 +1114,8 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 
 	sb.SetCreated()
 	s.generateCRIEvent(ctx, sb.InfraContainer(), types.ContainerEventType_CONTAINER_STARTED_EVENT)
+	s.watchOOM(ctx, sb, container)
+	s.watchSandboxIPs(ctx, sb)
 
 	log.Infof(ctx, "Ran pod sandbox %s with infra container: %s", container.ID(), container.Description())
 	resp = &types.RunPodSandboxResponse{PodSandboxId: sbox.ID()}
@@ -1079,11 +1160,11 @@ func setupShm(ctx context.Context, podSandboxRunDir, mountLabel string, shmSize
 	return shmPath, nil
 }
 
-func (s *Server) configureGeneratorForSysctls(ctx context.Context, g *generate.Generator, hostNetwork, hostIPC bool, sysctls map[string]string) map[string]string {
+func (s *Server) configureGeneratorForSysctls(ctx context.Context, g *generate.Generator, runtimeHandler string, hostNetwork, hostIPC bool, sysctls map[string]string) map[string]string {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
 	sysctlsToReturn := make(map[string]string)
-	defaultSysctls, err := s.config.RuntimeConfig.Sysctls()
+	defaultSysctls, err := s.config.RuntimeConfig.SysctlsForRuntime(runtimeHandler)
 	if err != nil {
 		log.Warnf(ctx, "Sysctls invalid: %v", err)
 	}
@@ -1111,6 +1192,57 @@ func (s *Server) configureGeneratorForSysctls(ctx context.Context, g *generate.G
 	return sysctlsToReturn
 }
 
+// addPodTimeNamespace adds the time namespace requested by the pod
+// annotations to the namespaces pinned for the pod.
+func addPodTimeNamespace(kubeAnnotations map[string]string, namespaceConfig *nsmgr.PodNamespacesConfig) error {
+	value, ok := kubeAnnotations[ann.TimeNamespaceAnnotation]
+	if !ok {
+		return nil
+	}
+	offsets, err := nsmgr.ParseTimeOffsets(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s annotation: %w", ann.TimeNamespaceAnnotation, err)
+	}
+	namespaceConfig.TimeOffsets = offsets
+	namespaceConfig.Namespaces = append(namespaceConfig.Namespaces, &nsmgr.PodNamespaceConfig{
+		Type: nsmgr.TIMENS,
+	})
+	return nil
+}
+
+// addPodCgroupNamespace adds the cgroup namespace shared by the pod containers
+// to the namespaces pinned for the pod, if requested by the pod annotations.
+// The namespace is created within the pod cgroup, so that it is the root of the
+// namespace and the containers see their own cgroups below it. The pod cgroup
+// is usually created by the kubelet, but is created here if it does not exist
+// yet, for example with crictl.
+func (s *Server) addPodCgroupNamespace(sb *libsandbox.Sandbox, namespaceConfig *nsmgr.PodNamespacesConfig) error {
+	value, ok := sb.Annotations()[ann.CgroupNamespaceAnnotation]
+	if !ok {
+		return nil
+	}
+	if value != ann.CgroupNamespacePod {
+		return fmt.Errorf("invalid %s annotation %q, must be %q", ann.CgroupNamespaceAnnotation, value, ann.CgroupNamespacePod)
+	}
+	if !node.CgroupIsV2() {
+		return fmt.Errorf("%s annotation requires cgroup v2", ann.CgroupNamespaceAnnotation)
+	}
+	cgroupPath, err := s.config.CgroupManager().PodCgroupPath(sb.CgroupParent())
+	if err != nil {
+		return fmt.Errorf("get pod cgroup of sandbox %s: %w", sb.ID(), err)
+	}
+	// The pod cgroup is usually created by the kubelet, but the namespace
+	// has to be unshared within it before any container got created.
+	if err := s.config.CgroupManager().CreatePodCgroup(sb.CgroupParent()); err != nil {
+		return fmt.Errorf("create pod cgroup of sandbox %s: %w", sb.ID(), err)
+	}
+	namespaceConfig.CgroupPath = filepath.Join("/sys/fs/cgroup", cgroupPath)
+	namespaceConfig.Namespaces = append(namespaceConfig.Namespaces, &nsmgr.PodNamespaceConfig{
+		Type: nsmgr.CGROUPNS,
+	})
+	return nil
+}
+
 // configureGeneratorForSandboxNamespaces set the linux namespaces for the generator, based on whether the pod is sharing namespaces with the host,
 // as well as whether CRI-O should be managing the namespace lifecycle.
 // it returns a slice of cleanup funcs, all of which are the respective NamespaceRemove() for the sandbox.
@@ -1146,6 +1278,12 @@ func (s *Server) configureGeneratorForSandboxNamespaces(ctx context.Context, hos
 			Type: nsmgr.USERNS,
 		})
 	}
+	if err := addPodTimeNamespace(sb.Annotations(), namespaceConfig); err != nil {
+		return nil, err
+	}
+	if err := s.addPodCgroupNamespace(sb, namespaceConfig); err != nil {
+		return nil, err
+	}
 
 	// now that we've configured the namespaces we're sharing, create them
 	namespaces, err := s.config.NamespaceManager().NewPodNamespaces(namespaceConfig)
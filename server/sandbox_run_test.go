@@ -8,6 +8,8 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
@@ -67,6 +69,32 @@ var _ = t.Describe("RunPodSandbox", func() {
 			Expect(response).To(BeNil())
 		})
 
+		It("should fail when setting a denied sysctl", func() {
+			// Given
+			serverConfig.DeniedSysctls = []string{"net.ipv4.conf.*"}
+			setupSUT()
+
+			// When
+			response, err := sut.RunPodSandbox(context.Background(),
+				&types.RunPodSandboxRequest{Config: &types.PodSandboxConfig{
+					Metadata: &types.PodSandboxMetadata{
+						Name:      "name",
+						Namespace: "default",
+						Uid:       "uid",
+					},
+					Linux: &types.LinuxPodSandboxConfig{
+						Sysctls: map[string]string{
+							"net.ipv4.conf.all.forwarding": "1",
+						},
+					},
+				}})
+
+			// Then
+			Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+			Expect(err.Error()).To(ContainSubstring("net.ipv4.conf.all.forwarding"))
+			Expect(response).To(BeNil())
+		})
+
 		It("should fail when metadata is nil", func() {
 			// Given
 			// When
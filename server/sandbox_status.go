@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/cri-o/cri-o/internal/dnspolicy"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/internal/oci"
 	json "github.com/json-iterator/go"
@@ -75,6 +76,39 @@ func (s *Server) PodSandboxStatus(ctx context.Context, req *types.PodSandboxStat
 		if err != nil {
 			return nil, fmt.Errorf("creating sandbox info: %w", err)
 		}
+		if attachments := sb.NetworkAttachments(); len(attachments) > 0 {
+			bytes, err := json.Marshal(attachments)
+			if err != nil {
+				return nil, fmt.Errorf("marshal network attachments: %w", err)
+			}
+			info["networks"] = string(bytes)
+		}
+		if check := sb.NetworkCheck(); check != nil {
+			bytes, err := json.Marshal(check)
+			if err != nil {
+				return nil, fmt.Errorf("marshal network check: %w", err)
+			}
+			info["networkCheck"] = string(bytes)
+		}
+		if limits := sb.BandwidthLimits(); limits != nil {
+			bytes, err := json.Marshal(limits)
+			if err != nil {
+				return nil, fmt.Errorf("marshal bandwidth limits: %w", err)
+			}
+			info["bandwidth"] = string(bytes)
+		}
+		if dnsConfig := sb.DNSConfig(); dnsConfig != nil {
+			bytes, err := json.Marshal(&dnspolicy.Result{
+				Servers:  dnsConfig.Servers,
+				Searches: dnsConfig.Searches,
+				Options:  dnsConfig.Options,
+				Warnings: sb.DNSWarnings(),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("marshal DNS configuration: %w", err)
+			}
+			info["dns"] = string(bytes)
+		}
 		resp.Info = info
 	}
 
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"time"
+
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/mcs"
+	"github.com/cri-o/cri-o/pkg/types"
+	selinux "github.com/opencontainers/selinux/go-selinux"
+	"github.com/opencontainers/selinux/go-selinux/label"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// startMCSTracker loads the persisted SELinux MCS reservations and reserves
+// their levels again, so that no pod created before the existing ones got
+// restored can be assigned one of them.
+func (s *Server) startMCSTracker() error {
+	if !s.config.SELinux || !selinux.GetEnabled() {
+		return nil
+	}
+	tracker, err := mcs.New(s.config.SELinuxMCSReservationsFile)
+	if err != nil {
+		return err
+	}
+	for _, r := range tracker.List() {
+		if err := label.ReserveLabel(r.Label); err != nil {
+			logrus.Warnf("Unable to reserve MCS level %s of pod %s: %v", r.Level, r.PodID, err)
+		}
+	}
+	logrus.Infof("Tracking SELinux MCS levels, %d levels currently reserved", len(tracker.List()))
+	s.mcsTracker = tracker
+	return nil
+}
+
+// reconcileMCSReservations releases the MCS levels of pods which do not exist
+// anymore, tracks the levels of restored pods which are not tracked yet and
+// reports the levels shared by pods which should be isolated from each other.
+func (s *Server) reconcileMCSReservations(ctx context.Context) {
+	if s.mcsTracker == nil {
+		return
+	}
+	sandboxes := make(map[string]bool)
+	levels := make(map[string]bool)
+	for _, sb := range s.ListSandboxes() {
+		sandboxes[sb.ID()] = true
+		levels[mcs.LevelOf(sb.ProcessLabel())] = true
+	}
+
+	pruned, err := s.mcsTracker.Prune(func(podID string) bool {
+		return sandboxes[podID]
+	})
+	if err != nil {
+		log.Warnf(ctx, "Unable to release MCS levels of removed pods: %v", err)
+	}
+	for _, r := range pruned {
+		if levels[r.Level] || s.mcsTracker.InUse(r.Level) {
+			continue
+		}
+		if err := label.ReleaseLabel(r.Label); err != nil {
+			log.Warnf(ctx, "Unable to release MCS level %s of removed pod %s: %v", r.Level, r.PodID, err)
+			continue
+		}
+		log.Infof(ctx, "Released MCS level %s of removed pod %s", r.Level, r.PodID)
+	}
+
+	for _, sb := range s.ListSandboxes() {
+		if s.mcsTracker.Get(sb.ID()) != nil {
+			continue
+		}
+		level := mcs.LevelOf(sb.ProcessLabel())
+		source := mcs.SourceAuto
+		if level != "" && level == s.config.SELinuxFixedMCSForNamespace(sb.Namespace()) {
+			source = mcs.SourceFixed
+		}
+		if _, err := s.mcsTracker.Add(&mcs.Reservation{
+			PodID:     sb.ID(),
+			Pod:       sb.KubeName(),
+			Namespace: sb.Namespace(),
+			Label:     sb.ProcessLabel(),
+			Level:     level,
+			Source:    source,
+			Created:   time.Unix(0, sb.CreatedAt()),
+		}); err != nil {
+			log.Warnf(ctx, "Unable to track MCS level of pod %s: %v", sb.ID(), err)
+		}
+	}
+
+	for _, c := range s.mcsTracker.Collisions() {
+		log.Warnf(ctx, "SELinux MCS level %s is shared by pods %v, which are not isolated from each other", c.Level, c.PodIDs)
+	}
+}
+
+// reserveSandboxMCS tracks the MCS level of a newly created pod and reports
+// the pods it collides with.
+func (s *Server) reserveSandboxMCS(ctx context.Context, podID, pod, namespace, processLabel string, source mcs.Source) error {
+	if s.mcsTracker == nil {
+		return nil
+	}
+	collisions, err := s.mcsTracker.Add(&mcs.Reservation{
+		PodID:     podID,
+		Pod:       pod,
+		Namespace: namespace,
+		Label:     processLabel,
+		Level:     mcs.LevelOf(processLabel),
+		Source:    source,
+	})
+	if err != nil {
+		return err
+	}
+	for _, c := range collisions {
+		log.Errorf(ctx, "SELinux MCS level %s of pod %s/%s collides with pod %s/%s (%s)", c.Level, namespace, pod, c.Namespace, c.Pod, c.PodID)
+	}
+	return nil
+}
+
+// removeSandboxMCS stops tracking the MCS level of the provided pod. The level
+// itself is released together with the sandbox.
+func (s *Server) removeSandboxMCS(ctx context.Context, podID string) {
+	if s.mcsTracker == nil {
+		return
+	}
+	if _, err := s.mcsTracker.Remove(podID); err != nil {
+		log.Warnf(ctx, "Unable to remove MCS reservation of pod %s: %v", podID, err)
+	}
+}
+
+// getSELinuxInfo returns the MCS levels reserved for pods and the collisions
+// between them.
+func (s *Server) getSELinuxInfo() types.SELinuxInfo {
+	info := types.SELinuxInfo{
+		Reservations: []types.SELinuxReservation{},
+		Collisions:   []types.SELinuxCollision{},
+	}
+	if s.mcsTracker == nil {
+		return info
+	}
+	info.Enabled = true
+	for _, r := range s.mcsTracker.List() {
+		info.Reservations = append(info.Reservations, types.SELinuxReservation{
+			PodID:       r.PodID,
+			Pod:         r.Pod,
+			Namespace:   r.Namespace,
+			Label:       r.Label,
+			Level:       r.Level,
+			Source:      string(r.Source),
+			CreatedTime: r.Created.UnixNano(),
+		})
+	}
+	for _, c := range s.mcsTracker.Collisions() {
+		info.Collisions = append(info.Collisions, types.SELinuxCollision{
+			Level:  c.Level,
+			PodIDs: c.PodIDs,
+		})
+	}
+	return info
+}
@@ -26,17 +26,26 @@ import (
 	imageTypes "github.com/containers/image/v5/types"
 	"github.com/containers/storage/pkg/idtools"
 	storageTypes "github.com/containers/storage/types"
+	"github.com/cri-o/cri-o/internal/audit"
+	"github.com/cri-o/cri-o/internal/config/node"
 	"github.com/cri-o/cri-o/internal/config/seccomp"
 	"github.com/cri-o/cri-o/internal/hostport"
 	"github.com/cri-o/cri-o/internal/lib"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/logforward"
+	"github.com/cri-o/cri-o/internal/loglimit"
+	"github.com/cri-o/cri-o/internal/mcs"
 	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/internal/oom"
 	"github.com/cri-o/cri-o/internal/resourcestore"
 	"github.com/cri-o/cri-o/internal/runtimehandlerhooks"
+	"github.com/cri-o/cri-o/internal/seccompimage"
 	"github.com/cri-o/cri-o/internal/signals"
 	"github.com/cri-o/cri-o/internal/storage"
+	"github.com/cri-o/cri-o/internal/userns"
 	"github.com/cri-o/cri-o/internal/version"
+	crioann "github.com/cri-o/cri-o/pkg/annotations"
 	libconfig "github.com/cri-o/cri-o/pkg/config"
 	"github.com/cri-o/cri-o/server/metrics"
 	"github.com/cri-o/cri-o/utils"
@@ -80,6 +89,17 @@ type Server struct {
 
 	minimumMappableUID, minimumMappableGID int64
 
+	// usernsAllocator assigns the user namespace ranges of pods using the
+	// "auto" user namespace mode, nil if not configured.
+	usernsAllocator *userns.Allocator
+
+	// mcsTracker persists the SELinux MCS levels reserved for pods, nil if
+	// SELinux is disabled.
+	mcsTracker *mcs.Tracker
+
+	// hostportRegistry persists the host ports reserved by pods.
+	hostportRegistry *hostport.Registry
+
 	// pullOperationsInProgress is used to avoid pulling the same image in parallel. Goroutines
 	// will block on the pullResult.
 	pullOperationsInProgress map[pullArguments]*pullOperation
@@ -91,6 +111,34 @@ type Server struct {
 	seccompNotifierChan chan seccomp.Notification
 	seccompNotifiers    sync.Map
 
+	// sandboxIPWatchers are the address watchers of the sandboxes by ID,
+	// if watch_pod_ips is enabled.
+	sandboxIPWatchers sync.Map
+
+	// sandboxesCreating are the start times of the sandboxes being created
+	// by ID. The namespace reconciler keeps the namespaces created since then,
+	// because they are not known to belong to a sandbox yet.
+	sandboxesCreating sync.Map
+
+	// seccompProfilePuller pulls and caches the seccomp profiles referenced
+	// by images.
+	seccompProfilePuller *seccompimage.Puller
+
+	// oomWatcher reports OOM kills in container cgroups, nil if not
+	// supported on the node.
+	oomWatcher *oom.Watcher
+
+	// logLimitEnforcer checks the log rate limits of the containers.
+	logLimitEnforcer *loglimit.Enforcer
+
+	// logForwarder forwards the container logs to the configured log
+	// forward socket, nil if not configured.
+	logForwarder *logforward.Forwarder
+
+	// auditLogger writes the audit log of exec, attach, port forward and
+	// privileged container creation requests, nil if not configured.
+	auditLogger *audit.Logger
+
 	containerEventClients           sync.Map
 	containerEventStreamBroadcaster sync.Once
 
@@ -308,6 +356,12 @@ func (s *Server) restore(ctx context.Context) []string {
 			continue
 		}
 		sb.AddIPs(ips)
+		if !sb.Stopped() && !sb.NetworkStopped() {
+			if err := s.applySandboxBandwidth(ctx, sb); err != nil {
+				log.Warnf(ctx, "Could not restore bandwidth limits for %v: %v", sb.ID(), err)
+			}
+			s.watchSandboxIPs(ctx, sb)
+		}
 	}
 
 	// Return a slice of images to remove, if internal_wipe is set.
@@ -328,6 +382,12 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		return err
 	}
 
+	if s.auditLogger != nil {
+		if err := s.auditLogger.Close(); err != nil {
+			log.Warnf(ctx, "Unable to close audit log: %v", err)
+		}
+	}
+
 	// first, make sure we sync all the changes to the file system holding
 	// the graph root
 	if err := utils.Syncfs(s.Store().GraphRoot()); err != nil {
@@ -463,6 +523,7 @@ func New(
 		minimumMappableGID:       config.MinimumMappableGID,
 		pullOperationsInProgress: make(map[pullArguments]*pullOperation),
 		resourceStore:            resourcestore.New(),
+		seccompProfilePuller:     seccompimage.New(),
 	}
 	if s.config.EnablePodEvents {
 		// creating a container events channel only if the evented pleg is enabled
@@ -483,8 +544,23 @@ func New(
 		return nil, fmt.Errorf("close stdin: %w", err)
 	}
 
+	if err := s.startUsernsAllocator(); err != nil {
+		return nil, fmt.Errorf("start user namespace allocator: %w", err)
+	}
+
+	if err := s.startMCSTracker(); err != nil {
+		return nil, fmt.Errorf("start SELinux MCS tracker: %w", err)
+	}
+
+	if err := s.startHostportRegistry(); err != nil {
+		return nil, fmt.Errorf("start host port registry: %w", err)
+	}
+
 	deletedImages := s.restore(ctx)
 	s.wipeIfAppropriate(ctx, deletedImages)
+	s.pruneUsernsAllocations(ctx)
+	s.reconcileMCSReservations(ctx)
+	s.reconcileHostportReservations(ctx)
 
 	var bindAddressStr string
 	bindAddress := net.ParseIP(config.StreamAddress)
@@ -559,6 +635,24 @@ func New(
 		return nil, fmt.Errorf("start seccomp notifier watcher: %w", err)
 	}
 
+	if err := s.startOOMWatcher(ctx); err != nil {
+		return nil, fmt.Errorf("start OOM watcher: %w", err)
+	}
+
+	s.startLogLimitEnforcer(ctx)
+
+	s.startNetworkReconciler(ctx)
+
+	s.startNamespaceReconciler(ctx)
+
+	if err := s.startLogForwarder(ctx); err != nil {
+		return nil, fmt.Errorf("start log forwarder: %w", err)
+	}
+
+	if err := s.startAuditLogger(); err != nil {
+		return nil, fmt.Errorf("start audit logger: %w", err)
+	}
+
 	// Set up our NRI adaptation.
 	api, err := nriIf.New(s.Config().NRI)
 	if err != nil {
@@ -689,6 +783,9 @@ func (s *Server) getSandbox(ctx context.Context, id string) *sandbox.Sandbox {
 func (s *Server) removeSandbox(ctx context.Context, id string) error {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
+	s.releaseUsernsAllocation(ctx, id)
+	s.removeSandboxMCS(ctx, id)
+	s.releaseSandboxHostports(ctx, id)
 	return s.ContainerServer.RemoveSandbox(ctx, id)
 }
 
@@ -713,12 +810,20 @@ func (s *Server) getInfraContainer(ctx context.Context, id string) *oci.Containe
 func (s *Server) removeContainer(ctx context.Context, c *oci.Container) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
+	s.unwatchOOM(c)
+	if s.logLimitEnforcer != nil {
+		s.logLimitEnforcer.Remove(c.ID())
+	}
+	if s.logForwarder != nil {
+		s.logForwarder.Remove(c.ID())
+	}
 	s.ContainerServer.RemoveContainer(ctx, c)
 }
 
 func (s *Server) removeInfraContainer(ctx context.Context, c *oci.Container) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
+	s.unwatchOOM(c)
 	s.ContainerServer.RemoveInfraContainer(ctx, c)
 }
 
@@ -754,9 +859,13 @@ func (s *Server) MonitorsCloseChan() chan struct{} {
 	return s.monitorsChan
 }
 
+// seccompNotifierChanSize is the number of seccomp notifications buffered
+// until the notifiers block, or drop them for audited syscalls.
+const seccompNotifierChanSize = 1024
+
 func (s *Server) startSeccompNotifierWatcher(ctx context.Context) error {
 	logrus.Info("Starting seccomp notifier watcher")
-	s.seccompNotifierChan = make(chan seccomp.Notification)
+	s.seccompNotifierChan = make(chan seccomp.Notification, seccompNotifierChanSize)
 
 	// Restore or cleanup
 	notifierPath := s.config.Seccomp().NotifierPath()
@@ -818,6 +927,7 @@ func (s *Server) startSeccompNotifierWatcher(ctx context.Context) error {
 			ctx := msg.Ctx()
 			id := msg.ContainerID()
 			syscall := msg.Syscall()
+			action := msg.Action()
 
 			log.Infof(ctx, "Got seccomp notifier message for container ID: %s (syscall = %s)", id, syscall)
 
@@ -831,34 +941,273 @@ func (s *Server) startSeccompNotifierWatcher(ctx context.Context) error {
 				log.Errorf(ctx, "Notifier is not a seccomp notifier type")
 				continue
 			}
-			notifier.AddSyscall(syscall)
+			if action != crioann.SeccompNotifierActionAudit {
+				// Audited syscalls are allowed and therefore not forbidden.
+				notifier.AddSyscall(syscall)
+			}
 
 			ctr := s.ContainerServer.GetContainer(ctx, id)
 			usedSyscalls := notifier.UsedSyscalls()
+			stop := func() {
+				state := ctr.StateNoLock()
+				state.SeccompKilled = true
+				state.Error = "Used forbidden syscalls: " + usedSyscalls
+
+				if err := s.stopContainer(context.Background(), ctr, 0); err != nil {
+					log.Errorf(ctx, "Unable to stop container %s: %v", id, err)
+				}
+			}
 
-			if notifier.StopContainers() {
+			switch action {
+			case crioann.SeccompNotifierActionStop:
 				// Stop the container only if the notifier timer has expired
 				// The timer will be refreshed after each call to OnExpired.
 				notifier.OnExpired(func() {
-					log.Infof(ctx, "Seccomp notifier timer expired, stopping container %s", id)
-
-					state := ctr.StateNoLock()
-					state.SeccompKilled = true
-					state.Error = "Used forbidden syscalls: " + usedSyscalls
-
-					if err := s.stopContainer(context.Background(), ctr, 0); err != nil {
-						log.Errorf(ctx, "Unable to stop container %s: %v", id, err)
+					if notifier.ShouldStop() {
+						log.Infof(ctx, "Seccomp notifier timer expired, stopping container %s", id)
+						stop()
 					}
 				})
+			case crioann.SeccompNotifierActionKill:
+				// The notifier already killed the calling process, the
+				// rest of the container gets stopped in the background to
+				// not block further notifications.
+				if notifier.ShouldStop() {
+					log.Infof(ctx, "Stopping container %s because of seccomp notifier kill action for syscall %s", id, syscall)
+					go stop()
+				}
+			case crioann.SeccompNotifierActionAudit:
+				s.auditSeccompSyscall(ctr, &msg)
 			}
 
-			metrics.Instance().MetricContainersSeccompNotifierCountTotalInc(ctr.Name(), syscall)
+			if action != crioann.SeccompNotifierActionAudit {
+				metrics.Instance().MetricContainersSeccompNotifierCountTotalInc(ctr.Name(), syscall)
+			}
+			metrics.Instance().MetricSeccompNotifierActionsTotalInc(ctr.Name(), syscall, action)
+		}
+	}()
+
+	return nil
+}
+
+// writeRecordedSeccompProfile writes the seccomp profile recorded for the
+// provided container, if recording is enabled.
+func (s *Server) writeRecordedSeccompProfile(ctx context.Context, c *oci.Container) {
+	result, ok := s.seccompNotifiers.Load(c.ID())
+	if !ok {
+		return
+	}
+	notifier, ok := result.(*seccomp.Notifier)
+	if !ok || !notifier.Recording() {
+		return
+	}
+
+	profilePath, ok := c.CrioAnnotations()[crioann.SeccompProfileRecordPath]
+	if !ok {
+		log.Warnf(ctx, "Unable to find seccomp profile path of container %s", c.ID())
+		return
+	}
+	if err := notifier.WriteProfile(profilePath); err != nil {
+		log.Errorf(ctx, "Unable to write recorded seccomp profile of container %s: %v", c.ID(), err)
+		return
+	}
+	log.Infof(ctx, "Wrote recorded seccomp profile of container %s to %s", c.ID(), profilePath)
+}
+
+// startOOMWatcher starts watching the cgroup v2 memory events of all running
+// containers and sandboxes for OOM kills.
+func (s *Server) startOOMWatcher(ctx context.Context) error {
+	if !node.CgroupIsV2() {
+		log.Debugf(ctx, "Not starting OOM watcher because cgroup v2 is not enabled")
+		return nil
+	}
+
+	logrus.Info("Starting OOM watcher")
+	watcher, err := oom.New()
+	if err != nil {
+		return err
+	}
+	s.oomWatcher = watcher
+
+	// Watch the containers which are already running
+	for _, sb := range s.ListSandboxes() {
+		if infra := sb.InfraContainer(); infra != nil {
+			s.watchOOM(ctx, sb, infra)
+		}
+		for _, c := range sb.Containers().List() {
+			s.watchOOM(ctx, sb, c)
+		}
+	}
+
+	watcher.Start(s.monitorsChan)
+	go func() {
+		for {
+			select {
+			case event := <-watcher.Events():
+				s.handleOOMEvent(ctx, event)
+			case <-s.monitorsChan:
+				return
+			}
 		}
 	}()
 
 	return nil
 }
 
+// watchOOM adds the cgroup of the provided container to the OOM watcher.
+func (s *Server) watchOOM(ctx context.Context, sb *sandbox.Sandbox, c *oci.Container) {
+	if s.oomWatcher == nil || c.Spoofed() || c.State().Status != oci.ContainerStateRunning {
+		return
+	}
+
+	cgroupPath, err := s.config.CgroupManager().ContainerCgroupAbsolutePath(sb.CgroupParent(), c.ID())
+	if err != nil {
+		log.Warnf(ctx, "Unable to get cgroup path to watch container %s for OOM kills: %v", c.ID(), err)
+		return
+	}
+
+	if err := s.oomWatcher.Add(c.ID(), cgroupPath); err != nil {
+		// Not all runtimes create a cgroup for the container on the host,
+		// for example VM based ones.
+		log.Debugf(ctx, "Unable to watch container %s for OOM kills: %v", c.ID(), err)
+	}
+}
+
+// unwatchOOM removes the cgroup of the provided container from the OOM watcher.
+func (s *Server) unwatchOOM(c *oci.Container) {
+	if s.oomWatcher == nil {
+		return
+	}
+	s.oomWatcher.Remove(c.ID())
+}
+
+// handleOOMEvent reports an OOM kill observed by the OOM watcher.
+func (s *Server) handleOOMEvent(ctx context.Context, event *oom.Event) {
+	resource := "container"
+	c := s.GetContainer(ctx, event.ID)
+	if c == nil {
+		sb := s.GetSandbox(event.ID)
+		if sb == nil {
+			return
+		}
+		c = sb.InfraContainer()
+		resource = "sandbox infra container"
+	}
+
+	labels := c.Labels()
+	name := labels[kubetypes.KubernetesContainerNameLabel]
+	pod := labels[kubetypes.KubernetesPodNameLabel]
+	namespace := labels[kubetypes.KubernetesPodNamespaceLabel]
+
+	log.WithFields(ctx, map[string]interface{}{
+		"containerID": c.ID(),
+		"sandboxID":   c.Sandbox(),
+		"pod":         pod,
+		"namespace":   namespace,
+		"count":       event.Count,
+	}).Warnf("OOM killer killed %s in %s %s", event.Process, resource, c.Description())
+
+	metrics.Instance().MetricContainersOOMKillsTotalAdd(float64(event.Count), name, pod, namespace)
+
+	// The CRI does not define an OOM event type. Containers exiting because
+	// of the OOM kill emit their stopped event when their exit is handled,
+	// while containers surviving the kill of a non init process do not
+	// change their state and therefore emit no event at all.
+}
+
+// startLogLimitEnforcer starts checking the log rate limits of the
+// containers, including the ones which are already running.
+func (s *Server) startLogLimitEnforcer(ctx context.Context) {
+	s.logLimitEnforcer = loglimit.New()
+
+	for _, sb := range s.ListSandboxes() {
+		for _, c := range sb.Containers().List() {
+			// The limits are not part of the container state, which is
+			// why they get restored from the sandbox annotations.
+			limits, err := s.containerLogLimits(c.Metadata().Name, sb.Annotations())
+			if err != nil {
+				log.Warnf(ctx, "Unable to restore log limits of container %s: %v", c.ID(), err)
+				continue
+			}
+			c.SetLogLimits(limits)
+			s.enforceLogLimits(c)
+		}
+	}
+
+	s.logLimitEnforcer.Start(s.monitorsChan)
+}
+
+// containerLogLimits returns the log limits of the container with the
+// provided name configured by the sandbox annotations or its workload.
+func (s *Server) containerLogLimits(ctrName string, sboxAnnotations map[string]string) (*loglimit.Limits, error) {
+	rateLimit, quota, err := s.config.Workloads.LogLimitsGivenAnnotations(ctrName, sboxAnnotations)
+	if err != nil {
+		return nil, err
+	}
+	return loglimit.FromAnnotations(sboxAnnotations, rateLimit, quota)
+}
+
+// enforceLogLimits adds the provided running container to the log limit
+// enforcer if its log rate is limited. The log quota is enforced by the
+// monitor.
+func (s *Server) enforceLogLimits(c *oci.Container) {
+	limits := c.LogLimits()
+	if s.logLimitEnforcer == nil || limits == nil || !limits.RateLimited() || c.Spoofed() || c.State().Status != oci.ContainerStateRunning {
+		return
+	}
+
+	labels := c.Labels()
+	name := labels[kubetypes.KubernetesContainerNameLabel]
+	pod := labels[kubetypes.KubernetesPodNameLabel]
+	namespace := labels[kubetypes.KubernetesPodNamespaceLabel]
+
+	s.logLimitEnforcer.Add(c.ID(), c.LogPath(), limits, func(lines uint64) {
+		logrus.Debugf("%d log lines of container %s exceeded the log rate limit", lines, c.ID())
+		metrics.Instance().MetricContainersLogExceededLinesTotalAdd(float64(lines), name, pod, namespace)
+	})
+}
+
+// startLogForwarder starts forwarding the container logs to the configured
+// log forward socket, including the ones of already running containers.
+func (s *Server) startLogForwarder(ctx context.Context) error {
+	if s.config.LogForwardSocket == "" {
+		return nil
+	}
+
+	logrus.Infof("Starting to forward container logs to %s", s.config.LogForwardSocket)
+	forwarder, err := logforward.New(s.config.LogForwardSocket, s.config.LogForwardFormat, s.config.LogForwardBufferSize)
+	if err != nil {
+		return err
+	}
+	s.logForwarder = forwarder
+
+	for _, sb := range s.ListSandboxes() {
+		for _, c := range sb.Containers().List() {
+			// Lines written while CRI-O was not running are not forwarded
+			// to avoid duplicates.
+			s.forwardLogs(c, false)
+		}
+	}
+
+	forwarder.Start(s.monitorsChan)
+	return nil
+}
+
+// forwardLogs adds the provided running container to the log forwarder.
+func (s *Server) forwardLogs(c *oci.Container, fromStart bool) {
+	if s.logForwarder == nil || c.Spoofed() || c.State().Status != oci.ContainerStateRunning {
+		return
+	}
+
+	labels := c.Labels()
+	s.logForwarder.Add(c.ID(), c.LogPath(), logforward.Metadata{
+		Pod:         labels[kubetypes.KubernetesPodNameLabel],
+		Namespace:   labels[kubetypes.KubernetesPodNamespaceLabel],
+		Container:   labels[kubetypes.KubernetesContainerNameLabel],
+		ContainerID: c.ID(),
+	}, fromStart)
+}
+
 // StartExitMonitor start a routine that monitors container exits
 // and updates the container status
 func (s *Server) StartExitMonitor(ctx context.Context) {
@@ -930,6 +1279,7 @@ func (s *Server) handleExit(ctx context.Context, event fsnotify.Event) {
 	}
 
 	if nriCtr != nil {
+		s.writeRecordedSeccompProfile(ctx, nriCtr)
 		if err := s.nri.stopContainer(ctx, nil, nriCtr); err != nil {
 			log.Warnf(ctx, "NRI stop container request of %s failed: %v", nriCtr.ID(), err)
 		}
@@ -144,6 +144,8 @@ var beforeEach = func() {
 	serverConfig.ContainerExitsDir = path.Join(testPath, "exits")
 	serverConfig.LogDir = path.Join(testPath, "log")
 	serverConfig.CleanShutdownFile = path.Join(testPath, "clean.shutdown")
+	serverConfig.CNIAttachmentsDir = path.Join(testPath, "cni-attachments")
+	serverConfig.HostportReservationsFile = path.Join(testPath, "hostport-reservations.json")
 	serverConfig.EnablePodEvents = true
 
 	// We want a directory that is guaranteed to exist, but it must
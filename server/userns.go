@@ -0,0 +1,118 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+
+	cstorage "github.com/containers/storage"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/userns"
+	"github.com/cri-o/cri-o/pkg/annotations"
+	"github.com/cri-o/cri-o/pkg/types"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// startUsernsAllocator loads the persisted user namespace ranges if a
+// userns_range is configured.
+func (s *Server) startUsernsAllocator() error {
+	if s.config.UsernsRange == "" {
+		return nil
+	}
+	start, size, err := userns.ParseRange(s.config.UsernsRange)
+	if err != nil {
+		return err
+	}
+	allocator, err := userns.New(s.config.UsernsAllocationsFile, start, size)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("Assigning user namespaces from range %s, %d ranges currently assigned", s.config.UsernsRange, len(allocator.List()))
+	s.usernsAllocator = allocator
+	return nil
+}
+
+// pruneUsernsAllocations releases the user namespace ranges of pods which do
+// not exist anymore, for example because they got removed while CRI-O was not
+// running.
+func (s *Server) pruneUsernsAllocations(ctx context.Context) {
+	if s.usernsAllocator == nil {
+		return
+	}
+	sandboxes := make(map[string]bool)
+	for _, sb := range s.ListSandboxes() {
+		sandboxes[sb.ID()] = true
+	}
+	pruned, err := s.usernsAllocator.Prune(func(podID string) bool {
+		return sandboxes[podID]
+	})
+	if err != nil {
+		log.Warnf(ctx, "Unable to release user namespaces of removed pods: %v", err)
+		return
+	}
+	for _, alloc := range pruned {
+		log.Infof(ctx, "Released user namespace %d-%d of removed pod %s", alloc.HostID, alloc.HostID+alloc.Size-1, alloc.PodID)
+	}
+}
+
+// allocateSandboxUserns assigns a user namespace range to a pod using the
+// "auto" user namespace mode and returns the resulting ID mappings. The size
+// requested by the pod annotation takes precedence over the one of the user
+// namespace mode. The provided options are returned unchanged if the pod does
+// not use the "auto" mode.
+func (s *Server) allocateSandboxUserns(ctx context.Context, podID, pod, namespace string, podAnnotations map[string]string, opts *cstorage.IDMappingOptions) (*cstorage.IDMappingOptions, error) {
+	if opts == nil || !opts.AutoUserNs {
+		return opts, nil
+	}
+	if v, ok := podAnnotations[annotations.UsernsSizeAnnotation]; ok {
+		size, err := strconv.ParseUint(v, 10, 32)
+		if err != nil || size == 0 {
+			return nil, fmt.Errorf("invalid %s annotation %q", annotations.UsernsSizeAnnotation, v)
+		}
+		opts.AutoUserNsOpts.Size = uint32(size)
+	}
+	if s.usernsAllocator == nil {
+		return opts, nil
+	}
+
+	alloc, err := s.usernsAllocator.Allocate(podID, pod, namespace, opts.AutoUserNsOpts.Size)
+	if err != nil {
+		return nil, fmt.Errorf("allocate user namespace: %w", err)
+	}
+	log.Infof(ctx, "Assigned user namespace %d-%d to pod %s/%s", alloc.HostID, alloc.HostID+alloc.Size-1, namespace, pod)
+	return &cstorage.IDMappingOptions{
+		UIDMap: alloc.IDMap(opts.AutoUserNsOpts.AdditionalUIDMappings),
+		GIDMap: alloc.IDMap(opts.AutoUserNsOpts.AdditionalGIDMappings),
+	}, nil
+}
+
+// releaseUsernsAllocation releases the user namespace range of the provided
+// pod, if it has one.
+func (s *Server) releaseUsernsAllocation(ctx context.Context, podID string) {
+	if s.usernsAllocator == nil {
+		return
+	}
+	if err := s.usernsAllocator.Release(podID); err != nil {
+		log.Warnf(ctx, "Unable to release user namespace of pod %s: %v", podID, err)
+	}
+}
+
+// getUsernsInfo returns the user namespace ranges assigned to pods.
+func (s *Server) getUsernsInfo() types.UsernsInfo {
+	info := types.UsernsInfo{Allocations: []types.UsernsAllocation{}}
+	if s.usernsAllocator == nil {
+		return info
+	}
+	info.FirstID, info.Size = s.usernsAllocator.Range()
+	for _, alloc := range s.usernsAllocator.List() {
+		info.Allocations = append(info.Allocations, types.UsernsAllocation{
+			PodID:       alloc.PodID,
+			Pod:         alloc.Pod,
+			Namespace:   alloc.Namespace,
+			HostID:      alloc.HostID,
+			Size:        alloc.Size,
+			CreatedTime: alloc.Created.UnixNano(),
+		})
+	}
+	return info
+}
@@ -81,6 +81,40 @@ function teardown() {
 	[[ -z $(ls "$CONTAINER_NAMESPACES_DIR/pidns") ]]
 }
 
+@test "pod cgroup namespace is rooted at the pod cgroup" {
+	if ! is_cgroup_v2; then
+		skip "node must be configured with cgroupv2 for this test"
+	fi
+	parent="Burstablecriotest123"
+	ctr_cgroup_suffix=""
+	if [[ "$CONTAINER_CGROUP_MANAGER" == "systemd" ]]; then
+		parent="$parent".slice
+		ctr_cgroup_suffix=".scope"
+	fi
+	create_runtime_with_allowed_annotation "cgroupns" "io.kubernetes.cri-o.CgroupNamespace"
+	start_crio
+
+	pod_config="$TESTDIR"/sandbox_config.json
+	jq --arg cg "$parent" '	  .annotations."io.kubernetes.cri-o.CgroupNamespace" = "pod"
+		| .linux.cgroup_parent = $cg' \
+		"$TESTDATA"/sandbox_config.json > "$pod_config"
+	pod_id=$(crictl runp "$pod_config")
+
+	ctr_id=$(crictl create "$pod_id" "$TESTDATA"/container_sleep.json "$pod_config")
+	crictl start "$ctr_id"
+
+	# the container sees its own cgroup below the pod cgroup
+	output=$(crictl exec --sync "$ctr_id" cat /proc/self/cgroup)
+	[[ "$output" == "0::/crio-$ctr_id$ctr_cgroup_suffix" ]]
+
+	ctr_pid=$(crictl inspect "$ctr_id" | jq .info.pid)
+	[[ $(stat -L -c %i /proc/"$ctr_pid"/ns/cgroup) == $(stat -c %i "$CONTAINER_NAMESPACES_DIR"/cgroupns/*) ]]
+
+	crictl rmp -fa
+	# make sure namespace is cleaned up
+	[[ -z $(ls "$CONTAINER_NAMESPACES_DIR/cgroupns") ]]
+}
+
 @test "KUBENSMNT mount namespace" {
 	original_ns=$(readlink /proc/self/ns/mnt)
 
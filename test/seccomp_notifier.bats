@@ -73,6 +73,31 @@ function teardown() {
 	curl -sf "http://localhost:$PORT/metrics" | grep 'container_runtime_crio_containers_seccomp_notifier_count_total{name="k8s_podsandbox1-redis_podsandbox1_redhat.test.crio_redhat-test-crio_0",syscall="swapoff"} 3'
 }
 
+@test "seccomp notifier with audit action and syscall rules" {
+	# Run with enabled feature set
+	create_runtime_with_allowed_annotation seccomp io.kubernetes.cri-o.seccompNotifierAction
+	start_crio
+
+	# Run with runtime/default
+	jq '.linux.security_context.seccomp.profile_type = 0' \
+		"$TESTDATA"/container_redis.json > "$TESTDIR"/container.json
+
+	# Audit blocked syscalls and stop the workload on swapon
+	jq '.annotations += { "io.kubernetes.cri-o.seccompNotifierAction": "audit,swapon=kill" }' \
+		"$TESTDATA"/sandbox_config.json > "$TESTDIR"/sandbox.json
+
+	CTR=$(crictl run "$TESTDIR"/container.json "$TESTDIR"/sandbox.json)
+
+	# The syscall gets allowed, but fails because of missing capabilities
+	run ! crictl exec -s "$CTR" swapoff -a
+	grep -q "Allowed audited seccomp syscall.*containerID=$CTR.*syscall=swapoff" "$CRIO_LOG"
+	crictl inspect "$CTR" | jq -e '.status.state == "CONTAINER_RUNNING"'
+
+	run ! crictl exec -s "$CTR" swapon /dev/null
+	EXPECTED_EXIT_STATUS=137 wait_until_exit "$CTR"
+	crictl inspect "$CTR" | jq -e '.status.reason == "seccomp killed"'
+}
+
 @test "seccomp notifier with custom profile" {
 	# Run with enabled feature set
 	create_runtime_with_allowed_annotation seccomp io.kubernetes.cri-o.seccompNotifierAction